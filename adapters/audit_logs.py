@@ -4,7 +4,7 @@ from __future__ import annotations
 
 import logging
 from datetime import datetime, timezone
-from typing import Any, Dict, Optional
+from typing import Any, Dict, Mapping, Optional
 from uuid import uuid4
 
 try:
@@ -17,6 +17,27 @@ from adapters.storage import get_table_client
 
 AUDIT_TABLE_NAME = "AuditLogs"
 
+# Run headers the Terraform provider sends, and the audit fields they are
+# recorded under.
+_RUN_HEADERS = {
+    "x-sanmar-terraform-version": "TerraformVersion",
+    "x-sanmar-workspace": "TerraformWorkspace",
+    "x-sanmar-execution": "TerraformExecution",
+}
+_RUN_HEADER_MAX_LENGTH = 128
+
+
+def run_metadata(headers: Mapping[str, str]) -> Dict[str, str]:
+    """Return the audit fields for the run headers present in a request."""
+
+    metadata: Dict[str, str] = {}
+    for header, value in headers.items():
+        field = _RUN_HEADERS.get(str(header).lower())
+        value = str(value or "").strip()
+        if field and value:
+            metadata[field] = value[:_RUN_HEADER_MAX_LENGTH]
+    return metadata
+
 
 def write_audit_log(
     name: str,
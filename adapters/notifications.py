@@ -0,0 +1,81 @@
+"""Deliver claim and release events to notification subscriptions."""
+
+from __future__ import annotations
+
+import logging
+from datetime import datetime, timezone
+from typing import Any, Dict, List
+from uuid import uuid4
+
+import requests
+
+from adapters.storage import get_table_client
+
+NOTIFICATIONS_TABLE = "NotificationSubscriptions"
+NOTIFICATION_PARTITION_KEY = "notification"
+# Deliveries run inside the claim request, so a slow webhook must not hold it up.
+DELIVERY_TIMEOUT_SECONDS = 5
+
+
+def _message(event: str, name: str, details: Dict[str, Any]) -> str:
+    verb = "claimed" if event == "claim" else "released"
+    user = details.get("user") or "unknown"
+    text = f"{name} was {verb} by {user}"
+    scope = "-".join(str(details[key]) for key in ("region", "environment") if details.get(key))
+    if scope:
+        text += f" ({scope})"
+    return text
+
+
+def build_delivery(kind: str, event: str, name: str, details: Dict[str, Any]) -> Any:
+    """Return the request body a subscription of ``kind`` expects."""
+
+    if kind == "event_grid":
+        return [
+            {
+                "id": str(uuid4()),
+                "eventType": f"SanMar.Naming.Name{event.capitalize()}",
+                "subject": f"names/{name}",
+                "eventTime": datetime.now(tz=timezone.utc).isoformat(),
+                "data": {"name": name, **details},
+                "dataVersion": "1.0",
+            }
+        ]
+    # Teams incoming webhooks and Slack both render a plain ``text`` field.
+    return {"text": _message(event, name, details)}
+
+
+def _subscriptions(event: str) -> List[Dict[str, Any]]:
+    table = get_table_client(NOTIFICATIONS_TABLE)
+    matches = []
+    for entity in table.query_entities(f"PartitionKey eq '{NOTIFICATION_PARTITION_KEY}'"):
+        if not entity.get("Enabled", True):
+            continue
+        events = str(entity.get("Events") or "").split(",")
+        if event in events:
+            matches.append(entity)
+    return matches
+
+
+def notify(event: str, name: str, details: Dict[str, Any]) -> None:
+    """Post ``event`` for ``name`` to every enabled subscription that wants it.
+
+    Delivery is best effort: failures are logged and never fail the claim or
+    release that triggered them.
+    """
+
+    try:
+        subscriptions = _subscriptions(event)
+    except Exception:
+        logging.exception("[notifications] Failed to load notification subscriptions.")
+        return
+
+    for entity in subscriptions:
+        body = build_delivery(str(entity.get("Kind")), event, name, details)
+        try:
+            response = requests.post(entity.get("Url"), json=body, timeout=DELIVERY_TIMEOUT_SECONDS)
+            response.raise_for_status()
+        except Exception:
+            logging.warning(
+                "[notifications] Delivery to subscription %s failed.", entity.get("RowKey"), exc_info=True
+            )
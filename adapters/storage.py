@@ -66,16 +66,22 @@ def get_table_client(table_name: str):
     return service.get_table_client(table_name)
 
 
-def check_name_exists(region: str, environment: str, name: str) -> bool:
-    """Return True if the claimed name entity exists and is marked in use."""
+def get_name_record(region: str, environment: str, name: str) -> Optional[Dict[str, Any]]:
+    """Return the stored entity of a name, or None when it was never claimed."""
 
     table = get_table_client("ClaimedNames")
     partition_key = f"{region.lower()}-{environment.lower()}"
     try:
-        entity = table.get_entity(partition_key=partition_key, row_key=name)
-        return bool(entity.get("InUse", False))
+        return table.get_entity(partition_key=partition_key, row_key=name)
     except ResourceNotFoundError:
-        return False
+        return None
+
+
+def check_name_exists(region: str, environment: str, name: str) -> bool:
+    """Return True if the claimed name entity exists and is marked in use."""
+
+    entity = get_name_record(region, environment, name)
+    return bool(entity and entity.get("InUse", False))
 
 
 def claim_name(
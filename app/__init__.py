@@ -20,7 +20,16 @@ app = func.FunctionApp(http_auth_level=func.AuthLevel.FUNCTION)
 # Import route modules so decorators execute at import time
 from .routes import audit as _audit_routes  # noqa: F401
 from .routes import docs as _docs_routes  # noqa: F401
+from .routes import environments as _environment_routes  # noqa: F401
+from .routes import index_reservations as _index_reservation_routes  # noqa: F401
 from .routes import names as _name_routes  # noqa: F401
+from .routes import notifications as _notification_routes  # noqa: F401
+from .routes import operations as _operation_routes  # noqa: F401
+from .routes import projects as _project_routes  # noqa: F401
+from .routes import regions as _region_routes  # noqa: F401
+from .routes import rules as _rule_routes  # noqa: F401
+from .routes import sessions as _session_routes  # noqa: F401
 from .routes import slug as _slug_routes  # noqa: F401
+from .routes import systems as _system_routes  # noqa: F401
 
 __all__ = ["app"]
@@ -2,8 +2,27 @@
 
 NAMES_TABLE_NAME = "ClaimedNames"
 AUDIT_TABLE_NAME = "AuditLogs"
+IDEMPOTENCY_TABLE_NAME = "ClaimIdempotency"
+# Claims accepted with 202 are stored here, partitioned by the lowercased
+# caller, and finished by a function reading the queue.
+OPERATIONS_TABLE_NAME = "ClaimOperations"
+OPERATIONS_QUEUE_NAME = "claim-operations"
 SLUG_TABLE_NAME = "SlugMappings"
 SLUG_PARTITION_KEY = "slug"
+ENVIRONMENTS_TABLE_NAME = "Environments"
+ENVIRONMENT_PARTITION_KEY = "environment"
+REGIONS_TABLE_NAME = "Regions"
+REGION_PARTITION_KEY = "region"
+PROJECTS_TABLE_NAME = "Projects"
+PROJECT_PARTITION_KEY = "project"
+SYSTEMS_TABLE_NAME = "Systems"
+SYSTEM_PARTITION_KEY = "system"
+# Subsystems are partitioned by the code of the system they belong to.
+SUBSYSTEMS_TABLE_NAME = "Subsystems"
+NOTIFICATIONS_TABLE_NAME = "NotificationSubscriptions"
+NOTIFICATION_PARTITION_KEY = "notification"
+INDEX_RESERVATIONS_TABLE_NAME = "IndexReservations"
+INDEX_RESERVATION_PARTITION_KEY = "reservation"
 ELEVATED_ROLES = {"admin"}
 API_TITLE = "Azure Naming Service API"
 API_VERSION = "1.2.0"
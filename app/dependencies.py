@@ -20,15 +20,21 @@ except ImportError:  # pragma: no cover - fallback when Azure SDK extras absent
     class ResourceNotFoundError(Exception):  # type: ignore[override]
         """Fallback ResourceNotFoundError when Azure SDK is absent."""
 
-from adapters.audit_logs import write_audit_log
+from adapters.audit_logs import run_metadata, write_audit_log
+from adapters.notifications import notify
 from adapters.slug_fetcher import SlugSourceError, get_all_remote_slugs
-from adapters.storage import get_table_client
+from adapters.storage import check_name_exists, get_name_record, get_table_client
 from core.auth import AuthError, is_authorized, require_role
+from core.index_reservations import find_overlap
+from core.user_settings import settings_service
 from core.name_service import (
     InvalidRequestError,
     NameConflictError,
     NameGenerationResult,
+    claimed_name_result,
     generate_and_claim_name,
+    preview_name,
+    suggest_names,
 )
 
 __all__: Iterable[str] = (
@@ -39,12 +45,21 @@ __all__: Iterable[str] = (
     "NameGenerationResult",
     "ResourceNotFoundError",
     "SlugSourceError",
+    "check_name_exists",
+    "claimed_name_result",
+    "find_overlap",
     "UpdateMode",
     "generate_and_claim_name",
     "get_all_remote_slugs",
+    "get_name_record",
     "get_table_client",
     "is_authorized",
     "logging",
+    "preview_name",
+    "notify",
     "require_role",
+    "run_metadata",
+    "settings_service",
+    "suggest_names",
     "write_audit_log",
 )
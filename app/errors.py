@@ -6,11 +6,16 @@ import logging
 
 import azure.functions as func
 
-from .responses import json_message
+from .responses import json_message, json_payload
 from .dependencies import InvalidRequestError, NameConflictError
 
 
 def handle_name_generation_error(exc: Exception, *, log_prefix: str) -> func.HttpResponse:
+    if isinstance(exc, InvalidRequestError) and exc.fields:
+        # Errors about particular fields name them, so clients can report
+        # them against their own inputs.
+        errors = [{"field": field, "message": str(exc)} for field in exc.fields]
+        return json_payload({"message": str(exc), "errors": errors}, status_code=400)
     if isinstance(exc, InvalidRequestError):
         return func.HttpResponse(str(exc), status_code=400)
     if isinstance(exc, NameConflictError):
@@ -2,7 +2,7 @@
 
 from __future__ import annotations
 
-from typing import List
+from typing import Dict, List
 
 from pydantic import BaseModel, ConfigDict, Field
 
@@ -20,11 +20,32 @@ class NameClaimRequest(BaseModel):
     subsystem: str | None = Field(default=None, description="Optional subsystem identifier.")
     system: str | None = Field(default=None, description="Optional system identifier.")
     index: str | None = Field(default=None, description="Optional numeric tie breaker.")
+    index_reuse: str | None = Field(
+        default=None,
+        description="Allocate the lowest free index when index is not set: never reuse the index of a released name, after_hold once the environment's retention has passed, or always.",
+    )
+    suffix: str | None = Field(default=None, description="Optional unique suffix (1-16 lowercase letters or digits).")
+    template: str | None = Field(
+        default=None,
+        description="Optional name template used instead of the rule's template for this claim.",
+    )
+    expires_at: str | None = Field(
+        default=None,
+        description="Optional RFC 3339 time after which the service releases the claim unless it is renewed.",
+    )
+    release_after: str | None = Field(
+        default=None,
+        description="Optional duration such as 720h after which the service releases the claim. Cannot be combined with expires_at.",
+    )
     session_id: str | None = Field(
         default=None,
         description="Optional session identifier to apply user defaults.",
         alias="sessionId",
     )
+    idempotency_key: str | None = Field(
+        default=None,
+        description="Optional key that makes repeating the request return the first response instead of claiming another name.",
+    )
 
 
 class DisplayFieldEntry(BaseModel):
@@ -48,10 +69,87 @@ class NameClaimResponse(BaseModel):
     subsystem: str | None = None
     system: str | None = None
     index: str | None = None
+    expiresAt: str | None = Field(default=None, description="Expiry sent with the claim, unchanged.")
+    releaseAt: str | None = Field(default=None, description="RFC 3339 time the service releases the claim because of release_after.")
+    display: List[DisplayFieldEntry] = Field(default_factory=list)
+    summary: str | None = Field(default=None, description="Human-readable summary produced by the naming rule template.")
+
+
+class FieldErrorEntry(BaseModel):
+    field: str = Field(..., description="Request field the error is about.")
+    message: str
+
+
+class OperationError(BaseModel):
+    status: int = Field(..., description="HTTP status the claim would have returned.")
+    message: str
+    errors: List[FieldErrorEntry] | None = Field(default=None, description="Errors about particular request fields.")
+
+
+class NameClaimBatchRequest(BaseModel):
+    """Claims sent together to /claim/batch."""
+
+    claims: List[NameClaimRequest] = Field(..., description="Up to 25 claims, each with the body of /claim.")
+
+
+class NameClaimBatchResult(BaseModel):
+    claim: NameClaimResponse | None = Field(default=None, description="Response of a claim that succeeded.")
+    error: OperationError | None = Field(default=None, description="Error a refused claim would have returned.")
+
+
+class NameClaimBatchResponse(BaseModel):
+    results: List[NameClaimBatchResult] = Field(..., description="One result per claim, in request order.")
+
+
+class OperationResponse(BaseModel):
+    """State of a claim accepted as a long-running operation."""
+
+    id: str
+    status: str = Field(..., description="running, succeeded, or failed.")
+    created_at: str | None = None
+    completed_at: str | None = None
+    result: NameClaimResponse | None = Field(default=None, description="The claim, once the operation succeeded.")
+    error: OperationError | None = Field(default=None, description="Why the claim failed, once the operation failed.")
+
+
+class NamePreviewResponse(BaseModel):
+    """The name a claim would get, returned without claiming it."""
+
+    name: str
+    resourceType: str
+    region: str
+    environment: str
+    slug: str
+    available: bool = Field(..., description="False when the name is already claimed, so claiming it now would fail.")
+    project: str | None = None
+    purpose: str | None = None
+    subsystem: str | None = None
+    system: str | None = None
+    index: str | None = None
     display: List[DisplayFieldEntry] = Field(default_factory=list)
     summary: str | None = Field(default=None, description="Human-readable summary produced by the naming rule template.")
 
 
+class SuggestionRequest(NameClaimRequest):
+    """Schema describing a request for candidate names."""
+
+    purposes: List[str] | None = Field(
+        default=None, description="Purpose segments to try at each index. Omit to vary only the index."
+    )
+    count: int = Field(default=5, description="Maximum number of candidates to return (1-50).")
+
+
+class NameSuggestion(BaseModel):
+    name: str
+    purpose: str
+    index: str
+    available: bool = Field(..., description="False when the candidate is already claimed.")
+
+
+class SuggestionResponse(BaseModel):
+    suggestions: List[NameSuggestion]
+
+
 class ReleaseRequest(BaseModel):
     """Schema describing a release request."""
 
@@ -64,6 +162,157 @@ class ReleaseRequest(BaseModel):
     )
 
 
+class TransferRequest(BaseModel):
+    """Schema describing a claim transfer request."""
+
+    name: str = Field(..., description="Claimed name to transfer.")
+    region: str = Field(..., description="Region where the name was registered.")
+    environment: str = Field(..., description="Environment where the name was registered.")
+    new_owner: str = Field(..., description="User or group that takes over the claim.")
+    reason: str | None = Field(default=None, description="Optional note recorded in the audit history.")
+
+
+class RenewRequest(BaseModel):
+    """Schema describing a claim renewal request."""
+
+    name: str = Field(..., description="Claimed name to renew.")
+    region: str = Field(..., description="Region where the name was registered.")
+    environment: str = Field(..., description="Environment where the name was registered.")
+    expires_at: str | None = Field(
+        default=None,
+        description="New RFC 3339 expiry, stored as sent. Omit or leave empty to remove the expiry.",
+    )
+
+
+class PurgeRequest(BaseModel):
+    """Schema describing a purge request for a released name."""
+
+    name: str = Field(..., description="Released name whose record is deleted.")
+    region: str = Field(..., description="Region where the name was registered.")
+    environment: str = Field(..., description="Environment where the name was registered.")
+    reason: str = Field(..., description="Why the record is purged; recorded in the audit history.")
+
+
+class ProjectRequest(BaseModel):
+    """Schema describing a project registry entry."""
+
+    code: str = Field(..., description="Project code used as the project segment of names (e.g. atlas).")
+    owner: str = Field(..., description="Team or person accountable for names claimed under the project.")
+    description: str | None = Field(default=None, description="Free-form description of the project.")
+    cost_center: str | None = Field(default=None, description="Cost center charged for resources in the project.")
+    index_policy: str = Field(
+        default="auto",
+        description="How the index segment is assigned for the project's claims: auto or manual.",
+    )
+
+
+class ProjectListResponse(BaseModel):
+    projects: List[ProjectRequest]
+
+
+class SystemRequest(BaseModel):
+    """Schema describing a system catalog entry."""
+
+    code: str = Field(..., description="System code used as the system segment of names (e.g. erp).")
+    description: str | None = Field(default=None, description="What the system is.")
+    owner: str | None = Field(default=None, description="Team or person that owns the system.")
+
+
+class SubsystemRequest(SystemRequest):
+    """Schema describing a subsystem catalog entry."""
+
+    code: str = Field(..., description="Subsystem code used as the subsystem segment of names (e.g. billing).")
+    system: str = Field(..., description="Code of the registered system the subsystem belongs to.")
+
+
+class SystemListResponse(BaseModel):
+    systems: List[SystemRequest]
+
+
+class SubsystemListResponse(BaseModel):
+    subsystems: List[SubsystemRequest]
+
+
+class EnvironmentRequest(BaseModel):
+    """Schema describing an environment catalog entry."""
+
+    code: str = Field(..., description="Environment code used as the environment segment of names (e.g. prd).")
+    display_name: str = Field(..., description="Human-readable environment name.")
+    allowed_regions: List[str] | None = Field(
+        default=None,
+        description="Region short codes names in this environment may use. Omit to allow every region.",
+    )
+    released_retention: str | None = Field(
+        default=None,
+        description="How long released names stay reserved, as a duration such as 720h.",
+    )
+
+
+class RegionRequest(BaseModel):
+    """Schema describing a region catalog entry."""
+
+    code: str = Field(..., description="Region short code used as the region segment of names (e.g. wus2).")
+    location: str = Field(..., description="Azure Resource Manager location name (e.g. westus2).")
+    display_name: str | None = Field(default=None, description="Optional human-readable region name.")
+
+
+class SessionDefaultsRequest(BaseModel):
+    """Schema describing the segment defaults stored for a session."""
+
+    session_id: str = Field(..., description="Session identifier the defaults belong to.")
+    defaults: Dict[str, str] = Field(
+        ..., description="Segment defaults keyed by claim field (region, environment, project, system, ...)."
+    )
+
+
+class SessionDefaultsResponse(BaseModel):
+    """Defaults the service applies to claims made with a session."""
+
+    sessionId: str
+    defaults: Dict[str, str] = Field(
+        ..., description="The caller's permanent defaults overlaid with the session's own defaults."
+    )
+    lastSeen: str = Field(..., description="When the session was last used, in ISO 8601 format.")
+
+
+class NotificationRequest(BaseModel):
+    """Schema describing a notification subscription."""
+
+    name: str = Field(..., description="Display name for the subscription.")
+    kind: str = Field(..., description="Delivery channel: teams, slack, or event_grid.")
+    url: str = Field(..., description="HTTPS webhook or Event Grid topic endpoint that receives the events.")
+    events: List[str] = Field(
+        default_factory=lambda: ["claim", "release"],
+        description="Events that trigger the notification: claim, release, or both.",
+    )
+    enabled: bool = Field(default=True, description="Whether the subscription is active.")
+
+
+class NotificationResponse(NotificationRequest):
+    """Notification subscription as stored by the service."""
+
+    id: str = Field(..., description="Identifier assigned to the subscription.")
+
+
+class IndexReservationRequest(BaseModel):
+    """Schema describing a block of reserved indices."""
+
+    region: str = Field(..., description="Region short code the reservation applies to.")
+    environment: str = Field(..., description="Environment the reservation applies to.")
+    resource_type: str | None = Field(default=None, description="Resource type the reservation applies to; every type when omitted.")
+    project: str | None = Field(default=None, description="Project the reservation applies to; every project when omitted.")
+    team: str = Field(..., description="Team that owns the reserved indices.")
+    start: int = Field(..., description="First reserved index (inclusive).")
+    end: int = Field(..., description="Last reserved index (inclusive).")
+    expires_at: str | None = Field(default=None, description="RFC 3339 time after which the reservation no longer applies; never when omitted.")
+
+
+class IndexReservationResponse(IndexReservationRequest):
+    """Index reservation as stored by the service."""
+
+    id: str = Field(..., description="Identifier assigned to the reservation.")
+
+
 class MessageResponse(BaseModel):
     message: str
 
@@ -94,6 +343,16 @@ class AuditRecordResponse(BaseModel):
     purpose: str | None = None
     system: str | None = None
     index: str | None = None
+    azure_resource_id: str | None = None
+
+
+class LinkRequest(BaseModel):
+    """Schema describing a link between a claimed name and an Azure resource."""
+
+    azure_resource_id: str = Field(
+        ...,
+        description="Azure Resource Manager ID of the resource using the name. An empty string removes the link.",
+    )
 
 
 class AuditLogEntry(BaseModel):
@@ -108,6 +367,14 @@ class AuditLogEntry(BaseModel):
     project: str | None = None
     purpose: str | None = None
     resource_type: str | None = None
+    slug: str | None = None
+    system: str | None = None
+    subsystem: str | None = None
+    index: str | None = None
+    new_owner: str | None = Field(default=None, description="Owner a transferred event moved the claim to.")
+    terraform_version: str | None = Field(default=None, description="Terraform version of the run that made the change.")
+    terraform_workspace: str | None = Field(default=None, description="Terraform workspace of the run that made the change.")
+    terraform_execution: str | None = Field(default=None, description="Where the run executed: tfc, automation, or cli.")
 
 
 class AuditBulkResponse(BaseModel):
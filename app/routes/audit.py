@@ -4,16 +4,20 @@ from __future__ import annotations
 
 import json
 import logging
+import re
 from datetime import datetime
 from typing import Dict, List
 
 import azure.functions as func
+from azure.core import MatchConditions
+from azure.core.exceptions import ResourceModifiedError
+from azure.data.tables import UpdateMode
 from azure_functions_openapi.decorator import openapi as openapi_doc
 
 from app import app
 from app.constants import AUDIT_TABLE_NAME, ELEVATED_ROLES, NAMES_TABLE_NAME
-from app.models import AuditBulkResponse, AuditRecordResponse
-from app.responses import json_payload
+from app.models import AuditBulkResponse, AuditRecordResponse, LinkRequest, MessageResponse
+from app.responses import json_message, json_payload
 from app.dependencies import (
     AuthError,
     ResourceNotFoundError,
@@ -97,6 +101,10 @@ def _build_filter(params: Dict[str, str]) -> str:
     return " and ".join(filters)
 
 
+# Header carrying whether a name is claimed on HEAD /api/audit responses.
+IN_USE_HEADER = "X-Sanmar-In-Use"
+
+
 def _query_audit_entities(table, filter_query: str | None):
     if filter_query:
         return list(table.query_entities(query_filter=filter_query))
@@ -104,10 +112,14 @@ def _query_audit_entities(table, filter_query: str | None):
 
 
 @app.function_name(name="audit_name")
-@app.route(route="audit", methods=[func.HttpMethod.GET])
+@app.route(route="audit", methods=[func.HttpMethod.GET, func.HttpMethod.HEAD])
 @openapi_doc(
     summary="Retrieve audit details for a claimed name",
-    description="Returns the audit record for a single resource name if the caller is authorized.",
+    description=(
+        "Returns the audit record for a single resource name if the caller is authorized. "
+        "HEAD answers without a body, reporting whether the name is claimed in the "
+        "X-Sanmar-In-Use header, so availability checks stay cheap."
+    ),
     tags=["Audit"],
     parameters=[
         {
@@ -141,6 +153,7 @@ def audit_name(req: func.HttpRequest) -> func.HttpResponse:
     """Retrieve audit information for a single claimed name."""
 
     logging.info("[audit_name] Starting RBAC-secured audit check.")
+    head = (getattr(req, "method", None) or "GET").upper() == "HEAD"
 
     try:
         user_id, user_roles = require_role(req.headers, min_role="reader")
@@ -162,11 +175,19 @@ def audit_name(req: func.HttpRequest) -> func.HttpResponse:
         table = get_table_client(NAMES_TABLE_NAME)
         entity = table.get_entity(partition_key=partition_key, row_key=name)
     except ResourceNotFoundError:
+        if head:
+            return func.HttpResponse(status_code=404, headers={IN_USE_HEADER: "false"})
         return func.HttpResponse("Audit entry not found.", status_code=404)
     except Exception:
         logging.exception("[audit_name] Failed to retrieve audit entity.")
         return func.HttpResponse("Error retrieving audit entry.", status_code=500)
 
+    if head:
+        # Only whether the name is claimed, which a conflicting claim reveals
+        # anyway, so any reader may ask about names they did not claim.
+        in_use = "true" if entity.get("InUse", False) else "false"
+        return func.HttpResponse(status_code=200, headers={IN_USE_HEADER: in_use})
+
     if not is_authorized(user_roles, user_id, entity.get("ClaimedBy"), entity.get("ReleasedBy")):
         return func.HttpResponse("Forbidden: not authorized to view this name.", status_code=403)
 
@@ -203,6 +224,97 @@ def audit_name(req: func.HttpRequest) -> func.HttpResponse:
     return json_payload(audit_info)
 
 
+# Azure Resource Manager IDs start with the subscription scope.
+_AZURE_RESOURCE_ID_PATTERN = re.compile(r"^/subscriptions/[^/]+/", re.IGNORECASE)
+
+
+@app.function_name(name="link_azure_resource")
+@app.route(route="audit", methods=[func.HttpMethod.PATCH])
+@openapi_doc(
+    summary="Link a claimed name to the Azure resource that uses it",
+    description=(
+        "Records the Azure Resource Manager ID of the resource that uses a claimed name on "
+        "its record, which the audit lookup then reports as azure_resource_id. An empty ID "
+        "removes the link. Only the claim's owner or an elevated role can link it."
+    ),
+    tags=["Audit"],
+    parameters=[
+        {"name": "region", "in": "query", "required": True, "schema": {"type": "string"}},
+        {"name": "environment", "in": "query", "required": True, "schema": {"type": "string"}},
+        {"name": "name", "in": "query", "required": True, "schema": {"type": "string"}},
+    ],
+    request_model=LinkRequest,
+    response_model=MessageResponse,
+    operation_id="linkAzureResource",
+    route="/audit",
+    method="patch",
+)
+def link_azure_resource(req: func.HttpRequest) -> func.HttpResponse:
+    """Record or remove the Azure resource linked to a claimed name."""
+
+    logging.info("[link_azure_resource] Processing link request with RBAC.")
+
+    try:
+        user_id, user_roles = require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    region = (req.params.get("region") or "").lower()
+    environment = (req.params.get("environment") or "").lower()
+    name = (req.params.get("name") or "").lower()
+    if not region or not environment or not name:
+        return func.HttpResponse(
+            "Missing query parameters: region, environment, name.", status_code=400
+        )
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+    if not isinstance(data, dict) or not isinstance(data.get("azure_resource_id", ""), str):
+        return func.HttpResponse("azure_resource_id must be a string.", status_code=400)
+
+    resource_id = data.get("azure_resource_id", "").strip()
+    if resource_id and not _AZURE_RESOURCE_ID_PATTERN.match(resource_id):
+        return func.HttpResponse(
+            "azure_resource_id must be an Azure resource ID starting with /subscriptions/.",
+            status_code=400,
+        )
+
+    try:
+        table = get_table_client(NAMES_TABLE_NAME)
+        entity = table.get_entity(partition_key=f"{region}-{environment}", row_key=name)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Audit entry not found.", status_code=404)
+    except Exception:
+        logging.exception("[link_azure_resource] Failed to retrieve name entity.")
+        return func.HttpResponse("Error retrieving audit entry.", status_code=500)
+
+    if not entity.get("InUse", False):
+        return func.HttpResponse("Name is not claimed, so it cannot be linked.", status_code=409)
+
+    if not is_authorized(user_roles, user_id, entity.get("ClaimedBy"), None):
+        return func.HttpResponse("Forbidden: not authorized to link this name.", status_code=403)
+
+    if resource_id:
+        entity["AzureResourceId"] = resource_id
+    else:
+        entity.pop("AzureResourceId", None)
+
+    try:
+        # REPLACE so removing the link drops the property.
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE, match_condition=MatchConditions.IfNotModified)
+    except ResourceModifiedError:
+        logging.warning("[link_azure_resource] Concurrent modification detected (ETag mismatch).")
+        return func.HttpResponse("Name was modified by another request. Please retrieve and try again.", status_code=409)
+    except Exception:
+        logging.exception("[link_azure_resource] Failed to update storage.")
+        return func.HttpResponse("Error linking name.", status_code=500)
+
+    message = "Azure resource linked." if resource_id else "Azure resource link removed."
+    return json_message(message, status_code=200)
+
+
 @app.function_name(name="audit_bulk")
 @app.route(route="audit_bulk", methods=[func.HttpMethod.GET])
 @openapi_doc(
@@ -291,6 +403,14 @@ def audit_bulk(req: func.HttpRequest) -> func.HttpResponse:
                 "project": entity.get("Project"),
                 "purpose": entity.get("Purpose"),
                 "resource_type": entity.get("ResourceType"),
+                "slug": entity.get("Slug"),
+                "system": entity.get("System"),
+                "subsystem": entity.get("Subsystem"),
+                "index": entity.get("Index"),
+                "new_owner": entity.get("NewOwner"),
+                "terraform_version": entity.get("TerraformVersion"),
+                "terraform_workspace": entity.get("TerraformWorkspace"),
+                "terraform_execution": entity.get("TerraformExecution"),
             }
         )
 
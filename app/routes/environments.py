@@ -0,0 +1,225 @@
+"""HTTP routes managing the environment catalog."""
+
+from __future__ import annotations
+
+import logging
+import re
+from typing import Dict, List, Optional, Tuple
+
+import azure.functions as func
+from azure.core.exceptions import ResourceExistsError, ResourceNotFoundError
+from azure.data.tables import UpdateMode
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import ENVIRONMENT_PARTITION_KEY, ENVIRONMENTS_TABLE_NAME
+from app.models import EnvironmentRequest, MessageResponse
+from app.responses import json_payload
+from app.dependencies import AuthError, get_table_client, require_role
+from core.claim_lifetime import parse_duration
+
+_CODE_PATTERN = re.compile(r"^[a-z0-9-]+$")
+
+
+def _environment_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    payload: Dict[str, object] = {
+        "code": entity.get("RowKey"),
+        "display_name": entity.get("DisplayName") or "",
+    }
+    allowed = str(entity.get("AllowedRegions") or "")
+    if allowed:
+        payload["allowed_regions"] = allowed.split(",")
+    if entity.get("ReleasedRetention"):
+        payload["released_retention"] = entity["ReleasedRetention"]
+    return payload
+
+
+def _parse_environment(data, code: Optional[str] = None) -> Tuple[Optional[Dict[str, object]], Optional[func.HttpResponse]]:
+    """Return a table entity from a request body, or an error response."""
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    code = (code or data.get("code") or "").strip().lower()
+    if not _CODE_PATTERN.match(code):
+        return None, func.HttpResponse("Field 'code' must contain only letters, numbers, and hyphens.", status_code=400)
+
+    display_name = (data.get("display_name") or "").strip()
+    if not display_name:
+        return None, func.HttpResponse("Missing required field: display_name.", status_code=400)
+
+    allowed: List[str] = []
+    for region in data.get("allowed_regions") or []:
+        region = str(region).strip().lower()
+        if not _CODE_PATTERN.match(region):
+            return None, func.HttpResponse(f"Invalid region code in allowed_regions: {region!r}.", status_code=400)
+        if region not in allowed:
+            allowed.append(region)
+
+    retention = (data.get("released_retention") or "").strip()
+    if retention:
+        try:
+            parse_duration(retention, "released_retention")
+        except ValueError as exc:
+            return None, func.HttpResponse(str(exc), status_code=400)
+
+    entity: Dict[str, object] = {
+        "PartitionKey": ENVIRONMENT_PARTITION_KEY,
+        "RowKey": code,
+        "DisplayName": display_name,
+    }
+    if allowed:
+        entity["AllowedRegions"] = ",".join(sorted(allowed))
+    if retention:
+        entity["ReleasedRetention"] = retention
+    return entity, None
+
+
+def _route_code(req: func.HttpRequest) -> str:
+    return (req.route_params.get("code") or "").strip().lower()
+
+
+@app.function_name(name="create_environment")
+@app.route(route="environments", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Register an environment",
+    description="Adds an environment code to the catalog. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=EnvironmentRequest,
+    response_model=EnvironmentRequest,
+    operation_id="createEnvironment",
+    route="/environments",
+    method="post",
+)
+def create_environment(req: func.HttpRequest) -> func.HttpResponse:
+    """Register a new environment code."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_environment(data)
+    if error is not None:
+        return error
+
+    try:
+        get_table_client(ENVIRONMENTS_TABLE_NAME).create_entity(entity=entity)
+    except ResourceExistsError:
+        return func.HttpResponse(f"Environment '{entity['RowKey']}' already exists.", status_code=409)
+    except Exception:
+        logging.exception("[create_environment] Failed to store environment.")
+        return func.HttpResponse("Error registering environment.", status_code=500)
+
+    return json_payload(_environment_payload(entity), status_code=201)
+
+
+@app.function_name(name="get_environment")
+@app.route(route="environments/{code}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve an environment",
+    description="Returns the catalog entry for an environment code.",
+    tags=["Catalog"],
+    response_model=EnvironmentRequest,
+    operation_id="getEnvironment",
+    route="/environments/{code}",
+    method="get",
+)
+def get_environment(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the catalog entry for an environment code."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(ENVIRONMENTS_TABLE_NAME).get_entity(
+            partition_key=ENVIRONMENT_PARTITION_KEY, row_key=_route_code(req)
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Environment not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_environment] Failed to read environment.")
+        return func.HttpResponse("Error reading environment.", status_code=500)
+
+    return json_payload(_environment_payload(entity))
+
+
+@app.function_name(name="update_environment")
+@app.route(route="environments/{code}", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Replace an environment",
+    description="Replaces the settings of a registered environment. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=EnvironmentRequest,
+    response_model=EnvironmentRequest,
+    operation_id="updateEnvironment",
+    route="/environments/{code}",
+    method="put",
+)
+def update_environment(req: func.HttpRequest) -> func.HttpResponse:
+    """Replace the settings of a registered environment."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_environment(data, code=_route_code(req))
+    if error is not None:
+        return error
+
+    try:
+        table = get_table_client(ENVIRONMENTS_TABLE_NAME)
+        table.get_entity(partition_key=ENVIRONMENT_PARTITION_KEY, row_key=entity["RowKey"])
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Environment not found.", status_code=404)
+    except Exception:
+        logging.exception("[update_environment] Failed to update environment.")
+        return func.HttpResponse("Error updating environment.", status_code=500)
+
+    return json_payload(_environment_payload(entity))
+
+
+@app.function_name(name="delete_environment")
+@app.route(route="environments/{code}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove an environment",
+    description="Removes an environment code from the catalog. Existing claims are kept. Requires the admin role.",
+    tags=["Catalog"],
+    response_model=MessageResponse,
+    operation_id="deleteEnvironment",
+    route="/environments/{code}",
+    method="delete",
+)
+def delete_environment(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove an environment code from the catalog."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        table = get_table_client(ENVIRONMENTS_TABLE_NAME)
+        table.get_entity(partition_key=ENVIRONMENT_PARTITION_KEY, row_key=_route_code(req))
+        table.delete_entity(partition_key=ENVIRONMENT_PARTITION_KEY, row_key=_route_code(req))
+    except ResourceNotFoundError:
+        return func.HttpResponse("Environment not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_environment] Failed to delete environment.")
+        return func.HttpResponse("Error deleting environment.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
@@ -0,0 +1,190 @@
+"""HTTP routes managing index reservations."""
+
+from __future__ import annotations
+
+import logging
+from typing import Dict, Optional, Tuple
+from uuid import uuid4
+
+import azure.functions as func
+from azure.core.exceptions import ResourceNotFoundError
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import INDEX_RESERVATION_PARTITION_KEY, INDEX_RESERVATIONS_TABLE_NAME
+from app.models import IndexReservationRequest, IndexReservationResponse, MessageResponse
+from app.responses import json_payload
+from app.dependencies import AuthError, find_overlap, get_table_client, require_role
+from core.claim_lifetime import parse_timestamp
+
+# Indices are two-digit segments.
+_MAX_INDEX = 99
+
+
+def _reservation_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    return {
+        "id": entity.get("RowKey"),
+        "region": entity.get("Region") or "",
+        "environment": entity.get("Environment") or "",
+        "resource_type": entity.get("ResourceType") or None,
+        "project": entity.get("Project") or None,
+        "team": entity.get("Team") or "",
+        "start": entity.get("Start"),
+        "end": entity.get("End"),
+        "expires_at": entity.get("ExpiresAt") or None,
+    }
+
+
+def _parse_reservation(data) -> Tuple[Optional[Dict[str, object]], Optional[func.HttpResponse]]:
+    """Return a table entity from a request body, or an error response."""
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    missing = [field for field in ("region", "environment", "team") if not str(data.get(field) or "").strip()]
+    if missing:
+        return None, func.HttpResponse(f"Missing required field(s): {', '.join(missing)}.", status_code=400)
+
+    start, end = data.get("start"), data.get("end")
+    if not isinstance(start, int) or not isinstance(end, int) or isinstance(start, bool) or isinstance(end, bool):
+        return None, func.HttpResponse("Fields 'start' and 'end' must be integers.", status_code=400)
+    if not 0 <= start <= end <= _MAX_INDEX:
+        return None, func.HttpResponse(f"Require 0 <= start <= end <= {_MAX_INDEX}.", status_code=400)
+
+    expires_at = data.get("expires_at")
+    if expires_at is not None:
+        try:
+            parse_timestamp(expires_at, "expires_at")
+        except ValueError as exc:
+            return None, func.HttpResponse(str(exc), status_code=400)
+
+    return {
+        "PartitionKey": INDEX_RESERVATION_PARTITION_KEY,
+        "RowKey": str(uuid4()),
+        "Region": str(data["region"]).strip().lower(),
+        "Environment": str(data["environment"]).strip().lower(),
+        "ResourceType": str(data.get("resource_type") or "").strip().lower(),
+        "Project": str(data.get("project") or "").strip().lower(),
+        "Team": str(data["team"]).strip().lower(),
+        "Start": start,
+        "End": end,
+        # Kept as sent, so clients read back the value they configured.
+        "ExpiresAt": str(expires_at).strip() if expires_at is not None else "",
+    }, None
+
+
+def _route_id(req: func.HttpRequest) -> str:
+    return (req.route_params.get("id") or "").strip()
+
+
+@app.function_name(name="create_index_reservation")
+@app.route(route="index_reservations", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Reserve a block of indices for a team",
+    description=(
+        "Reserves indices start-end (inclusive) for a team within a region and environment, "
+        "optionally narrowed to a resource type and project. Claims with an index in the block "
+        "must name the team in their 'team' field or metadata. Overlapping blocks return 409. "
+        "A reservation with expires_at stops applying once that time has passed."
+    ),
+    tags=["Index Reservations"],
+    request_model=IndexReservationRequest,
+    response_model=IndexReservationResponse,
+    operation_id="createIndexReservation",
+    route="/index_reservations",
+    method="post",
+)
+def create_index_reservation(req: func.HttpRequest) -> func.HttpResponse:
+    """Reserve a block of indices."""
+
+    try:
+        require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_reservation(data)
+    if error is not None:
+        return error
+
+    try:
+        overlap = find_overlap(entity)
+        if overlap is not None:
+            return func.HttpResponse(
+                f"Indices {overlap['Start']}-{overlap['End']} are already reserved for team '{overlap.get('Team')}'.",
+                status_code=409,
+            )
+        get_table_client(INDEX_RESERVATIONS_TABLE_NAME).create_entity(entity=entity)
+    except Exception:
+        logging.exception("[create_index_reservation] Failed to store index reservation.")
+        return func.HttpResponse("Error creating index reservation.", status_code=500)
+
+    return json_payload(_reservation_payload(entity), status_code=201)
+
+
+@app.function_name(name="get_index_reservation")
+@app.route(route="index_reservations/{id}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve an index reservation",
+    description="Returns a reserved block of indices.",
+    tags=["Index Reservations"],
+    response_model=IndexReservationResponse,
+    operation_id="getIndexReservation",
+    route="/index_reservations/{id}",
+    method="get",
+)
+def get_index_reservation(req: func.HttpRequest) -> func.HttpResponse:
+    """Return an index reservation."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(INDEX_RESERVATIONS_TABLE_NAME).get_entity(
+            partition_key=INDEX_RESERVATION_PARTITION_KEY, row_key=_route_id(req)
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Index reservation not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_index_reservation] Failed to read index reservation.")
+        return func.HttpResponse("Error reading index reservation.", status_code=500)
+
+    return json_payload(_reservation_payload(entity))
+
+
+@app.function_name(name="delete_index_reservation")
+@app.route(route="index_reservations/{id}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove an index reservation",
+    description="Returns the reserved indices to the shared pool. Names already claimed in the block are kept.",
+    tags=["Index Reservations"],
+    response_model=MessageResponse,
+    operation_id="deleteIndexReservation",
+    route="/index_reservations/{id}",
+    method="delete",
+)
+def delete_index_reservation(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove an index reservation."""
+
+    try:
+        require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        table = get_table_client(INDEX_RESERVATIONS_TABLE_NAME)
+        table.get_entity(partition_key=INDEX_RESERVATION_PARTITION_KEY, row_key=_route_id(req))
+        table.delete_entity(partition_key=INDEX_RESERVATION_PARTITION_KEY, row_key=_route_id(req))
+    except ResourceNotFoundError:
+        return func.HttpResponse("Index reservation not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_index_reservation] Failed to delete index reservation.")
+        return func.HttpResponse("Error deleting index reservation.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
@@ -2,32 +2,61 @@
 
 from __future__ import annotations
 
+import hashlib
+import json
 import logging
-from datetime import datetime, timezone
+import re
+from datetime import datetime, timedelta, timezone
 
 import azure.functions as func
 from azure.core import MatchConditions
-from azure.core.exceptions import ResourceModifiedError
+from azure.core.exceptions import ResourceExistsError, ResourceModifiedError, ResourceNotFoundError
 from azure.data.tables import UpdateMode
 from azure_functions_openapi.decorator import openapi as openapi_doc
 
 from app import app
-from app.constants import NAMES_TABLE_NAME
+from app.constants import IDEMPOTENCY_TABLE_NAME, NAMES_TABLE_NAME, OPERATIONS_QUEUE_NAME
 from app.errors import handle_name_generation_error
-from app.models import MessageResponse, NameClaimRequest, NameClaimResponse, ReleaseRequest
-from app.responses import build_claim_response, json_message
+from app.models import (
+    MessageResponse,
+    NameClaimBatchRequest,
+    NameClaimBatchResponse,
+    NameClaimRequest,
+    NameClaimResponse,
+    NamePreviewResponse,
+    PurgeRequest,
+    ReleaseRequest,
+    RenewRequest,
+    SuggestionRequest,
+    SuggestionResponse,
+    TransferRequest,
+)
+from app.responses import build_claim_response, json_message, json_payload
+from app.routes.operations import accepted_response, new_operation_id, prefers_async, start_claim_operation
 from app.dependencies import (
     AuthError,
+    InvalidRequestError,
+    NameConflictError,
+    check_name_exists,
+    claimed_name_result,
     generate_and_claim_name,
+    get_name_record,
     get_table_client,
     is_authorized,
+    notify,
+    preview_name,
     require_role,
+    run_metadata,
+    suggest_names,
     write_audit_log,
 )
+from core.claim_lifetime import lapse_reason, parse_timestamp
 from core.name_service import _sanitize_metadata_dict
 
 
-def _handle_claim_request(req: func.HttpRequest, *, log_prefix: str) -> func.HttpResponse:
+def _handle_claim_request(
+    req: func.HttpRequest, *, log_prefix: str, operations: func.Out[str] | None = None
+) -> func.HttpResponse:
     logging.info("[%s] Processing claim request with RBAC.", log_prefix)
 
     try:
@@ -40,21 +69,274 @@ def _handle_claim_request(req: func.HttpRequest, *, log_prefix: str) -> func.Htt
     except ValueError:
         return func.HttpResponse("Invalid JSON payload.", status_code=400)
 
+    if operations is not None and not prefers_async(req.headers):
+        operations = None
+    return _claim(payload, user_id, run=run_metadata(req.headers), operations=operations, log_prefix=log_prefix)
+
+
+def _claim(
+    payload, user_id: str, *, run: dict, operations: func.Out[str] | None = None, log_prefix: str
+) -> func.HttpResponse:
+    """Claim the name a request body describes and return the response for it."""
+
+    idempotency_key = None
+    if isinstance(payload, dict):
+        idempotency_key = payload.pop("idempotency_key", None) or payload.pop("idempotencyKey", None)
+    if idempotency_key:
+        return _handle_idempotent_claim(
+            payload, user_id, str(idempotency_key), run=run, operations=operations, log_prefix=log_prefix
+        )
+    if operations is not None:
+        return start_claim_operation(payload, user_id, run, operations)
+
     try:
-        result = generate_and_claim_name(payload, requested_by=user_id)
+        result = generate_and_claim_name(payload, requested_by=user_id, run_metadata=run)
         return build_claim_response(result, user_id)
     except Exception as exc:  # pragma: no cover - centralised error handling
         return handle_name_generation_error(exc, log_prefix=log_prefix)
 
 
+# Keys are used as table row keys, so only allow characters that are valid there.
+_IDEMPOTENCY_KEY_PATTERN = re.compile(r"^[A-Za-z0-9._:-]{8,128}$")
+
+# A claim still marked in progress after this long belongs to an invocation
+# that died, since it is longer than the Functions timeout.
+_IDEMPOTENCY_STALE_AFTER = timedelta(minutes=10)
+
+# Keys are forgotten this long after their claim started, so they can be
+# reused and the table does not grow without bound.
+_IDEMPOTENCY_TTL = timedelta(hours=24)
+
+
+def _handle_idempotent_claim(
+    payload: dict,
+    user_id: str,
+    key: str,
+    *,
+    run: dict,
+    operations: func.Out[str] | None = None,
+    log_prefix: str,
+) -> func.HttpResponse:
+    """Claim a name once per idempotency key, replaying the first response.
+
+    Keys are scoped to the caller, so one caller can never replay another's
+    claim, and bound to the request they were first used with. Refused
+    claims forget the key so the request can be retried. The name is stored
+    with the key before it is claimed, so a retry that takes over from an
+    invocation that died adopts its claim instead of claiming a second name.
+    With an operations queue the claim runs as an operation, and repeats
+    are pointed at it until it finishes.
+    """
+
+    if not _IDEMPOTENCY_KEY_PATTERN.match(key):
+        return func.HttpResponse(
+            "Invalid idempotency_key: use 8-128 letters, digits, '.', '_', ':' or '-'.",
+            status_code=400,
+        )
+
+    partition_key = user_id.lower()
+    payload_hash = _payload_hash(payload)
+    try:
+        table = get_table_client(IDEMPOTENCY_TABLE_NAME)
+        record = _get_idempotency_record(table, partition_key, key)
+        if record is not None and _idempotency_record_age(record) > _IDEMPOTENCY_TTL:
+            table.delete_entity(partition_key=partition_key, row_key=key)
+            record = None
+        if record is not None:
+            if record.get("PayloadHash") and record["PayloadHash"] != payload_hash:
+                return func.HttpResponse(
+                    "This idempotency_key was already used with a different request.",
+                    status_code=422,
+                )
+            if record.get("Response"):
+                logging.info("[%s] Replaying claim for idempotency key.", log_prefix)
+                return _replayed_claim(record["Response"])
+            if record.get("OperationId"):
+                return accepted_response(record["OperationId"])
+            if _idempotency_record_age(record) <= _IDEMPOTENCY_STALE_AFTER:
+                return func.HttpResponse(
+                    "A claim with this idempotency key is in progress. Retry shortly.",
+                    status_code=503,
+                    headers={"Retry-After": "2"},
+                )
+            adopted = _adopt_interrupted_claim(
+                table, partition_key, key, record, payload, user_id, log_prefix=log_prefix
+            )
+            if adopted is not None:
+                return adopted
+            table.delete_entity(partition_key=partition_key, row_key=key)
+
+        entity = {
+            "PartitionKey": partition_key,
+            "RowKey": key,
+            "StartedAt": datetime.now(tz=timezone.utc).isoformat(),
+            "PayloadHash": payload_hash,
+        }
+        if operations is not None:
+            entity["OperationId"] = new_operation_id()
+        else:
+            pending = preview_name(payload, requested_by=user_id)
+            entity.update({"Name": pending.name, "Region": pending.region, "Environment": pending.environment})
+        table.create_entity(entity=entity)
+    except ResourceExistsError:
+        return func.HttpResponse(
+            "A claim with this idempotency key is in progress. Retry shortly.",
+            status_code=503,
+            headers={"Retry-After": "2"},
+        )
+    except (InvalidRequestError, NameConflictError) as exc:
+        return handle_name_generation_error(exc, log_prefix=log_prefix)
+    except Exception:
+        logging.exception("[%s] Failed to record idempotency key.", log_prefix)
+        return json_message("Error claiming name.", status_code=500)
+
+    if operations is not None:
+        response = start_claim_operation(
+            payload, user_id, run, operations, operation_id=entity["OperationId"], idempotency_key=key
+        )
+        if response.status_code != 202:
+            _forget_idempotency_key(table, partition_key, key, log_prefix=log_prefix)
+        return response
+
+    try:
+        result = generate_and_claim_name(payload, requested_by=user_id, run_metadata=run)
+        response = build_claim_response(result, user_id)
+    except Exception as exc:  # pragma: no cover - centralised error handling
+        _forget_idempotency_key(table, partition_key, key, log_prefix=log_prefix)
+        return handle_name_generation_error(exc, log_prefix=log_prefix)
+
+    _record_idempotent_response(table, partition_key, key, result.name, response, log_prefix=log_prefix)
+    return response
+
+
+def _payload_hash(payload: dict) -> str:
+    """Return a digest of a claim request, independent of key order."""
+
+    canonical = json.dumps(payload, sort_keys=True, separators=(",", ":"), default=str)
+    return hashlib.sha256(canonical.encode("utf-8")).hexdigest()
+
+
+def _replayed_claim(body: str) -> func.HttpResponse:
+    return func.HttpResponse(
+        body,
+        mimetype="application/json",
+        status_code=201,
+        headers={"Idempotent-Replayed": "true"},
+    )
+
+
+def _record_idempotent_response(
+    table, partition_key: str, key: str, name: str, response: func.HttpResponse, *, log_prefix: str
+) -> None:
+    try:
+        table.update_entity(
+            entity={
+                "PartitionKey": partition_key,
+                "RowKey": key,
+                "Name": name,
+                "Response": response.get_body().decode("utf-8"),
+            },
+            mode=UpdateMode.MERGE,
+        )
+    except Exception:
+        # The name is claimed either way. The record already holds it, so a
+        # retry once the record is stale adopts this claim.
+        logging.exception("[%s] Failed to record claim for idempotency key.", log_prefix)
+
+
+def _adopt_interrupted_claim(
+    table, partition_key: str, key: str, record: dict, payload: dict, user_id: str, *, log_prefix: str
+) -> func.HttpResponse | None:
+    """Answer for a stale record whose invocation claimed its name before dying.
+
+    Returns None when the stored name was not claimed by that invocation, so
+    the caller claims afresh. The answer is built from the claimed record, as
+    rendering the request again would allocate another index under
+    index_reuse now that the claimed one is taken.
+    """
+
+    name, region, environment = record.get("Name"), record.get("Region"), record.get("Environment")
+    if not (name and region and environment):
+        return None
+    claimed = get_name_record(region, environment, name)
+    if not claimed or not claimed.get("InUse") or str(claimed.get("ClaimedBy") or "").lower() != user_id.lower():
+        return None
+    claimed_at = _stored_time(claimed.get("ClaimedAt"))
+    started_at = _stored_time(record.get("StartedAt"))
+    if claimed_at is None or started_at is None or claimed_at < started_at:
+        return None
+
+    logging.info("[%s] Adopting claim of an interrupted request for idempotency key.", log_prefix)
+    response = build_claim_response(claimed_name_result(name, region, environment, claimed), user_id)
+    _record_idempotent_response(table, partition_key, key, name, response, log_prefix=log_prefix)
+    return _replayed_claim(response.get_body().decode("utf-8"))
+
+
+def _get_idempotency_record(table, partition_key: str, key: str) -> dict | None:
+    try:
+        return table.get_entity(partition_key=partition_key, row_key=key)
+    except ResourceNotFoundError:
+        return None
+
+
+def _stored_time(value) -> datetime | None:
+    try:
+        return parse_timestamp(value, "timestamp")
+    except ValueError:
+        return None
+
+
+def _idempotency_record_age(record: dict) -> timedelta:
+    started = _stored_time(record.get("StartedAt"))
+    if started is None:
+        return timedelta.max
+    return datetime.now(tz=timezone.utc) - started
+
+
+def _forget_idempotency_key(table, partition_key: str, key: str, *, log_prefix: str) -> None:
+    try:
+        table.delete_entity(partition_key=partition_key, row_key=key)
+    except Exception:
+        logging.exception("[%s] Failed to remove idempotency key after a refused claim.", log_prefix)
+
+
+def _purge_expired_idempotency_keys(now: datetime) -> int:
+    """Delete the idempotency records older than the TTL; return how many."""
+
+    table = get_table_client(IDEMPOTENCY_TABLE_NAME)
+    cutoff = (now - _IDEMPOTENCY_TTL).isoformat()
+    purged = 0
+    for entity in table.query_entities(f"StartedAt lt '{cutoff}'"):
+        table.delete_entity(partition_key=entity["PartitionKey"], row_key=entity["RowKey"])
+        purged += 1
+    return purged
+
+
+@app.schedule(schedule="0 30 * * * *", arg_name="mytimer", run_on_startup=False, use_monitor=True)
+def purge_idempotency_keys_timer(mytimer: func.TimerRequest) -> None:  # pragma: no cover - timer integration
+    """Timer triggered removal of expired idempotency keys."""
+
+    try:
+        purged = _purge_expired_idempotency_keys(datetime.now(tz=timezone.utc))
+    except Exception:
+        logging.exception("[purge_idempotency_keys] Sweep failed.")
+        return
+    logging.info("[purge_idempotency_keys] Removed %d expired idempotency key(s).", purged)
+
+
 @app.function_name(name="claim_name")
 @app.route(route="claim", methods=[func.HttpMethod.POST])
+@app.queue_output(arg_name="operations", queue_name=OPERATIONS_QUEUE_NAME, connection="AzureWebJobsStorage")
 @openapi_doc(
     summary="Generate and claim a compliant resource name",
     description=(
         "Generates an Azure-compliant name based on resource type, region, and environment, "
         "then marks it as claimed for the caller. Optional metadata segments can be supplied "
-        "to influence slug composition."
+        "to influence slug composition. Requests that repeat an idempotency_key get the "
+        "response of the first request instead of a second name, and reusing one with a "
+        "different body is refused with 422. Requests sent with "
+        "'Prefer: respond-async' and no idempotency_key are answered with 202 and an "
+        "Operation-Location to poll, and the claim finishes in the background."
     ),
     tags=["Names"],
     request_model=NameClaimRequest,
@@ -63,10 +345,174 @@ def _handle_claim_request(req: func.HttpRequest, *, log_prefix: str) -> func.Htt
     route="/claim",
     method="post",
 )
-def claim_name(req: func.HttpRequest) -> func.HttpResponse:
+def claim_name(req: func.HttpRequest, operations: func.Out[str]) -> func.HttpResponse:
     """Generate and claim a compliant name."""
 
-    return _handle_claim_request(req, log_prefix="claim_name")
+    return _handle_claim_request(req, log_prefix="claim_name", operations=operations)
+
+
+# Matches the largest batch the Terraform provider sends.
+_MAX_BATCH_CLAIMS = 25
+
+
+def _batch_result(response: func.HttpResponse) -> dict:
+    """Return one claim's response as its entry in a batch response."""
+
+    body = response.get_body().decode("utf-8")
+    if response.status_code == 201:
+        return {"claim": json.loads(body)}
+    error: dict = {"status": response.status_code, "message": body}
+    try:
+        parsed = json.loads(body)
+    except ValueError:
+        parsed = None
+    if isinstance(parsed, dict):
+        error["message"] = str(parsed.get("message") or body)
+        if parsed.get("errors"):
+            error["errors"] = parsed["errors"]
+    return {"error": error}
+
+
+@app.function_name(name="claim_names")
+@app.route(route="claim/batch", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Claim several names in one request",
+    description=(
+        "Accepts up to 25 claims, each with the body of /claim, and claims them in order. Each "
+        "result holds the claim's response, or the error it would have returned on its own, so "
+        "one refused claim does not fail the others. A claim's idempotency_key applies as it "
+        "does on /claim. Claims in a batch always run synchronously."
+    ),
+    tags=["Names"],
+    request_model=NameClaimBatchRequest,
+    response_model=NameClaimBatchResponse,
+    operation_id="claimNames",
+    route="/claim/batch",
+    method="post",
+)
+def claim_names(req: func.HttpRequest) -> func.HttpResponse:
+    """Claim each name in a batch, answering with one result per claim."""
+
+    logging.info("[claim_names] Processing claim batch with RBAC.")
+    try:
+        user_id, _roles = require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        body = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+    claims = body.get("claims") if isinstance(body, dict) else None
+    if not isinstance(claims, list) or not claims:
+        return func.HttpResponse("Field 'claims' must be a non-empty list.", status_code=400)
+    if len(claims) > _MAX_BATCH_CLAIMS:
+        return func.HttpResponse(f"A batch holds at most {_MAX_BATCH_CLAIMS} claims.", status_code=400)
+
+    run = run_metadata(req.headers)
+    results = []
+    for payload in claims:
+        if not isinstance(payload, dict):
+            results.append({"error": {"status": 400, "message": "Invalid JSON payload."}})
+            continue
+        results.append(_batch_result(_claim(payload, user_id, run=run, log_prefix="claim_names")))
+    return json_payload({"results": results})
+
+
+@app.function_name(name="preview_name")
+@app.route(route="preview", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Preview the name a claim would get",
+    description=(
+        "Accepts the same body as /claim and returns the name it would generate, applying the "
+        "caller's session and permanent defaults, without claiming it. available is false when "
+        "the name is already claimed."
+    ),
+    tags=["Names"],
+    request_model=NameClaimRequest,
+    response_model=NamePreviewResponse,
+    operation_id="previewName",
+    route="/preview",
+    method="post",
+)
+def preview(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the name a claim would get without claiming it."""
+
+    try:
+        user_id, _ = require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        payload = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+    if not isinstance(payload, dict):
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+    payload.pop("idempotency_key", None)
+    payload.pop("idempotencyKey", None)
+
+    try:
+        result = preview_name(payload, requested_by=user_id)
+        body = result.to_dict()
+        body["available"] = not check_name_exists(result.region, result.environment, result.name)
+    except Exception as exc:  # pragma: no cover - centralised error handling
+        return handle_name_generation_error(exc, log_prefix="preview_name")
+
+    body.setdefault("display", [])
+    return json_payload(body)
+
+
+_MAX_SUGGESTIONS = 50
+
+
+@app.function_name(name="suggest_names")
+@app.route(route="suggestions", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Suggest candidate names",
+    description=(
+        "Accepts the body of /claim plus optional purposes and count, and returns up to count "
+        "distinct names built as a claim would build them, walking the index up from 01 and "
+        "trying each purpose at every index. Each candidate says whether it is free; nothing "
+        "is claimed."
+    ),
+    tags=["Names"],
+    request_model=SuggestionRequest,
+    response_model=SuggestionResponse,
+    operation_id="suggestNames",
+    route="/suggestions",
+    method="post",
+)
+def suggestions(req: func.HttpRequest) -> func.HttpResponse:
+    """Return candidate names without claiming any of them."""
+
+    try:
+        user_id, _ = require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        payload = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+    if not isinstance(payload, dict):
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    purposes = payload.pop("purposes", None) or []
+    count = payload.pop("count", 5)
+    if not isinstance(purposes, list) or not all(isinstance(p, str) and p for p in purposes):
+        return func.HttpResponse("Field 'purposes' must be a list of strings.", status_code=400)
+    if not isinstance(count, int) or isinstance(count, bool) or not 1 <= count <= _MAX_SUGGESTIONS:
+        return func.HttpResponse(f"Field 'count' must be between 1 and {_MAX_SUGGESTIONS}.", status_code=400)
+    for key in ("idempotency_key", "idempotencyKey", "index"):
+        payload.pop(key, None)
+
+    try:
+        candidates = suggest_names(payload, user_id, purposes=purposes, count=count)
+    except Exception as exc:  # pragma: no cover - centralised error handling
+        return handle_name_generation_error(exc, log_prefix="suggest_names")
+
+    return json_payload({"suggestions": candidates})
 
 
 @app.function_name(name="release_name")
@@ -187,8 +633,335 @@ def release_name(req: func.HttpRequest) -> func.HttpResponse:
             # Add any additional custom metadata that was stored
             metadata[key] = value
 
+    metadata.update(run_metadata(req.headers))
+
     # Sanitize metadata before audit logging
     metadata = _sanitize_metadata_dict(metadata)
     write_audit_log(name, user_id, "released", reason, metadata=metadata)
+    notify(
+        "release",
+        name,
+        {
+            "user": user_id,
+            "resourceType": entity.get("ResourceType"),
+            "region": region,
+            "environment": environment,
+            "reason": reason,
+        },
+    )
 
     return json_message("Name released successfully.", status_code=200)
+
+
+def _claim_operation_target(data):
+    """Return (name, partition_key) from an operation payload, or an error response."""
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    name = (data.get("name") or "").lower()
+    region = (data.get("region") or "").lower()
+    environment = (data.get("environment") or "").lower()
+    if not name or not region or not environment:
+        return None, func.HttpResponse("Missing required fields: name, region and environment.", status_code=400)
+
+    return (name, f"{region}-{environment}"), None
+
+
+@app.function_name(name="transfer_claim")
+@app.route(route="claim/transfer", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Transfer a claimed name to a new owner",
+    description=(
+        "Reassigns an active claim to another user or group without releasing the name. "
+        "Only the current owner or an elevated role can transfer a claim."
+    ),
+    tags=["Names"],
+    request_model=TransferRequest,
+    response_model=MessageResponse,
+    operation_id="transferClaim",
+    route="/claim/transfer",
+    method="post",
+)
+def transfer_claim(req: func.HttpRequest) -> func.HttpResponse:
+    """Transfer a claimed name to a new owner."""
+
+    logging.info("[transfer_claim] Processing transfer request with RBAC.")
+
+    try:
+        user_id, user_roles = require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    target, error = _claim_operation_target(data)
+    if error is not None:
+        return error
+    name, partition_key = target
+
+    new_owner = (data.get("new_owner") or "").strip().lower()
+    if not new_owner:
+        return func.HttpResponse("Missing required field: new_owner.", status_code=400)
+
+    try:
+        names_table = get_table_client(NAMES_TABLE_NAME)
+        entity = names_table.get_entity(partition_key=partition_key, row_key=name)
+    except Exception:
+        logging.exception("[transfer_claim] Name not found during transfer.")
+        return func.HttpResponse("Name not found.", status_code=404)
+
+    if not entity.get("InUse"):
+        return func.HttpResponse("Name is not claimed, so it cannot be transferred.", status_code=409)
+
+    if not is_authorized(user_roles, user_id, entity.get("ClaimedBy"), None):
+        return func.HttpResponse("Forbidden: not authorized to transfer this name.", status_code=403)
+
+    previous_owner = entity.get("ClaimedBy")
+    entity["ClaimedBy"] = new_owner
+    entity["TransferredBy"] = user_id
+    entity["TransferredAt"] = datetime.now(tz=timezone.utc).isoformat()
+
+    try:
+        names_table.update_entity(entity=entity, mode=UpdateMode.REPLACE, match_condition=MatchConditions.IfNotModified)
+    except ResourceModifiedError:
+        logging.warning("[transfer_claim] Concurrent modification detected (ETag mismatch).")
+        return func.HttpResponse("Name was modified by another request. Please retrieve and try again.", status_code=409)
+    except Exception:
+        logging.exception("[transfer_claim] Failed to update storage during transfer.")
+        return func.HttpResponse("Error transferring name.", status_code=500)
+
+    region, environment = partition_key.split("-", 1)
+    note = f"Transferred from {previous_owner} to {new_owner}"
+    if data.get("reason"):
+        note += f": {data['reason']}"
+    metadata = _sanitize_metadata_dict(
+        {
+            "Region": region,
+            "Environment": environment,
+            "ResourceType": entity.get("ResourceType"),
+            "NewOwner": new_owner,
+            **run_metadata(req.headers),
+        }
+    )
+    write_audit_log(name, user_id, "transferred", note, metadata=metadata)
+
+    return json_message("Name transferred successfully.", status_code=200)
+
+
+@app.function_name(name="renew_claim")
+@app.route(route="claim/renew", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Move the expiry of a claimed name",
+    description=(
+        "Sets a new expires_at on an active claim, stored exactly as sent, or removes the expiry "
+        "when expires_at is empty. The service releases claims whose expiry has passed. Only the "
+        "current owner or an elevated role can renew a claim."
+    ),
+    tags=["Names"],
+    request_model=RenewRequest,
+    response_model=MessageResponse,
+    operation_id="renewClaim",
+    route="/claim/renew",
+    method="post",
+)
+def renew_claim(req: func.HttpRequest) -> func.HttpResponse:
+    """Set or remove the expiry of a claimed name."""
+
+    logging.info("[renew_claim] Processing renew request with RBAC.")
+
+    try:
+        user_id, user_roles = require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    target, error = _claim_operation_target(data)
+    if error is not None:
+        return error
+    name, partition_key = target
+
+    expires_at = (data.get("expires_at") or "").strip()
+    if expires_at:
+        try:
+            expiry = parse_timestamp(expires_at, "expires_at")
+        except ValueError as exc:
+            return func.HttpResponse(str(exc), status_code=400)
+        if expiry <= datetime.now(tz=timezone.utc):
+            return func.HttpResponse("Field 'expires_at' must be in the future.", status_code=400)
+
+    try:
+        names_table = get_table_client(NAMES_TABLE_NAME)
+        entity = names_table.get_entity(partition_key=partition_key, row_key=name)
+    except Exception:
+        logging.exception("[renew_claim] Name not found during renewal.")
+        return func.HttpResponse("Name not found.", status_code=404)
+
+    if not entity.get("InUse"):
+        return func.HttpResponse("Name is not claimed, so it cannot be renewed.", status_code=409)
+
+    if not is_authorized(user_roles, user_id, entity.get("ClaimedBy"), None):
+        return func.HttpResponse("Forbidden: not authorized to renew this name.", status_code=403)
+
+    previous = entity.get("ExpiresAt")
+    if expires_at:
+        entity["ExpiresAt"] = expires_at
+    else:
+        entity.pop("ExpiresAt", None)
+
+    try:
+        names_table.update_entity(entity=entity, mode=UpdateMode.REPLACE, match_condition=MatchConditions.IfNotModified)
+    except ResourceModifiedError:
+        logging.warning("[renew_claim] Concurrent modification detected (ETag mismatch).")
+        return func.HttpResponse("Name was modified by another request. Please retrieve and try again.", status_code=409)
+    except Exception:
+        logging.exception("[renew_claim] Failed to update storage during renewal.")
+        return func.HttpResponse("Error renewing name.", status_code=500)
+
+    region, environment = partition_key.split("-", 1)
+    note = f"Expiry moved from {previous or 'none'} to {expires_at or 'none'}"
+    metadata = _sanitize_metadata_dict(
+        {
+            "Region": region,
+            "Environment": environment,
+            "ResourceType": entity.get("ResourceType"),
+            **run_metadata(req.headers),
+        }
+    )
+    write_audit_log(name, user_id, "renewed", note, metadata=metadata)
+
+    return json_message("Name renewed successfully.", status_code=200)
+
+
+# Lapsed claims are released by this account in the audit history.
+_LAPSE_USER = "system"
+
+
+def _release_lapsed_claims(now: datetime) -> int:
+    """Release every active claim that has lapsed at ``now``; return how many."""
+
+    names_table = get_table_client(NAMES_TABLE_NAME)
+    released = 0
+    for entity in names_table.query_entities("InUse eq true"):
+        reason = lapse_reason(entity, now)
+        if reason is None:
+            continue
+
+        entity["InUse"] = False
+        entity["ReleasedBy"] = _LAPSE_USER
+        entity["ReleasedAt"] = now.isoformat()
+        entity["ReleaseReason"] = reason
+        try:
+            names_table.update_entity(entity=entity, mode=UpdateMode.REPLACE, match_condition=MatchConditions.IfNotModified)
+        except ResourceModifiedError:
+            # Renewed or released since the query; the next run sees the change.
+            continue
+
+        region, environment = entity["PartitionKey"].split("-", 1)
+        metadata = _sanitize_metadata_dict(
+            {"Region": region, "Environment": environment, "ResourceType": entity.get("ResourceType")}
+        )
+        write_audit_log(entity["RowKey"], _LAPSE_USER, "released", reason, metadata=metadata)
+        notify(
+            "release",
+            entity["RowKey"],
+            {
+                "user": _LAPSE_USER,
+                "resourceType": entity.get("ResourceType"),
+                "region": region,
+                "environment": environment,
+                "reason": reason,
+            },
+        )
+        released += 1
+    return released
+
+
+@app.schedule(schedule="0 */15 * * * *", arg_name="mytimer", run_on_startup=False, use_monitor=True)
+def release_lapsed_claims_timer(mytimer: func.TimerRequest) -> None:  # pragma: no cover - timer integration
+    """Timer triggered release of lapsed claims."""
+
+    try:
+        released = _release_lapsed_claims(datetime.now(tz=timezone.utc))
+    except Exception:
+        logging.exception("[release_lapsed_claims] Sweep failed.")
+        return
+    logging.info("[release_lapsed_claims] Released %d lapsed claim(s).", released)
+
+
+@app.function_name(name="purge_claim")
+@app.route(route="claim/purge", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Purge the record of a released name",
+    description=(
+        "Deletes the stored record of a released name so it can be claimed fresh. "
+        "Requires the admin role and cannot be undone; the audit history is kept."
+    ),
+    tags=["Names"],
+    request_model=PurgeRequest,
+    response_model=MessageResponse,
+    operation_id="purgeClaim",
+    route="/claim/purge",
+    method="post",
+)
+def purge_claim(req: func.HttpRequest) -> func.HttpResponse:
+    """Delete the record of a released name."""
+
+    logging.info("[purge_claim] Processing purge request with RBAC.")
+
+    try:
+        user_id, _ = require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    target, error = _claim_operation_target(data)
+    if error is not None:
+        return error
+    name, partition_key = target
+
+    reason = (data.get("reason") or "").strip()
+    if not reason:
+        return func.HttpResponse("Missing required field: reason.", status_code=400)
+
+    try:
+        names_table = get_table_client(NAMES_TABLE_NAME)
+        entity = names_table.get_entity(partition_key=partition_key, row_key=name)
+    except Exception:
+        logging.exception("[purge_claim] Name not found during purge.")
+        return func.HttpResponse("Name not found.", status_code=404)
+
+    if entity.get("InUse"):
+        return func.HttpResponse("Name is still claimed; release it before purging.", status_code=409)
+
+    try:
+        names_table.delete_entity(partition_key=partition_key, row_key=name)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Name not found.", status_code=404)
+    except Exception:
+        logging.exception("[purge_claim] Failed to delete the name record.")
+        return func.HttpResponse("Error purging name.", status_code=500)
+
+    region, environment = partition_key.split("-", 1)
+    metadata = _sanitize_metadata_dict(
+        {
+            "Region": region,
+            "Environment": environment,
+            "ResourceType": entity.get("ResourceType"),
+            **run_metadata(req.headers),
+        }
+    )
+    write_audit_log(name, user_id, "purged", reason, metadata=metadata)
+
+    return json_message("Name purged successfully.", status_code=200)
@@ -0,0 +1,225 @@
+"""HTTP routes managing notification subscriptions."""
+
+from __future__ import annotations
+
+import logging
+from typing import Dict, List, Optional, Tuple
+from uuid import uuid4
+
+import azure.functions as func
+from azure.core.exceptions import ResourceNotFoundError
+from azure.data.tables import UpdateMode
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import NOTIFICATION_PARTITION_KEY, NOTIFICATIONS_TABLE_NAME
+from app.models import MessageResponse, NotificationRequest, NotificationResponse
+from app.responses import json_payload
+from app.dependencies import AuthError, get_table_client, require_role
+
+_KINDS = {"teams", "slack", "event_grid"}
+_EVENTS = ("claim", "release")
+
+
+def _notification_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    return {
+        "id": entity.get("RowKey"),
+        "name": entity.get("Name") or "",
+        "kind": entity.get("Kind") or "",
+        "url": entity.get("Url") or "",
+        "events": [event for event in str(entity.get("Events") or "").split(",") if event],
+        "enabled": bool(entity.get("Enabled", True)),
+    }
+
+
+def _parse_notification(data, subscription_id: str) -> Tuple[Optional[Dict[str, object]], Optional[func.HttpResponse]]:
+    """Return a table entity from a request body, or an error response."""
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    name = (data.get("name") or "").strip()
+    if not name:
+        return None, func.HttpResponse("Missing required field: name.", status_code=400)
+
+    kind = (data.get("kind") or "").strip().lower()
+    if kind not in _KINDS:
+        return None, func.HttpResponse("Field 'kind' must be one of teams, slack or event_grid.", status_code=400)
+
+    url = (data.get("url") or "").strip()
+    if not url.lower().startswith("https://"):
+        return None, func.HttpResponse("Field 'url' must be an https:// endpoint.", status_code=400)
+
+    events: List[str] = []
+    for event in data.get("events") or _EVENTS:
+        event = str(event).strip().lower()
+        if event not in _EVENTS:
+            return None, func.HttpResponse(f"Invalid event {event!r}: use claim or release.", status_code=400)
+        if event not in events:
+            events.append(event)
+
+    enabled = data.get("enabled", True)
+    if not isinstance(enabled, bool):
+        return None, func.HttpResponse("Field 'enabled' must be a boolean.", status_code=400)
+
+    return {
+        "PartitionKey": NOTIFICATION_PARTITION_KEY,
+        "RowKey": subscription_id,
+        "Name": name,
+        "Kind": kind,
+        "Url": url,
+        "Events": ",".join(sorted(events)),
+        "Enabled": enabled,
+    }, None
+
+
+def _route_id(req: func.HttpRequest) -> str:
+    return (req.route_params.get("id") or "").strip()
+
+
+@app.function_name(name="create_notification")
+@app.route(route="notifications", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Create a notification subscription",
+    description=(
+        "Registers a Teams, Slack or Event Grid webhook that the service calls when names are "
+        "claimed or released. Requires the admin role."
+    ),
+    tags=["Notifications"],
+    request_model=NotificationRequest,
+    response_model=NotificationResponse,
+    operation_id="createNotification",
+    route="/notifications",
+    method="post",
+)
+def create_notification(req: func.HttpRequest) -> func.HttpResponse:
+    """Register a new notification subscription."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_notification(data, str(uuid4()))
+    if error is not None:
+        return error
+
+    try:
+        get_table_client(NOTIFICATIONS_TABLE_NAME).create_entity(entity=entity)
+    except Exception:
+        logging.exception("[create_notification] Failed to store notification subscription.")
+        return func.HttpResponse("Error creating notification subscription.", status_code=500)
+
+    return json_payload(_notification_payload(entity), status_code=201)
+
+
+@app.function_name(name="get_notification")
+@app.route(route="notifications/{id}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve a notification subscription",
+    description="Returns a notification subscription, including its endpoint. Requires the admin role.",
+    tags=["Notifications"],
+    response_model=NotificationResponse,
+    operation_id="getNotification",
+    route="/notifications/{id}",
+    method="get",
+)
+def get_notification(req: func.HttpRequest) -> func.HttpResponse:
+    """Return a notification subscription."""
+
+    # Webhook URLs carry their own credentials, so reading them needs admin too.
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(NOTIFICATIONS_TABLE_NAME).get_entity(
+            partition_key=NOTIFICATION_PARTITION_KEY, row_key=_route_id(req)
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Notification subscription not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_notification] Failed to read notification subscription.")
+        return func.HttpResponse("Error reading notification subscription.", status_code=500)
+
+    return json_payload(_notification_payload(entity))
+
+
+@app.function_name(name="update_notification")
+@app.route(route="notifications/{id}", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Replace a notification subscription",
+    description="Replaces the settings of a notification subscription. Requires the admin role.",
+    tags=["Notifications"],
+    request_model=NotificationRequest,
+    response_model=NotificationResponse,
+    operation_id="updateNotification",
+    route="/notifications/{id}",
+    method="put",
+)
+def update_notification(req: func.HttpRequest) -> func.HttpResponse:
+    """Replace the settings of a notification subscription."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_notification(data, _route_id(req))
+    if error is not None:
+        return error
+
+    try:
+        table = get_table_client(NOTIFICATIONS_TABLE_NAME)
+        table.get_entity(partition_key=NOTIFICATION_PARTITION_KEY, row_key=entity["RowKey"])
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Notification subscription not found.", status_code=404)
+    except Exception:
+        logging.exception("[update_notification] Failed to update notification subscription.")
+        return func.HttpResponse("Error updating notification subscription.", status_code=500)
+
+    return json_payload(_notification_payload(entity))
+
+
+@app.function_name(name="delete_notification")
+@app.route(route="notifications/{id}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove a notification subscription",
+    description="Stops and removes a notification subscription. Requires the admin role.",
+    tags=["Notifications"],
+    response_model=MessageResponse,
+    operation_id="deleteNotification",
+    route="/notifications/{id}",
+    method="delete",
+)
+def delete_notification(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove a notification subscription."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        table = get_table_client(NOTIFICATIONS_TABLE_NAME)
+        table.get_entity(partition_key=NOTIFICATION_PARTITION_KEY, row_key=_route_id(req))
+        table.delete_entity(partition_key=NOTIFICATION_PARTITION_KEY, row_key=_route_id(req))
+    except ResourceNotFoundError:
+        return func.HttpResponse("Notification subscription not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_notification] Failed to delete notification subscription.")
+        return func.HttpResponse("Error deleting notification subscription.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
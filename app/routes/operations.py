@@ -0,0 +1,232 @@
+"""Claims accepted as long-running operations and the route reporting on them."""
+
+from __future__ import annotations
+
+import json
+import logging
+from datetime import datetime, timezone
+from typing import Dict, Mapping, Optional
+from uuid import uuid4
+
+import azure.functions as func
+from azure.core.exceptions import ResourceNotFoundError
+from azure.data.tables import UpdateMode
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import IDEMPOTENCY_TABLE_NAME, OPERATIONS_QUEUE_NAME, OPERATIONS_TABLE_NAME
+from app.errors import handle_name_generation_error
+from app.models import OperationResponse
+from app.responses import build_claim_response, json_message
+from app.dependencies import AuthError, generate_and_claim_name, get_table_client, require_role
+
+# Seconds clients are asked to wait between polls.
+_POLL_AFTER = "2"
+
+
+def prefers_async(headers: Mapping[str, str]) -> bool:
+    """Return whether the request asked to be answered with an operation."""
+
+    for header, value in headers.items():
+        if str(header).lower() == "prefer" and "respond-async" in str(value).lower():
+            return True
+    return False
+
+
+def _now() -> str:
+    return datetime.now(tz=timezone.utc).isoformat()
+
+
+def new_operation_id() -> str:
+    return str(uuid4())
+
+
+def accepted_response(operation_id: str) -> func.HttpResponse:
+    """Return the 202 pointing a caller at a running operation."""
+
+    return func.HttpResponse(
+        json.dumps({"id": operation_id, "status": "running"}),
+        mimetype="application/json",
+        status_code=202,
+        headers={"Operation-Location": f"/api/operations/{operation_id}", "Retry-After": _POLL_AFTER},
+    )
+
+
+def start_claim_operation(
+    payload,
+    user_id: str,
+    run: Dict[str, str],
+    queue: func.Out[str],
+    *,
+    operation_id: Optional[str] = None,
+    idempotency_key: Optional[str] = None,
+) -> func.HttpResponse:
+    """Store a claim as a running operation and queue it, answering 202.
+
+    With an idempotency key, the operation records the claim under that key
+    when it finishes, as a synchronous claim would.
+    """
+
+    operation_id = operation_id or new_operation_id()
+    partition_key = user_id.lower()
+    entity = {
+        "PartitionKey": partition_key,
+        "RowKey": operation_id,
+        "Status": "running",
+        "RequestedBy": user_id,
+        "Payload": json.dumps(payload),
+        "RunMetadata": json.dumps(run),
+        "CreatedAt": _now(),
+    }
+    if idempotency_key:
+        entity["IdempotencyKey"] = idempotency_key
+    try:
+        get_table_client(OPERATIONS_TABLE_NAME).create_entity(entity=entity)
+    except Exception:
+        logging.exception("[claim_name] Failed to store claim operation.")
+        return json_message("Error claiming name.", status_code=500)
+
+    queue.set(json.dumps({"user": partition_key, "id": operation_id}))
+    return accepted_response(operation_id)
+
+
+def _error_message(response: func.HttpResponse) -> str:
+    body = response.get_body().decode("utf-8")
+    try:
+        return str(json.loads(body).get("message") or body)
+    except (ValueError, AttributeError):
+        return body
+
+
+def _error_fields(response: func.HttpResponse) -> str:
+    """Return the field errors of a refused claim as stored JSON, or ""."""
+
+    try:
+        errors = json.loads(response.get_body().decode("utf-8")).get("errors")
+    except (ValueError, AttributeError):
+        return ""
+    return json.dumps(errors) if errors else ""
+
+
+def _run_claim_operation(partition_key: str, operation_id: str) -> None:
+    """Claim the name of a running operation and store the outcome."""
+
+    table = get_table_client(OPERATIONS_TABLE_NAME)
+    try:
+        entity = table.get_entity(partition_key=partition_key, row_key=operation_id)
+    except ResourceNotFoundError:
+        logging.warning("[run_claim_operation] Operation %s no longer exists.", operation_id)
+        return
+    if entity.get("Status") != "running":
+        # A redelivered message for an operation that already finished.
+        return
+
+    requested_by = entity["RequestedBy"]
+    try:
+        result = generate_and_claim_name(
+            json.loads(entity["Payload"]),
+            requested_by=requested_by,
+            run_metadata=json.loads(entity.get("RunMetadata") or "{}"),
+        )
+        response = build_claim_response(result, requested_by)
+        outcome = {"Status": "succeeded", "Result": response.get_body().decode("utf-8")}
+    except Exception as exc:
+        response = handle_name_generation_error(exc, log_prefix="run_claim_operation")
+        outcome = {
+            "Status": "failed",
+            "ErrorStatus": response.status_code,
+            "ErrorMessage": _error_message(response),
+            "ErrorFields": _error_fields(response),
+        }
+
+    outcome.update({"PartitionKey": partition_key, "RowKey": operation_id, "CompletedAt": _now()})
+    table.update_entity(entity=outcome, mode=UpdateMode.MERGE)
+    if entity.get("IdempotencyKey"):
+        _settle_idempotency_key(partition_key, entity["IdempotencyKey"], outcome)
+
+
+def _settle_idempotency_key(partition_key: str, key: str, outcome: Dict[str, object]) -> None:
+    """Record a finished operation under its idempotency key, or forget a refused one."""
+
+    table = get_table_client(IDEMPOTENCY_TABLE_NAME)
+    if outcome["Status"] == "succeeded":
+        response = str(outcome["Result"])
+        table.update_entity(
+            entity={
+                "PartitionKey": partition_key,
+                "RowKey": key,
+                "Name": json.loads(response).get("name"),
+                "Response": response,
+            },
+            mode=UpdateMode.MERGE,
+        )
+    else:
+        table.delete_entity(partition_key=partition_key, row_key=key)
+
+
+@app.function_name(name="run_claim_operation")
+@app.queue_trigger(arg_name="msg", queue_name=OPERATIONS_QUEUE_NAME, connection="AzureWebJobsStorage")
+def run_claim_operation(msg: func.QueueMessage) -> None:  # pragma: no cover - queue integration
+    """Queue triggered completion of claims accepted with 202."""
+
+    message = msg.get_json()
+    _run_claim_operation(message["user"], message["id"])
+
+
+def _operation_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    payload: Dict[str, object] = {
+        "id": entity.get("RowKey"),
+        "status": entity.get("Status"),
+        "created_at": entity.get("CreatedAt"),
+    }
+    if entity.get("CompletedAt"):
+        payload["completed_at"] = entity["CompletedAt"]
+    if entity.get("Status") == "succeeded":
+        payload["result"] = json.loads(str(entity.get("Result") or "null"))
+    elif entity.get("Status") == "failed":
+        payload["error"] = {"status": entity.get("ErrorStatus"), "message": entity.get("ErrorMessage")}
+        if entity.get("ErrorFields"):
+            payload["error"]["errors"] = json.loads(str(entity["ErrorFields"]))
+    return payload
+
+
+@app.function_name(name="get_operation")
+@app.route(route="operations/{operation_id}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Poll a claim operation",
+    description=(
+        "Returns the state of a claim accepted with 202. Once it succeeded, result holds the claim "
+        "the synchronous request would have returned; once it failed, error holds its status and message. "
+        "Callers can only read their own operations."
+    ),
+    tags=["Names"],
+    response_model=OperationResponse,
+    operation_id="getOperation",
+    route="/operations/{operation_id}",
+    method="get",
+)
+def get_operation(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the state of one of the caller's claim operations."""
+
+    try:
+        user_id, _ = require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    operation_id = (req.route_params.get("operation_id") or "").strip()
+    try:
+        entity = get_table_client(OPERATIONS_TABLE_NAME).get_entity(
+            partition_key=user_id.lower(), row_key=operation_id
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Operation not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_operation] Failed to read operation.")
+        return func.HttpResponse("Error reading operation.", status_code=500)
+
+    return func.HttpResponse(
+        json.dumps(_operation_payload(entity)),
+        mimetype="application/json",
+        status_code=200,
+        headers={"Retry-After": _POLL_AFTER} if entity.get("Status") == "running" else None,
+    )
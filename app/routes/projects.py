@@ -0,0 +1,250 @@
+"""HTTP routes managing the project registry."""
+
+from __future__ import annotations
+
+import logging
+import re
+from typing import Dict, Optional, Tuple
+
+import azure.functions as func
+from azure.core.exceptions import ResourceExistsError, ResourceNotFoundError
+from azure.data.tables import UpdateMode
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import PROJECT_PARTITION_KEY, PROJECTS_TABLE_NAME
+from app.models import MessageResponse, ProjectListResponse, ProjectRequest
+from app.responses import json_payload
+from app.dependencies import AuthError, get_table_client, require_role
+
+_CODE_PATTERN = re.compile(r"^[a-z0-9-]+$")
+_INDEX_POLICIES = ("auto", "manual")
+
+
+def _project_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    payload: Dict[str, object] = {
+        "code": entity.get("RowKey"),
+        "owner": entity.get("Owner") or "",
+        "index_policy": entity.get("IndexPolicy") or "auto",
+    }
+    if entity.get("Description"):
+        payload["description"] = entity["Description"]
+    if entity.get("CostCenter"):
+        payload["cost_center"] = entity["CostCenter"]
+    return payload
+
+
+def _parse_project(data, code: Optional[str] = None) -> Tuple[Optional[Dict[str, object]], Optional[func.HttpResponse]]:
+    """Return a table entity from a request body, or an error response."""
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    code = (code or data.get("code") or "").strip().lower()
+    if not _CODE_PATTERN.match(code):
+        return None, func.HttpResponse("Field 'code' must contain only letters, numbers, and hyphens.", status_code=400)
+
+    owner = (data.get("owner") or "").strip()
+    if not owner:
+        return None, func.HttpResponse("Missing required field: owner.", status_code=400)
+
+    policy = (data.get("index_policy") or "auto").strip().lower()
+    if policy not in _INDEX_POLICIES:
+        return None, func.HttpResponse("Field 'index_policy' must be 'auto' or 'manual'.", status_code=400)
+
+    entity: Dict[str, object] = {
+        "PartitionKey": PROJECT_PARTITION_KEY,
+        "RowKey": code,
+        "Owner": owner,
+        "IndexPolicy": policy,
+    }
+    description = (data.get("description") or "").strip()
+    if description:
+        entity["Description"] = description
+    cost_center = (data.get("cost_center") or "").strip()
+    if cost_center:
+        entity["CostCenter"] = cost_center
+    return entity, None
+
+
+def _route_code(req: func.HttpRequest) -> str:
+    return (req.route_params.get("code") or "").strip().lower()
+
+
+@app.function_name(name="create_project")
+@app.route(route="projects", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Register a project",
+    description="Adds a project code to the registry. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=ProjectRequest,
+    response_model=ProjectRequest,
+    operation_id="createProject",
+    route="/projects",
+    method="post",
+)
+def create_project(req: func.HttpRequest) -> func.HttpResponse:
+    """Register a new project code."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_project(data)
+    if error is not None:
+        return error
+
+    try:
+        get_table_client(PROJECTS_TABLE_NAME).create_entity(entity=entity)
+    except ResourceExistsError:
+        return func.HttpResponse(f"Project '{entity['RowKey']}' already exists.", status_code=409)
+    except Exception:
+        logging.exception("[create_project] Failed to store project.")
+        return func.HttpResponse("Error registering project.", status_code=500)
+
+    return json_payload(_project_payload(entity), status_code=201)
+
+
+@app.function_name(name="list_projects")
+@app.route(route="projects", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="List projects",
+    description="Returns every registered project, ordered by code.",
+    tags=["Catalog"],
+    response_model=ProjectListResponse,
+    operation_id="listProjects",
+    route="/projects",
+    method="get",
+)
+def list_projects(req: func.HttpRequest) -> func.HttpResponse:
+    """Return every registered project."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entities = list(
+            get_table_client(PROJECTS_TABLE_NAME).query_entities(
+                query_filter="PartitionKey eq @pk", parameters={"pk": PROJECT_PARTITION_KEY}
+            )
+        )
+    except Exception:
+        logging.exception("[list_projects] Failed to list projects.")
+        return func.HttpResponse("Error listing projects.", status_code=500)
+
+    projects = sorted((_project_payload(entity) for entity in entities), key=lambda project: str(project["code"]))
+    return json_payload({"projects": projects})
+
+
+@app.function_name(name="get_project")
+@app.route(route="projects/{code}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve a project",
+    description="Returns the registry entry for a project code.",
+    tags=["Catalog"],
+    response_model=ProjectRequest,
+    operation_id="getProject",
+    route="/projects/{code}",
+    method="get",
+)
+def get_project(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the registry entry for a project code."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(PROJECTS_TABLE_NAME).get_entity(
+            partition_key=PROJECT_PARTITION_KEY, row_key=_route_code(req)
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Project not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_project] Failed to read project.")
+        return func.HttpResponse("Error reading project.", status_code=500)
+
+    return json_payload(_project_payload(entity))
+
+
+@app.function_name(name="update_project")
+@app.route(route="projects/{code}", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Replace a project",
+    description="Replaces the settings of a registered project. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=ProjectRequest,
+    response_model=ProjectRequest,
+    operation_id="updateProject",
+    route="/projects/{code}",
+    method="put",
+)
+def update_project(req: func.HttpRequest) -> func.HttpResponse:
+    """Replace the settings of a registered project."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_project(data, code=_route_code(req))
+    if error is not None:
+        return error
+
+    try:
+        table = get_table_client(PROJECTS_TABLE_NAME)
+        table.get_entity(partition_key=PROJECT_PARTITION_KEY, row_key=entity["RowKey"])
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Project not found.", status_code=404)
+    except Exception:
+        logging.exception("[update_project] Failed to update project.")
+        return func.HttpResponse("Error updating project.", status_code=500)
+
+    return json_payload(_project_payload(entity))
+
+
+@app.function_name(name="delete_project")
+@app.route(route="projects/{code}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove a project",
+    description="Removes a project code from the registry. Existing claims are kept. Requires the admin role.",
+    tags=["Catalog"],
+    response_model=MessageResponse,
+    operation_id="deleteProject",
+    route="/projects/{code}",
+    method="delete",
+)
+def delete_project(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove a project code from the registry."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        table = get_table_client(PROJECTS_TABLE_NAME)
+        table.get_entity(partition_key=PROJECT_PARTITION_KEY, row_key=_route_code(req))
+        table.delete_entity(partition_key=PROJECT_PARTITION_KEY, row_key=_route_code(req))
+    except ResourceNotFoundError:
+        return func.HttpResponse("Project not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_project] Failed to delete project.")
+        return func.HttpResponse("Error deleting project.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
@@ -0,0 +1,208 @@
+"""HTTP routes managing the region catalog."""
+
+from __future__ import annotations
+
+import logging
+import re
+from typing import Dict, Optional, Tuple
+
+import azure.functions as func
+from azure.core.exceptions import ResourceExistsError, ResourceNotFoundError
+from azure.data.tables import UpdateMode
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import REGION_PARTITION_KEY, REGIONS_TABLE_NAME
+from app.models import MessageResponse, RegionRequest
+from app.responses import json_payload
+from app.dependencies import AuthError, get_table_client, require_role
+
+_CODE_PATTERN = re.compile(r"^[a-z0-9-]+$")
+_LOCATION_PATTERN = re.compile(r"^[a-z0-9]+$")
+
+
+def _region_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    payload: Dict[str, object] = {
+        "code": entity.get("RowKey"),
+        "location": entity.get("Location") or "",
+    }
+    if entity.get("DisplayName"):
+        payload["display_name"] = entity["DisplayName"]
+    return payload
+
+
+def _parse_region(data, code: Optional[str] = None) -> Tuple[Optional[Dict[str, object]], Optional[func.HttpResponse]]:
+    """Return a table entity from a request body, or an error response."""
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    code = (code or data.get("code") or "").strip().lower()
+    if not _CODE_PATTERN.match(code):
+        return None, func.HttpResponse("Field 'code' must contain only letters, numbers, and hyphens.", status_code=400)
+
+    location = (data.get("location") or "").strip().lower()
+    if not _LOCATION_PATTERN.match(location):
+        return None, func.HttpResponse(
+            "Field 'location' must be an Azure location name such as westus2.", status_code=400
+        )
+
+    entity: Dict[str, object] = {
+        "PartitionKey": REGION_PARTITION_KEY,
+        "RowKey": code,
+        "Location": location,
+    }
+    display_name = (data.get("display_name") or "").strip()
+    if display_name:
+        entity["DisplayName"] = display_name
+    return entity, None
+
+
+def _route_code(req: func.HttpRequest) -> str:
+    return (req.route_params.get("code") or "").strip().lower()
+
+
+@app.function_name(name="create_region")
+@app.route(route="regions", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Register a region",
+    description="Adds a region short code to the catalog. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=RegionRequest,
+    response_model=RegionRequest,
+    operation_id="createRegion",
+    route="/regions",
+    method="post",
+)
+def create_region(req: func.HttpRequest) -> func.HttpResponse:
+    """Register a new region short code."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_region(data)
+    if error is not None:
+        return error
+
+    try:
+        get_table_client(REGIONS_TABLE_NAME).create_entity(entity=entity)
+    except ResourceExistsError:
+        return func.HttpResponse(f"Region '{entity['RowKey']}' already exists.", status_code=409)
+    except Exception:
+        logging.exception("[create_region] Failed to store region.")
+        return func.HttpResponse("Error registering region.", status_code=500)
+
+    return json_payload(_region_payload(entity), status_code=201)
+
+
+@app.function_name(name="get_region")
+@app.route(route="regions/{code}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve a region",
+    description="Returns the catalog entry for a region short code.",
+    tags=["Catalog"],
+    response_model=RegionRequest,
+    operation_id="getRegion",
+    route="/regions/{code}",
+    method="get",
+)
+def get_region(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the catalog entry for a region short code."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(REGIONS_TABLE_NAME).get_entity(
+            partition_key=REGION_PARTITION_KEY, row_key=_route_code(req)
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Region not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_region] Failed to read region.")
+        return func.HttpResponse("Error reading region.", status_code=500)
+
+    return json_payload(_region_payload(entity))
+
+
+@app.function_name(name="update_region")
+@app.route(route="regions/{code}", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Replace a region",
+    description="Remaps a region short code. Existing claims are not renamed. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=RegionRequest,
+    response_model=RegionRequest,
+    operation_id="updateRegion",
+    route="/regions/{code}",
+    method="put",
+)
+def update_region(req: func.HttpRequest) -> func.HttpResponse:
+    """Replace the catalog entry for a region short code."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_region(data, code=_route_code(req))
+    if error is not None:
+        return error
+
+    try:
+        table = get_table_client(REGIONS_TABLE_NAME)
+        table.get_entity(partition_key=REGION_PARTITION_KEY, row_key=entity["RowKey"])
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Region not found.", status_code=404)
+    except Exception:
+        logging.exception("[update_region] Failed to update region.")
+        return func.HttpResponse("Error updating region.", status_code=500)
+
+    return json_payload(_region_payload(entity))
+
+
+@app.function_name(name="delete_region")
+@app.route(route="regions/{code}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove a region",
+    description="Removes a region short code from the catalog. Existing claims are kept. Requires the admin role.",
+    tags=["Catalog"],
+    response_model=MessageResponse,
+    operation_id="deleteRegion",
+    route="/regions/{code}",
+    method="delete",
+)
+def delete_region(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove a region short code from the catalog."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        table = get_table_client(REGIONS_TABLE_NAME)
+        table.get_entity(partition_key=REGION_PARTITION_KEY, row_key=_route_code(req))
+        table.delete_entity(partition_key=REGION_PARTITION_KEY, row_key=_route_code(req))
+    except ResourceNotFoundError:
+        return func.HttpResponse("Region not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_region] Failed to delete region.")
+        return func.HttpResponse("Error deleting region.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
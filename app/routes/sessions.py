@@ -0,0 +1,117 @@
+"""HTTP routes exposing the segment defaults stored for a session."""
+
+from __future__ import annotations
+
+import logging
+import re
+
+import azure.functions as func
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.models import SessionDefaultsRequest, SessionDefaultsResponse
+from app.responses import json_payload
+from app.dependencies import AuthError, require_role, settings_service
+
+# Session IDs are used as table row keys, so only allow characters that are valid there.
+_SESSION_ID_PATTERN = re.compile(r"^[A-Za-z0-9._:-]{1,128}$")
+_DEFAULT_FIELDS = {"resource_type", "region", "environment", "project", "purpose", "system", "subsystem", "index"}
+
+
+def _session_payload(session_id: str, defaults, last_seen) -> dict:
+    return {"sessionId": session_id, "defaults": defaults, "lastSeen": last_seen.isoformat()}
+
+
+@app.function_name(name="get_session")
+@app.route(route="session", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Show the defaults applied for a session",
+    description=(
+        "Returns the segment defaults the service applies to the caller's claims that carry "
+        "session_id: the caller's permanent defaults overlaid with the session's own. "
+        "Reading a session does not extend it. Unknown and expired sessions return 404."
+    ),
+    tags=["Sessions"],
+    response_model=SessionDefaultsResponse,
+    operation_id="getSession",
+    route="/session",
+    method="get",
+)
+def get_session(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the defaults applied for a session."""
+
+    try:
+        user_id, _ = require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    session_id = (req.params.get("session_id") or "").strip()
+    if not _SESSION_ID_PATTERN.match(session_id):
+        return func.HttpResponse("Query parameter 'session_id' is required.", status_code=400)
+
+    try:
+        session = settings_service.describe_session(user_id, session_id)
+    except Exception:
+        logging.exception("[get_session] Failed to read session defaults.")
+        return func.HttpResponse("Error reading session defaults.", status_code=500)
+
+    if session is None:
+        return func.HttpResponse("Session not found or expired.", status_code=404)
+
+    defaults, last_seen = session
+    return json_payload(_session_payload(session_id, defaults, last_seen))
+
+
+@app.function_name(name="set_session")
+@app.route(route="session", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Store the defaults for a session",
+    description=(
+        "Replaces the segment defaults applied to the caller's claims that carry session_id. "
+        "Sessions expire an hour after they were last used."
+    ),
+    tags=["Sessions"],
+    request_model=SessionDefaultsRequest,
+    response_model=SessionDefaultsResponse,
+    operation_id="setSession",
+    route="/session",
+    method="put",
+)
+def set_session(req: func.HttpRequest) -> func.HttpResponse:
+    """Store the defaults for a session."""
+
+    try:
+        user_id, _ = require_role(req.headers, min_role="contributor")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    if not isinstance(data, dict):
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    session_id = str(data.get("session_id") or "").strip()
+    if not _SESSION_ID_PATTERN.match(session_id):
+        return func.HttpResponse("Field 'session_id' must be 1-128 letters, digits, '.', '_', ':' or '-'.", status_code=400)
+
+    defaults = data.get("defaults")
+    if not isinstance(defaults, dict):
+        return func.HttpResponse("Field 'defaults' must be an object.", status_code=400)
+    unknown = sorted(set(defaults) - _DEFAULT_FIELDS)
+    if unknown:
+        return func.HttpResponse(f"Unsupported default field(s): {', '.join(unknown)}.", status_code=400)
+
+    values = {key: str(value).lower() for key, value in defaults.items() if value not in (None, "")}
+    try:
+        # Table storage merges on write, so clear first to replace the defaults.
+        settings_service.clear_session(user_id, session_id)
+        settings_service.set_session_defaults(user_id, session_id, values)
+        defaults, last_seen = settings_service.describe_session(user_id, session_id)
+    except Exception:
+        logging.exception("[set_session] Failed to store session defaults.")
+        return func.HttpResponse("Error storing session defaults.", status_code=500)
+
+    return json_payload(_session_payload(session_id, defaults, last_seen))
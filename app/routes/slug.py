@@ -48,45 +48,83 @@ def _resolve_slug_payload(resource_type: str) -> Dict[str, str]:
 
         if entity:
             resolved_resource_type = str(entity.get("ResourceType") or resolved_resource_type)
-            # Include all non-personal metadata fields present on the entity.
-            for key, val in entity.items():
-                if key in {"PartitionKey", "RowKey"}:
-                    continue
-                # Avoid returning anything that looks like a person identifier
-                if isinstance(key, str) and key.lower() in {"claimedby", "releasedby", "user", "email", "upn"}:
-                    continue
-                # Normalize keys to camelCase in the response
-                normalized_key = key[0].lower() + key[1:] if key else key
-                resource_metadata[normalized_key] = val
+            resource_metadata = _entity_metadata(entity)
     except Exception:
         logging.exception("[slug_lookup] Failed to hydrate slug metadata.")
 
+    return _slug_payload(resolved_resource_type, slug_value, resource_metadata)
+
+
+def _entity_metadata(entity: Dict[str, object]) -> Dict[str, Optional[str]]:
+    """Return the non-personal fields of a slug table entity, keyed in camelCase."""
+
+    metadata: Dict[str, Optional[str]] = {}
+    for key, val in entity.items():
+        if key in {"PartitionKey", "RowKey"}:
+            continue
+        # Avoid returning anything that looks like a person identifier
+        if isinstance(key, str) and key.lower() in {"claimedby", "releasedby", "user", "email", "upn"}:
+            continue
+        # Normalize keys to camelCase in the response
+        normalized_key = key[0].lower() + key[1:] if key else key
+        metadata[normalized_key] = val
+    return metadata
+
+
+def _slug_payload(resource_type: str, slug_value: str, metadata: Dict[str, Optional[str]]) -> Dict[str, str]:
     payload: Dict[str, str] = {
-        "resourceType": resolved_resource_type.strip().lower(),
+        "resourceType": resource_type.strip().lower(),
         "slug": slug_value,
     }
 
-    for key, value in resource_metadata.items():
+    for key, value in metadata.items():
         if value:
             payload[key] = str(value)
 
     return payload
 
 
+def _resolve_resource_type_payload(slug: str) -> Dict[str, str]:
+    """Return the resource type that uses ``slug`` and its slug table metadata.
+
+    Raises ValueError when no slug table entry has that slug or the entry does
+    not name its resource type.
+    """
+
+    cleaned = slug.strip().lower()
+    if not cleaned:
+        raise ValueError("slug cannot be empty")
+    try:
+        entity = get_table_client(SLUG_TABLE_NAME).get_entity(partition_key=SLUG_PARTITION_KEY, row_key=cleaned)
+    except ResourceNotFoundError:
+        raise ValueError(f"No resource type uses slug '{cleaned}'.") from None
+    resource_type = str(entity.get("ResourceType") or "").strip()
+    if not resource_type:
+        raise ValueError(f"Slug '{cleaned}' does not name a resource type.")
+    return _slug_payload(resource_type, cleaned, _entity_metadata(entity))
+
+
 @app.function_name(name="get_slug_mapping")
 @app.route(route="slug", methods=[func.HttpMethod.GET])
 @openapi_doc(
-    summary="Resolve a slug for a resource type",
-    description="Returns the short slug used when generating names for the requested resource type.",
+    summary="Resolve a slug for a resource type, or a resource type for a slug",
+    description="Returns the short slug used when generating names for the requested resource type. With ?slug= instead, returns the resource type that uses the slug.",
     tags=["Slugs"],
     parameters=[
         {
             "name": "resource_type",
             "in": "query",
-            "required": True,
+            "required": False,
             "schema": {"type": "string"},
             "description": "Canonical resource type identifier (for example, storage_account).",
-        }
+        },
+        {
+            "name": "slug",
+            "in": "query",
+            "required": False,
+            "schema": {"type": "string"},
+            "description": "Slug to map back to its resource type (for example, st). Used when resource_type is not set.",
+        },
     ],
     response_model=SlugLookupResponse,
     operation_id="getSlug",
@@ -110,8 +148,11 @@ def _handle_slug_lookup(req: func.HttpRequest) -> func.HttpResponse:
         return func.HttpResponse(str(exc), status_code=exc.status)
 
     resource_type = (req.params.get("resource_type") or req.params.get("resourceType") or "").strip()
+    slug = (req.params.get("slug") or "").strip()
+    if not resource_type and slug:
+        return _handle_reverse_slug_lookup(slug)
     if not resource_type:
-        return func.HttpResponse("Query parameter 'resource_type' is required.", status_code=400)
+        return func.HttpResponse("Query parameter 'resource_type' or 'slug' is required.", status_code=400)
 
     normalised = resource_type.lower()
 
@@ -126,6 +167,19 @@ def _handle_slug_lookup(req: func.HttpRequest) -> func.HttpResponse:
         return json_message("Slug lookup failed.", status_code=500)
 
 
+def _handle_reverse_slug_lookup(slug: str) -> func.HttpResponse:
+    """Map a slug back to the resource type that uses it."""
+
+    try:
+        return json_payload(_resolve_resource_type_payload(slug))
+    except ValueError:
+        logging.info("[slug_lookup] No resource type uses slug '%s'.", slug.lower())
+        return json_message(f"No resource type uses slug '{slug.lower()}'.", status_code=404)
+    except Exception:
+        logging.exception("[slug_lookup] Unexpected error while resolving resource type.")
+        return json_message("Slug lookup failed.", status_code=500)
+
+
 def _perform_slug_sync() -> Tuple[int, str]:
     remote_slugs = get_all_remote_slugs()
     if not remote_slugs:
@@ -0,0 +1,457 @@
+"""HTTP routes managing the system and subsystem catalog."""
+
+from __future__ import annotations
+
+import logging
+import re
+from typing import Dict, Optional, Tuple
+
+import azure.functions as func
+from azure.core.exceptions import ResourceExistsError, ResourceNotFoundError
+from azure.data.tables import UpdateMode
+from azure_functions_openapi.decorator import openapi as openapi_doc
+
+from app import app
+from app.constants import SUBSYSTEMS_TABLE_NAME, SYSTEM_PARTITION_KEY, SYSTEMS_TABLE_NAME
+from app.models import (
+    MessageResponse,
+    SubsystemListResponse,
+    SubsystemRequest,
+    SystemListResponse,
+    SystemRequest,
+)
+from app.responses import json_payload
+from app.dependencies import AuthError, get_table_client, require_role
+
+_CODE_PATTERN = re.compile(r"^[a-z0-9-]+$")
+
+
+def _entry_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    payload: Dict[str, object] = {"code": entity.get("RowKey")}
+    if entity.get("Description"):
+        payload["description"] = entity["Description"]
+    if entity.get("Owner"):
+        payload["owner"] = entity["Owner"]
+    return payload
+
+
+def _subsystem_payload(entity: Dict[str, object]) -> Dict[str, object]:
+    payload = _entry_payload(entity)
+    payload["system"] = entity.get("PartitionKey")
+    return payload
+
+
+def _parse_code(value, field: str) -> Tuple[str, Optional[func.HttpResponse]]:
+    code = str(value or "").strip().lower()
+    if not _CODE_PATTERN.match(code):
+        return code, func.HttpResponse(f"Field '{field}' must contain only letters, numbers, and hyphens.", status_code=400)
+    return code, None
+
+
+def _parse_entry(data, partition_key: Optional[str], code: Optional[str] = None) -> Tuple[Optional[Dict[str, object]], Optional[func.HttpResponse]]:
+    """Return a table entity from a request body, or an error response.
+
+    Systems pass their partition key; subsystems pass None and take it from
+    the body's system field.
+    """
+
+    if not isinstance(data, dict):
+        return None, func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    code, error = _parse_code(code or data.get("code"), "code")
+    if error is not None:
+        return None, error
+    if partition_key is None:
+        partition_key, error = _parse_code(data.get("system"), "system")
+        if error is not None:
+            return None, error
+
+    entity: Dict[str, object] = {"PartitionKey": partition_key, "RowKey": code}
+    description = str(data.get("description") or "").strip()
+    if description:
+        entity["Description"] = description
+    owner = str(data.get("owner") or "").strip()
+    if owner:
+        entity["Owner"] = owner
+    return entity, None
+
+
+def _route_value(req: func.HttpRequest, name: str) -> str:
+    return (req.route_params.get(name) or "").strip().lower()
+
+
+def _has_subsystems(system: str) -> bool:
+    entities = get_table_client(SUBSYSTEMS_TABLE_NAME).query_entities(
+        query_filter="PartitionKey eq @pk", parameters={"pk": system}
+    )
+    return next(iter(entities), None) is not None
+
+
+@app.function_name(name="create_system")
+@app.route(route="systems", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Register a system",
+    description="Adds a system code to the catalog. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=SystemRequest,
+    response_model=SystemRequest,
+    operation_id="createSystem",
+    route="/systems",
+    method="post",
+)
+def create_system(req: func.HttpRequest) -> func.HttpResponse:
+    """Register a new system code."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_entry(data, SYSTEM_PARTITION_KEY)
+    if error is not None:
+        return error
+
+    try:
+        get_table_client(SYSTEMS_TABLE_NAME).create_entity(entity=entity)
+    except ResourceExistsError:
+        return func.HttpResponse(f"System '{entity['RowKey']}' already exists.", status_code=409)
+    except Exception:
+        logging.exception("[create_system] Failed to store system.")
+        return func.HttpResponse("Error registering system.", status_code=500)
+
+    return json_payload(_entry_payload(entity), status_code=201)
+
+
+@app.function_name(name="list_systems")
+@app.route(route="systems", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="List systems",
+    description="Returns every registered system, ordered by code.",
+    tags=["Catalog"],
+    response_model=SystemListResponse,
+    operation_id="listSystems",
+    route="/systems",
+    method="get",
+)
+def list_systems(req: func.HttpRequest) -> func.HttpResponse:
+    """Return every registered system."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entities = list(
+            get_table_client(SYSTEMS_TABLE_NAME).query_entities(
+                query_filter="PartitionKey eq @pk", parameters={"pk": SYSTEM_PARTITION_KEY}
+            )
+        )
+    except Exception:
+        logging.exception("[list_systems] Failed to list systems.")
+        return func.HttpResponse("Error listing systems.", status_code=500)
+
+    systems = sorted((_entry_payload(entity) for entity in entities), key=lambda system: str(system["code"]))
+    return json_payload({"systems": systems})
+
+
+@app.function_name(name="get_system")
+@app.route(route="systems/{code}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve a system",
+    description="Returns the catalog entry for a system code.",
+    tags=["Catalog"],
+    response_model=SystemRequest,
+    operation_id="getSystem",
+    route="/systems/{code}",
+    method="get",
+)
+def get_system(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the catalog entry for a system code."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(SYSTEMS_TABLE_NAME).get_entity(
+            partition_key=SYSTEM_PARTITION_KEY, row_key=_route_value(req, "code")
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("System not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_system] Failed to read system.")
+        return func.HttpResponse("Error reading system.", status_code=500)
+
+    return json_payload(_entry_payload(entity))
+
+
+@app.function_name(name="update_system")
+@app.route(route="systems/{code}", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Replace a system",
+    description="Replaces the description and owner of a registered system. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=SystemRequest,
+    response_model=SystemRequest,
+    operation_id="updateSystem",
+    route="/systems/{code}",
+    method="put",
+)
+def update_system(req: func.HttpRequest) -> func.HttpResponse:
+    """Replace the description and owner of a registered system."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_entry(data, SYSTEM_PARTITION_KEY, code=_route_value(req, "code"))
+    if error is not None:
+        return error
+
+    try:
+        table = get_table_client(SYSTEMS_TABLE_NAME)
+        table.get_entity(partition_key=SYSTEM_PARTITION_KEY, row_key=entity["RowKey"])
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE)
+    except ResourceNotFoundError:
+        return func.HttpResponse("System not found.", status_code=404)
+    except Exception:
+        logging.exception("[update_system] Failed to update system.")
+        return func.HttpResponse("Error updating system.", status_code=500)
+
+    return json_payload(_entry_payload(entity))
+
+
+@app.function_name(name="delete_system")
+@app.route(route="systems/{code}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove a system",
+    description="Removes a system code that has no subsystems from the catalog. Existing claims are kept. Requires the admin role.",
+    tags=["Catalog"],
+    response_model=MessageResponse,
+    operation_id="deleteSystem",
+    route="/systems/{code}",
+    method="delete",
+)
+def delete_system(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove a system code from the catalog."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    code = _route_value(req, "code")
+    try:
+        table = get_table_client(SYSTEMS_TABLE_NAME)
+        table.get_entity(partition_key=SYSTEM_PARTITION_KEY, row_key=code)
+        if _has_subsystems(code):
+            return func.HttpResponse(f"System '{code}' still has subsystems; remove them first.", status_code=409)
+        table.delete_entity(partition_key=SYSTEM_PARTITION_KEY, row_key=code)
+    except ResourceNotFoundError:
+        return func.HttpResponse("System not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_system] Failed to delete system.")
+        return func.HttpResponse("Error deleting system.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
+
+
+@app.function_name(name="create_subsystem")
+@app.route(route="subsystems", methods=[func.HttpMethod.POST])
+@openapi_doc(
+    summary="Register a subsystem",
+    description="Adds a subsystem code under a registered system. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=SubsystemRequest,
+    response_model=SubsystemRequest,
+    operation_id="createSubsystem",
+    route="/subsystems",
+    method="post",
+)
+def create_subsystem(req: func.HttpRequest) -> func.HttpResponse:
+    """Register a new subsystem code under a system."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_entry(data, None)
+    if error is not None:
+        return error
+
+    system = entity["PartitionKey"]
+    try:
+        get_table_client(SYSTEMS_TABLE_NAME).get_entity(partition_key=SYSTEM_PARTITION_KEY, row_key=system)
+        get_table_client(SUBSYSTEMS_TABLE_NAME).create_entity(entity=entity)
+    except ResourceNotFoundError:
+        return func.HttpResponse(f"System '{system}' is not registered.", status_code=400)
+    except ResourceExistsError:
+        return func.HttpResponse(f"Subsystem '{system}/{entity['RowKey']}' already exists.", status_code=409)
+    except Exception:
+        logging.exception("[create_subsystem] Failed to store subsystem.")
+        return func.HttpResponse("Error registering subsystem.", status_code=500)
+
+    return json_payload(_subsystem_payload(entity), status_code=201)
+
+
+@app.function_name(name="list_subsystems")
+@app.route(route="subsystems", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="List subsystems",
+    description="Returns the registered subsystems ordered by system and code, limited to one system with ?system=.",
+    tags=["Catalog"],
+    response_model=SubsystemListResponse,
+    operation_id="listSubsystems",
+    route="/subsystems",
+    method="get",
+)
+def list_subsystems(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the registered subsystems, optionally of one system."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    system = (req.params.get("system") or "").strip().lower()
+    try:
+        table = get_table_client(SUBSYSTEMS_TABLE_NAME)
+        if system:
+            entities = list(table.query_entities(query_filter="PartitionKey eq @pk", parameters={"pk": system}))
+        else:
+            entities = list(table.list_entities())
+    except Exception:
+        logging.exception("[list_subsystems] Failed to list subsystems.")
+        return func.HttpResponse("Error listing subsystems.", status_code=500)
+
+    subsystems = sorted(
+        (_subsystem_payload(entity) for entity in entities),
+        key=lambda subsystem: (str(subsystem["system"]), str(subsystem["code"])),
+    )
+    return json_payload({"subsystems": subsystems})
+
+
+@app.function_name(name="get_subsystem")
+@app.route(route="subsystems/{system}/{code}", methods=[func.HttpMethod.GET])
+@openapi_doc(
+    summary="Retrieve a subsystem",
+    description="Returns the catalog entry for a subsystem of a system.",
+    tags=["Catalog"],
+    response_model=SubsystemRequest,
+    operation_id="getSubsystem",
+    route="/subsystems/{system}/{code}",
+    method="get",
+)
+def get_subsystem(req: func.HttpRequest) -> func.HttpResponse:
+    """Return the catalog entry for a subsystem."""
+
+    try:
+        require_role(req.headers, min_role="reader")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        entity = get_table_client(SUBSYSTEMS_TABLE_NAME).get_entity(
+            partition_key=_route_value(req, "system"), row_key=_route_value(req, "code")
+        )
+    except ResourceNotFoundError:
+        return func.HttpResponse("Subsystem not found.", status_code=404)
+    except Exception:
+        logging.exception("[get_subsystem] Failed to read subsystem.")
+        return func.HttpResponse("Error reading subsystem.", status_code=500)
+
+    return json_payload(_subsystem_payload(entity))
+
+
+@app.function_name(name="update_subsystem")
+@app.route(route="subsystems/{system}/{code}", methods=[func.HttpMethod.PUT])
+@openapi_doc(
+    summary="Replace a subsystem",
+    description="Replaces the description and owner of a registered subsystem. Requires the admin role.",
+    tags=["Catalog"],
+    request_model=SubsystemRequest,
+    response_model=SubsystemRequest,
+    operation_id="updateSubsystem",
+    route="/subsystems/{system}/{code}",
+    method="put",
+)
+def update_subsystem(req: func.HttpRequest) -> func.HttpResponse:
+    """Replace the description and owner of a registered subsystem."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    try:
+        data = req.get_json()
+    except ValueError:
+        return func.HttpResponse("Invalid JSON payload.", status_code=400)
+
+    entity, error = _parse_entry(data, _route_value(req, "system"), code=_route_value(req, "code"))
+    if error is not None:
+        return error
+
+    try:
+        table = get_table_client(SUBSYSTEMS_TABLE_NAME)
+        table.get_entity(partition_key=entity["PartitionKey"], row_key=entity["RowKey"])
+        table.update_entity(entity=entity, mode=UpdateMode.REPLACE)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Subsystem not found.", status_code=404)
+    except Exception:
+        logging.exception("[update_subsystem] Failed to update subsystem.")
+        return func.HttpResponse("Error updating subsystem.", status_code=500)
+
+    return json_payload(_subsystem_payload(entity))
+
+
+@app.function_name(name="delete_subsystem")
+@app.route(route="subsystems/{system}/{code}", methods=[func.HttpMethod.DELETE])
+@openapi_doc(
+    summary="Remove a subsystem",
+    description="Removes a subsystem code from the catalog. Existing claims are kept. Requires the admin role.",
+    tags=["Catalog"],
+    response_model=MessageResponse,
+    operation_id="deleteSubsystem",
+    route="/subsystems/{system}/{code}",
+    method="delete",
+)
+def delete_subsystem(req: func.HttpRequest) -> func.HttpResponse:
+    """Remove a subsystem code from the catalog."""
+
+    try:
+        require_role(req.headers, min_role="admin")
+    except AuthError as exc:
+        return func.HttpResponse(str(exc), status_code=exc.status)
+
+    system, code = _route_value(req, "system"), _route_value(req, "code")
+    try:
+        table = get_table_client(SUBSYSTEMS_TABLE_NAME)
+        table.get_entity(partition_key=system, row_key=code)
+        table.delete_entity(partition_key=system, row_key=code)
+    except ResourceNotFoundError:
+        return func.HttpResponse("Subsystem not found.", status_code=404)
+    except Exception:
+        logging.exception("[delete_subsystem] Failed to delete subsystem.")
+        return func.HttpResponse("Error deleting subsystem.", status_code=500)
+
+    return func.HttpResponse(status_code=204)
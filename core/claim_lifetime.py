@@ -0,0 +1,88 @@
+"""When an active claim lapses and the service releases it."""
+
+from __future__ import annotations
+
+import re
+from datetime import datetime, timedelta, timezone
+from typing import Any, Dict, Optional
+
+# release_after uses Go duration syntax, as the Terraform provider does,
+# limited to hours, minutes and seconds, e.g. "720h" or "1h30m".
+_DURATION_PART = re.compile(r"(\d+(?:\.\d+)?)([hms])")
+_DURATION_UNITS = {"h": 3600, "m": 60, "s": 1}
+
+
+def parse_timestamp(value: Any, field: str) -> datetime:
+    """Return an RFC 3339 timestamp as an aware datetime.
+
+    Raises ValueError naming ``field`` when the value is not a timestamp with
+    a time zone.
+    """
+
+    message = f"Field '{field}' must be an RFC 3339 timestamp such as 2025-01-31T00:00:00Z."
+    if not isinstance(value, str) or not value.strip():
+        raise ValueError(message)
+    text = value.strip()
+    if text[-1] in "Zz":
+        text = text[:-1] + "+00:00"
+    try:
+        parsed = datetime.fromisoformat(text)
+    except ValueError:
+        raise ValueError(message) from None
+    if parsed.tzinfo is None:
+        raise ValueError(message)
+    return parsed
+
+
+def parse_duration(value: Any, field: str) -> timedelta:
+    """Return a positive duration such as "720h", or raise ValueError naming ``field``."""
+
+    message = f"Field '{field}' must be a positive duration such as 720h."
+    text = value.strip() if isinstance(value, str) else ""
+    parts = _DURATION_PART.findall(text)
+    if not parts or "".join(number + unit for number, unit in parts) != text:
+        raise ValueError(message)
+    duration = timedelta(seconds=sum(float(number) * _DURATION_UNITS[unit] for number, unit in parts))
+    if duration <= timedelta(0):
+        raise ValueError(message)
+    return duration
+
+
+def lifetime_fields(payload: Dict[str, Any], now: Optional[datetime] = None) -> Dict[str, str]:
+    """Return the entity fields recording when a new claim lapses.
+
+    expires_at is stored exactly as sent, so clients read back the value
+    they configured. release_after is turned into the time the claim is
+    released, counted from ``now``.
+    """
+
+    fields: Dict[str, str] = {}
+    expires_at = payload.get("expires_at")
+    release_after = payload.get("release_after")
+    if expires_at and release_after:
+        raise ValueError("Fields 'expires_at' and 'release_after' cannot be combined.")
+    if expires_at:
+        parse_timestamp(expires_at, "expires_at")
+        fields["ExpiresAt"] = expires_at.strip()
+    if release_after:
+        now = now or datetime.now(tz=timezone.utc)
+        release_at = now + parse_duration(release_after, "release_after")
+        fields["ReleaseAt"] = release_at.astimezone(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+    return fields
+
+
+def lapse_reason(entity: Dict[str, Any], now: datetime) -> Optional[str]:
+    """Return why an active claim has lapsed at ``now``, or None while it holds."""
+
+    for field, reason in (("ExpiresAt", "Claim expired at {}"), ("ReleaseAt", "Scheduled release at {}")):
+        value = entity.get(field)
+        if not value:
+            continue
+        try:
+            when = parse_timestamp(value, field)
+        except ValueError:
+            # Never release a claim on a value we cannot read.
+            continue
+        if when <= now:
+            return reason.format(value)
+    return None
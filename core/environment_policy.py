@@ -0,0 +1,55 @@
+"""Claim restrictions stored in the environment catalog."""
+
+from __future__ import annotations
+
+from datetime import datetime
+from typing import Any, Dict, Optional
+
+try:
+    from azure.core.exceptions import ResourceNotFoundError
+except ImportError:  # pragma: no cover - allow tests without Azure SDK
+    class ResourceNotFoundError(Exception):
+        """Fallback exception when Azure SDK is unavailable."""
+
+from adapters.storage import get_table_client
+from core.claim_lifetime import parse_duration, parse_timestamp
+
+ENVIRONMENTS_TABLE = "Environments"
+ENVIRONMENT_PARTITION_KEY = "environment"
+
+
+def get_environment(environment: str) -> Optional[Dict[str, Any]]:
+    """Return the catalog entry of an environment, or None when it has none."""
+
+    try:
+        return get_table_client(ENVIRONMENTS_TABLE).get_entity(
+            partition_key=ENVIRONMENT_PARTITION_KEY, row_key=environment.lower()
+        )
+    except ResourceNotFoundError:
+        return None
+
+
+def region_allowed(entry: Dict[str, Any], region: str) -> bool:
+    """Return whether names in the environment may use ``region``.
+
+    An entry without allowed regions allows every region.
+    """
+
+    allowed = [code for code in str(entry.get("AllowedRegions") or "").split(",") if code]
+    return not allowed or region.lower() in allowed
+
+
+def held_until(entry: Dict[str, Any], released_at: Any) -> Optional[datetime]:
+    """Return when a name released at ``released_at`` may be claimed again.
+
+    Returns None when the environment keeps no retention or the release time
+    cannot be read, so an unreadable record never blocks a claim.
+    """
+
+    retention = entry.get("ReleasedRetention")
+    if not retention or not released_at:
+        return None
+    try:
+        return parse_timestamp(released_at, "ReleasedAt") + parse_duration(retention, "ReleasedRetention")
+    except ValueError:
+        return None
@@ -0,0 +1,82 @@
+"""Blocks of indices reserved for a team within a naming scope."""
+
+from __future__ import annotations
+
+from datetime import datetime, timezone
+from typing import Any, Dict, List, Optional
+
+from adapters.storage import get_table_client
+from core.claim_lifetime import parse_timestamp
+
+INDEX_RESERVATIONS_TABLE = "IndexReservations"
+INDEX_RESERVATION_PARTITION_KEY = "reservation"
+
+
+def reservation_expired(reservation: Dict[str, Any], now: Optional[datetime] = None) -> bool:
+    """Return whether a reservation's expires_at has passed.
+
+    Reservations without an expiry never expire.
+    """
+
+    expires_at = reservation.get("ExpiresAt")
+    if not expires_at:
+        return False
+    now = now or datetime.now(tz=timezone.utc)
+    return parse_timestamp(expires_at, "expires_at") <= now
+
+
+def list_reservations(region: str, environment: str) -> List[Dict[str, Any]]:
+    """Return the unexpired reservations stored for a region and environment."""
+
+    table = get_table_client(INDEX_RESERVATIONS_TABLE)
+    return [
+        entity
+        for entity in table.query_entities(f"PartitionKey eq '{INDEX_RESERVATION_PARTITION_KEY}'")
+        if entity.get("Region") == region.lower()
+        and entity.get("Environment") == environment.lower()
+        and not reservation_expired(entity)
+    ]
+
+
+def _scope_matches(reserved: Optional[str], value: Optional[str]) -> bool:
+    # An empty scope field covers every value.
+    return not reserved or not value or reserved == value
+
+
+def find_overlap(reservation: Dict[str, Any]) -> Optional[Dict[str, Any]]:
+    """Return an existing reservation whose block overlaps ``reservation``."""
+
+    for existing in list_reservations(reservation["Region"], reservation["Environment"]):
+        if existing.get("RowKey") == reservation.get("RowKey"):
+            continue
+        if not _scope_matches(existing.get("ResourceType"), reservation.get("ResourceType")):
+            continue
+        if not _scope_matches(existing.get("Project"), reservation.get("Project")):
+            continue
+        if existing["Start"] <= reservation["End"] and reservation["Start"] <= existing["End"]:
+            return existing
+    return None
+
+
+def reserving_team(
+    region: str, environment: str, resource_type: str, project: Optional[str], index: Any
+) -> Optional[str]:
+    """Return the team that reserved ``index`` in this scope, or None when it is free.
+
+    Only the resource type and project narrow a reservation; an empty field on
+    the reservation covers every claim.
+    """
+
+    if index is None or not str(index).isdigit():
+        return None
+    value = int(str(index))
+    resource_type = resource_type.lower()
+    project = str(project).lower() if project else ""
+    for reservation in list_reservations(region, environment):
+        if reservation.get("ResourceType") and reservation["ResourceType"] != resource_type:
+            continue
+        if reservation.get("Project") and reservation["Project"] != project:
+            continue
+        if reservation["Start"] <= value <= reservation["End"]:
+            return reservation.get("Team")
+    return None
@@ -48,6 +48,20 @@ def _apply_prefix(name: str, rule) -> str:
     return name
 
 
+def _append_suffix(name: str, template: str, optional_inputs: Mapping[str, str]) -> str:
+    """Append the unique suffix to names whose template does not place it.
+
+    The suffix is joined with a hyphen only when the template uses hyphens, so
+    compact names such as storage accounts stay compact.
+    """
+
+    suffix = optional_inputs.get("suffix")
+    if not suffix or "{suffix" in template:
+        return name
+    separator = "-" if "-" in template else ""
+    return f"{name}{separator}{suffix}"
+
+
 def build_name(region, environment, slug, rule, optional_inputs):
     """
     Build a resource name following the provided naming rule and inputs.
@@ -75,7 +89,7 @@ def build_name(region, environment, slug, rule, optional_inputs):
             missing = exc.args[0]
             raise ValueError(f"name_template references unknown placeholder '{missing}'") from exc
         rendered = re.sub(r"-{2,}", "-", rendered.strip("-"))
-        name = rendered.lower()
+        name = _append_suffix(rendered.lower(), template, optional_inputs)
         # Only apply auto-prefix if template doesn't already include sanmar_prefix
         if require_prefix and "{sanmar_prefix}" not in template and not name.startswith("sanmar"):
             return f"sanmar-{name}"
@@ -93,6 +107,9 @@ def build_name(region, environment, slug, rule, optional_inputs):
             value = optional_inputs.get(segment, "")
             parts.append(value)
 
+    if optional_inputs.get("suffix") and "suffix" not in _get_segments(rule):
+        parts.append(optional_inputs["suffix"])
+
     name = "-".join(filter(None, parts)).lower()
 
     return _apply_prefix(name, rule)
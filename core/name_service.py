@@ -10,11 +10,16 @@ from __future__ import annotations
 import json
 import logging
 import re
-from dataclasses import dataclass
-from typing import Any, Dict, Optional, Tuple
+from dataclasses import dataclass, replace
+from datetime import datetime, timezone
+from typing import Any, Dict, List, Optional, Sequence, Tuple
 
 from adapters.audit_logs import write_audit_log
-from adapters.storage import check_name_exists, claim_name
+from adapters.notifications import notify
+from adapters.storage import check_name_exists, claim_name, get_name_record
+from core.claim_lifetime import lifetime_fields
+from core.environment_policy import get_environment, held_until, region_allowed
+from core.index_reservations import reserving_team
 from core.name_generator import build_name
 from core.naming_rules import NamingRule, load_naming_rule
 from core.user_settings import settings_service
@@ -25,7 +30,15 @@ logger = logging.getLogger(__name__)
 
 
 class InvalidRequestError(ValueError):
-    """Raised when required fields are missing from a name generation payload."""
+    """Raised when required fields are missing from a name generation payload.
+
+    fields names the request fields at fault, when the error is about
+    particular ones, so clients can point at them.
+    """
+
+    def __init__(self, message: str, *, fields: Sequence[str] = ()) -> None:
+        super().__init__(message)
+        self.fields = tuple(fields)
 
 
 class NameConflictError(RuntimeError):
@@ -173,7 +186,41 @@ _FIELD_ALIASES = {
     "system": "system",
     "subsystem": "subsystem",
     "index": "index",
+    "suffix": "suffix",
 }
+# Unique suffixes are short hashes, so only allow lowercase letters and digits.
+_SUFFIX_PATTERN = re.compile(r"^[a-z0-9]{1,16}$")
+
+# Placeholders a per-claim template may use. Optional segments also have a
+# "<segment>_segment" form that adds a leading hyphen only when set.
+_TEMPLATE_REQUIRED = {"slug", "region", "environment", "sanmar_prefix"}
+_TEMPLATE_OPTIONAL = {"project", "purpose", "system", "subsystem", "index", "suffix"}
+_PLACEHOLDER_PATTERN = re.compile(r"\{([^{}]*)\}")
+
+
+def _custom_template(payload: Dict[str, Any]) -> Optional[str]:
+    """Return the claim's own name template, or None to use the rule's."""
+
+    template = payload.get("template")
+    if not template:
+        return None
+    if not isinstance(template, str):
+        raise InvalidRequestError("Field 'template' must be a string.", fields=["template"])
+
+    template = template.replace("{env}", "{environment}")
+    placeholders = _PLACEHOLDER_PATTERN.findall(template)
+    if not placeholders:
+        raise InvalidRequestError(
+            "Field 'template' must contain at least one placeholder such as {slug}.", fields=["template"]
+        )
+    for placeholder in placeholders:
+        segment = placeholder[: -len("_segment")] if placeholder.endswith("_segment") else placeholder
+        known = segment in _TEMPLATE_OPTIONAL or (segment == placeholder and segment in _TEMPLATE_REQUIRED)
+        if not known:
+            raise InvalidRequestError(
+                f"Field 'template' uses unknown placeholder {{{placeholder}}}.", fields=["template"]
+            )
+    return template
 
 
 def _normalise_payload(payload: Dict[str, Any]) -> Tuple[Dict[str, Any], Dict[str, str]]:
@@ -184,7 +231,7 @@ def _normalise_payload(payload: Dict[str, Any]) -> Tuple[Dict[str, Any], Dict[st
     missing = [field for field in _REQUIRED_FIELDS if not normalised_payload.get(field)]
     if missing:
         raise InvalidRequestError(
-            f"Missing required field(s): {', '.join(missing)}"
+            f"Missing required field(s): {', '.join(missing)}", fields=missing
         )
 
     optional_segments: Dict[str, str] = {}
@@ -193,11 +240,32 @@ def _normalise_payload(payload: Dict[str, Any]) -> Tuple[Dict[str, Any], Dict[st
         if value:
             optional_segments[target] = str(value).lower()
 
+    if "suffix" in optional_segments and not _SUFFIX_PATTERN.match(optional_segments["suffix"]):
+        raise InvalidRequestError("Field 'suffix' must be 1-16 letters or digits.", fields=["suffix"])
+
     return normalised_payload, optional_segments
 
 
-def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameGenerationResult:
-    """Generate a compliant name from the payload and persist the claim."""
+@dataclass
+class _RenderedName:
+    """A generated name together with the inputs that produced it."""
+
+    name: str
+    resource_type: str
+    region: str
+    environment: str
+    slug: str
+    rule: NamingRule
+    payload: Dict[str, Any]
+    metadata: Dict[str, str]
+
+
+def _render_name(payload: Dict[str, Any], requested_by: str) -> _RenderedName:
+    """Apply the caller's defaults to the payload and build the name it describes.
+
+    Claims and previews both go through here, so a preview always shows the
+    name the same claim would get.
+    """
 
     session_id = payload.get("session_id") or payload.get("sessionId")
     scrubbed_payload = {k: v for k, v in payload.items() if k not in {"session_id", "sessionId"}}
@@ -229,19 +297,40 @@ def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameG
 
     slug = get_slug(resource_type)
 
+    # A per-claim template replaces the rule's and may also place the project
+    # and purpose, which the rule templates leave out of names. The rule's
+    # other constraints, such as its maximum length, still apply.
+    template = _custom_template(normalized_payload)
+    build_rule = rule
+    if template:
+        build_rule = replace(rule, name_template=template)
+        for field in ("project", "purpose"):
+            if normalized_payload.get(field):
+                optional_segments[field] = str(normalized_payload[field]).lower()
+
     name = build_name(
         region=region,
         environment=environment,
         slug=slug,
-        rule=rule,
+        rule=build_rule,
         optional_inputs=optional_segments,
     )
 
     validate_name(name, rule)
 
-    if check_name_exists(region, environment, name):
-        raise NameConflictError(f"Name '{name}' is already in use.")
+    return _RenderedName(
+        name=name,
+        resource_type=resource_type,
+        region=region,
+        environment=environment,
+        slug=slug,
+        rule=rule,
+        payload=normalized_payload,
+        metadata=_entity_metadata(normalized_payload, slug, requested_by),
+    )
 
+
+def _entity_metadata(normalized_payload: Dict[str, Any], slug: str, requested_by: str) -> Dict[str, str]:
     subsystem_value = normalized_payload.get("subsystem")
     system_value = normalized_payload.get("system") or normalized_payload.get("system_short")
     index_value = normalized_payload.get("index")
@@ -261,7 +350,8 @@ def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameG
     # Add any additional custom fields from the normalized payload
     # (excluding core naming fields and internal fields)
     core_fields = {"resource_type", "region", "environment", 
-                   "system", "system_short", "subsystem", "index", "sessionId", "session_id"}
+                   "system", "system_short", "subsystem", "index", "sessionId", "session_id",
+                   "expires_at", "release_after", "index_reuse"}
     skip_fields = {"sessionId", "session_id"}
     for key, value in normalized_payload.items():
         if key not in core_fields and key not in skip_fields and value is not None:
@@ -271,8 +361,213 @@ def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameG
             if entity_key not in entity_metadata:
                 entity_metadata[entity_key] = entity_value
 
+    # Lifetime fields are timestamps clients compare, so they keep their case.
+    try:
+        entity_metadata.update(lifetime_fields(normalized_payload))
+    except ValueError as exc:
+        set_fields = [field for field in ("expires_at", "release_after") if normalized_payload.get(field)]
+        raise InvalidRequestError(str(exc), fields=set_fields)
+
     # Sanitize all metadata for safe storage
-    entity_metadata = _sanitize_metadata_dict(entity_metadata)
+    return _sanitize_metadata_dict(entity_metadata)
+
+
+# Indices are two-digit segments, so suggestions and allocated indices never
+# go past 99.
+_MAX_INDEX = 99
+
+# index_reuse values: never allocate the index of a released name, only once
+# the environment's released-name retention has passed, or as soon as the
+# name is released.
+_INDEX_REUSE_POLICIES = ("never", "after_hold", "always")
+
+
+def _index_reuse(payload: Dict[str, Any]) -> Optional[str]:
+    """Return the claim's index_reuse policy, or None when it sets none."""
+
+    policy = payload.get("index_reuse")
+    if not policy:
+        return None
+    policy = str(policy).lower()
+    if policy not in _INDEX_REUSE_POLICIES:
+        raise InvalidRequestError(
+            f"Field 'index_reuse' must be one of {', '.join(_INDEX_REUSE_POLICIES)}.", fields=["index_reuse"]
+        )
+    return policy
+
+
+def _index_free(rendered: _RenderedName, policy: str, entry: Optional[Dict[str, Any]]) -> bool:
+    """Return whether the index of ``rendered`` may be allocated under ``policy``."""
+
+    team = reserving_team(
+        rendered.region,
+        rendered.environment,
+        rendered.resource_type,
+        rendered.payload.get("project"),
+        rendered.payload.get("index"),
+    )
+    if team is not None and team != _claim_team(rendered.payload):
+        return False
+    record = get_name_record(rendered.region, rendered.environment, rendered.name)
+    if record is None:
+        return True
+    if record.get("InUse") or policy == "never":
+        return False
+    if policy == "always":
+        return True
+    until = held_until(entry, record.get("ReleasedAt")) if entry else None
+    return until is None or datetime.now(tz=timezone.utc) >= until
+
+
+def _render_claim(payload: Dict[str, Any], requested_by: str) -> Tuple[_RenderedName, Optional[str]]:
+    """Build the claim's name, allocating an index when the claim asks for one.
+
+    Claims that set index_reuse without an index get the lowest index whose
+    name the policy allows. Returns the rendered name and the policy used to
+    allocate its index, or None when the index came from the claim.
+    """
+
+    rendered = _render_name(payload, requested_by)
+    policy = _index_reuse(rendered.payload)
+    if policy is None or rendered.payload.get("index"):
+        return rendered, None
+
+    entry = get_environment(rendered.environment)
+    for index in range(1, _MAX_INDEX + 1):
+        candidate = _render_name({**payload, "index": f"{index:02d}"}, requested_by)
+        if _index_free(candidate, policy, entry):
+            return candidate, policy
+    raise NameConflictError(
+        f"No index up to {_MAX_INDEX:02d} is free under index_reuse '{policy}'."
+    )
+
+
+def preview_name(payload: Dict[str, Any], requested_by: str) -> NameGenerationResult:
+    """Return the name a claim with this payload would get, without claiming it."""
+
+    rendered, _policy = _render_claim(payload, requested_by)
+    return NameGenerationResult(
+        name=rendered.name,
+        resource_type=rendered.resource_type,
+        region=rendered.region,
+        environment=rendered.environment,
+        slug=rendered.slug,
+        metadata=rendered.metadata,
+        rule=rendered.rule,
+    )
+
+
+def suggest_names(
+    payload: Dict[str, Any], requested_by: str, *, purposes: List[str], count: int
+) -> List[Dict[str, Any]]:
+    """Return up to ``count`` distinct candidate names for the payload's scope.
+
+    Candidates walk the index upwards from 01, trying each purpose in turn at
+    every index (or the payload's own purpose when none are given). Each is
+    built exactly as a claim would build it and flagged with whether it is
+    free; nothing is claimed.
+    """
+
+    candidates: List[Dict[str, Any]] = []
+    seen = set()
+    for index in range(1, _MAX_INDEX + 1):
+        for purpose in purposes or [payload.get("purpose")]:
+            candidate = {**payload, "index": f"{index:02d}"}
+            if purpose:
+                candidate["purpose"] = purpose
+            rendered = _render_name(candidate, requested_by)
+            if rendered.name in seen:
+                continue
+            seen.add(rendered.name)
+            candidates.append(
+                {
+                    "name": rendered.name,
+                    "purpose": str(purpose or "").lower(),
+                    "index": f"{index:02d}",
+                    "available": not check_name_exists(rendered.region, rendered.environment, rendered.name),
+                }
+            )
+            if len(candidates) >= count:
+                return candidates
+    return candidates
+
+
+def _claim_team(payload: Dict[str, Any]) -> str:
+    """Return the team a claim is made for, from the payload or its metadata."""
+
+    team = payload.get("team")
+    metadata = payload.get("metadata")
+    if not team and isinstance(metadata, dict):
+        team = metadata.get("team")
+    return str(team or "").lower()
+
+
+def _check_index_reservation(rendered: _RenderedName) -> None:
+    """Reject explicit indices that another team has reserved in this scope."""
+
+    team = reserving_team(
+        rendered.region,
+        rendered.environment,
+        rendered.resource_type,
+        rendered.payload.get("project"),
+        rendered.payload.get("index"),
+    )
+    if team is not None and team != _claim_team(rendered.payload):
+        raise NameConflictError(
+            f"Index '{rendered.payload.get('index')}' is reserved for team '{team}'."
+        )
+
+
+def _check_environment_policy(rendered: _RenderedName, *, hold_released: bool = True) -> None:
+    """Apply the environment catalog's region list and released-name retention.
+
+    Environments without a catalog entry are not restricted. hold_released is
+    False for indices allocated under index_reuse 'always', which may reuse a
+    released name straight away.
+    """
+
+    entry = get_environment(rendered.environment)
+    if entry is None:
+        return
+    if not region_allowed(entry, rendered.region):
+        raise InvalidRequestError(
+            f"Region '{rendered.region}' is not allowed in environment '{rendered.environment}'.",
+            fields=["region"],
+        )
+    if not hold_released or not entry.get("ReleasedRetention"):
+        return
+    record = get_name_record(rendered.region, rendered.environment, rendered.name)
+    until = held_until(entry, record.get("ReleasedAt")) if record else None
+    if until is not None and datetime.now(tz=timezone.utc) < until:
+        raise NameConflictError(
+            f"Name '{rendered.name}' was released recently and is held until {until.isoformat()}."
+        )
+
+
+def generate_and_claim_name(
+    payload: Dict[str, Any], requested_by: str, *, run_metadata: Optional[Dict[str, str]] = None
+) -> NameGenerationResult:
+    """Generate a compliant name from the payload and persist the claim.
+
+    run_metadata is added to the audit entry, describing the run that made
+    the request.
+    """
+
+    rendered, policy = _render_claim(payload, requested_by)
+    name = rendered.name
+    resource_type = rendered.resource_type
+    region = rendered.region
+    environment = rendered.environment
+    slug = rendered.slug
+    rule = rendered.rule
+    normalized_payload = rendered.payload
+    entity_metadata = rendered.metadata
+
+    if check_name_exists(region, environment, name):
+        raise NameConflictError(f"Name '{name}' is already in use.")
+
+    _check_environment_policy(rendered, hold_released=policy != "always")
+    _check_index_reservation(rendered)
 
     claim_name(
         region=region,
@@ -308,6 +603,7 @@ def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameG
     audit_metadata.setdefault("Region", region)
     audit_metadata.setdefault("Environment", environment)
     audit_metadata["Slug"] = slug
+    audit_metadata.update(run_metadata or {})
 
     # Sanitize audit metadata for safe storage
     audit_metadata = _sanitize_metadata_dict(audit_metadata)
@@ -319,6 +615,11 @@ def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameG
         note=f"{resource_type}:{region}-{environment}",
         metadata=audit_metadata,
     )
+    notify(
+        "claim",
+        name,
+        {"user": requested_by, "resourceType": resource_type, "region": region, "environment": environment},
+    )
 
     return NameGenerationResult(
         name=name,
@@ -329,3 +630,34 @@ def generate_and_claim_name(payload: Dict[str, Any], requested_by: str) -> NameG
         metadata=entity_metadata,
         rule=rule,
     )
+
+
+# Fields the names table keeps for every claim, as opposed to the metadata
+# the claim was made with.
+_STORED_CLAIM_FIELDS = {
+    "PartitionKey", "RowKey", "InUse", "ResourceType", "ClaimedBy", "ClaimedAt",
+    "ReleasedBy", "ReleasedAt", "ReleaseReason", "Timestamp", "etag",
+}
+
+
+def claimed_name_result(
+    name: str, region: str, environment: str, record: Dict[str, Any]
+) -> NameGenerationResult:
+    """Rebuild the result of a claim from its names-table record.
+
+    Used to answer for a claim that was made but never answered, where
+    rendering the request again could give another name, for example once
+    the claimed index is no longer free.
+    """
+
+    resource_type = str(record.get("ResourceType") or "").lower()
+    metadata = {k: v for k, v in record.items() if k not in _STORED_CLAIM_FIELDS}
+    return NameGenerationResult(
+        name=name,
+        resource_type=resource_type,
+        region=region.lower(),
+        environment=environment.lower(),
+        slug=str(record.get("Slug") or ""),
+        metadata=metadata,
+        rule=load_naming_rule(resource_type),
+    )
@@ -15,6 +15,8 @@ from pathlib import Path
 from string import Formatter
 from typing import Callable, Dict, List, Mapping, Optional, Protocol, Sequence
 
+from core.validation import NAME_CHARACTERS
+
 
 class _SafeFormatDict(dict):
     """Dict that returns an empty string when formatting misses a key."""
@@ -68,6 +70,12 @@ class NamingRule:
     validators: Sequence[Callable[[Mapping[str, object]], None]] = ()
     name_template: Optional[str] = None
     summary_template: Optional[str] = None
+    # Regular expression character class, without brackets, that names must
+    # stay within (e.g. "a-z0-9"); None allows every character names may use.
+    valid_characters: Optional[str] = None
+    # Where Azure requires the name to be unique: global, resource-group or
+    # parent. Informational only; None when the rule does not say.
+    uniqueness_scope: Optional[str] = None
 
     def to_dict(self) -> Dict[str, object]:
         return {
@@ -77,6 +85,8 @@ class NamingRule:
             "display_fields": [field.to_dict() for field in self.display_fields],
             "name_template": self.name_template,
             "summary_template": self.summary_template,
+            "valid_characters": self.valid_characters,
+            "uniqueness_scope": self.uniqueness_scope,
         }
 
     def validate_payload(self, payload: Mapping[str, object]) -> None:
@@ -304,6 +314,17 @@ def list_resource_types(include_default: bool = True) -> Sequence[str]:
     return tuple(ordered)
 
 
+# Steps the name service applies to claim segments before building and
+# recording a name: values are lowercased and stored trimmed. Clients compare
+# segments in this form to tell a change in spelling from a real change.
+SEGMENT_CANONICALIZATION: Dict[str, List[str]] = {
+    "project": ["trim", "lower"],
+    "purpose": ["trim", "lower"],
+    "system": ["trim", "lower"],
+    "subsystem": ["trim", "lower"],
+}
+
+
 def describe_rule(resource_type: str) -> Dict[str, object]:
     """Provide a user-friendly JSON-compatible description of a naming rule."""
 
@@ -329,6 +350,10 @@ def describe_rule(resource_type: str) -> Dict[str, object]:
     description: Dict[str, object] = {
         "resourceType": normalised,
         "maxLength": rule.max_length,
+        "validCharacters": rule.valid_characters or NAME_CHARACTERS,
+        # validate_name only accepts lowercase names.
+        "caseRule": "lower",
+        "uniquenessScope": rule.uniqueness_scope,
         "requireSanmarPrefix": rule.require_sanmar_prefix,
         "segments": list(rule.segments),
         "optionalSegments": [segment for segment in rule.segments if segment not in {"slug", "region", "environment"}],
@@ -341,6 +366,7 @@ def describe_rule(resource_type: str) -> Dict[str, object]:
             "required": payload_required,
             "optional": sorted(optional_aliases),
         },
+        "canonicalization": {segment: list(steps) for segment, steps in SEGMENT_CANONICALIZATION.items()},
     }
 
     return description
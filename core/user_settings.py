@@ -221,6 +221,31 @@ class UserSettingsService:
                     defaults.update(values)
         return defaults
 
+    def describe_session(
+        self,
+        user_id: str,
+        session_id: str,
+        *,
+        now: Optional[datetime] = None,
+    ) -> Optional[Tuple[Dict[str, str], datetime]]:
+        """Return the defaults a claim with ``session_id`` would get, and when the session was last used.
+
+        Unlike :meth:`get_defaults` this does not keep the session alive, so
+        inspecting a session never extends it. Returns ``None`` for unknown or
+        expired sessions.
+        """
+
+        now = now or datetime.now(timezone.utc)
+        session = self.repository.get_session(user_id, session_id)
+        if not session:
+            return None
+        values, last_seen = session
+        if now - last_seen > self.session_timeout:
+            return None
+        defaults = self.repository.get_permanent(user_id)
+        defaults.update(values)
+        return defaults, last_seen
+
     def apply_defaults(
         self,
         payload: Dict[str, object],
@@ -3,7 +3,10 @@
 from __future__ import annotations
 
 import re
-from typing import Any
+from typing import Any, Optional
+
+# Characters every generated name is limited to, as a character class body.
+NAME_CHARACTERS = "a-z0-9-"
 
 
 def _get_rule_value(rule: Any, key: str, default: int) -> int:
@@ -14,6 +17,14 @@ def _get_rule_value(rule: Any, key: str, default: int) -> int:
     return default
 
 
+def _valid_characters(rule: Any) -> Optional[str]:
+    if hasattr(rule, "valid_characters"):
+        return getattr(rule, "valid_characters")
+    if isinstance(rule, dict):
+        return rule.get("valid_characters")
+    return None
+
+
 def validate_name(name: str, rule) -> None:
     """Raise :class:`ValueError` when the generated name violates policy."""
 
@@ -31,9 +42,17 @@ def validate_name(name: str, rule) -> None:
     if not name.islower():
         raise ValueError(f"Name '{name}' must be lowercase. Found uppercase or non-alphabetic characters.")
 
-    if not re.fullmatch(r"[a-z0-9-]+", name):
-        invalid_chars = set(c for c in name if not re.fullmatch(r"[a-z0-9-]", c))
+    if not re.fullmatch(f"[{NAME_CHARACTERS}]+", name):
+        invalid_chars = set(c for c in name if not re.fullmatch(f"[{NAME_CHARACTERS}]", c))
         raise ValueError(
             f"Name '{name}' contains invalid characters: {', '.join(sorted(invalid_chars))}. "
             f"Only lowercase letters (a-z), numbers (0-9), and hyphens (-) are allowed."
         )
+
+    allowed = _valid_characters(rule)
+    if allowed and not re.fullmatch(f"[{allowed}]+", name):
+        invalid_chars = set(c for c in name if not re.fullmatch(f"[{allowed}]", c))
+        raise ValueError(
+            f"Name '{name}' contains characters this resource type does not allow: {', '.join(sorted(invalid_chars))}. "
+            f"Allowed characters: {allowed}."
+        )
@@ -3,12 +3,15 @@
 from __future__ import annotations
 
 import json
+import re
 from dataclasses import dataclass
 from pathlib import Path
 from typing import Any, Callable, Dict, Iterable, Mapping, Sequence
 
 from core.naming_rules import DisplayField, NamingRule, NamingRuleProvider
 
+_UNIQUENESS_SCOPES = ("global", "resource-group", "parent")
+
 
 @dataclass(slots=True)
 class _RuleLayer:
@@ -150,6 +153,22 @@ def _to_rule(
     if summary_template is None and fallback_rule is not None:
         summary_template = fallback_rule.summary_template
 
+    valid_characters = config.get("valid_characters")
+    if valid_characters is None and fallback_rule is not None:
+        valid_characters = fallback_rule.valid_characters
+    if valid_characters:
+        valid_characters = str(valid_characters)
+        try:
+            re.compile(f"[{valid_characters}]")
+        except re.error as exc:
+            raise ValueError(f"Rule property 'valid_characters' is not a character class: {exc}.") from None
+
+    uniqueness_scope = config.get("uniqueness_scope")
+    if uniqueness_scope is None and fallback_rule is not None:
+        uniqueness_scope = fallback_rule.uniqueness_scope
+    if uniqueness_scope and uniqueness_scope not in _UNIQUENESS_SCOPES:
+        raise ValueError(f"Rule property 'uniqueness_scope' must be one of {', '.join(_UNIQUENESS_SCOPES)}.")
+
     return NamingRule(
         segments=segments,
         max_length=max_length,
@@ -158,6 +177,8 @@ def _to_rule(
         validators=validators,
         name_template=str(name_template) if name_template else None,
         summary_template=str(summary_template) if summary_template else None,
+        valid_characters=valid_characters or None,
+        uniqueness_scope=uniqueness_scope or None,
     )
 
 
@@ -0,0 +1,75 @@
+// Command sanmar-webhook is a Kubernetes validating admission webhook that
+// checks the Azure names of Azure Service Operator and Crossplane resources
+// against the SanMar naming service before they are created.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "sanmar-webhook:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	endpoint := flag.String("endpoint", os.Getenv("SANMAR_ENDPOINT"), "naming service base URL (env SANMAR_ENDPOINT)")
+	scope := flag.String("scope", os.Getenv("SANMAR_SCOPE"), "AAD scope to request tokens for (env SANMAR_SCOPE)")
+	listen := flag.String("listen", ":8443", "address to serve admission reviews on")
+	certFile := flag.String("tls-cert", "/etc/sanmar-webhook/tls.crt", "TLS certificate presented to the API server")
+	keyFile := flag.String("tls-key", "/etc/sanmar-webhook/tls.key", "TLS private key")
+	var v validator
+	flag.StringVar(&v.region, "region", "", "region names must be claimed in when objects have no "+regionLabel+" label")
+	flag.StringVar(&v.environment, "environment", "", "environment names must be claimed in when objects have no "+environmentLabel+" label")
+	flag.BoolVar(&v.checkNamespaces, "check-namespaces", false, "also check namespace names against the base naming rules")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := provider.NewAPIClient(ctx, *endpoint, *scope, provider.RetryConfig{
+		MaxAttempts: 3,
+		MinBackoff:  200 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	})
+	if err != nil {
+		return err
+	}
+	// The API server gives up on webhooks after 10 seconds by default.
+	client.SetOperationTimeout(8 * time.Second)
+	v.names = client
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate", &v)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("serving admission reviews on %s", *listen)
+		errs <- srv.ListenAndServeTLS(*certFile, *keyFile)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdown)
+	}
+}
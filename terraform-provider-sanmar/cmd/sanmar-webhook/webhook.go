@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+const (
+	// regionLabel and environmentLabel name the region and environment an
+	// object's Azure name must be claimed in.
+	regionLabel      = provider.KubernetesRegionLabel
+	environmentLabel = provider.KubernetesEnvironmentLabel
+
+	// crossplaneExternalName holds the Azure name of a Crossplane managed
+	// resource when it differs from the object name.
+	crossplaneExternalName = "crossplane.io/external-name"
+)
+
+// groupKind identifies a Kubernetes resource kind.
+type groupKind struct {
+	Group string
+	Kind  string
+}
+
+// managedKinds maps Azure Service Operator and Crossplane kinds to the
+// canonical resource types their Azure names are checked against. Kinds
+// missing here are admitted without checks.
+var managedKinds = map[groupKind]string{
+	{"resources.azure.com", "ResourceGroup"}:                   "resource_group",
+	{"storage.azure.com", "StorageAccount"}:                    "storage_account",
+	{"keyvault.azure.com", "Vault"}:                            "key_vault",
+	{"containerservice.azure.com", "ManagedCluster"}:           "kubernetes_cluster",
+	{"containerregistry.azure.com", "Registry"}:                "container_registry",
+	{"sql.azure.com", "Server"}:                                "sql_server",
+	{"azure.upbound.io", "ResourceGroup"}:                      "resource_group",
+	{"storage.azure.upbound.io", "Account"}:                    "storage_account",
+	{"keyvault.azure.upbound.io", "Vault"}:                     "key_vault",
+	{"containerservice.azure.upbound.io", "KubernetesCluster"}: "kubernetes_cluster",
+	{"containerregistry.azure.upbound.io", "Registry"}:         "container_registry",
+	{"sql.azure.upbound.io", "MSSQLServer"}:                    "sql_server",
+}
+
+// admissionReview is the subset of admission.k8s.io/v1 AdmissionReview the
+// webhook reads and writes.
+type admissionReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Request    *admissionRequest  `json:"request,omitempty"`
+	Response   *admissionResponse `json:"response,omitempty"`
+}
+
+type admissionRequest struct {
+	UID       string           `json:"uid"`
+	Kind      groupVersionKind `json:"kind"`
+	Operation string           `json:"operation"`
+	Object    json.RawMessage  `json:"object"`
+}
+
+type groupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+type admissionResponse struct {
+	UID     string  `json:"uid"`
+	Allowed bool    `json:"allowed"`
+	Result  *status `json:"status,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// object is the subset of a Kubernetes object the webhook reads.
+type object struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Labels      map[string]string `json:"labels"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		// AzureName is set on Azure Service Operator resources whose Azure
+		// name differs from the object name.
+		AzureName string `json:"azureName"`
+	} `json:"spec"`
+}
+
+// nameChecker reports whether names are claimed; *provider.APIClient
+// implements it.
+type nameChecker interface {
+	CheckName(ctx context.Context, region, environment, name string) (provider.NameState, error)
+}
+
+// validator answers admission reviews for objects whose names become Azure
+// resource names.
+type validator struct {
+	names           nameChecker
+	region          string
+	environment     string
+	checkNamespaces bool
+}
+
+func (v *validator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var review admissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+		http.Error(w, "expected an AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	resp := v.review(r.Context(), review.Request)
+	resp.UID = review.Request.UID
+	review.Request = nil
+	review.Response = resp
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Printf("failed to write admission response: %v", err)
+	}
+}
+
+// review decides whether req is admitted. Only creates are checked, since
+// Azure names cannot change once the resource exists.
+func (v *validator) review(ctx context.Context, req *admissionRequest) *admissionResponse {
+	allow := &admissionResponse{Allowed: true}
+	if req.Operation != "CREATE" {
+		return allow
+	}
+
+	kind := groupKind{req.Kind.Group, req.Kind.Kind}
+	resourceType, managed := managedKinds[kind]
+	namespace := kind == groupKind{"", "Namespace"}
+	if !managed && !(namespace && v.checkNamespaces) {
+		return allow
+	}
+
+	var obj object
+	if err := json.Unmarshal(req.Object, &obj); err != nil {
+		return deny(http.StatusBadRequest, fmt.Sprintf("failed to decode object: %v", err))
+	}
+	name := azureName(obj)
+
+	if violations := provider.LintName(name, resourceType); len(violations) > 0 {
+		return deny(http.StatusForbidden, fmt.Sprintf("name %q breaks the SanMar naming convention: %s", name, strings.Join(violations, "; ")))
+	}
+	if namespace {
+		return allow
+	}
+
+	region := labelOr(obj, regionLabel, v.region)
+	environment := labelOr(obj, environmentLabel, v.environment)
+	if region == "" || environment == "" {
+		return allow
+	}
+	state, err := v.names.CheckName(ctx, region, environment, name)
+	if err != nil {
+		return deny(http.StatusServiceUnavailable, fmt.Sprintf("failed to check name %q with the naming service: %v", name, err))
+	}
+	if !state.InUse {
+		return deny(http.StatusForbidden, fmt.Sprintf("name %q is not claimed in %s/%s; claim it with the naming service first", name, region, environment))
+	}
+	return allow
+}
+
+// azureName returns the name the object's Azure resource gets.
+func azureName(obj object) string {
+	if obj.Spec.AzureName != "" {
+		return obj.Spec.AzureName
+	}
+	if name := obj.Metadata.Annotations[crossplaneExternalName]; name != "" {
+		return name
+	}
+	return obj.Metadata.Name
+}
+
+func labelOr(obj object, label, fallback string) string {
+	if value := obj.Metadata.Labels[label]; value != "" {
+		return value
+	}
+	return fallback
+}
+
+func deny(code int, message string) *admissionResponse {
+	return &admissionResponse{Result: &status{Code: code, Message: message}}
+}
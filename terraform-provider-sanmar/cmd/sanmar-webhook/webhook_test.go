@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+type fakeNames map[string]provider.NameState
+
+func (f fakeNames) CheckName(_ context.Context, region, environment, name string) (provider.NameState, error) {
+	return f[region+"/"+environment+"/"+name], nil
+}
+
+func sendReview(t *testing.T, v *validator, kind groupVersionKind, operation, obj string) *admissionResponse {
+	t.Helper()
+	body, err := json.Marshal(admissionReview{
+		APIVersion: "admission.k8s.io/v1",
+		Kind:       "AdmissionReview",
+		Request:    &admissionRequest{UID: "uid-1", Kind: kind, Operation: operation, Object: json.RawMessage(obj)},
+	})
+	if err != nil {
+		t.Fatalf("marshal review: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	v.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(string(body))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body)
+	}
+
+	var review admissionReview
+	if err := json.Unmarshal(rec.Body.Bytes(), &review); err != nil {
+		t.Fatalf("decode review: %v", err)
+	}
+	if review.Response == nil || review.Response.UID != "uid-1" || review.Kind != "AdmissionReview" {
+		t.Fatalf("unexpected review: %+v", review)
+	}
+	return review.Response
+}
+
+func TestValidatorChecksManagedResources(t *testing.T) {
+	v := &validator{
+		names:       fakeNames{"wus2/prd/wus2-prd-aks-atlas": {Exists: true, InUse: true}},
+		region:      "wus2",
+		environment: "prd",
+	}
+	cluster := groupVersionKind{Group: "containerservice.azure.com", Version: "v1api20231001", Kind: "ManagedCluster"}
+
+	if resp := sendReview(t, v, cluster, "CREATE", `{"metadata":{"name":"atlas"},"spec":{"azureName":"wus2-prd-aks-atlas"}}`); !resp.Allowed {
+		t.Fatalf("expected claimed name to be allowed, got %+v", resp.Result)
+	}
+
+	resp := sendReview(t, v, cluster, "CREATE", `{"metadata":{"name":"wus2-prd-aks-other"}}`)
+	if resp.Allowed || resp.Result.Code != http.StatusForbidden || !strings.Contains(resp.Result.Message, "not claimed in wus2/prd") {
+		t.Fatalf("expected unclaimed name to be denied, got %+v", resp)
+	}
+
+	resp = sendReview(t, v, cluster, "CREATE", `{"metadata":{"name":"Cluster_1"}}`)
+	if resp.Allowed || !strings.Contains(resp.Result.Message, "breaks the SanMar naming convention") {
+		t.Fatalf("expected invalid name to be denied, got %+v", resp)
+	}
+
+	if resp := sendReview(t, v, cluster, "UPDATE", `{"metadata":{"name":"Cluster_1"}}`); !resp.Allowed {
+		t.Fatalf("expected updates to be allowed, got %+v", resp.Result)
+	}
+}
+
+func TestValidatorUsesCrossplaneExternalName(t *testing.T) {
+	v := &validator{names: fakeNames{"eus2/dev/eus2-dev-st-atlas-001": {Exists: true, InUse: true}}}
+	account := groupVersionKind{Group: "storage.azure.upbound.io", Version: "v1beta1", Kind: "Account"}
+	obj := `{"metadata":{"name":"atlas","labels":{"sanmar.io/region":"eus2","sanmar.io/environment":"dev"},"annotations":{"crossplane.io/external-name":"eus2-dev-st-atlas-001"}}}`
+
+	resp := sendReview(t, v, account, "CREATE", obj)
+	if resp.Allowed || !strings.Contains(resp.Result.Message, "eus2-dev-st-atlas-001") {
+		t.Fatalf("expected the storage rules to reject hyphens in the external name, got %+v", resp)
+	}
+}
+
+func TestValidatorIgnoresUnmanagedKinds(t *testing.T) {
+	v := &validator{names: fakeNames{}}
+	if resp := sendReview(t, v, groupVersionKind{Version: "v1", Kind: "ConfigMap"}, "CREATE", `{"metadata":{"name":"Bad_Name"}}`); !resp.Allowed {
+		t.Fatalf("expected unmanaged kinds to be allowed, got %+v", resp.Result)
+	}
+
+	namespace := groupVersionKind{Version: "v1", Kind: "Namespace"}
+	if resp := sendReview(t, v, namespace, "CREATE", `{"metadata":{"name":"kube-tools"}}`); !resp.Allowed {
+		t.Fatalf("expected namespaces to be allowed by default, got %+v", resp.Result)
+	}
+	v.checkNamespaces = true
+	if resp := sendReview(t, v, namespace, "CREATE", `{"metadata":{"name":"kube-tools"}}`); resp.Allowed {
+		t.Fatal("expected namespace name to be checked with -check-namespaces")
+	}
+}
+
+func TestValidatorReportsServiceErrors(t *testing.T) {
+	v := &validator{names: errorNames{}, region: "wus2", environment: "prd"}
+	rg := groupVersionKind{Group: "resources.azure.com", Version: "v1api20200601", Kind: "ResourceGroup"}
+
+	resp := sendReview(t, v, rg, "CREATE", `{"metadata":{"name":"wus2-prd-rg-atlas"}}`)
+	if resp.Allowed || resp.Result.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected service errors to deny, got %+v", resp)
+	}
+}
+
+type errorNames struct{}
+
+func (errorNames) CheckName(context.Context, string, string, string) (provider.NameState, error) {
+	return provider.NameState{}, errors.New("boom")
+}
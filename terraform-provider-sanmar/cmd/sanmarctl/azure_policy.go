@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// armResourceTypes maps canonical resource types to the Azure Resource
+// Manager types their names are enforced on. Resource types missing here are
+// skipped by the azure format.
+var armResourceTypes = map[string]string{
+	"app_service":         "Microsoft.Web/sites",
+	"app_service_plan":    "Microsoft.Web/serverfarms",
+	"application_gateway": "Microsoft.Network/applicationGateways",
+	"container_registry":  "Microsoft.ContainerRegistry/registries",
+	"cosmosdb_account":    "Microsoft.DocumentDB/databaseAccounts",
+	"function_app":        "Microsoft.Web/sites",
+	"key_vault":           "Microsoft.KeyVault/vaults",
+	"kubernetes_cluster":  "Microsoft.ContainerService/managedClusters",
+	"load_balancer":       "Microsoft.Network/loadBalancers",
+	"log_analytics":       "Microsoft.OperationalInsights/workspaces",
+	"network_interface":   "Microsoft.Network/networkInterfaces",
+	"network_security":    "Microsoft.Network/networkSecurityGroups",
+	"public_ip":           "Microsoft.Network/publicIPAddresses",
+	"resource_group":      "Microsoft.Resources/resourceGroups",
+	"service_bus":         "Microsoft.ServiceBus/namespaces",
+	"sql_server":          "Microsoft.Sql/servers",
+	"storage_account":     "Microsoft.Storage/storageAccounts",
+	"subnet":              "Microsoft.Network/virtualNetworks/subnets",
+	"virtual_machine":     "Microsoft.Compute/virtualMachines",
+	"virtual_network":     "Microsoft.Network/virtualNetworks",
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// azurePolicyDefinition is the subset of an Azure Policy definition the azure
+// format writes, in the shape accepted by "az policy definition create".
+type azurePolicyDefinition struct {
+	Name       string                    `json:"name"`
+	Properties azurePolicyDefinitionBody `json:"properties"`
+}
+
+type azurePolicyDefinitionBody struct {
+	DisplayName string         `json:"displayName"`
+	Description string         `json:"description"`
+	PolicyType  string         `json:"policyType"`
+	Mode        string         `json:"mode"`
+	Metadata    map[string]any `json:"metadata"`
+	Parameters  map[string]any `json:"parameters"`
+	PolicyRule  map[string]any `json:"policyRule"`
+}
+
+// writeAzurePolicies writes one Azure Policy definition per resource type
+// with an ARM mapping into dir, named <resource_type>.json. It returns the
+// resource types that were skipped because they have no mapping.
+func writeAzurePolicies(dir string, data policyData) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var skipped []string
+	for resourceType, rule := range data.Rules {
+		armType, ok := armResourceTypes[resourceType]
+		if !ok {
+			skipped = append(skipped, resourceType)
+			continue
+		}
+		content, err := json.MarshalIndent(azurePolicy(resourceType, armType, rule), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, resourceType+".json"), content, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(skipped)
+	return skipped, nil
+}
+
+// azurePolicy builds the definition flagging armType resources whose names
+// are longer than the rule allows or lack the fixed text around the slug.
+func azurePolicy(resourceType, armType string, rule policyRule) azurePolicyDefinition {
+	violations := []any{
+		map[string]any{
+			"value":   "[length(field('name'))]",
+			"greater": rule.MaxLength,
+		},
+	}
+	if fixed := templateFixedText(rule); fixed != "" {
+		violations = append(violations, map[string]any{
+			"field":       "name",
+			"notContains": fixed,
+		})
+	}
+
+	return azurePolicyDefinition{
+		Name: "sanmar-naming-" + strings.ReplaceAll(resourceType, "_", "-"),
+		Properties: azurePolicyDefinitionBody{
+			DisplayName: fmt.Sprintf("SanMar naming convention for %s", resourceType),
+			Description: fmt.Sprintf("Flags %s resources whose names do not follow the SanMar naming rule for %s. Generated by sanmarctl policy; re-export after rule changes.", armType, resourceType),
+			PolicyType:  "Custom",
+			Mode:        "All",
+			Metadata: map[string]any{
+				"category":     "Naming",
+				"resourceType": resourceType,
+				"nameTemplate": rule.NameTemplate,
+			},
+			Parameters: map[string]any{
+				"effect": map[string]any{
+					"type":          "String",
+					"allowedValues": []string{"Audit", "Deny", "Disabled"},
+					"defaultValue":  "Audit",
+					"metadata": map[string]any{
+						"displayName": "Effect",
+						"description": "Effect applied to resources with non-compliant names.",
+					},
+				},
+			},
+			PolicyRule: map[string]any{
+				"if": map[string]any{
+					"allOf": []any{
+						map[string]any{"field": "type", "equals": armType},
+						map[string]any{"anyOf": violations},
+					},
+				},
+				"then": map[string]any{"effect": "[parameters('effect')]"},
+			},
+		},
+	}
+}
+
+// templateFixedText returns the text every name built from the rule's
+// template contains around the slug, such as "-st-" for
+// "{region}-{environment}-{slug}-{system}" or "stsanmar" when the slug is
+// followed by a required sanmar prefix. Azure Policy has no regular
+// expressions, so this substring is what the definition can check. It returns
+// "" when the rule has no slug or its template does not place one.
+func templateFixedText(rule policyRule) string {
+	if rule.Slug == "" {
+		return ""
+	}
+
+	var run strings.Builder
+	hasSlug := false
+	last := 0
+	for _, match := range templatePlaceholder.FindAllStringSubmatchIndex(rule.NameTemplate, -1) {
+		run.WriteString(rule.NameTemplate[last:match[0]])
+		last = match[1]
+
+		switch rule.NameTemplate[match[2]:match[3]] {
+		case "slug":
+			run.WriteString(rule.Slug)
+			hasSlug = true
+		case "sanmar_prefix":
+			if rule.RequireSanmarPrefix {
+				run.WriteString("sanmar")
+			}
+		default:
+			// Any other placeholder varies between names and ends the run.
+			if hasSlug {
+				return run.String()
+			}
+			run.Reset()
+		}
+	}
+	run.WriteString(rule.NameTemplate[last:])
+	if !hasSlug {
+		return ""
+	}
+	return run.String()
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func runClaim(ctx context.Context, args []string) error {
+	return generateName(ctx, "claim", args)
+}
+
+func runPreview(ctx context.Context, args []string) error {
+	return generateName(ctx, "preview", args)
+}
+
+// generateName claims or previews one name, depending on command, and
+// writes it in the format selected with -output.
+func generateName(ctx context.Context, command string, args []string) error {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	var req provider.ClaimNameRequest
+	fs.StringVar(&req.ResourceType, "resource-type", "", "canonical resource type, such as storage_account (required)")
+	fs.StringVar(&req.Region, "region", "", "region to generate the name in (required)")
+	fs.StringVar(&req.Environment, "environment", "", "environment to generate the name in (required)")
+	project := fs.String("project", "", "project segment")
+	purpose := fs.String("purpose", "", "purpose segment")
+	system := fs.String("system", "", "system segment")
+	subsystem := fs.String("subsystem", "", "subsystem segment")
+	index := fs.String("index", "", "index segment")
+	template := fs.String("template", "", "naming convention override for this name")
+	var expiresAt *string
+	if command == "claim" {
+		expiresAt = fs.String("expires-at", "", "RFC 3339 time after which the claim may be released")
+	}
+	output := fs.String("output", "text", "output format: text, json or github (step outputs in GitHub Actions)")
+	outputName := fs.String("output-name", "name", "step output name for -output github")
+	envVar := fs.String("env-var", "", "also export the name as this environment variable for later steps with -output github")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *output {
+	case "text", "json", "github":
+	default:
+		return fmt.Errorf("unsupported output %q", *output)
+	}
+	if req.ResourceType == "" || req.Region == "" || req.Environment == "" {
+		return errors.New("-resource-type, -region and -environment are required")
+	}
+
+	req.Project = optional(*project)
+	req.Purpose = optional(*purpose)
+	req.System = optional(*system)
+	req.Subsystem = optional(*subsystem)
+	req.Index = optional(*index)
+	req.Template = optional(*template)
+	if expiresAt != nil {
+		req.ExpiresAt = optional(*expiresAt)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	var resp *provider.ClaimNameResponse
+	if command == "claim" {
+		resp, err = client.ClaimName(ctx, req)
+	} else {
+		resp, err = client.PreviewName(ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp)
+	case "github":
+		return writeGitHubOutputs(resp.Name, *outputName, *envVar)
+	}
+	fmt.Println(resp.Name)
+	return nil
+}
+
+// optional returns nil for empty flag values so they are left out of the
+// request.
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// writeGitHubOutputs appends the name to the step outputs file named by
+// GITHUB_OUTPUT and, when envVar is set, to the GITHUB_ENV file read by later
+// steps.
+func writeGitHubOutputs(name, outputName, envVar string) error {
+	if err := appendGitHubFile("GITHUB_OUTPUT", outputName, name); err != nil {
+		return err
+	}
+	if envVar == "" {
+		return nil
+	}
+	return appendGitHubFile("GITHUB_ENV", envVar, name)
+}
+
+func appendGitHubFile(variable, key, value string) error {
+	path := os.Getenv(variable)
+	if path == "" {
+		return fmt.Errorf("%s is not set; -output github only works in GitHub Actions", variable)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeGitHubEntry(f, key, value); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeGitHubEntry writes key=value in the format GitHub Actions reads from
+// its output and environment files.
+func writeGitHubEntry(w io.Writer, key, value string) error {
+	if key == "" || strings.ContainsAny(key, "=\r\n") {
+		return fmt.Errorf("invalid GitHub output name %q", key)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("value for %s must be a single line", key)
+	}
+	_, err := fmt.Fprintf(w, "%s=%s\n", key, value)
+	return err
+}
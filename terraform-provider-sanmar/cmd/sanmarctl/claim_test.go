@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHubOutputs(t *testing.T) {
+	dir := t.TempDir()
+	outputs := filepath.Join(dir, "output")
+	env := filepath.Join(dir, "env")
+	if err := os.WriteFile(outputs, []byte("other=value\n"), 0o644); err != nil {
+		t.Fatalf("write outputs: %v", err)
+	}
+	t.Setenv("GITHUB_OUTPUT", outputs)
+	t.Setenv("GITHUB_ENV", env)
+
+	if err := writeGitHubOutputs("wus2-prd-kv-atlas", "key_vault_name", "KEY_VAULT_NAME"); err != nil {
+		t.Fatalf("writeGitHubOutputs: %v", err)
+	}
+
+	content, err := os.ReadFile(outputs)
+	if err != nil {
+		t.Fatalf("read outputs: %v", err)
+	}
+	if string(content) != "other=value\nkey_vault_name=wus2-prd-kv-atlas\n" {
+		t.Fatalf("unexpected outputs file:\n%s", content)
+	}
+	content, err = os.ReadFile(env)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if string(content) != "KEY_VAULT_NAME=wus2-prd-kv-atlas\n" {
+		t.Fatalf("unexpected env file:\n%s", content)
+	}
+
+	t.Setenv("GITHUB_OUTPUT", "")
+	if err := writeGitHubOutputs("wus2-prd-kv-atlas", "name", ""); err == nil || !strings.Contains(err.Error(), "GITHUB_OUTPUT is not set") {
+		t.Fatalf("expected missing GITHUB_OUTPUT error, got %v", err)
+	}
+}
+
+func TestWriteGitHubEntryRejectsInjection(t *testing.T) {
+	var b strings.Builder
+	if err := writeGitHubEntry(&b, "name", "a\nINJECTED=1"); err == nil {
+		t.Fatal("expected multi-line value to be rejected")
+	}
+	if err := writeGitHubEntry(&b, "na=me", "a"); err == nil {
+		t.Fatal("expected invalid key to be rejected")
+	}
+	if b.Len() != 0 {
+		t.Fatalf("nothing should be written, got %q", b.String())
+	}
+}
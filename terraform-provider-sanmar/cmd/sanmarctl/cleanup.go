@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+// cleanupOptions selects the claims cleanup releases.
+type cleanupOptions struct {
+	workspace string
+	filter    provider.ClaimFilter
+	reason    string
+	dryRun    bool
+}
+
+func runCleanup(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("cleanup", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	var opts cleanupOptions
+	fs.StringVar(&opts.workspace, "workspace", "", "release claims tagged with this cleanup_workspace (required)")
+	statePath := fs.String("state", "", "Terraform state file of the workspace, as written by terraform state pull (required)")
+	fs.StringVar(&opts.filter.Environment, "environment", "", "only release claims in this environment")
+	fs.StringVar(&opts.filter.Region, "region", "", "only release claims in this region")
+	fs.StringVar(&opts.reason, "reason", "", "release reason recorded in the audit history (default names the workspace)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "list the claims that would be released without releasing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.workspace == "" {
+		return errors.New("-workspace is required")
+	}
+	if *statePath == "" {
+		return errors.New("-state is required, so claims still tracked in state are never released")
+	}
+	if opts.reason == "" {
+		opts.reason = "Released by sanmarctl cleanup for workspace " + opts.workspace
+	}
+
+	data, err := os.ReadFile(*statePath)
+	if err != nil {
+		return err
+	}
+	tracked, err := readStateClaims(data)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *statePath, err)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	return releaseStragglers(ctx, client, os.Stdout, tracked, opts)
+}
+
+// releaseStragglers releases the active claims tagged with opts.workspace
+// that are not among the claims in state, and reports each one to w.
+func releaseStragglers(ctx context.Context, client *provider.APIClient, w io.Writer, tracked []stateClaim, opts cleanupOptions) error {
+	return client.ReleaseStragglers(ctx, cleanupWalk(tracked, opts), func(outcome provider.StragglerOutcome) {
+		switch {
+		case outcome.Err != nil:
+			fmt.Fprintf(w, "failed to release %s: %v\n", outcome.Claim.Identity(), outcome.Err)
+		case opts.dryRun:
+			fmt.Fprintf(w, "would release %s\n", outcome.Claim.Identity())
+		default:
+			fmt.Fprintf(w, "released %s\n", outcome.Claim.Identity())
+		}
+	})
+}
+
+// cleanupWalk selects the claims tagged with opts.workspace that are not
+// among the claims in state.
+func cleanupWalk(tracked []stateClaim, opts cleanupOptions) provider.StragglerWalk {
+	inState := make(map[string]bool, len(tracked))
+	for _, claim := range tracked {
+		inState[stragglerKey(claim.attr("region"), claim.attr("environment"), claim.attr("name"))] = true
+	}
+
+	return provider.StragglerWalk{
+		Filter: opts.filter,
+		Candidate: func(claim provider.ClaimSummary) bool {
+			return !inState[stragglerKey(claim.Region, claim.Environment, claim.Name)]
+		},
+		Match: func(record *provider.AuditRecord) bool {
+			return provider.TaggedWith(record, opts.workspace)
+		},
+		Reason: opts.reason,
+		DryRun: opts.dryRun,
+	}
+}
+
+// stragglerKey identifies a claim regardless of the case the service or the
+// state used.
+func stragglerKey(region, environment, name string) string {
+	return strings.ToLower(region + "/" + environment + "/" + name)
+}
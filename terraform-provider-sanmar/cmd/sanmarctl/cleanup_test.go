@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+const cleanupState = `{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "sanmar_claim", "name": "kept", "instances": [
+      {"attributes": {"name": "VMWUS2DEVKEPT", "region": "wus2", "environment": "dev"}}
+    ]}
+  ]
+}`
+
+// auditRecord decodes an active claim's audit lookup body in the shape the
+// service returns it, with custom metadata as a JSON string.
+func auditRecord(t *testing.T, metadata map[string]string) *provider.AuditRecord {
+	t.Helper()
+	nested, _ := json.Marshal(metadata)
+	body, _ := json.Marshal(map[string]any{
+		"name": "vmwus2devleaked", "resource_type": "virtual_machine", "in_use": true,
+		"claimed_by": "ci@sanmar.com", "region": "wus2", "environment": "dev",
+		"slug": "vm", "project": nil, "metadata": string(nested),
+	})
+	var record provider.AuditRecord
+	if err := json.Unmarshal(body, &record); err != nil {
+		t.Fatalf("decode audit record: %v", err)
+	}
+	return &record
+}
+
+// The walk itself is tested in the provider package; these cover what
+// cleanup selects.
+func TestCleanupWalk(t *testing.T) {
+	tracked, err := readStateClaims([]byte(cleanupState))
+	if err != nil {
+		t.Fatalf("readStateClaims: %v", err)
+	}
+	walk := cleanupWalk(tracked, cleanupOptions{workspace: "Payments-Dev", reason: "cleanup", dryRun: true})
+	if walk.Reason != "cleanup" || !walk.DryRun {
+		t.Fatalf("unexpected walk: %#v", walk)
+	}
+
+	if walk.Candidate(provider.ClaimSummary{Name: "vmwus2devkept", Region: "WUS2", Environment: "dev"}) {
+		t.Fatal("claims in state must never be candidates")
+	}
+	if !walk.Candidate(provider.ClaimSummary{Name: "vmwus2devleaked", Region: "wus2", Environment: "dev"}) {
+		t.Fatal("expected an untracked claim to be a candidate")
+	}
+
+	if !walk.Match(auditRecord(t, map[string]string{provider.CleanupMetadataKey: "payments-dev"})) {
+		t.Fatal("expected the nested tag to match regardless of case")
+	}
+	if walk.Match(auditRecord(t, map[string]string{provider.CleanupMetadataKey: "another-workspace"})) {
+		t.Fatal("claims tagged with another workspace must not match")
+	}
+	if walk.Match(auditRecord(t, map[string]string{"owner": "team"})) {
+		t.Fatal("untagged claims must not match")
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func runExport(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	var filter provider.ClaimFilter
+	fs.StringVar(&filter.Project, "project", "", "only export claims for this project")
+	fs.StringVar(&filter.Environment, "environment", "", "only export claims for this environment")
+	fs.StringVar(&filter.Region, "region", "", "only export claims for this region")
+	fs.StringVar(&filter.Purpose, "purpose", "", "only export claims for this purpose")
+	fs.StringVar(&filter.User, "user", "", "only export claims made by this user")
+	format := fs.String("format", "import-blocks", "output format: import-blocks, csv or json")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "import-blocks", "csv", "json":
+	default:
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	claims, err := client.ListClaims(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list claims: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "csv":
+		return writeClaimsCSV(w, claims)
+	case "json":
+		return writeClaimsJSON(w, claims)
+	}
+
+	// The audit history omits metadata, so fetch each claim's record to
+	// generate complete resource blocks.
+	records := make([]provider.AuditRecord, 0, len(claims))
+	for _, claim := range claims {
+		record, err := client.GetAudit(ctx, claim.Region, claim.Environment, claim.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read claim %s: %w", claim.Identity(), err)
+		}
+		if record == nil || !record.InUse {
+			continue
+		}
+		records = append(records, *record)
+	}
+	return writeImportBlocks(w, records)
+}
+
+// claimColumns are the CSV columns, matching the JSON field names.
+var claimColumns = []string{"name", "resource_type", "region", "environment", "project", "purpose", "slug", "system", "subsystem", "index", "claimed_by", "claimed_at"}
+
+// writeClaimsCSV writes one row per claim with a header line.
+func writeClaimsCSV(w io.Writer, claims []provider.ClaimSummary) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(claimColumns); err != nil {
+		return err
+	}
+	for _, c := range claims {
+		row := []string{c.Name, c.ResourceType, c.Region, c.Environment, c.Project, c.Purpose, c.Slug, c.System, c.Subsystem, c.Index, c.ClaimedBy, c.ClaimedAt}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeClaimsJSON writes the claims as an indented JSON array.
+func writeClaimsJSON(w io.Writer, claims []provider.ClaimSummary) error {
+	if claims == nil {
+		claims = []provider.ClaimSummary{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(claims)
+}
+
+// writeImportBlocks renders an import block and a matching sanmar_claim
+// resource for each record.
+func writeImportBlocks(w io.Writer, records []provider.AuditRecord) error {
+	var b strings.Builder
+	labels := map[string]int{}
+	for i, record := range records {
+		label := resourceLabel(record.Name)
+		if labels[label]++; labels[label] > 1 {
+			label = fmt.Sprintf("%s_%d", label, labels[label])
+		}
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "import {\n  to = sanmar_claim.%s\n  id = %q\n}\n\n", label, record.Region+"/"+record.Environment+"/"+record.Name)
+		fmt.Fprintf(&b, "resource \"sanmar_claim\" %q {\n", label)
+		writeAttributes(&b, "  ", [][2]string{
+			{"resource_type", record.Resource},
+			{"region", record.Region},
+			{"environment", record.Environment},
+		})
+		segments := [][2]string{
+			{"project", record.Project},
+			{"purpose", record.Purpose},
+			{"system", record.System},
+			{"subsystem", record.Subsystem},
+			{"index", record.Index},
+		}
+		for _, segment := range segments {
+			if segment[1] != "" {
+				b.WriteString("\n  segments = {\n")
+				writeAttributes(&b, "    ", segments)
+				b.WriteString("  }\n")
+				break
+			}
+		}
+		if len(record.Metadata) > 0 {
+			keys := make([]string, 0, len(record.Metadata))
+			for k := range record.Metadata {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			entries := make([][2]string, 0, len(keys))
+			for _, k := range keys {
+				entries = append(entries, [2]string{k, record.Metadata[k]})
+			}
+			b.WriteString("\n  metadata = {\n")
+			writeAttributes(&b, "    ", entries)
+			b.WriteString("  }\n")
+		}
+		b.WriteString("}\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeAttributes writes aligned key = "value" lines, skipping empty values.
+func writeAttributes(b *strings.Builder, indent string, attrs [][2]string) {
+	width := 0
+	for _, a := range attrs {
+		if a[1] != "" && len(a[0]) > width {
+			width = len(a[0])
+		}
+	}
+	for _, a := range attrs {
+		if a[1] == "" {
+			continue
+		}
+		fmt.Fprintf(b, "%s%-*s = %q\n", indent, width, a[0], a[1])
+	}
+}
+
+// resourceLabel turns a generated name into a valid Terraform resource label.
+func resourceLabel(name string) string {
+	label := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '_'
+		}
+	}, name)
+	if label == "" || (label[0] >= '0' && label[0] <= '9') || label[0] == '-' {
+		label = "claim_" + label
+	}
+	return label
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func TestWriteImportBlocks(t *testing.T) {
+	records := []provider.AuditRecord{
+		{
+			Name:        "kvwus2prdatlas",
+			Resource:    "key_vault",
+			Region:      "wus2",
+			Environment: "prd",
+			Project:     "atlas",
+			Metadata:    map[string]string{"owner": "finops"},
+		},
+	}
+
+	var b strings.Builder
+	if err := writeImportBlocks(&b, records); err != nil {
+		t.Fatalf("writeImportBlocks: %v", err)
+	}
+
+	want := `import {
+  to = sanmar_claim.kvwus2prdatlas
+  id = "wus2/prd/kvwus2prdatlas"
+}
+
+resource "sanmar_claim" "kvwus2prdatlas" {
+  resource_type = "key_vault"
+  region        = "wus2"
+  environment   = "prd"
+
+  segments = {
+    project = "atlas"
+  }
+
+  metadata = {
+    owner = "finops"
+  }
+}
+`
+	if b.String() != want {
+		t.Fatalf("unexpected output:\n%s", b.String())
+	}
+}
+
+func TestResourceLabel(t *testing.T) {
+	cases := map[string]string{
+		"kvwus2prdatlas": "kvwus2prdatlas",
+		"KV.Atlas":       "kv_atlas",
+		"01app":          "claim_01app",
+	}
+	for in, want := range cases {
+		if got := resourceLabel(in); got != want {
+			t.Fatalf("resourceLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteClaimsCSV(t *testing.T) {
+	claims := []provider.ClaimSummary{
+		{Name: "wus2prdkvsanmaratlas01", ResourceType: "key_vault", Region: "wus2", Environment: "prd", Project: "atlas", Slug: "kv", System: "atlas", Index: "01", ClaimedBy: "alice", ClaimedAt: "2026-01-02T03:04:05"},
+	}
+
+	var b strings.Builder
+	if err := writeClaimsCSV(&b, claims); err != nil {
+		t.Fatalf("writeClaimsCSV: %v", err)
+	}
+
+	want := "name,resource_type,region,environment,project,purpose,slug,system,subsystem,index,claimed_by,claimed_at\n" +
+		"wus2prdkvsanmaratlas01,key_vault,wus2,prd,atlas,,kv,atlas,,01,alice,2026-01-02T03:04:05\n"
+	if b.String() != want {
+		t.Fatalf("unexpected output:\n%s", b.String())
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+// gcOptions selects the claims gc releases.
+type gcOptions struct {
+	filter  provider.ClaimFilter
+	markers []metadataMarker
+	cutoff  time.Time
+	reason  string
+	dryRun  bool
+}
+
+// metadataMarker is a key=value pair in claim metadata that marks a claim as
+// short-lived.
+type metadataMarker struct {
+	key   string
+	value string
+}
+
+func runGC(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("gc", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	var opts gcOptions
+	fs.StringVar(&opts.filter.Environment, "environment", "", "only release claims in this environment")
+	fs.StringVar(&opts.filter.Region, "region", "", "only release claims in this region")
+	fs.StringVar(&opts.filter.Project, "project", "", "only release claims for this project")
+	olderThan := fs.String("older-than", "30d", "release claims made longer ago than this, such as 30d or 72h")
+	markers := fs.String("marker", "lifecycle=ci,lifecycle=preview", "comma-separated key=value metadata pairs; claims matching any are eligible")
+	fs.StringVar(&opts.reason, "reason", "Released by sanmarctl gc", "release reason recorded in the audit history")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "list the claims that would be released without releasing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		return err
+	}
+	opts.cutoff = time.Now().Add(-age)
+	if opts.markers, err = parseMarkers(*markers); err != nil {
+		return err
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	return collectGarbage(ctx, client, os.Stdout, opts)
+}
+
+// collectGarbage releases the claims matching opts and reports each one to w.
+func collectGarbage(ctx context.Context, client *provider.APIClient, w io.Writer, opts gcOptions) error {
+	return client.ReleaseStragglers(ctx, gcWalk(w, opts), func(outcome provider.StragglerOutcome) {
+		switch {
+		case outcome.Err != nil:
+			fmt.Fprintf(w, "failed to release %s: %v\n", outcome.Claim.Identity(), outcome.Err)
+		case opts.dryRun:
+			fmt.Fprintf(w, "would release %s (claimed %s)\n", outcome.Claim.Identity(), outcome.Claim.ClaimedAt)
+		default:
+			fmt.Fprintf(w, "released %s (claimed %s)\n", outcome.Claim.Identity(), outcome.Claim.ClaimedAt)
+		}
+	})
+}
+
+// gcWalk selects the claims made before opts.cutoff whose metadata matches
+// one of opts.markers. Claims with unreadable claim times are reported to w
+// and skipped.
+func gcWalk(w io.Writer, opts gcOptions) provider.StragglerWalk {
+	return provider.StragglerWalk{
+		Filter: opts.filter,
+		Candidate: func(claim provider.ClaimSummary) bool {
+			claimedAt, err := parseClaimedAt(claim.ClaimedAt)
+			if err != nil {
+				fmt.Fprintf(w, "skipped %s: %v\n", claim.Identity(), err)
+				return false
+			}
+			return claimedAt.Before(opts.cutoff)
+		},
+		Match: func(record *provider.AuditRecord) bool {
+			return matchesMarker(record.Metadata, opts.markers)
+		},
+		Reason: opts.reason,
+		DryRun: opts.dryRun,
+	}
+}
+
+// parseAge parses a Go duration, also accepting a whole number of days such as 30d.
+func parseAge(value string) (time.Duration, error) {
+	var age time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid -older-than %q", value)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if age, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("invalid -older-than %q", value)
+		}
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("-older-than must be positive, got %q", value)
+	}
+	return age, nil
+}
+
+// parseMarkers parses comma-separated key=value pairs.
+func parseMarkers(value string) ([]metadataMarker, error) {
+	var markers []metadataMarker
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid -marker %q, expected key=value", pair)
+		}
+		markers = append(markers, metadataMarker{key: key, value: val})
+	}
+	if len(markers) == 0 {
+		return nil, fmt.Errorf("-marker must list at least one key=value pair")
+	}
+	return markers, nil
+}
+
+// matchesMarker reports whether metadata, as decoded from a claim's record,
+// holds any of the markers. The service may change the case of stored
+// metadata, so keys and values are compared without regard to case.
+func matchesMarker(metadata map[string]string, markers []metadataMarker) bool {
+	for key, value := range metadata {
+		for _, m := range markers {
+			if strings.EqualFold(key, m.key) && strings.EqualFold(value, m.value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseClaimedAt parses audit timestamps, which the service writes in ISO 8601
+// with or without a UTC offset. Timestamps without an offset are UTC.
+func parseClaimedAt(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02T15:04:05.999999999", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognised claim time %q", value)
+	}
+	return t, nil
+}
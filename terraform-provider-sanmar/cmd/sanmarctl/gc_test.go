@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func TestGCWalk(t *testing.T) {
+	markers, _ := parseMarkers("lifecycle=ci,lifecycle=preview")
+	var skipped strings.Builder
+	walk := gcWalk(&skipped, gcOptions{
+		filter:  provider.ClaimFilter{Environment: "dev"},
+		markers: markers,
+		cutoff:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	if !walk.Candidate(provider.ClaimSummary{Name: "app-pr-101", Region: "wus2", Environment: "dev", ClaimedAt: "2025-01-01T00:00:00"}) {
+		t.Fatal("expected an old claim to be a candidate")
+	}
+	if walk.Candidate(provider.ClaimSummary{Name: "app-pr-202", Region: "wus2", Environment: "dev", ClaimedAt: "2025-03-30T00:00:00+00:00"}) {
+		t.Fatal("claims made after the cutoff must not be candidates")
+	}
+	if walk.Candidate(provider.ClaimSummary{Name: "app-pr-303", Region: "wus2", Environment: "dev", ClaimedAt: "yesterday"}) ||
+		!strings.HasPrefix(skipped.String(), "skipped wus2/dev/app-pr-303:") {
+		t.Fatalf("expected an unreadable claim time to be skipped, got %q", skipped.String())
+	}
+
+	if !walk.Match(auditRecord(t, map[string]string{"Lifecycle": "Preview"})) {
+		t.Fatal("expected the nested marker to match regardless of case")
+	}
+	if walk.Match(auditRecord(t, map[string]string{"lifecycle": "shared"})) {
+		t.Fatal("claims without a marker must not match")
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	cases := map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"72h": 72 * time.Hour,
+	}
+	for in, want := range cases {
+		if got, err := parseAge(in); err != nil || got != want {
+			t.Fatalf("parseAge(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0d", "-1h", "monthly"} {
+		if _, err := parseAge(in); err == nil {
+			t.Fatalf("parseAge(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+// indexScope is the set of segments that, apart from the index, make up a
+// name. Indices are counted separately for each scope.
+type indexScope struct {
+	ResourceType string `json:"resource_type"`
+	Region       string `json:"region"`
+	Environment  string `json:"environment"`
+	Project      string `json:"project,omitempty"`
+	System       string `json:"system,omitempty"`
+	Subsystem    string `json:"subsystem,omitempty"`
+}
+
+func (s indexScope) String() string {
+	parts := []string{s.ResourceType, s.Region, s.Environment, s.Project, s.System, s.Subsystem}
+	for i, part := range parts {
+		if part == "" {
+			parts[i] = "-"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// indexUsage reports how a scope's index space is used. Free indices were
+// claimed and released and can be reused; gaps were never claimed. Retired
+// indices can never be claimed again.
+type indexUsage struct {
+	indexScope
+	Used    []int `json:"used"`
+	Free    []int `json:"free"`
+	Gaps    []int `json:"gaps"`
+	Retired []int `json:"retired"`
+	// Next is the lowest index available for a new claim.
+	Next int `json:"next"`
+}
+
+func runIndices(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("indices", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	var filter provider.ClaimFilter
+	fs.StringVar(&filter.Project, "project", "", "only report indices for this project")
+	fs.StringVar(&filter.Environment, "environment", "", "only report indices in this environment")
+	fs.StringVar(&filter.Region, "region", "", "only report indices in this region")
+	resourceType := fs.String("resource-type", "", "only report indices for this resource type")
+	format := fs.String("format", "text", "output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	events, err := client.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	// The audit history omits the index, so fetch the record of every name
+	// the scope has used.
+	var records []provider.AuditRecord
+	for _, event := range latestEvents(events) {
+		if *resourceType != "" && event.ResourceType != *resourceType {
+			continue
+		}
+		record, err := client.GetAudit(ctx, event.Region, event.Environment, event.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read %s/%s/%s: %w", event.Region, event.Environment, event.Name, err)
+		}
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+
+	usage := summarizeIndices(records)
+	if *format == "json" {
+		if usage == nil {
+			usage = []indexUsage{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usage)
+	}
+	return writeIndexUsage(os.Stdout, usage)
+}
+
+// latestEvents keeps the newest event per name. Events must be ordered
+// newest first.
+func latestEvents(events []provider.AuditEvent) []provider.AuditEvent {
+	type identity struct{ region, environment, name string }
+	seen := make(map[identity]bool, len(events))
+	var latest []provider.AuditEvent
+	for _, event := range events {
+		id := identity{event.Region, event.Environment, event.Name}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		latest = append(latest, event)
+	}
+	return latest
+}
+
+// summarizeIndices groups records by scope and reports the index space of
+// each, sorted by scope. Records without a numeric index are ignored.
+func summarizeIndices(records []provider.AuditRecord) []indexUsage {
+	byScope := map[indexScope]*indexUsage{}
+	for _, record := range records {
+		index, err := strconv.Atoi(record.Index)
+		if err != nil || index < 1 {
+			continue
+		}
+		scope := indexScope{
+			ResourceType: record.Resource,
+			Region:       record.Region,
+			Environment:  record.Environment,
+			Project:      record.Project,
+			System:       record.System,
+			Subsystem:    record.Subsystem,
+		}
+		usage, ok := byScope[scope]
+		if !ok {
+			usage = &indexUsage{indexScope: scope}
+			byScope[scope] = usage
+		}
+		switch {
+		case record.Retired:
+			usage.Retired = append(usage.Retired, index)
+		case record.InUse:
+			usage.Used = append(usage.Used, index)
+		default:
+			usage.Free = append(usage.Free, index)
+		}
+	}
+
+	summaries := make([]indexUsage, 0, len(byScope))
+	for _, usage := range byScope {
+		usage.finish()
+		summaries = append(summaries, *usage)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].String() < summaries[j].String()
+	})
+	return summaries
+}
+
+// finish sorts the index lists and fills in the gaps and next index.
+func (u *indexUsage) finish() {
+	known := map[int]bool{}
+	highest := 0
+	// Empty rather than nil, so JSON output has [] instead of null.
+	for _, list := range []*[]int{&u.Used, &u.Free, &u.Gaps, &u.Retired} {
+		if *list == nil {
+			*list = []int{}
+		}
+		sort.Ints(*list)
+		for _, index := range *list {
+			known[index] = true
+			if index > highest {
+				highest = index
+			}
+		}
+	}
+
+	for index := 1; index < highest; index++ {
+		if !known[index] {
+			u.Gaps = append(u.Gaps, index)
+		}
+	}
+
+	u.Next = highest + 1
+	if len(u.Gaps) > 0 {
+		u.Next = u.Gaps[0]
+	}
+	if len(u.Free) > 0 && u.Free[0] < u.Next {
+		u.Next = u.Free[0]
+	}
+}
+
+// writeIndexUsage writes one row per scope with the indices as ranges.
+func writeIndexUsage(w io.Writer, usage []indexUsage) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCOPE\tUSED\tFREE\tGAPS\tRETIRED\tNEXT")
+	for _, u := range usage {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", u.indexScope, formatRanges(u.Used), formatRanges(u.Free), formatRanges(u.Gaps), formatRanges(u.Retired), u.Next)
+	}
+	return tw.Flush()
+}
+
+// formatRanges writes sorted indices as comma-separated ranges, such as
+// "1-3,7", or "-" when there are none.
+func formatRanges(indices []int) string {
+	if len(indices) == 0 {
+		return "-"
+	}
+	var parts []string
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && indices[j+1] == indices[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(indices[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", indices[i], indices[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
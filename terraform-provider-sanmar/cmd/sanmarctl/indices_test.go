@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func TestSummarizeIndices(t *testing.T) {
+	record := func(index string, inUse, retired bool) provider.AuditRecord {
+		return provider.AuditRecord{Resource: "key_vault", Region: "wus2", Environment: "prd", System: "erp", Index: index, InUse: inUse, Retired: retired}
+	}
+	records := []provider.AuditRecord{
+		record("01", true, false),
+		record("02", false, false),
+		record("03", true, false),
+		record("06", true, false),
+		record("05", false, true),
+		record("", true, false),
+		{Resource: "key_vault", Region: "eus2", Environment: "prd", System: "erp", Index: "01", InUse: true},
+	}
+
+	usage := summarizeIndices(records)
+	if len(usage) != 2 {
+		t.Fatalf("expected two scopes, got %+v", usage)
+	}
+	if usage[0].Region != "eus2" || usage[0].Next != 2 || len(usage[0].Gaps) != 0 {
+		t.Fatalf("unexpected eus2 usage: %+v", usage[0])
+	}
+
+	got := usage[1]
+	if got.String() != "key_vault/wus2/prd/-/erp/-" {
+		t.Fatalf("unexpected scope %s", got.String())
+	}
+	want := indexUsage{indexScope: got.indexScope, Used: []int{1, 3, 6}, Free: []int{2}, Gaps: []int{4}, Retired: []int{5}, Next: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("summarizeIndices = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteIndexUsage(t *testing.T) {
+	usage := []indexUsage{{
+		indexScope: indexScope{ResourceType: "key_vault", Region: "wus2", Environment: "prd"},
+		Used:       []int{1, 2, 3, 7},
+		Free:       []int{},
+		Gaps:       []int{4, 5, 6},
+		Retired:    []int{},
+		Next:       4,
+	}}
+
+	var b strings.Builder
+	if err := writeIndexUsage(&b, usage); err != nil {
+		t.Fatalf("writeIndexUsage: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[1]), " ") != "key_vault/wus2/prd/-/-/- 1-3,7 - 4-6 - 4" {
+		t.Fatalf("unexpected report:\n%s", b.String())
+	}
+}
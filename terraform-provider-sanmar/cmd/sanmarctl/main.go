@@ -0,0 +1,90 @@
+// Command sanmarctl is a command line companion to the SanMar naming provider
+// for bulk operations against the naming service.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+const usage = `Usage: sanmarctl <command> [flags]
+
+Commands:
+  claim       Claim a name and print it or write it as step outputs
+  cleanup     Release a workspace's tagged claims that are no longer in its state
+  export      Export claims for a scope as import blocks, CSV or JSON
+  gc          Release CI and preview claims older than a threshold
+  indices     Report used, free and missing indices per naming scope
+  policy      Export naming rules and slugs as an OPA bundle, Sentinel mock data or Azure Policy
+  preview     Print the name a claim would get without claiming it
+  purge       Delete the record of a released name
+  render      Preview or claim names and write them as a Bicep parameters file
+  renew       Set a new expiry on a claim
+  sync-slugs  Refresh the service's slug table from its upstream source
+  transfer    Move a claim to a new owner
+  verify      Check the claims in a Terraform state file against the service and Azure
+  watch       Follow claim and release events as they happen
+
+Run "sanmarctl <command> -h" for command flags.
+`
+
+type command func(ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"claim":      runClaim,
+	"cleanup":    runCleanup,
+	"export":     runExport,
+	"gc":         runGC,
+	"indices":    runIndices,
+	"policy":     runPolicy,
+	"preview":    runPreview,
+	"purge":      runPurge,
+	"render":     runRender,
+	"renew":      runRenew,
+	"sync-slugs": runSyncSlugs,
+	"transfer":   runTransfer,
+	"verify":     runVerify,
+	"watch":      runWatch,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err := cmd(context.Background(), os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "sanmarctl:", err)
+		os.Exit(1)
+	}
+}
+
+// clientFlags registers the connection flags shared by every command.
+type clientFlags struct {
+	endpoint string
+	scope    string
+}
+
+func (f *clientFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.endpoint, "endpoint", os.Getenv("SANMAR_ENDPOINT"), "naming service base URL (env SANMAR_ENDPOINT)")
+	fs.StringVar(&f.scope, "scope", os.Getenv("SANMAR_SCOPE"), "AAD scope to request tokens for (env SANMAR_SCOPE)")
+}
+
+func (f *clientFlags) client(ctx context.Context) (*provider.APIClient, error) {
+	return provider.NewAPIClient(ctx, f.endpoint, f.scope, provider.RetryConfig{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	})
+}
@@ -0,0 +1,53 @@
+# Validates planned sanmar_claim names against the naming rules exported by
+# "sanmarctl policy". Evaluate data.sanmar.naming.deny with the JSON plan
+# ("terraform show -json tfplan") as input.
+package sanmar.naming
+
+import rego.v1
+
+claims contains after if {
+	some change in input.resource_changes
+	change.type == "sanmar_claim"
+	after := change.change.after
+	is_string(after.name)
+}
+
+rule(resource_type) := data.sanmar.rules[resource_type]
+
+rule(resource_type) := data.sanmar.rules["default"] if {
+	not data.sanmar.rules[resource_type]
+}
+
+deny contains msg if {
+	some claim in claims
+	r := rule(claim.resource_type)
+	count(claim.name) > r.max_length
+	msg := sprintf("%s: name %q is longer than %d characters", [claim.resource_type, claim.name, r.max_length])
+}
+
+deny contains msg if {
+	some claim in claims
+	r := rule(claim.resource_type)
+	r.require_sanmar_prefix
+	not has_sanmar_prefix(claim.name, r)
+	msg := sprintf("%s: name %q is missing the sanmar prefix", [claim.resource_type, claim.name])
+}
+
+# The service prepends "sanmar" to names unless the rule's template places
+# it with {sanmar_prefix}, as compact templates do after the slug.
+has_sanmar_prefix(name, r) if {
+	not contains(r.name_template, "{sanmar_prefix}")
+	startswith(name, "sanmar")
+}
+
+has_sanmar_prefix(name, r) if {
+	contains(r.name_template, "{sanmar_prefix}")
+	contains(name, "sanmar")
+}
+
+deny contains msg if {
+	some claim in claims
+	slug := rule(claim.resource_type).slug
+	not contains(claim.name, slug)
+	msg := sprintf("%s: name %q does not contain slug %q", [claim.resource_type, claim.name, slug])
+}
@@ -0,0 +1,77 @@
+# Tests for naming.rego; run with "opa test cmd/sanmarctl".
+package sanmar.naming_test
+
+import rego.v1
+
+import data.sanmar.naming
+
+rules := {
+	"default": {
+		"max_length": 80,
+		"require_sanmar_prefix": true,
+		"name_template": "{region}-{environment}-{slug}-{system}{subsystem_segment}{index_segment}",
+		"slug": "",
+	},
+	"key_vault": {
+		"max_length": 24,
+		"require_sanmar_prefix": true,
+		"name_template": "{region}{environment}{slug}{sanmar_prefix}{system}{subsystem}{index}",
+		"slug": "kv",
+	},
+	"app_service": {
+		"max_length": 60,
+		"require_sanmar_prefix": true,
+		"name_template": "{region}-{environment}-{slug}-{system}{subsystem_segment}{index_segment}",
+		"slug": "app",
+	},
+}
+
+plan(claims) := {"resource_changes": [change |
+	some claim in claims
+	change := {"type": "sanmar_claim", "change": {"after": claim}}
+]}
+
+test_valid_names_pass if {
+	count(naming.deny) == 0 with input as plan([
+		{"resource_type": "app_service", "name": "sanmar-wus2-prd-app-erp-01"},
+		{"resource_type": "key_vault", "name": "wus2prdkvsanmarerp01"},
+	])
+		with data.sanmar.rules as rules
+}
+
+test_long_name_denied if {
+	naming.deny == {`key_vault: name "wus2prdkvsanmarinventory01" is longer than 24 characters`} with input as plan([{"resource_type": "key_vault", "name": "wus2prdkvsanmarinventory01"}])
+		with data.sanmar.rules as rules
+}
+
+test_missing_prefix_denied if {
+	naming.deny == {`app_service: name "wus2-prd-app-erp-01" is missing the sanmar prefix`} with input as plan([{"resource_type": "app_service", "name": "wus2-prd-app-erp-01"}])
+		with data.sanmar.rules as rules
+}
+
+# Compact templates place the prefix after the slug rather than in front.
+test_compact_prefix_after_slug if {
+	naming.deny == {`key_vault: name "wus2prdkverp01" is missing the sanmar prefix`} with input as plan([{"resource_type": "key_vault", "name": "wus2prdkverp01"}])
+		with data.sanmar.rules as rules
+}
+
+test_missing_slug_denied if {
+	naming.deny == {`app_service: name "sanmar-wus2-prd-web-erp-01" does not contain slug "app"`} with input as plan([{"resource_type": "app_service", "name": "sanmar-wus2-prd-web-erp-01"}])
+		with data.sanmar.rules as rules
+}
+
+test_unknown_type_uses_default_rule if {
+	naming.deny == {`function_app: name "wus2-prd-func-erp" is missing the sanmar prefix`} with input as plan([{"resource_type": "function_app", "name": "wus2-prd-func-erp"}])
+		with data.sanmar.rules as rules
+}
+
+# Names only known after apply are not checked.
+test_unknown_names_skipped if {
+	count(naming.deny) == 0 with input as plan([{"resource_type": "key_vault"}])
+		with data.sanmar.rules as rules
+}
+
+test_other_resources_ignored if {
+	count(naming.deny) == 0 with input as {"resource_changes": [{"type": "azurerm_key_vault", "change": {"after": {"resource_type": "key_vault", "name": "x"}}}]}
+		with data.sanmar.rules as rules
+}
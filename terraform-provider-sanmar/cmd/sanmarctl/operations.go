@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+// claimArg is a claim identity given as "<region>/<environment>/<name>".
+type claimArg struct {
+	region      string
+	environment string
+	name        string
+}
+
+func parseClaimArg(fs *flag.FlagSet) (claimArg, error) {
+	if fs.NArg() != 1 {
+		return claimArg{}, errors.New("expected one claim as <region>/<environment>/<name>")
+	}
+	parts := strings.Split(fs.Arg(0), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return claimArg{}, fmt.Errorf("expected <region>/<environment>/<name>, got %q", fs.Arg(0))
+	}
+	return claimArg{region: parts[0], environment: parts[1], name: parts[2]}, nil
+}
+
+func runRenew(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("renew", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+	expiresAt := fs.String("expires-at", "", "new RFC 3339 expiry; empty removes the expiry")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	claim, err := parseClaimArg(fs)
+	if err != nil {
+		return err
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.RenewClaim(ctx, provider.RenewRequest{
+		Name:        claim.name,
+		Region:      claim.region,
+		Environment: claim.environment,
+		ExpiresAt:   *expiresAt,
+	})
+}
+
+func runTransfer(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("transfer", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+	owner := fs.String("to", "", "user or group that takes over the claim (required)")
+	reason := fs.String("reason", "", "reason recorded in the audit history")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	claim, err := parseClaimArg(fs)
+	if err != nil {
+		return err
+	}
+	if *owner == "" {
+		return errors.New("-to is required")
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.TransferClaim(ctx, provider.TransferRequest{
+		Name:        claim.name,
+		Region:      claim.region,
+		Environment: claim.environment,
+		NewOwner:    *owner,
+		Reason:      *reason,
+	})
+}
+
+func runPurge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+	reason := fs.String("reason", "", "reason recorded with the purge (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	claim, err := parseClaimArg(fs)
+	if err != nil {
+		return err
+	}
+	if *reason == "" {
+		return errors.New("-reason is required")
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.PurgeClaim(ctx, provider.PurgeRequest{
+		Name:        claim.name,
+		Region:      claim.region,
+		Environment: claim.environment,
+		Reason:      *reason,
+	})
+}
+
+func runSyncSlugs(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("sync-slugs", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+	message, err := client.SyncSlugs(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Println(message)
+	return nil
+}
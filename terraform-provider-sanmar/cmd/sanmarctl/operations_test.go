@@ -0,0 +1,30 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestParseClaimArg(t *testing.T) {
+	fs := flag.NewFlagSet("renew", flag.ContinueOnError)
+	if err := fs.Parse([]string{"wus2/prd/kvwus2prdatlas"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	claim, err := parseClaimArg(fs)
+	if err != nil {
+		t.Fatalf("parseClaimArg: %v", err)
+	}
+	if claim != (claimArg{region: "wus2", environment: "prd", name: "kvwus2prdatlas"}) {
+		t.Fatalf("unexpected claim: %+v", claim)
+	}
+
+	for _, args := range [][]string{nil, {"kvwus2prdatlas"}, {"wus2//kv"}, {"wus2/prd/a", "wus2/prd/b"}} {
+		fs := flag.NewFlagSet("renew", flag.ContinueOnError)
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("Parse: %v", err)
+		}
+		if _, err := parseClaimArg(fs); err == nil {
+			t.Fatalf("parseClaimArg(%v) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// namingRego validates planned sanmar_claim names against the exported data.
+//
+//go:embed naming.rego
+var namingRego string
+
+// policyRule is the rule data exported for policy engines, keyed by resource type.
+type policyRule struct {
+	MaxLength           int      `json:"max_length"`
+	RequireSanmarPrefix bool     `json:"require_sanmar_prefix"`
+	Segments            []string `json:"segments"`
+	NameTemplate        string   `json:"name_template"`
+	Slug                string   `json:"slug,omitempty"`
+}
+
+// policyData is the document shared by the OPA bundle and Sentinel mock.
+type policyData struct {
+	Rules map[string]policyRule `json:"rules"`
+}
+
+func runPolicy(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("policy", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	format := fs.String("format", "opa", "output format: opa (bundle directory), sentinel (mock data file) or azure (Azure Policy definitions)")
+	output := fs.String("output", "", "directory for opa (default sanmar-policy) and azure (default sanmar-azure-policy), or file for sentinel (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "opa", "sentinel", "azure":
+	default:
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules, err := client.ListNamingRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list naming rules: %w", err)
+	}
+
+	data := policyData{Rules: map[string]policyRule{}}
+	for _, rule := range rules {
+		entry := policyRule{
+			MaxLength:           rule.MaxLength,
+			RequireSanmarPrefix: rule.RequireSanmarPrefix,
+			Segments:            rule.Segments,
+			NameTemplate:        rule.NameTemplate,
+		}
+		// The default rule has no slug of its own.
+		slug, err := client.LookupSlug(ctx, rule.ResourceType)
+		if err != nil {
+			return fmt.Errorf("failed to look up slug for %s: %w", rule.ResourceType, err)
+		}
+		if slug != nil {
+			entry.Slug = slug.Slug
+		}
+		data.Rules[rule.ResourceType] = entry
+	}
+
+	if *format == "opa" {
+		dir := *output
+		if dir == "" {
+			dir = "sanmar-policy"
+		}
+		return writeOPABundle(dir, data)
+	}
+
+	if *format == "azure" {
+		dir := *output
+		if dir == "" {
+			dir = "sanmar-azure-policy"
+		}
+		skipped, err := writeAzurePolicies(dir, data)
+		if err != nil {
+			return err
+		}
+		for _, resourceType := range skipped {
+			fmt.Fprintf(os.Stderr, "skipped %s: no Azure resource type mapping\n", resourceType)
+		}
+		return nil
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return writeSentinelMock(w, data)
+}
+
+// writeOPABundle writes a bundle directory that "opa run" or "opa build" can
+// load, with the data under data.sanmar and the policy in package sanmar.naming.
+func writeOPABundle(dir string, data policyData) error {
+	if err := os.MkdirAll(filepath.Join(dir, "sanmar"), 0o755); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(map[string]any{"roots": []string{"sanmar"}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		".manifest":                            manifest,
+		filepath.Join("sanmar", "data.json"):   content,
+		filepath.Join("sanmar", "naming.rego"): []byte(namingRego),
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), body, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSentinelMock writes the data as a Sentinel mock import, exposing
+// rules as a map keyed by resource type.
+func writeSentinelMock(w io.Writer, data policyData) error {
+	types := make([]string, 0, len(data.Rules))
+	for resourceType := range data.Rules {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+
+	// JSON objects, arrays, strings, numbers and booleans are valid Sentinel
+	// literals, so each rule is written as indented JSON.
+	if _, err := fmt.Fprintln(w, "rules = {"); err != nil {
+		return err
+	}
+	for _, resourceType := range types {
+		rule, err := json.MarshalIndent(data.Rules[resourceType], "\t", "\t")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "\t%q: %s,\n", resourceType, rule); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testPolicyData() policyData {
+	return policyData{Rules: map[string]policyRule{
+		"storage_account": {MaxLength: 24, RequireSanmarPrefix: true, Segments: []string{"slug", "system"}, Slug: "st"},
+		"default":         {MaxLength: 80, Segments: []string{"slug"}},
+	}}
+}
+
+func TestWriteOPABundle(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeOPABundle(dir, testPolicyData()); err != nil {
+		t.Fatalf("writeOPABundle: %v", err)
+	}
+
+	var manifest struct {
+		Roots []string `json:"roots"`
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ".manifest"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil || len(manifest.Roots) != 1 || manifest.Roots[0] != "sanmar" {
+		t.Fatalf("unexpected manifest %s: %v", content, err)
+	}
+
+	var data policyData
+	content, err = os.ReadFile(filepath.Join(dir, "sanmar", "data.json"))
+	if err != nil {
+		t.Fatalf("read data: %v", err)
+	}
+	if err := json.Unmarshal(content, &data); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+	if data.Rules["storage_account"].Slug != "st" || data.Rules["default"].MaxLength != 80 {
+		t.Fatalf("unexpected data: %+v", data)
+	}
+
+	rego, err := os.ReadFile(filepath.Join(dir, "sanmar", "naming.rego"))
+	if err != nil || !strings.Contains(string(rego), "package sanmar.naming") {
+		t.Fatalf("unexpected policy: %v", err)
+	}
+}
+
+func TestWriteSentinelMock(t *testing.T) {
+	var b strings.Builder
+	if err := writeSentinelMock(&b, testPolicyData()); err != nil {
+		t.Fatalf("writeSentinelMock: %v", err)
+	}
+
+	out := b.String()
+	if !strings.HasPrefix(out, "rules = {\n\t\"default\": {") {
+		t.Fatalf("expected rules sorted by resource type:\n%s", out)
+	}
+	if !strings.Contains(out, `"slug": "st"`) || !strings.HasSuffix(out, "},\n}\n") {
+		t.Fatalf("unexpected mock:\n%s", out)
+	}
+}
+
+func TestWriteAzurePolicies(t *testing.T) {
+	dir := t.TempDir()
+	data := testPolicyData()
+	rule := data.Rules["storage_account"]
+	rule.NameTemplate = "{region}{environment}{slug}{sanmar_prefix}{system}{index}"
+	data.Rules["storage_account"] = rule
+
+	skipped, err := writeAzurePolicies(dir, data)
+	if err != nil {
+		t.Fatalf("writeAzurePolicies: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != "default" {
+		t.Fatalf("expected default to be skipped, got %v", skipped)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "storage_account.json"))
+	if err != nil {
+		t.Fatalf("read definition: %v", err)
+	}
+	out := string(content)
+	for _, want := range []string{`"equals": "Microsoft.Storage/storageAccounts"`, `"notContains": "stsanmar"`, `"greater": 24`, `"effect": "[parameters('effect')]"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in definition:\n%s", want, out)
+		}
+	}
+}
+
+func TestTemplateFixedText(t *testing.T) {
+	cases := []struct {
+		rule policyRule
+		want string
+	}{
+		{policyRule{NameTemplate: "{region}-{environment}-{slug}-{system}{subsystem_segment}{index_segment}", Slug: "kv"}, "-kv-"},
+		{policyRule{NameTemplate: "{slug}-{project}", Slug: "app"}, "app-"},
+		{policyRule{NameTemplate: "{region}{slug}{sanmar_prefix}{system}", Slug: "st", RequireSanmarPrefix: true}, "stsanmar"},
+		{policyRule{NameTemplate: "{region}{slug}{sanmar_prefix}{system}", Slug: "st"}, "st"},
+		{policyRule{NameTemplate: "{region}-{slug}", Slug: ""}, ""},
+		{policyRule{NameTemplate: "{region}-{system}", Slug: "vm"}, ""},
+	}
+	for _, tc := range cases {
+		if got := templateFixedText(tc.rule); got != tc.want {
+			t.Errorf("templateFixedText(%q, %q) = %q, want %q", tc.rule.NameTemplate, tc.rule.Slug, got, tc.want)
+		}
+	}
+}
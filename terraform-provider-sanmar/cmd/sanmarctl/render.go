@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+// bicepIdentifier matches names Bicep accepts for parameters.
+var bicepIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func runRender(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	format := fs.String("format", "bicepparam", "output format: bicepparam")
+	names := fs.String("names", "", "JSON file mapping parameter names to claim requests (required)")
+	region := fs.String("region", "", "region for requests that do not set one")
+	environment := fs.String("environment", "", "environment for requests that do not set one")
+	using := fs.String("using", "main.bicep", "Bicep file the parameters file applies to")
+	claim := fs.Bool("claim", false, "claim the names instead of previewing them")
+	output := fs.String("output", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "bicepparam" {
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+	if *names == "" {
+		return errors.New("-names is required")
+	}
+
+	requests, err := readRenderRequests(*names, *region, *environment)
+	if err != nil {
+		return err
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := make(map[string]string, len(requests))
+	for param, req := range requests {
+		var resp *provider.ClaimNameResponse
+		if *claim {
+			resp, err = client.ClaimName(ctx, req)
+		} else {
+			resp, err = client.PreviewName(ctx, req)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to generate name for %s: %w", param, err)
+		}
+		params[param] = resp.Name
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return writeBicepParams(w, *using, params)
+}
+
+// readRenderRequests reads the claim requests keyed by Bicep parameter name,
+// filling in region and environment where a request leaves them out.
+func readRenderRequests(path, region, environment string) (map[string]provider.ClaimNameRequest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests map[string]provider.ClaimNameRequest
+	if err := json.Unmarshal(content, &requests); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	for param, req := range requests {
+		if !bicepIdentifier.MatchString(param) {
+			return nil, fmt.Errorf("%s: %q is not a valid Bicep parameter name", path, param)
+		}
+		if req.Region == "" {
+			req.Region = region
+		}
+		if req.Environment == "" {
+			req.Environment = environment
+		}
+		if req.ResourceType == "" || req.Region == "" || req.Environment == "" {
+			return nil, fmt.Errorf("%s: %s needs resource_type, region and environment", path, param)
+		}
+		requests[param] = req
+	}
+	return requests, nil
+}
+
+// writeBicepParams writes a .bicepparam file for the Bicep file at using,
+// with one string parameter per name, sorted by parameter name.
+func writeBicepParams(w io.Writer, using string, params map[string]string) error {
+	keys := make([]string, 0, len(params))
+	for param := range params {
+		keys = append(keys, param)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "using %s\n", bicepString(using))
+	if len(keys) > 0 {
+		b.WriteString("\n")
+	}
+	for _, param := range keys {
+		fmt.Fprintf(&b, "param %s = %s\n", param, bicepString(params[param]))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// bicepString quotes s as a Bicep string literal.
+func bicepString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "${", `\${`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
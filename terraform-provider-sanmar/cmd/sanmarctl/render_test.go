@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadRenderRequests(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.json")
+	content := `{
+  "storageAccountName": {"resource_type": "storage_account", "system": "atlas"},
+  "keyVaultName": {"resource_type": "key_vault", "region": "eus2", "system": "atlas"}
+}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write names: %v", err)
+	}
+
+	requests, err := readRenderRequests(path, "wus2", "prd")
+	if err != nil {
+		t.Fatalf("readRenderRequests: %v", err)
+	}
+	if got := requests["storageAccountName"]; got.Region != "wus2" || got.Environment != "prd" || *got.System != "atlas" {
+		t.Fatalf("expected defaults to be applied, got %+v", got)
+	}
+	if got := requests["keyVaultName"]; got.Region != "eus2" {
+		t.Fatalf("expected explicit region to be kept, got %+v", got)
+	}
+
+	if _, err := readRenderRequests(path, "", "prd"); err == nil || !strings.Contains(err.Error(), "storageAccountName needs") {
+		t.Fatalf("expected missing region error, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"storage-name": {"resource_type": "storage_account"}}`), 0o644); err != nil {
+		t.Fatalf("write names: %v", err)
+	}
+	if _, err := readRenderRequests(path, "wus2", "prd"); err == nil || !strings.Contains(err.Error(), "not a valid Bicep parameter name") {
+		t.Fatalf("expected invalid parameter error, got %v", err)
+	}
+}
+
+func TestWriteBicepParams(t *testing.T) {
+	var b strings.Builder
+	params := map[string]string{
+		"storageAccountName": "wus2prdstsanmaratlas",
+		"keyVaultName":       "wus2-prd-kv-atlas",
+	}
+	if err := writeBicepParams(&b, "main.bicep", params); err != nil {
+		t.Fatalf("writeBicepParams: %v", err)
+	}
+
+	want := `using 'main.bicep'
+
+param keyVaultName = 'wus2-prd-kv-atlas'
+param storageAccountName = 'wus2prdstsanmaratlas'
+`
+	if b.String() != want {
+		t.Fatalf("unexpected parameters file:\n%s", b.String())
+	}
+
+	if got := bicepString(`it's ${x}`); got != `'it\'s \${x}'` {
+		t.Fatalf("unexpected escaping: %s", got)
+	}
+}
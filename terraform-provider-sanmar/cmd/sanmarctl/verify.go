@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+// stateClaim is a sanmar_claim instance read from a Terraform state file.
+type stateClaim struct {
+	address    string
+	attributes map[string]any
+}
+
+func (c stateClaim) attr(name string) string {
+	switch v := c.attributes[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return ""
+}
+
+// verifyFinding is a problem verify found with one claim in state.
+type verifyFinding struct {
+	address string
+	kind    string
+	detail  string
+}
+
+// azureResource is what verify needs to know about an Azure resource.
+type azureResource struct {
+	Name string `json:"name"`
+}
+
+// azureLookup returns the Azure resources among ids that exist, keyed by
+// lower-case ID.
+type azureLookup func(ctx context.Context, ids []string) (map[string]azureResource, error)
+
+func runVerify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+	statePath := fs.String("state", "terraform.tfstate", "Terraform state file to check, as written by terraform state pull")
+	checkAzure := fs.Bool("azure", false, "also check that the Azure resources linked to claims exist, using Azure Resource Graph")
+	armEndpoint := fs.String("arm-endpoint", "https://management.azure.com", "Azure Resource Manager endpoint for -azure")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*statePath)
+	if err != nil {
+		return err
+	}
+	claims, err := readStateClaims(data)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *statePath, err)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	var lookup azureLookup
+	if *checkAzure {
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return fmt.Errorf("failed to initialize DefaultAzureCredential: %w", err)
+		}
+		lookup = resourceGraphLookup(http.DefaultClient, cred, *armEndpoint)
+	}
+
+	findings, err := verifyClaims(ctx, client, lookup, claims)
+	if err != nil {
+		return err
+	}
+	return reportFindings(os.Stdout, len(claims), findings)
+}
+
+// readStateClaims returns the sanmar_claim instances in a version 4 state
+// file, sorted by address. Data sources and dry_run claims are skipped.
+func readStateClaims(data []byte) ([]stateClaim, error) {
+	var state struct {
+		Version   int `json:"version"`
+		Resources []struct {
+			Module    string `json:"module"`
+			Mode      string `json:"mode"`
+			Type      string `json:"type"`
+			Name      string `json:"name"`
+			Instances []struct {
+				IndexKey   any            `json:"index_key"`
+				Attributes map[string]any `json:"attributes"`
+			} `json:"instances"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported state version %d", state.Version)
+	}
+
+	var claims []stateClaim
+	for _, res := range state.Resources {
+		if res.Mode != "managed" || res.Type != "sanmar_claim" {
+			continue
+		}
+		address := res.Type + "." + res.Name
+		if res.Module != "" {
+			address = res.Module + "." + address
+		}
+		for _, inst := range res.Instances {
+			claim := stateClaim{address: address, attributes: inst.Attributes}
+			switch key := inst.IndexKey.(type) {
+			case string:
+				claim.address += fmt.Sprintf("[%q]", key)
+			case float64:
+				claim.address += fmt.Sprintf("[%d]", int(key))
+			}
+			if claim.attr("dry_run") == "true" {
+				continue
+			}
+			claims = append(claims, claim)
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool { return claims[i].address < claims[j].address })
+	return claims, nil
+}
+
+// verifyClaims compares each claim in state with its audit record and, when
+// lookup is set, with the Azure resource linked to it.
+func verifyClaims(ctx context.Context, client *provider.APIClient, lookup azureLookup, claims []stateClaim) ([]verifyFinding, error) {
+	var findings []verifyFinding
+	linked := map[string]stateClaim{}
+	for _, claim := range claims {
+		name, region, environment := claim.attr("name"), claim.attr("region"), claim.attr("environment")
+		if name == "" {
+			findings = append(findings, verifyFinding{claim.address, "missing", "no name in state; the claim was never completed"})
+			continue
+		}
+
+		record, err := client.GetAudit(ctx, region, environment, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read claim %s: %w", claim.address, err)
+		}
+		switch {
+		case record == nil:
+			findings = append(findings, verifyFinding{claim.address, "missing", fmt.Sprintf("the service has no record of %s in %s/%s", name, region, environment)})
+			continue
+		case record.Retired:
+			findings = append(findings, verifyFinding{claim.address, "stale", fmt.Sprintf("%s has been retired", name)})
+			continue
+		case !record.InUse:
+			findings = append(findings, verifyFinding{claim.address, "stale", fmt.Sprintf("%s has been released", name)})
+			continue
+		}
+
+		for _, field := range []struct{ attribute, state, service string }{
+			{"resource_type", claim.attr("resource_type"), record.Resource},
+			{"effective_project", claim.attr("effective_project"), record.Project},
+			{"effective_purpose", claim.attr("effective_purpose"), record.Purpose},
+			{"effective_system", claim.attr("effective_system"), record.System},
+			{"effective_subsystem", claim.attr("effective_subsystem"), record.Subsystem},
+			{"effective_index", claim.attr("effective_index"), record.Index},
+			{"azure_resource_id", claim.attr("azure_resource_id"), record.AzureResourceID},
+		} {
+			if field.state == "" || sameValue(field.state, field.service) {
+				continue
+			}
+			findings = append(findings, verifyFinding{claim.address, "mismatched", fmt.Sprintf("%s is %q in state but %q on the service", field.attribute, field.state, field.service)})
+		}
+
+		if id := record.AzureResourceID; id != "" {
+			linked[strings.ToLower(id)] = claim
+		}
+	}
+
+	if lookup != nil && len(linked) > 0 {
+		ids := make([]string, 0, len(linked))
+		for id := range linked {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		resources, err := lookup(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up Azure resources: %w", err)
+		}
+		for _, id := range ids {
+			claim := linked[id]
+			resource, ok := resources[id]
+			switch {
+			case !ok:
+				findings = append(findings, verifyFinding{claim.address, "missing", fmt.Sprintf("the linked Azure resource %s does not exist", id)})
+			case !strings.EqualFold(resource.Name, claim.attr("name")):
+				findings = append(findings, verifyFinding{claim.address, "mismatched", fmt.Sprintf("the linked Azure resource is named %q", resource.Name)})
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].address < findings[j].address })
+	return findings, nil
+}
+
+// sameValue compares a state value with the service's, ignoring case and the
+// zero padding of numbers, since indices are numbers in newer state.
+func sameValue(state, service string) bool {
+	if strings.EqualFold(state, service) {
+		return true
+	}
+	a, errA := strconv.ParseInt(state, 10, 64)
+	b, errB := strconv.ParseInt(service, 10, 64)
+	return errA == nil && errB == nil && a == b
+}
+
+// reportFindings writes findings to w and fails when there are any, so verify
+// can gate pipelines.
+func reportFindings(w io.Writer, checked int, findings []verifyFinding) error {
+	for _, f := range findings {
+		fmt.Fprintf(w, "%-10s %s: %s\n", f.kind, f.address, f.detail)
+	}
+	fmt.Fprintf(w, "checked %d claims, %d problems\n", checked, len(findings))
+	if len(findings) > 0 {
+		return fmt.Errorf("%d problems found", len(findings))
+	}
+	return nil
+}
+
+// resourceGraphBatch is how many IDs go in one Resource Graph query. It is
+// kept at the default page size, so no query needs paging.
+const resourceGraphBatch = 100
+
+// resourceGraphLookup looks resources up with Azure Resource Graph queries,
+// which work for every resource type without a per-type API version.
+func resourceGraphLookup(httpClient *http.Client, cred azcore.TokenCredential, endpoint string) azureLookup {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	return func(ctx context.Context, ids []string) (map[string]azureResource, error) {
+		token, err := cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{endpoint + "/.default"}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire token: %w", err)
+		}
+
+		resources := make(map[string]azureResource, len(ids))
+		for start := 0; start < len(ids); start += resourceGraphBatch {
+			end := start + resourceGraphBatch
+			if end > len(ids) {
+				end = len(ids)
+			}
+			if err := queryResourceGraph(ctx, httpClient, endpoint, token.Token, ids[start:end], resources); err != nil {
+				return nil, err
+			}
+		}
+		return resources, nil
+	}
+}
+
+// queryResourceGraph adds the resources among ids that exist to resources.
+func queryResourceGraph(ctx context.Context, httpClient *http.Client, endpoint, token string, ids []string, resources map[string]azureResource) error {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = "'" + strings.ReplaceAll(id, "'", `\'`) + "'"
+	}
+	body, err := json.Marshal(map[string]any{
+		"query": fmt.Sprintf("Resources | where tolower(id) in (%s) | project id, name", strings.Join(quoted, ", ")),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/providers/Microsoft.ResourceGraph/resources?api-version=2021-03-01", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("resource graph returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var result struct {
+		Data []struct {
+			ID string `json:"id"`
+			azureResource
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode resource graph response: %w", err)
+	}
+	for _, row := range result.Data {
+		resources[strings.ToLower(row.ID)] = row.azureResource
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+const verifyState = `{
+  "version": 4,
+  "resources": [
+    {"mode": "managed", "type": "sanmar_claim", "name": "storage", "instances": [
+      {"attributes": {"name": "stwus2prdatlas01", "region": "wus2", "environment": "prd", "resource_type": "storage_account", "effective_index": 1}}
+    ]},
+    {"module": "module.app", "mode": "managed", "type": "sanmar_claim", "name": "vault", "instances": [
+      {"index_key": "a", "attributes": {"name": "kvwus2prdatlas", "region": "wus2", "environment": "prd", "resource_type": "key_vault", "effective_project": "atlas"}},
+      {"index_key": "b", "attributes": {"name": "kvwus2prdold", "region": "wus2", "environment": "prd", "resource_type": "key_vault"}}
+    ]},
+    {"mode": "managed", "type": "sanmar_claim", "name": "gone", "instances": [
+      {"attributes": {"name": "appwus2prdgone", "region": "wus2", "environment": "prd"}}
+    ]},
+    {"mode": "managed", "type": "sanmar_claim", "name": "preview", "instances": [
+      {"attributes": {"name": "apppreview", "region": "wus2", "environment": "prd", "dry_run": true}}
+    ]},
+    {"mode": "data", "type": "sanmar_claims", "name": "all", "instances": [{"attributes": {}}]}
+  ]
+}`
+
+func TestVerifyClaims(t *testing.T) {
+	records := map[string]map[string]any{
+		"stwus2prdatlas01": {"in_use": true, "resource_type": "storage_account", "index": "01", "azure_resource_id": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/stwus2prdatlas01"},
+		"kvwus2prdatlas":   {"in_use": true, "resource_type": "key_vault", "project": "payments", "azure_resource_id": "/subscriptions/s/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/kvwus2prdatlas"},
+		"kvwus2prdold":     {"in_use": false, "resource_type": "key_vault"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		record, ok := records[name]
+		if !ok {
+			http.Error(w, `{"message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		record["name"] = name
+		_ = json.NewEncoder(w).Encode(record)
+	}))
+	defer srv.Close()
+
+	client, err := provider.NewAPIClient(context.Background(), srv.URL, "", provider.RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	claims, err := readStateClaims([]byte(verifyState))
+	if err != nil {
+		t.Fatalf("readStateClaims: %v", err)
+	}
+	if len(claims) != 4 {
+		t.Fatalf("expected 4 claims, got %d", len(claims))
+	}
+
+	lookup := func(_ context.Context, ids []string) (map[string]azureResource, error) {
+		return map[string]azureResource{
+			"/subscriptions/s/resourcegroups/rg/providers/microsoft.storage/storageaccounts/stwus2prdatlas01": {Name: "stwus2prdatlas01"},
+		}, nil
+	}
+	findings, err := verifyClaims(context.Background(), client, lookup, claims)
+	if err != nil {
+		t.Fatalf("verifyClaims: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := reportFindings(&out, len(claims), findings); err == nil {
+		t.Fatalf("expected problems to fail verify")
+	}
+	for _, want := range []string{
+		`missing    module.app.sanmar_claim.vault["a"]: the linked Azure resource`,
+		`mismatched module.app.sanmar_claim.vault["a"]: effective_project is "atlas" in state but "payments" on the service`,
+		`stale      module.app.sanmar_claim.vault["b"]: kvwus2prdold has been released`,
+		`missing    sanmar_claim.gone: the service has no record of appwus2prdgone`,
+		"checked 4 claims, 4 problems",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "sanmar_claim.storage") {
+		t.Errorf("expected the padded index to match, got:\n%s", out.String())
+	}
+}
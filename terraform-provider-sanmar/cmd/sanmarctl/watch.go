@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func runWatch(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var conn clientFlags
+	conn.register(fs)
+
+	var filter provider.ClaimFilter
+	fs.StringVar(&filter.User, "user", "", "only show events by this user")
+	fs.StringVar(&filter.Project, "project", "", "only show events for this project")
+	fs.StringVar(&filter.Purpose, "purpose", "", "only show events for this purpose")
+	fs.StringVar(&filter.Environment, "environment", "", "only show events in this environment")
+	fs.StringVar(&filter.Region, "region", "", "only show events in this region")
+	fs.StringVar(&filter.Action, "action", "", "only show events with this action, for example claimed or released")
+	resourceType := fs.String("resource-type", "", "only show events for this resource type")
+	since := fs.Duration("since", 0, "also show events from this long before starting, for example 1h")
+	interval := fs.Duration("interval", 5*time.Second, "how often to poll the audit history")
+	format := fs.String("format", "text", "output format: text or json (one event per line)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text", "json":
+	default:
+		return fmt.Errorf("unsupported format %q", *format)
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", *interval)
+	}
+
+	client, err := conn.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	w := newEventWatcher(time.Now().Add(-*since))
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		filter.Start = w.cursor.UTC().Format(time.RFC3339)
+		events, err := client.ListAuditEvents(ctx, filter)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			// Keep watching through outages; the next poll picks up
+			// where this one left off.
+			fmt.Fprintf(os.Stderr, "sanmarctl: failed to list audit events: %v\n", err)
+		default:
+			for _, event := range w.next(events) {
+				if *resourceType != "" && event.ResourceType != *resourceType {
+					continue
+				}
+				if err := writeEvent(os.Stdout, *format, event); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// eventWatcher tracks which audit events have been shown. The audit history
+// is polled from the newest event seen, inclusive, so events at that time are
+// remembered to avoid showing them twice.
+type eventWatcher struct {
+	cursor time.Time
+	// seen holds the time of each event shown in the cursor's second.
+	seen map[string]time.Time
+}
+
+func newEventWatcher(start time.Time) *eventWatcher {
+	return &eventWatcher{cursor: start, seen: map[string]time.Time{}}
+}
+
+// next returns the events not shown before, oldest first, and moves the
+// cursor to the newest of them. Events without a parsable timestamp are
+// shown once but do not move the cursor.
+func (w *eventWatcher) next(events []provider.AuditEvent) []provider.AuditEvent {
+	var fresh []provider.AuditEvent
+	for _, event := range events {
+		key := eventKey(event)
+		if _, ok := w.seen[key]; ok {
+			continue
+		}
+		at, _ := time.Parse(time.RFC3339, event.Timestamp)
+		w.seen[key] = at
+		if at.After(w.cursor) {
+			w.cursor = at
+		}
+		fresh = append(fresh, event)
+	}
+	sort.SliceStable(fresh, func(i, j int) bool {
+		return w.seen[eventKey(fresh[i])].Before(w.seen[eventKey(fresh[j])])
+	})
+
+	// Polls start from the cursor's second, so only events in that second
+	// can be listed again.
+	floor := w.cursor.Truncate(time.Second)
+	for key, at := range w.seen {
+		if !at.IsZero() && at.Before(floor) {
+			delete(w.seen, key)
+		}
+	}
+	return fresh
+}
+
+// eventKey identifies an event, falling back to its contents for services
+// that do not report event IDs.
+func eventKey(event provider.AuditEvent) string {
+	if event.EventID != "" {
+		return event.EventID
+	}
+	return event.Timestamp + "|" + event.Action + "|" + event.Region + "/" + event.Environment + "/" + event.Name
+}
+
+func writeEvent(w io.Writer, format string, event provider.AuditEvent) error {
+	if format == "json" {
+		return json.NewEncoder(w).Encode(event)
+	}
+
+	line := fmt.Sprintf("%s  %-9s %s/%s/%s  %s  %s", event.Timestamp, event.Action, event.Region, event.Environment, event.Name, orDash(event.ResourceType), orDash(event.User))
+	if event.Note != "" {
+		line += "  " + event.Note
+	}
+	_, err := fmt.Fprintln(w, line)
+	return err
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
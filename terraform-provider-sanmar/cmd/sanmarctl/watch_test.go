@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
+)
+
+func TestEventWatcherShowsEachEventOnce(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := newEventWatcher(start)
+
+	first := []provider.AuditEvent{
+		{EventID: "2", Action: "released", Name: "kvsanmaratlas01", Timestamp: "2024-05-01T12:00:05.5Z"},
+		{EventID: "1", Action: "claimed", Name: "kvsanmaratlas01", Timestamp: "2024-05-01T12:00:01Z"},
+	}
+	got := w.next(first)
+	if len(got) != 2 || got[0].EventID != "1" || got[1].EventID != "2" {
+		t.Fatalf("expected both events oldest first, got %+v", got)
+	}
+	if want := start.Add(5500 * time.Millisecond); !w.cursor.Equal(want) {
+		t.Fatalf("expected cursor %s, got %s", want, w.cursor)
+	}
+	if _, ok := w.seen["1"]; ok {
+		t.Fatalf("expected events before the cursor's second to be forgotten")
+	}
+
+	// The next poll starts at 12:00:05, so it lists event 2 again.
+	second := []provider.AuditEvent{
+		{EventID: "3", Action: "claimed", Name: "kvsanmaratlas02", Timestamp: "2024-05-01T12:00:05.9Z"},
+		first[0],
+	}
+	if got := w.next(second); len(got) != 1 || got[0].EventID != "3" {
+		t.Fatalf("expected only the new event, got %+v", got)
+	}
+}
+
+func TestWriteEvent(t *testing.T) {
+	event := provider.AuditEvent{Action: "claimed", Region: "wus2", Environment: "prd", Name: "kvsanmaratlas01", User: "ops@sanmar.com", Timestamp: "2024-05-01T12:00:01Z"}
+
+	var text bytes.Buffer
+	if err := writeEvent(&text, "text", event); err != nil {
+		t.Fatalf("writeEvent: %v", err)
+	}
+	if want := "2024-05-01T12:00:01Z  claimed   wus2/prd/kvsanmaratlas01  -  ops@sanmar.com\n"; text.String() != want {
+		t.Fatalf("unexpected text output %q", text.String())
+	}
+
+	var lines bytes.Buffer
+	if err := writeEvent(&lines, "json", event); err != nil {
+		t.Fatalf("writeEvent: %v", err)
+	}
+	if !bytes.Contains(lines.Bytes(), []byte(`"action":"claimed"`)) || bytes.Count(lines.Bytes(), []byte("\n")) != 1 {
+		t.Fatalf("unexpected json output %q", lines.String())
+	}
+}
@@ -1,23 +1,43 @@
 module github.com/gedefili/azure-naming/terraform-provider-sanmar
 
-go 1.21
+go 1.24.0
 
 require (
-    github.com/Azure/azure-sdk-for-go/sdk/azcore v1.11.1
-    github.com/Azure/azure-sdk-for-go/sdk/azidentity v1.7.0
-    github.com/hashicorp/terraform-plugin-framework v1.10.0
-    github.com/hashicorp/terraform-plugin-framework-validators v0.14.0
-    github.com/hashicorp/terraform-plugin-log v0.9.0
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.11.1
+	github.com/Azure/azure-sdk-for-go/sdk/azidentity v1.7.0
+	github.com/hashicorp/terraform-plugin-framework v1.16.1
+	github.com/hashicorp/terraform-plugin-framework-validators v0.14.0
+	github.com/hashicorp/terraform-plugin-go v0.29.0
+	github.com/hashicorp/terraform-plugin-log v0.9.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-    github.com/fatih/color v1.16.0 // indirect
-    github.com/hashicorp/go-hclog v1.6.2 // indirect
-    github.com/hashicorp/go-plugin v1.6.0 // indirect
-    github.com/hashicorp/terraform-plugin-go v0.20.0 // indirect
-    github.com/mattn/go-colorable v0.1.13 // indirect
-    github.com/mattn/go-isatty v0.0.20 // indirect
-    github.com/mitchellh/go-testing-interface v1.14.1 // indirect
-    golang.org/x/net v0.26.0 // indirect
-    golang.org/x/sys v0.21.0 // indirect
+	github.com/Azure/azure-sdk-for-go/sdk/internal v1.8.0 // indirect
+	github.com/AzureAD/microsoft-authentication-library-for-go v1.2.2 // indirect
+	github.com/fatih/color v1.16.0 // indirect
+	github.com/golang-jwt/jwt/v5 v5.2.1 // indirect
+	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/hashicorp/go-hclog v1.6.3 // indirect
+	github.com/hashicorp/go-plugin v1.7.0 // indirect
+	github.com/hashicorp/go-uuid v1.0.3 // indirect
+	github.com/hashicorp/terraform-registry-address v0.4.0 // indirect
+	github.com/hashicorp/terraform-svchost v0.1.1 // indirect
+	github.com/hashicorp/yamux v0.1.2 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mitchellh/go-testing-interface v1.14.1 // indirect
+	github.com/oklog/run v1.1.0 // indirect
+	github.com/pkg/browser v0.0.0-20240102092130-5ac0b6a4141c // indirect
+	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/crypto v0.41.0 // indirect
+	golang.org/x/net v0.43.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
+	golang.org/x/text v0.28.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250707201910-8d1bb00bc6a7 // indirect
+	google.golang.org/grpc v1.75.1 // indirect
+	google.golang.org/protobuf v1.36.9 // indirect
 )
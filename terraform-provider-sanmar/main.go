@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/gedefili/azure-naming/terraform-provider-sanmar/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -26,7 +27,17 @@ func main() {
 		Debug:   debug,
 	}
 
-	if err := providerserver.Serve(ctx, provider.New(version), opts); err != nil {
+	// Metrics cover the whole plugin process, so large runs can be profiled.
+	if addr := os.Getenv(provider.MetricsAddrEnv); addr != "" {
+		if _, err := provider.ServeMetrics(addr); err != nil {
+			log.Printf("metrics endpoint disabled: %v", err)
+		}
+	}
+
+	err := providerserver.Serve(ctx, provider.New(version), opts)
+	// Serve returns once Terraform stops the plugin.
+	provider.Shutdown()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,237 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ action.ActionWithConfigure = (*RenewClaimAction)(nil)
+var _ action.ActionWithConfigure = (*TransferClaimAction)(nil)
+var _ action.ActionWithConfigure = (*PurgeClaimAction)(nil)
+
+// claimActionAttributes returns the attributes that identify the claim an
+// action operates on, plus extra.
+func claimActionAttributes(extra map[string]schema.Attribute) map[string]schema.Attribute {
+	attributes := map[string]schema.Attribute{
+		"region": schema.StringAttribute{
+			Required:            true,
+			MarkdownDescription: "Region the name was claimed in.",
+		},
+		"environment": schema.StringAttribute{
+			Required:            true,
+			MarkdownDescription: "Environment the name was claimed in.",
+		},
+		"name": schema.StringAttribute{
+			Required:            true,
+			MarkdownDescription: "Claimed name.",
+		},
+	}
+	for name, attribute := range extra {
+		attributes[name] = attribute
+	}
+	return attributes
+}
+
+// configureActionClient returns the client from an action's provider data.
+func configureActionClient(req action.ConfigureRequest, resp *action.ConfigureResponse) *APIClient {
+	if req.ProviderData == nil {
+		return nil
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return nil
+	}
+	return client
+}
+
+// NewRenewClaimAction returns the action that sets a claim's expiry.
+func NewRenewClaimAction() action.Action {
+	return &RenewClaimAction{}
+}
+
+// RenewClaimAction is the action form of `sanmarctl renew`.
+type RenewClaimAction struct {
+	client *APIClient
+}
+
+type renewClaimActionModel struct {
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Name        types.String `tfsdk:"name"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+func (a *RenewClaimAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim_renew"
+}
+
+func (a *RenewClaimAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Sets a new expiry on a claim without releasing it.",
+		Attributes: claimActionAttributes(map[string]schema.Attribute{
+			"expires_at": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "New RFC 3339 expiry. Unset removes the expiry.",
+			},
+		}),
+	}
+}
+
+func (a *RenewClaimAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureActionClient(req, resp)
+}
+
+func (a *RenewClaimAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	if a.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer a.client.reportDeprecations(&resp.Diagnostics)
+	var data renewClaimActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := a.client.RenewClaim(ctx, RenewRequest{
+		Name:        data.Name.ValueString(),
+		Region:      data.Region.ValueString(),
+		Environment: data.Environment.ValueString(),
+		ExpiresAt:   data.ExpiresAt.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to renew claim", err.Error())
+	}
+}
+
+// NewTransferClaimAction returns the action that reassigns a claim.
+func NewTransferClaimAction() action.Action {
+	return &TransferClaimAction{}
+}
+
+// TransferClaimAction is the action form of `sanmarctl transfer`.
+type TransferClaimAction struct {
+	client *APIClient
+}
+
+type transferClaimActionModel struct {
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Name        types.String `tfsdk:"name"`
+	NewOwner    types.String `tfsdk:"new_owner"`
+	Reason      types.String `tfsdk:"reason"`
+}
+
+func (a *TransferClaimAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim_transfer"
+}
+
+func (a *TransferClaimAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Moves a claim to a new owner without releasing the name. Only the current owner or an elevated role can transfer a claim.",
+		Attributes: claimActionAttributes(map[string]schema.Attribute{
+			"new_owner": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "User or group that takes over the claim.",
+			},
+			"reason": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Reason recorded in the audit history.",
+			},
+		}),
+	}
+}
+
+func (a *TransferClaimAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureActionClient(req, resp)
+}
+
+func (a *TransferClaimAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	if a.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer a.client.reportDeprecations(&resp.Diagnostics)
+	var data transferClaimActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := a.client.TransferClaim(ctx, TransferRequest{
+		Name:        data.Name.ValueString(),
+		Region:      data.Region.ValueString(),
+		Environment: data.Environment.ValueString(),
+		NewOwner:    data.NewOwner.ValueString(),
+		Reason:      data.Reason.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to transfer claim", err.Error())
+	}
+}
+
+// NewPurgeClaimAction returns the action that deletes a released name's
+// record.
+func NewPurgeClaimAction() action.Action {
+	return &PurgeClaimAction{}
+}
+
+// PurgeClaimAction is the action form of `sanmarctl purge`.
+type PurgeClaimAction struct {
+	client *APIClient
+}
+
+type purgeClaimActionModel struct {
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Name        types.String `tfsdk:"name"`
+	Reason      types.String `tfsdk:"reason"`
+}
+
+func (a *PurgeClaimAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim_purge"
+}
+
+func (a *PurgeClaimAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deletes the record of a released name so it can be claimed fresh. Requires the admin role and cannot be undone.",
+		Attributes: claimActionAttributes(map[string]schema.Attribute{
+			"reason": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Reason recorded with the purge.",
+			},
+		}),
+	}
+}
+
+func (a *PurgeClaimAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureActionClient(req, resp)
+}
+
+func (a *PurgeClaimAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	if a.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer a.client.reportDeprecations(&resp.Diagnostics)
+	var data purgeClaimActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := a.client.PurgeClaim(ctx, PurgeRequest{
+		Name:        data.Name.ValueString(),
+		Region:      data.Region.ValueString(),
+		Environment: data.Environment.ValueString(),
+		Reason:      data.Reason.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to purge claim", err.Error())
+	}
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// actionConfig builds the configuration of a, with values set and every
+// other attribute null.
+func actionConfig(ctx context.Context, t *testing.T, a action.Action, values map[string]string) tfsdk.Config {
+	t.Helper()
+	var resp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &resp)
+	typ := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	raw := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		raw[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range values {
+		raw[name] = tftypes.NewValue(tftypes.String, value)
+	}
+	return tfsdk.Config{Schema: resp.Schema, Raw: tftypes.NewValue(typ, raw)}
+}
+
+func TestClaimOperationActions(t *testing.T) {
+	ctx := context.Background()
+	received := map[string]map[string]any{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		received[r.URL.Path] = body
+		if r.URL.Path == "/api/claim/purge" {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte("Name is still claimed; release it before purging."))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "ok"})
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	claim := map[string]string{"region": "wus2", "environment": "prd", "name": "kvwus2prdatlas"}
+	with := func(extra map[string]string) map[string]string {
+		values := map[string]string{}
+		for k, v := range claim {
+			values[k] = v
+		}
+		for k, v := range extra {
+			values[k] = v
+		}
+		return values
+	}
+
+	transfer := &TransferClaimAction{client: client}
+	var resp action.InvokeResponse
+	transfer.Invoke(ctx, action.InvokeRequest{Config: actionConfig(ctx, t, transfer, with(map[string]string{"new_owner": "platform-team"}))}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("transfer: %v", resp.Diagnostics)
+	}
+	if body := received["/api/claim/transfer"]; body["new_owner"] != "platform-team" || body["name"] != "kvwus2prdatlas" {
+		t.Fatalf("unexpected transfer request %v", body)
+	}
+
+	purge := &PurgeClaimAction{client: client}
+	resp = action.InvokeResponse{}
+	purge.Invoke(ctx, action.InvokeRequest{Config: actionConfig(ctx, t, purge, with(map[string]string{"reason": "typo"}))}, &resp)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected the refused purge to be reported")
+	}
+	if body := received["/api/claim/purge"]; body["reason"] != "typo" {
+		t.Fatalf("unexpected purge request %v", body)
+	}
+}
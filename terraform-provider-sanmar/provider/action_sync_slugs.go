@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+)
+
+var _ action.ActionWithConfigure = (*SyncSlugsAction)(nil)
+
+// NewSyncSlugsAction returns the action that refreshes the slug table.
+func NewSyncSlugsAction() action.Action {
+	return &SyncSlugsAction{}
+}
+
+// SyncSlugsAction is the action form of `sanmarctl sync-slugs`.
+type SyncSlugsAction struct {
+	client *APIClient
+}
+
+func (a *SyncSlugsAction) Metadata(_ context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sync_slugs"
+}
+
+func (a *SyncSlugsAction) Schema(_ context.Context, _ action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Refreshes the naming service's slug table from its upstream source. Requires the admin role.",
+	}
+}
+
+func (a *SyncSlugsAction) Configure(_ context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configureActionClient(req, resp)
+}
+
+func (a *SyncSlugsAction) Invoke(ctx context.Context, _ action.InvokeRequest, resp *action.InvokeResponse) {
+	if a.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer a.client.reportDeprecations(&resp.Diagnostics)
+	message, err := a.client.SyncSlugs(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to sync slugs", err.Error())
+		return
+	}
+	if message != "" && resp.SendProgress != nil {
+		resp.SendProgress(action.InvokeProgressEvent{Message: message})
+	}
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// runIDEnvVars identify the CI run applying the configuration, in order of preference.
+var runIDEnvVars = []string{"GITHUB_RUN_ID", "BUILD_BUILDID", "CI_PIPELINE_ID", "TFC_RUN_ID"}
+
+// auditLogRun describes the Terraform run that performed an action.
+type auditLogRun struct {
+	Host      string `json:"host,omitempty"`
+	User      string `json:"user,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+	RunID     string `json:"run_id,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// auditLogEntry is one line of the local audit log.
+type auditLogEntry struct {
+	Time         time.Time   `json:"time"`
+	Action       string      `json:"action"`
+	Name         string      `json:"name,omitempty"`
+	ResourceType string      `json:"resource_type,omitempty"`
+	Region       string      `json:"region"`
+	Environment  string      `json:"environment"`
+	Reason       string      `json:"reason,omitempty"`
+	Template     string      `json:"template,omitempty"`
+	Outcome      string      `json:"outcome"`
+	Error        string      `json:"error,omitempty"`
+	Run          auditLogRun `json:"run"`
+}
+
+// auditLog appends a JSON line for every claim and release to a local file.
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	run  auditLogRun
+	now  func() time.Time
+}
+
+// openAuditLog opens path for appending, creating it if needed.
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &auditLog{file: file, run: currentRun(), now: time.Now}, nil
+}
+
+func currentRun() auditLogRun {
+	run := auditLogRun{Workspace: currentWorkspace()}
+	if host, err := os.Hostname(); err == nil {
+		run.Host = host
+	}
+	if u, err := user.Current(); err == nil {
+		run.User = u.Username
+	}
+	for _, name := range runIDEnvVars {
+		if id := os.Getenv(name); id != "" {
+			run.RunID = id
+			break
+		}
+	}
+	return run
+}
+
+// record appends entry. Write failures are logged rather than failing the
+// action, which has already happened on the service.
+func (l *auditLog) record(ctx context.Context, entry auditLogEntry, err error) {
+	if l == nil {
+		return
+	}
+
+	entry.Time = l.now().UTC()
+	entry.Run = l.run
+	entry.Outcome = "success"
+	if err != nil {
+		entry.Outcome = "error"
+		entry.Error = err.Error()
+	}
+
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		tflog.Warn(ctx, "failed to encode audit log entry", map[string]any{"error": marshalErr.Error()})
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	// A single write per line keeps entries from concurrent processes intact.
+	if _, writeErr := l.file.Write(append(line, '\n')); writeErr != nil {
+		tflog.Warn(ctx, "failed to write audit log entry", map[string]any{"error": writeErr.Error()})
+	}
+}
+
+// close closes the log file; entries recorded afterwards are dropped.
+func (l *auditLog) close() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+}
+
+// EnableAuditLog records every claim and release in a JSON Lines file at path.
+func (c *APIClient) EnableAuditLog(path string) error {
+	log, err := openAuditLog(path)
+	if err != nil {
+		return err
+	}
+	log.run.SessionID = c.sessionID
+	c.auditLog = log
+	return nil
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAuditLogRecordsClaimsAndReleases(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ClaimNameResponse{Name: "sanmar-vm-wus2-prd", ResourceType: "vm"})
+	})
+	mux.HandleFunc("/api/release", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "name not found", http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.sessionID = "session-1"
+	logPath := filepath.Join(t.TempDir(), "sanmar-audit.jsonl")
+	if err := client.EnableAuditLog(logPath); err != nil {
+		t.Fatalf("EnableAuditLog: %v", err)
+	}
+	client.auditLog.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	ctx := context.Background()
+	if _, err := client.ClaimName(ctx, ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if err := client.ReleaseName(ctx, ReleaseRequest{Name: "sanmar-vm-wus2-prd", Region: "wus2", Environment: "prd", Reason: "decommissioned"}); err == nil {
+		t.Fatalf("expected release error")
+	}
+
+	file, err := os.Open(logPath)
+	if err != nil {
+		t.Fatalf("open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []auditLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry auditLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decode entry %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	claim, release := entries[0], entries[1]
+	if claim.Action != "claim" || claim.Name != "sanmar-vm-wus2-prd" || claim.Outcome != "success" || claim.Run.SessionID != "session-1" {
+		t.Fatalf("unexpected claim entry: %+v", claim)
+	}
+	if !claim.Time.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected claim time: %v", claim.Time)
+	}
+	if release.Action != "release" || release.Reason != "decommissioned" || release.Outcome != "error" || release.Error == "" {
+		t.Fatalf("unexpected release entry: %+v", release)
+	}
+}
+
+func TestShutdownClosesAuditLog(t *testing.T) {
+	client, err := NewAPIClient(context.Background(), "http://127.0.0.1", "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	logPath := filepath.Join(t.TempDir(), "sanmar-audit.jsonl")
+	if err := client.EnableAuditLog(logPath); err != nil {
+		t.Fatalf("EnableAuditLog: %v", err)
+	}
+	file := client.auditLog.file
+	trackClient(client)
+
+	Shutdown()
+	if client.auditLog.file != nil {
+		t.Fatal("expected Shutdown to close the audit log")
+	}
+	if err := file.Close(); err == nil {
+		t.Fatal("expected the audit log file to be closed already")
+	}
+
+	// Entries after shutdown are dropped instead of failing.
+	client.auditLog.record(context.Background(), auditLogEntry{Action: "claim"}, nil)
+	if content, err := os.ReadFile(logPath); err != nil || len(content) != 0 {
+		t.Fatalf("expected an empty log, got %q (%v)", content, err)
+	}
+}
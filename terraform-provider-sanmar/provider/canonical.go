@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Canonicalization steps the service may report for a segment in its naming
+// rules. Unknown steps are ignored.
+const (
+	canonicalTrim  = "trim"
+	canonicalLower = "lower"
+)
+
+// defaultCanonicalization mirrors what the service does to claim segments
+// when its rules do not say: surrounding whitespace is trimmed and values are
+// lowercased before the name is built and recorded.
+var defaultCanonicalization = map[string][]string{
+	"project":   {canonicalTrim, canonicalLower},
+	"purpose":   {canonicalTrim, canonicalLower},
+	"system":    {canonicalTrim, canonicalLower},
+	"subsystem": {canonicalTrim, canonicalLower},
+}
+
+// canonicalCache holds the canonicalization rules by resource type so a plan
+// lists the naming rules once rather than once per claim.
+type canonicalCache struct {
+	mu     sync.Mutex
+	loaded bool
+	// byType holds the rules that report canonicalization, including the
+	// "default" rule applied to other resource types.
+	byType map[string]map[string][]string
+}
+
+// canonicalization returns the steps the service applies to each segment of
+// resourceType claims. Failed lookups fall back to the built-in rules and are
+// not cached.
+func (c *APIClient) canonicalization(ctx context.Context, resourceType string) map[string][]string {
+	c.canonical.mu.Lock()
+	defer c.canonical.mu.Unlock()
+
+	if !c.canonical.loaded {
+		rules, err := c.ListNamingRules(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "failed to list naming rules; using built-in segment canonicalization", map[string]any{
+				"error": err.Error(),
+			})
+			return defaultCanonicalization
+		}
+		c.canonical.byType = map[string]map[string][]string{}
+		for _, rule := range rules {
+			if len(rule.Canonicalization) > 0 {
+				c.canonical.byType[strings.ToLower(rule.ResourceType)] = rule.Canonicalization
+			}
+		}
+		c.canonical.loaded = true
+	}
+
+	if steps, ok := c.canonical.byType[strings.ToLower(resourceType)]; ok {
+		return steps
+	}
+	if steps, ok := c.canonical.byType["default"]; ok {
+		return steps
+	}
+	return defaultCanonicalization
+}
+
+// canonicalize applies steps to s in order.
+func canonicalize(steps []string, s string) string {
+	for _, step := range steps {
+		switch step {
+		case canonicalTrim:
+			s = strings.TrimSpace(s)
+		case canonicalLower:
+			s = strings.ToLower(s)
+		}
+	}
+	return s
+}
+
+// sameCanonicalSegments reports whether the project, purpose, system and
+// subsystem segments of plan and state canonicalize to the same values, so a
+// change in spelling alone, such as after importing a claim the service
+// recorded in lower case, keeps the claim. Both models must be resolved.
+func (c *APIClient) sameCanonicalSegments(ctx context.Context, plan, state claimResourceModel) bool {
+	planned, current := plan.flatSegments(), state.flatSegments()
+	changed := false
+	for name := range defaultCanonicalization {
+		changed = changed || !planned[name].Equal(*current[name])
+	}
+	// Only fetch the rules when a segment is spelled differently.
+	if !changed {
+		return true
+	}
+
+	rules := c.canonicalization(ctx, plan.ResourceType.ValueString())
+	for name := range defaultCanonicalization {
+		if !sameCanonical(rules[name], *planned[name], *current[name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameCanonical(steps []string, a, b types.String) bool {
+	if a.IsNull() || a.IsUnknown() || b.IsNull() || b.IsUnknown() {
+		return a.Equal(b)
+	}
+	return canonicalize(steps, a.ValueString()) == canonicalize(steps, b.ValueString())
+}
+
+// keepCanonicalSegment is a plan modifier for the effective segment
+// attributes. When the configured segments only differ from state in
+// spelling the service canonicalizes away, the claim is kept, so the value
+// the service recorded is planned rather than left unknown.
+type keepCanonicalSegment struct {
+	resource *ClaimResource
+}
+
+func (m keepCanonicalSegment) Description(context.Context) string {
+	return "Keeps the prior value when the segments only change in spelling the service canonicalizes away."
+}
+
+func (m keepCanonicalSegment) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m keepCanonicalSegment) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if m.resource.client == nil || req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	if req.StateValue.IsNull() || !req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var plan, state claimResourceModel
+	if diags := req.Plan.Get(ctx, &plan); diags.HasError() {
+		return
+	}
+	if diags := req.State.Get(ctx, &state); diags.HasError() {
+		return
+	}
+	if !plan.ResourceType.Equal(state.ResourceType) {
+		return
+	}
+	if m.resource.client.sameCanonicalSegments(ctx, plan.resolveSegments(), state.resolveSegments()) {
+		resp.PlanValue = req.StateValue
+	}
+}
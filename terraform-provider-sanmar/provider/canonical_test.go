@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSameCanonicalSegments(t *testing.T) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rules", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"rules":[
+			{"resourceType":"key_vault","canonicalization":{"project":["trim"],"purpose":["trim","lower"]}},
+			{"resourceType":"storage_account"}
+		]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	claim := func(resourceType, project, purpose string) claimResourceModel {
+		return claimResourceModel{
+			ResourceType: types.StringValue(resourceType),
+			Project:      types.StringValue(project),
+			Purpose:      types.StringValue(purpose),
+			System:       types.StringNull(),
+			Subsystem:    types.StringNull(),
+		}
+	}
+	ctx := context.Background()
+
+	if !client.sameCanonicalSegments(ctx, claim("key_vault", "atlas", "web"), claim("key_vault", "atlas", "web")) || requests.Load() != 0 {
+		t.Fatalf("identical segments should match without listing rules, got %d requests", requests.Load())
+	}
+	if !client.sameCanonicalSegments(ctx, claim("key_vault", " atlas ", "Web"), claim("key_vault", "atlas", "web")) {
+		t.Fatal("expected the reported key_vault rules to trim project and lowercase purpose")
+	}
+	if client.sameCanonicalSegments(ctx, claim("key_vault", "Atlas", "web"), claim("key_vault", "atlas", "web")) {
+		t.Fatal("key_vault rules do not lowercase project, so Atlas should be a change")
+	}
+	// Rules without canonicalization fall back to trimming and lowercasing.
+	if !client.sameCanonicalSegments(ctx, claim("storage_account", "Atlas", "web"), claim("storage_account", "atlas", "web")) {
+		t.Fatal("expected the built-in canonicalization for storage_account")
+	}
+	if client.sameCanonicalSegments(ctx, claim("storage_account", "erp", "web"), claim("storage_account", "atlas", "web")) {
+		t.Fatal("different projects should not match")
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("expected the rules to be listed once, got %d", requests.Load())
+	}
+}
+
+func TestKeepCanonicalSegmentPlansStateValue(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rules":[{"resourceType":"default","canonicalization":{"project":["trim","lower"]}}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	prior := nullClaimModel(ctx, t, schemaResp.Schema)
+	prior.ResourceType = types.StringValue("storage_account")
+	prior.Project = types.StringValue("atlas")
+	prior.EffectiveProject = types.StringValue("atlas")
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, &prior); diags.HasError() {
+		t.Fatalf("state: %v", diags)
+	}
+
+	modify := func(project string) types.String {
+		planned := prior
+		planned.Project = types.StringValue(project)
+		planned.EffectiveProject = types.StringUnknown()
+		plan := tfsdk.Plan{Schema: schemaResp.Schema}
+		if diags := plan.Set(ctx, &planned); diags.HasError() {
+			t.Fatalf("plan: %v", diags)
+		}
+		req := planmodifier.StringRequest{
+			Path:       path.Root("effective_project"),
+			Plan:       plan,
+			PlanValue:  planned.EffectiveProject,
+			State:      state,
+			StateValue: prior.EffectiveProject,
+		}
+		resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+		keepCanonicalSegment{resource: r}.PlanModifyString(ctx, req, resp)
+		return resp.PlanValue
+	}
+
+	if got := modify(" Atlas "); got.ValueString() != "atlas" {
+		t.Fatalf("expected the recorded project to be kept, got %s", got)
+	}
+	if got := modify("erp"); !got.IsUnknown() {
+		t.Fatalf("expected a new project to stay unknown, got %s", got)
+	}
+}
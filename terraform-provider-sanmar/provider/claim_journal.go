@@ -0,0 +1,369 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Phases of a journaled claim. A claim is open until it is committed, failed
+// or released.
+const (
+	journalClaiming  = "claiming"
+	journalClaimed   = "claimed"
+	journalCommitted = "committed"
+	journalFailed    = "failed"
+	journalReleased  = "released"
+)
+
+// journalEntry is one line of a claim journal.
+type journalEntry struct {
+	ID           string    `json:"id"`
+	Phase        string    `json:"phase"`
+	Time         time.Time `json:"time"`
+	ResourceType string    `json:"resource_type,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	Region       string    `json:"region,omitempty"`
+	Environment  string    `json:"environment,omitempty"`
+	// Endpoint is the service the claim was made against, so only a client
+	// for that service releases it.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// claimJournal records the claims a provider process makes until Terraform
+// has their state, so names claimed by a process that died, or whose state
+// could not be written, can be released later. Every process writes its own
+// file in the journal directory, named after its host and process ID, so
+// processes never write to each other's files. Provider aliases in a process
+// share the journal of a directory, so their claim IDs never collide.
+type claimJournal struct {
+	mu   sync.Mutex
+	file *os.File
+	now  func() time.Time
+	seq  int
+
+	// dir and refs track the clients sharing the journal; the file is
+	// closed once the last one closes it.
+	dir  string
+	refs int
+}
+
+var (
+	sharedJournalsMu sync.Mutex
+	sharedJournals   = map[string]*claimJournal{}
+)
+
+// journalReconcileMu keeps provider aliases from reconciling the same
+// journals at once.
+var journalReconcileMu sync.Mutex
+
+// journalFileName returns the journal file name for a process.
+func journalFileName(host string, pid int) string {
+	return fmt.Sprintf("%s-%d.jsonl", host, pid)
+}
+
+// parseJournalFileName returns the host and process ID of a journal file.
+func parseJournalFileName(name string) (string, int, bool) {
+	base := strings.TrimSuffix(name, ".jsonl")
+	i := strings.LastIndex(base, "-")
+	if base == name || i <= 0 {
+		return "", 0, false
+	}
+	pid, err := strconv.Atoi(base[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return base[:i], pid, true
+}
+
+func openClaimJournal(dir, host string, pid int) (*claimJournal, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create claim journal directory: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, journalFileName(host, pid)), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open claim journal: %w", err)
+	}
+	return &claimJournal{file: file, now: time.Now}, nil
+}
+
+// sharedClaimJournal returns this process's journal in dir, opening it on
+// first use.
+func sharedClaimJournal(dir, host string, pid int) (*claimJournal, error) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	sharedJournalsMu.Lock()
+	defer sharedJournalsMu.Unlock()
+	if journal, ok := sharedJournals[dir]; ok {
+		journal.refs++
+		return journal, nil
+	}
+	journal, err := openClaimJournal(dir, host, pid)
+	if err != nil {
+		return nil, err
+	}
+	journal.dir, journal.refs = dir, 1
+	sharedJournals[dir] = journal
+	return journal, nil
+}
+
+// close drops one client's use of the journal and closes the file once no
+// client uses it. Claims begun afterwards are not journaled.
+func (j *claimJournal) close() {
+	if j == nil {
+		return
+	}
+	sharedJournalsMu.Lock()
+	defer sharedJournalsMu.Unlock()
+	if j.refs--; j.refs > 0 {
+		return
+	}
+	if sharedJournals[j.dir] == j {
+		delete(sharedJournals, j.dir)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file != nil {
+		_ = j.file.Close()
+		j.file = nil
+	}
+}
+
+// write appends entry. Failures are logged rather than failing the claim,
+// which the journal only protects.
+func (j *claimJournal) write(ctx context.Context, entry journalEntry) {
+	if j.file == nil {
+		return
+	}
+	entry.Time = j.now().UTC()
+	line, err := json.Marshal(entry)
+	if err == nil {
+		_, err = j.file.Write(append(line, '\n'))
+	}
+	if err == nil {
+		// The entry only helps if it survives the process being killed.
+		err = j.file.Sync()
+	}
+	if err != nil {
+		tflog.Warn(ctx, "failed to write claim journal", map[string]any{"error": err.Error()})
+	}
+}
+
+// begin records that a claim is about to be made against endpoint and
+// returns its journal ID, which is empty once the journal is closed.
+func (j *claimJournal) begin(ctx context.Context, endpoint string, payload ClaimNameRequest) string {
+	if j == nil {
+		return ""
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return ""
+	}
+	j.seq++
+	id := strconv.Itoa(j.seq)
+	j.write(ctx, journalEntry{ID: id, Phase: journalClaiming, ResourceType: payload.ResourceType, Region: payload.Region, Environment: payload.Environment, Endpoint: endpoint})
+	return id
+}
+
+// claimed records the name the service gave the claim.
+func (j *claimJournal) claimed(ctx context.Context, id, name, region, environment string) {
+	if j == nil || id == "" {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.write(ctx, journalEntry{ID: id, Phase: journalClaimed, Name: name, Region: region, Environment: environment})
+}
+
+// finish closes a claim with the given phase.
+func (j *claimJournal) finish(ctx context.Context, id, phase string) {
+	if j == nil || id == "" {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.write(ctx, journalEntry{ID: id, Phase: phase})
+}
+
+// openClaims returns the last entry of each claim in entries that was never
+// closed, in the order the claims began.
+func openClaims(entries []journalEntry) []journalEntry {
+	last := map[string]journalEntry{}
+	var order []string
+	for _, entry := range entries {
+		prior, ok := last[entry.ID]
+		switch {
+		case !ok:
+			order = append(order, entry.ID)
+			last[entry.ID] = entry
+		case entry.Phase == journalClaimed:
+			prior.Phase, prior.Name, prior.Region, prior.Environment = entry.Phase, entry.Name, entry.Region, entry.Environment
+			last[entry.ID] = prior
+		default:
+			// Closing entries carry only the phase.
+			prior.Phase = entry.Phase
+			last[entry.ID] = prior
+		}
+	}
+
+	var open []journalEntry
+	for _, id := range order {
+		if phase := last[id].Phase; phase == journalClaiming || phase == journalClaimed {
+			open = append(open, last[id])
+		}
+	}
+	return open
+}
+
+func readJournal(path string) ([]journalEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []journalEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry journalEntry
+		// A line cut short by the process dying is skipped.
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// processAlive reports whether a process with pid is running on this host.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// On Windows, FindProcess only succeeds for running processes.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// reconcileJournals releases the names this client's endpoint left claimed
+// in the journals of provider processes on this host that are no longer
+// running, then removes those journals. A journal whose releases fail, or
+// that holds claims against other endpoints, is rewritten with the claims
+// still open so the next run, or a client for that endpoint, handles them.
+func (c *APIClient) reconcileJournals(ctx context.Context, dir, host string, pid int, alive func(int) bool) []error {
+	journalReconcileMu.Lock()
+	defer journalReconcileMu.Unlock()
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read claim journal directory: %w", err)}
+	}
+
+	var errs []error
+	for _, file := range files {
+		fileHost, filePID, ok := parseJournalFileName(file.Name())
+		if !ok || fileHost != host || filePID == pid || alive(filePID) {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name())
+		entries, err := readJournal(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read claim journal %s: %w", file.Name(), err))
+			continue
+		}
+
+		var remaining []journalEntry
+		for _, entry := range openClaims(entries) {
+			if entry.Endpoint != c.endpoint {
+				remaining = append(remaining, entry)
+				continue
+			}
+			if entry.Phase == journalClaiming {
+				// The process died waiting for the service, so the name, if
+				// any was claimed, is unknown.
+				tflog.Warn(ctx, "a claim was interrupted before the service answered; check the audit history for a leaked name", map[string]any{
+					"resource_type": entry.ResourceType,
+					"region":        entry.Region,
+					"environment":   entry.Environment,
+					"time":          entry.Time.Format(time.RFC3339),
+				})
+				continue
+			}
+
+			err := c.EnsureReleased(ctx, ReleaseRequest{
+				Name:        entry.Name,
+				Region:      entry.Region,
+				Environment: entry.Environment,
+				Reason:      "Released by the claim journal: Terraform never recorded the claim in state",
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to release %s left by an interrupted claim: %w", claimIdentity{Region: entry.Region, Environment: entry.Environment, Name: entry.Name}, err))
+				remaining = append(remaining, entry)
+				continue
+			}
+			tflog.Info(ctx, "released name left by an interrupted claim", map[string]any{"name": entry.Name})
+		}
+
+		if len(remaining) == 0 {
+			err = os.Remove(path)
+		} else {
+			err = rewriteJournal(path, remaining)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update claim journal %s: %w", file.Name(), err))
+		}
+	}
+	return errs
+}
+
+func rewriteJournal(path string, entries []journalEntry) error {
+	var data []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// EnableClaimJournal journals claims in dir until their state is written,
+// after first releasing the names left claimed by earlier provider processes
+// on this host that died or could not write state. Release failures are
+// returned but do not stop the journal from being enabled.
+func (c *APIClient) EnableClaimJournal(ctx context.Context, dir string) ([]error, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine host name: %w", err)
+	}
+	pid := os.Getpid()
+
+	journal, err := sharedClaimJournal(dir, host, pid)
+	if err != nil {
+		return nil, err
+	}
+	c.journal.close()
+	c.journal = journal
+	return c.reconcileJournals(ctx, dir, host, pid, processAlive), nil
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReconcileJournalsReleasesUncommittedClaims(t *testing.T) {
+	var released []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload ReleaseRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Name == "stuck" {
+			http.Error(w, `{"message":"unavailable"}`, http.StatusInternalServerError)
+			return
+		}
+		released = append(released, payload.Name)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	var otherReleased []string
+	otherSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload ReleaseRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		otherReleased = append(otherReleased, payload.Name)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer otherSrv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	journal := func(pid int, names ...string) {
+		j, err := openClaimJournal(dir, "build01", pid)
+		if err != nil {
+			t.Fatalf("openClaimJournal: %v", err)
+		}
+		defer j.file.Close()
+		for _, name := range names {
+			id := j.begin(ctx, srv.URL, ClaimNameRequest{ResourceType: "storage_account", Region: "wus2", Environment: "prd"})
+			j.claimed(ctx, id, name, "wus2", "prd")
+		}
+	}
+
+	// A dead process that committed one claim, lost another, was killed
+	// waiting for a third, and lost a fourth claimed from another service.
+	journal(100, "committed", "leaked", "stuck")
+	dead, _ := openClaimJournal(dir, "build01", 100)
+	dead.seq = 10
+	dead.finish(ctx, "1", journalCommitted)
+	dead.begin(ctx, srv.URL, ClaimNameRequest{ResourceType: "key_vault", Region: "wus2", Environment: "prd"})
+	other := dead.begin(ctx, otherSrv.URL, ClaimNameRequest{ResourceType: "key_vault", Region: "eus2", Environment: "prd"})
+	dead.claimed(ctx, other, "elsewhere", "eus2", "prd")
+	dead.file.Close()
+	// A running process and one on another host are left alone.
+	journal(200, "running")
+	if err := os.WriteFile(filepath.Join(dir, journalFileName("build02", 300)), []byte(`{"id":"1","phase":"claimed","name":"elsewhere"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := client.reconcileJournals(ctx, dir, "build01", 400, func(pid int) bool { return pid == 200 })
+	if len(errs) != 1 {
+		t.Fatalf("expected the failed release to be reported, got %v", errs)
+	}
+	if !reflect.DeepEqual(released, []string{"leaked"}) {
+		t.Fatalf("expected only the uncommitted claim to be released, got %v", released)
+	}
+
+	entries, err := readJournal(filepath.Join(dir, journalFileName("build01", 100)))
+	if err != nil {
+		t.Fatalf("expected the journal with a failed release to be kept: %v", err)
+	}
+	if open := openClaims(entries); len(open) != 2 || open[0].Name != "stuck" || open[1].Endpoint != otherSrv.URL || open[1].ResourceType != "key_vault" {
+		t.Fatalf("expected the failed release and the other service's claim to stay open, got %+v", open)
+	}
+	for _, name := range []string{journalFileName("build01", 200), journalFileName("build02", 300)} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be kept: %v", name, err)
+		}
+	}
+
+	// The retried release succeeds once the service recovers.
+	released = nil
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		released = append(released, "stuck")
+		w.WriteHeader(http.StatusOK)
+	})
+	if errs := client.reconcileJournals(ctx, dir, "build01", 400, func(pid int) bool { return pid == 200 }); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if len(released) != 1 {
+		t.Fatalf("expected the failed release to be retried, got %v", released)
+	}
+	entries, err = readJournal(filepath.Join(dir, journalFileName("build01", 100)))
+	if err != nil {
+		t.Fatalf("expected the journal to be kept for the other service: %v", err)
+	}
+	if open := openClaims(entries); len(open) != 1 || open[0].Name != "elsewhere" {
+		t.Fatalf("expected only the other service's claim to stay open, got %+v", open)
+	}
+
+	// A client for the other service releases its claim and removes the journal.
+	otherClient, err := NewAPIClient(ctx, otherSrv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	if errs := otherClient.reconcileJournals(ctx, dir, "build01", 400, func(pid int) bool { return pid == 200 }); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !reflect.DeepEqual(otherReleased, []string{"elsewhere"}) {
+		t.Fatalf("expected the other service's claim to be released there, got %v", otherReleased)
+	}
+	if _, err := os.Stat(filepath.Join(dir, journalFileName("build01", 100))); !os.IsNotExist(err) {
+		t.Fatalf("expected the reconciled journal to be removed, got %v", err)
+	}
+}
+
+func TestClaimJournalSharedAndClosed(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	first, err := NewAPIClient(ctx, "https://naming.example", "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	second, err := NewAPIClient(ctx, "https://naming.other.example", "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	for _, c := range []*APIClient{first, second} {
+		if _, err := c.EnableClaimJournal(ctx, dir); err != nil {
+			t.Fatalf("EnableClaimJournal: %v", err)
+		}
+	}
+	if first.journal != second.journal {
+		t.Fatal("expected aliases to share the directory's journal")
+	}
+
+	payload := ClaimNameRequest{ResourceType: "key_vault", Region: "wus2", Environment: "prd"}
+	if a, b := first.journal.begin(ctx, first.endpoint, payload), second.journal.begin(ctx, second.endpoint, payload); a == b {
+		t.Fatalf("expected distinct claim IDs, got %q twice", a)
+	}
+
+	first.Close()
+	if second.journal.file == nil {
+		t.Fatal("expected the journal to stay open while a client uses it")
+	}
+	second.Close()
+	if second.journal.file != nil || second.journal.begin(ctx, second.endpoint, payload) != "" {
+		t.Fatal("expected the journal to be closed with its last client")
+	}
+}
+
+func TestParseJournalFileName(t *testing.T) {
+	host, pid, ok := parseJournalFileName(journalFileName("build-agent-7", 4242))
+	if !ok || host != "build-agent-7" || pid != 4242 {
+		t.Fatalf("got %q %d %v", host, pid, ok)
+	}
+	for _, name := range []string{"notes.txt", "build.jsonl", "build-x.jsonl"} {
+		if _, _, ok := parseJournalFileName(name); ok {
+			t.Errorf("expected %s to be ignored", name)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// claimAttemptKey is the private state key holding the idempotency key and
+// correlation ID of the claim that made the resource's name.
+const claimAttemptKey = "claim_attempt"
+
+// claimAttempt identifies a claim request. The service answers a request
+// whose idempotency key it has seen with the name it already gave, so an
+// interrupted claim can be repeated without claiming a second name.
+type claimAttempt struct {
+	IdempotencyKey string `json:"idempotency_key"`
+	CorrelationID  string `json:"correlation_id"`
+	// Pending is set while it is unknown whether the claim was made.
+	Pending bool `json:"pending,omitempty"`
+	// OfflineAt is when the name was generated offline, until the name is
+	// registered.
+	OfflineAt string `json:"offline_at,omitempty"`
+}
+
+func newClaimAttempt() (claimAttempt, error) {
+	key, err := newSessionID()
+	if err != nil {
+		return claimAttempt{}, err
+	}
+	correlationID, err := newSessionID()
+	if err != nil {
+		return claimAttempt{}, err
+	}
+	return claimAttempt{IdempotencyKey: key, CorrelationID: correlationID}, nil
+}
+
+// apply returns payload carrying the attempt's keys.
+func (a claimAttempt) apply(payload ClaimNameRequest) ClaimNameRequest {
+	payload.IdempotencyKey = &a.IdempotencyKey
+	payload.CorrelationID = &a.CorrelationID
+	return payload
+}
+
+func (a claimAttempt) save(ctx context.Context, private privateStateSetter) diag.Diagnostics {
+	value, err := json.Marshal(a)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to record claim attempt", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, claimAttemptKey, value)
+}
+
+// loadClaimAttempt returns the attempt recorded in private state. Missing or
+// unreadable entries are reported as absent.
+func loadClaimAttempt(ctx context.Context, private privateStateGetter) (claimAttempt, bool) {
+	if private == nil {
+		return claimAttempt{}, false
+	}
+	value, diags := private.GetKey(ctx, claimAttemptKey)
+	if diags.HasError() || len(value) == 0 {
+		return claimAttempt{}, false
+	}
+	var attempt claimAttempt
+	if err := json.Unmarshal(value, &attempt); err != nil || attempt.IdempotencyKey == "" {
+		return claimAttempt{}, false
+	}
+	return attempt, true
+}
+
+// claimOutcomeUnknown reports whether a claim that failed with err may still
+// have been made. Only a client error from the service is a definite refusal;
+// the request may have been handled before a timeout, dropped connection, or
+// server error.
+func claimOutcomeUnknown(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// pendingClaimState returns the planned state with the values only a claim
+// can fill, such as the name, set to null. It is saved when the outcome of a
+// claim is unknown so the claim can be resumed.
+func pendingClaimState(planned tftypes.Value) (tftypes.Value, error) {
+	return tftypes.Transform(planned, func(_ *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() {
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		return v, nil
+	})
+}
+
+// resumedClaimPlan returns planned with the null top-level attributes named
+// in computed set to unknown, so the values Update fills in when it resumes a
+// pending claim are expected by Terraform.
+func resumedClaimPlan(planned tftypes.Value, computed map[string]bool) (tftypes.Value, error) {
+	return tftypes.Transform(planned, func(p *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		steps := p.Steps()
+		if len(steps) != 1 || !v.IsNull() {
+			return v, nil
+		}
+		if name, ok := steps[0].(tftypes.AttributeName); ok && computed[string(name)] {
+			return tftypes.NewValue(v.Type(), tftypes.UnknownValue), nil
+		}
+		return v, nil
+	})
+}
+
+// resumeClaim repeats an interrupted claim with its idempotency key. The
+// service answers with the name the first request claimed, or claims one now
+// if that request never reached it. It is only called while applying, so
+// plans never claim names.
+func (r *ClaimResource) resumeClaim(ctx context.Context, state claimResourceModel, attempt claimAttempt) (*ClaimNameResponse, error) {
+	payload, diags := buildClaimPayload(ctx, state)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed to rebuild the claim request from state: %s", diags.Errors()[0].Detail())
+	}
+
+	tflog.Info(ctx, "resuming interrupted claim via SanMar provider", map[string]any{
+		"resource_type":  payload.ResourceType,
+		"region":         payload.Region,
+		"environment":    payload.Environment,
+		"correlation_id": attempt.CorrelationID,
+	})
+	return r.client.ClaimName(WithClaimPriority(ctx, state.Priority.ValueInt64()), attempt.apply(payload))
+}
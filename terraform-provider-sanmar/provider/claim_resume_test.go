@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type memoryPrivateState map[string][]byte
+
+func (m memoryPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return m[key], nil
+}
+
+func (m memoryPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	m[key] = value
+	return nil
+}
+
+func TestClaimAttemptRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	attempt, err := newClaimAttempt()
+	if err != nil {
+		t.Fatalf("newClaimAttempt: %v", err)
+	}
+	if attempt.IdempotencyKey == attempt.CorrelationID {
+		t.Fatalf("expected distinct keys, got %+v", attempt)
+	}
+
+	private := memoryPrivateState{}
+	if _, ok := loadClaimAttempt(ctx, private); ok {
+		t.Fatalf("expected no attempt in empty private state")
+	}
+	attempt.Pending = true
+	if diags := attempt.save(ctx, private); diags.HasError() {
+		t.Fatalf("save: %v", diags)
+	}
+	got, ok := loadClaimAttempt(ctx, private)
+	if !ok || got != attempt {
+		t.Fatalf("expected %+v, got %+v", attempt, got)
+	}
+
+	payload := attempt.apply(ClaimNameRequest{ResourceType: "storage_account"})
+	body, _ := json.Marshal(payload)
+	var sent map[string]any
+	_ = json.Unmarshal(body, &sent)
+	if sent["idempotency_key"] != attempt.IdempotencyKey || sent["correlation_id"] != attempt.CorrelationID {
+		t.Fatalf("expected the attempt's keys in the claim, got %s", body)
+	}
+}
+
+func TestClaimOutcomeUnknown(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"conflict":     {&APIError{StatusCode: http.StatusConflict}, false},
+		"invalid":      {&APIError{StatusCode: http.StatusBadRequest}, false},
+		"server error": {&APIError{StatusCode: http.StatusBadGateway}, true},
+		"timeout":      {context.DeadlineExceeded, true},
+		"network":      {errors.New("connection reset by peer"), true},
+	}
+	for name, tc := range cases {
+		if got := claimOutcomeUnknown(tc.err); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", name, tc.want, got)
+		}
+	}
+}
+
+func TestPendingClaimState(t *testing.T) {
+	ctx := context.Background()
+	r := &ClaimResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, tftypes.UnknownValue)
+	}
+	values["resource_type"] = tftypes.NewValue(tftypes.String, "storage_account")
+	values["region"] = tftypes.NewValue(tftypes.String, "wus2")
+	values["environment"] = tftypes.NewValue(tftypes.String, "prd")
+
+	raw, err := pendingClaimState(tftypes.NewValue(objType, values))
+	if err != nil {
+		t.Fatalf("pendingClaimState: %v", err)
+	}
+	if !raw.IsFullyKnown() {
+		t.Fatalf("expected no unknown values in pending state")
+	}
+
+	var got claimResourceModel
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+	if diags := state.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !got.Name.IsNull() || !got.ID.IsNull() {
+		t.Fatalf("expected no name in pending state, got %s %s", got.ID, got.Name)
+	}
+	if got.ResourceType.ValueString() != "storage_account" || got.Region.ValueString() != "wus2" {
+		t.Fatalf("expected planned values to be kept, got %s %s", got.ResourceType, got.Region)
+	}
+}
+
+func TestResumedClaimPlan(t *testing.T) {
+	objType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"name":          tftypes.String,
+		"region":        tftypes.String,
+		"project":       tftypes.String,
+		"resource_type": tftypes.String,
+	}}
+	planned := tftypes.NewValue(objType, map[string]tftypes.Value{
+		"name":          tftypes.NewValue(tftypes.String, nil),
+		"region":        tftypes.NewValue(tftypes.String, "wus2"),
+		"project":       tftypes.NewValue(tftypes.String, nil),
+		"resource_type": tftypes.NewValue(tftypes.String, "storage_account"),
+	})
+
+	raw, err := resumedClaimPlan(planned, map[string]bool{"name": true, "region": true})
+	if err != nil {
+		t.Fatalf("resumedClaimPlan: %v", err)
+	}
+	var values map[string]tftypes.Value
+	if err := raw.As(&values); err != nil {
+		t.Fatalf("As: %v", err)
+	}
+	if values["name"].IsKnown() {
+		t.Fatalf("expected the computed name to be unknown, got %s", values["name"])
+	}
+	if !values["region"].IsKnown() || values["region"].IsNull() {
+		t.Fatalf("expected planned values to be kept, got %s", values["region"])
+	}
+	if !values["project"].IsKnown() || !values["project"].IsNull() {
+		t.Fatalf("expected null configurable values to stay null, got %s", values["project"])
+	}
+}
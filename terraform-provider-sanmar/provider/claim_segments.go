@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// claimSegmentAttrTypes describes the nested segments attribute of sanmar_claim.
+var claimSegmentAttrTypes = map[string]attr.Type{
+	"project":   types.StringType,
+	"purpose":   types.StringType,
+	"system":    types.StringType,
+	"subsystem": types.StringType,
+	"index":     indexNumberType{},
+}
+
+// flatSegments returns the deprecated top-level string segment attributes by
+// name. The index is a number, so it is not among them.
+func (m *claimResourceModel) flatSegments() map[string]*types.String {
+	return map[string]*types.String{
+		"project":   &m.Project,
+		"purpose":   &m.Purpose,
+		"system":    &m.System,
+		"subsystem": &m.Subsystem,
+	}
+}
+
+// segmentValues returns every top-level segment by name as a string, with
+// the index unpadded, for code that only reads them.
+func (m claimResourceModel) segmentValues() map[string]types.String {
+	values := make(map[string]types.String, len(claimSegmentAttrTypes))
+	for name, flat := range m.flatSegments() {
+		values[name] = *flat
+	}
+	values["index"] = m.Index.segment()
+	return values
+}
+
+// nestedSegment returns the named value from the segments object, which is
+// unknown while the whole object is.
+func (m claimResourceModel) nestedSegment(name string) types.String {
+	if m.Segments.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if m.Segments.IsNull() {
+		return types.StringNull()
+	}
+	switch v := m.Segments.Attributes()[name].(type) {
+	case types.String:
+		return v
+	case indexNumberValue:
+		return v.segment()
+	}
+	return types.StringNull()
+}
+
+// nestedIndex returns the index from the segments object, which is unknown
+// while the whole object is.
+func (m claimResourceModel) nestedIndex() indexNumberValue {
+	if m.Segments.IsUnknown() {
+		return indexNumberValue{Int64Value: types.Int64Unknown()}
+	}
+	if v, ok := m.Segments.Attributes()["index"].(indexNumberValue); ok {
+		return v
+	}
+	return indexNumberValue{Int64Value: types.Int64Null()}
+}
+
+// unsetSegment reports whether v leaves a segment to the service. An empty
+// string counts as unset, the same as null, because the audit API returns
+// omitted segments as empty strings and modules often pass "" for "none".
+func unsetSegment(v types.String) bool {
+	return v.IsNull() || (!v.IsUnknown() && v.ValueString() == "")
+}
+
+// resolveSegments returns a copy of m whose top-level segment attributes
+// carry the values set in the segments object, so code that builds claims
+// reads one set of fields whichever form the configuration used. Unset
+// segments, including empty strings, resolve to null.
+func (m claimResourceModel) resolveSegments() claimResourceModel {
+	for name, flat := range m.flatSegments() {
+		if unsetSegment(*flat) {
+			*flat = m.nestedSegment(name)
+		}
+		if unsetSegment(*flat) {
+			*flat = types.StringNull()
+		}
+	}
+	if m.Index.IsNull() {
+		m.Index = m.nestedIndex()
+	}
+	return m
+}
+
+// segmentPath returns where the named segment is configured: inside the
+// segments object when only that sets it, otherwise the top-level attribute.
+func (m claimResourceModel) segmentPath(name string) path.Path {
+	flat, ok := m.segmentValues()[name]
+	if ok && unsetSegment(flat) && !unsetSegment(m.nestedSegment(name)) {
+		return path.Root("segments").AtName(name)
+	}
+	return path.Root(name)
+}
+
+// segmentsFromFlat builds the segments object from the top-level attributes.
+func (m claimResourceModel) segmentsFromFlat() types.Object {
+	values := make(map[string]attr.Value, len(claimSegmentAttrTypes))
+	for name, flat := range m.flatSegments() {
+		values[name] = *flat
+	}
+	values["index"] = m.Index
+	return types.ObjectValueMust(claimSegmentAttrTypes, values)
+}
+
+// validateSegmentConflicts rejects segments set both at the top level and
+// inside the segments object.
+func validateSegmentConflicts(m claimResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, name := range []string{"project", "purpose", "system", "subsystem", "index"} {
+		if !unsetSegment(m.segmentValues()[name]) && !unsetSegment(m.nestedSegment(name)) {
+			diags.AddAttributeError(path.Root("segments").AtName(name), "Conflicting segment values",
+				"Set "+name+" either at the top level or in segments, not both.")
+		}
+	}
+	return diags
+}
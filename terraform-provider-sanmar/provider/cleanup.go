@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"errors"
+)
+
+// CleanupMetadataKey is the claim metadata key that records the cleanup
+// workspace a claim was made from.
+const CleanupMetadataKey = "sanmar_workspace"
+
+// SetCleanupWorkspace tags every claim with workspace so sanmarctl cleanup
+// can find the claims this configuration made that are no longer in state.
+func (c *APIClient) SetCleanupWorkspace(workspace string) error {
+	if workspace == "" {
+		return errors.New("cleanup workspace must not be empty")
+	}
+	c.cleanupWorkspace = workspace
+	return nil
+}
+
+// tagWorkspace adds the cleanup workspace to the claim metadata unless the
+// configuration already sets the key.
+func (c *APIClient) tagWorkspace(payload ClaimNameRequest) ClaimNameRequest {
+	if c.cleanupWorkspace == "" {
+		return payload
+	}
+	if _, exists := payload.Metadata[CleanupMetadataKey]; exists {
+		return payload
+	}
+	metadata := make(map[string]any, len(payload.Metadata)+1)
+	for k, v := range payload.Metadata {
+		metadata[k] = v
+	}
+	metadata[CleanupMetadataKey] = c.cleanupWorkspace
+	payload.Metadata = metadata
+	return payload
+}
@@ -0,0 +1,23 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestCleanupWorkspaceTagsClaims(t *testing.T) {
+	client := &APIClient{cleanupWorkspace: "payments-dev"}
+	payload := client.tagWorkspace(ClaimNameRequest{Metadata: map[string]any{"owner": "team"}})
+	if payload.Metadata[CleanupMetadataKey] != "payments-dev" || payload.Metadata["owner"] != "team" {
+		t.Fatalf("unexpected metadata: %v", payload.Metadata)
+	}
+
+	explicit := client.tagWorkspace(ClaimNameRequest{Metadata: map[string]any{CleanupMetadataKey: "shared"}})
+	if explicit.Metadata[CleanupMetadataKey] != "shared" {
+		t.Fatalf("configured metadata must win, got %v", explicit.Metadata)
+	}
+
+	untagged := (&APIClient{}).tagWorkspace(ClaimNameRequest{})
+	if _, ok := untagged.Metadata[CleanupMetadataKey]; ok {
+		t.Fatalf("claims must not be tagged without a cleanup workspace, got %v", untagged.Metadata)
+	}
+}
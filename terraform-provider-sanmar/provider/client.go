@@ -3,17 +3,20 @@ package provider
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
-	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // RetryConfig configures retry behaviour for API calls.
@@ -27,44 +30,175 @@ type RetryConfig struct {
 type APIClient struct {
 	endpoint string
 	scope    string
-	cred     *azidentity.DefaultAzureCredential
+	cred     azcore.TokenCredential
 	retry    RetryConfig
 	http     *http.Client
+
+	// writeRetry applies to requests that change state; retry applies to reads.
+	writeRetry RetryConfig
+	// operationTimeout stops retries that would run past this long after a
+	// request was first sent; zero leaves only the attempt limit.
+	operationTimeout time.Duration
+
+	// sessionID is forwarded with claims that do not set their own session.
+	sessionID string
+	// indexReuse is forwarded with claims that do not set their own index
+	// reuse policy.
+	indexReuse string
+	// indexWidth, when set, is the number of digits claim indices are padded
+	// to instead of defaultIndexWidth.
+	indexWidth int
+	// validationMode is "warn" when convention violations are reported as
+	// warnings rather than errors.
+	validationMode string
+	// allowedEnvironments restricts claim environments when non-empty.
+	allowedEnvironments []string
+	// batcher coalesces concurrent claims when claim batching is enabled.
+	batcher *claimBatcher
+	// readCacheTTL lets claim refreshes skip the audit call for claims
+	// verified more recently than this.
+	readCacheTTL time.Duration
+	// expiryWarningWindow is how long before expires_at claims warn.
+	expiryWarningWindow time.Duration
+	// leaseHorizon, when set, warns at plan about claims whose lease ends
+	// within it.
+	leaseHorizon time.Duration
+	// offlineSlugs maps resource types to slugs for claims that fall back to
+	// generating their name offline.
+	offlineSlugs map[string]string
+	// deprecations tracks endpoints the service reported as deprecated.
+	deprecations deprecationNotices
+	// hmac, when set, signs requests instead of sending a bearer token.
+	hmac *HMACCredentials
+	// scheduler rate limits claims by priority when a claim rate is set.
+	scheduler *claimScheduler
+	// shared holds the state common to clients for the same endpoint and scope.
+	shared *sharedConnection
+	// auditLog records claims and releases locally when audit_log_path is set.
+	auditLog *auditLog
+	// planReport lists planned claims and releases when plan_report_path is
+	// set.
+	planReport *planReport
+	// journal records claims until their state is written when
+	// claim_journal_dir is set.
+	journal *claimJournal
+	// cleanupWorkspace tags every claim when cleanup_workspace is set.
+	cleanupWorkspace string
+	// projects caches the project registry for plan-time validation.
+	projects *projectCache
+	// canonical caches how the service canonicalizes claim segments.
+	canonical *canonicalCache
+	// slugDefaults caches the segment defaults declared in the slug table.
+	slugDefaults *slugDefaultsCache
+	// plannedNames records the names planned this run to catch duplicates.
+	plannedNames *plannedNames
+	// collisionCheck looks up the name each new claim would take at plan
+	// time and warns when another workspace holds it.
+	collisionCheck bool
+	// strictDecoding warns about response fields the provider does not know.
+	strictDecoding bool
+	// telemetry sets the User-Agent and run headers sent with every request.
+	telemetry telemetry
+	// operationPollInterval is how often long-running claims are polled.
+	operationPollInterval time.Duration
+	// asyncClaims asks the service to answer claims with an operation to
+	// poll rather than holding the request open.
+	asyncClaims bool
 }
 
 // NewAPIClient constructs a client with the supplied configuration.
 func NewAPIClient(ctx context.Context, endpoint, scope string, retry RetryConfig) (*APIClient, error) {
-	cred, err := azidentity.NewDefaultAzureCredential(nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize DefaultAzureCredential: %w", err)
-	}
-
 	ep := strings.TrimSuffix(endpoint, "/")
 	if ep == "" {
 		ep = "http://localhost:7071"
 	}
 
-	if retry.MaxAttempts <= 0 {
-		retry.MaxAttempts = 1
-	}
-	if retry.MinBackoff <= 0 {
-		retry.MinBackoff = 500 * time.Millisecond
+	conn, err := sharedConnectionFor(ep, scope)
+	if err != nil {
+		return nil, err
 	}
-	if retry.MaxBackoff < retry.MinBackoff {
-		retry.MaxBackoff = retry.MinBackoff
+	if _, ok := splitUnixEndpoint(ep); ok {
+		ep = unixSocketHost
 	}
 
+	retry = retry.normalized()
+
 	return &APIClient{
-		endpoint: ep,
-		scope:    scope,
-		cred:     cred,
-		retry:    retry,
-		http: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		endpoint:   ep,
+		scope:      scope,
+		cred:       conn.cred,
+		retry:      retry,
+		writeRetry: retry,
+		http:       conn.http,
+		shared:     conn,
+
+		expiryWarningWindow:   defaultExpiryWarningWindow,
+		operationPollInterval: defaultOperationPollInterval,
+		projects:              &projectCache{},
+		canonical:             &canonicalCache{},
+		slugDefaults:          &slugDefaultsCache{},
+		plannedNames:          &plannedNames{},
 	}, nil
 }
 
+// normalized fills in defaults for unset or inconsistent retry settings.
+func (r RetryConfig) normalized() RetryConfig {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = 1
+	}
+	if r.MinBackoff <= 0 {
+		r.MinBackoff = 500 * time.Millisecond
+	}
+	if r.MaxBackoff < r.MinBackoff {
+		r.MaxBackoff = r.MinBackoff
+	}
+	return r
+}
+
+// SetWriteRetry sets the retry policy for non-GET requests such as claims and
+// releases. By default writes use the same policy as reads.
+func (c *APIClient) SetWriteRetry(retry RetryConfig) {
+	c.writeRetry = retry.normalized()
+}
+
+// SetAttemptTimeout limits how long a single HTTP attempt, including reading
+// the response, may take before it fails and is retried. Claims that time out
+// are not retried, since the service may still make them. The default is 30s.
+func (c *APIClient) SetAttemptTimeout(timeout time.Duration) {
+	// Other clients share the default connection, so give this one its own
+	// client; the transport and its connection pool are still shared.
+	c.http = &http.Client{
+		Timeout:   timeout,
+		Transport: c.http.Transport,
+	}
+}
+
+// SetOperationTimeout limits how long a request may keep retrying. No new
+// attempt is started once the back-off would end past the deadline, so a
+// request can overrun it by at most one attempt timeout.
+func (c *APIClient) SetOperationTimeout(timeout time.Duration) {
+	c.operationTimeout = timeout
+}
+
+// retryFor returns the retry policy for an HTTP method.
+func (c *APIClient) retryFor(method string) RetryConfig {
+	if method == http.MethodGet || method == http.MethodHead {
+		return c.retry
+	}
+	return c.writeRetry
+}
+
+// newSessionID returns a random RFC 4122 version 4 UUID.
+func newSessionID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
 func (c *APIClient) buildRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
 	var reader io.Reader
 	if body != nil {
@@ -80,89 +214,349 @@ func (c *APIClient) buildRequest(ctx context.Context, method, path string, body
 	if err != nil {
 		return nil, fmt.Errorf("failed to build request: %w", err)
 	}
+	return c.prepareRequest(ctx, req, body != nil)
+}
 
-	if body != nil {
+// prepareRequest sets the headers every request carries and authorizes it.
+// The body, if any, must already be set so HMAC signatures cover it.
+func (c *APIClient) prepareRequest(ctx context.Context, req *http.Request, hasBody bool) (*http.Request, error) {
+	if hasBody {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	c.telemetry.apply(req.Header)
 
-	if c.scope != "" {
-		token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{c.scope}})
-		if err != nil {
-			return nil, fmt.Errorf("failed to acquire access token: %w", err)
-		}
-		req.Header.Set("Authorization", "Bearer "+token.Token)
+	if err := c.authorize(ctx, req); err != nil {
+		return nil, err
 	}
 
 	return req, nil
 }
 
+// endpointPath converts a URL returned by the service, such as an operation
+// or next page link, into a path for buildRequest. Absolute URLs must be on
+// the configured endpoint so credentials are never sent elsewhere.
+func (c *APIClient) endpointPath(location string) (string, error) {
+	base, err := url.Parse(c.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint %q: %w", c.endpoint, err)
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", location, err)
+	}
+	u = base.ResolveReference(u)
+	if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return "", fmt.Errorf("URL %q is not on the naming service endpoint", location)
+	}
+	// Requests are built relative to the endpoint, which may have a base path.
+	return strings.TrimPrefix(u.RequestURI(), strings.TrimSuffix(base.Path, "/")), nil
+}
+
+// authorize signs the request when HMAC credentials are configured, or sets
+// the bearer token header when a scope is configured.
+func (c *APIClient) authorize(ctx context.Context, req *http.Request) error {
+	if c.hmac != nil {
+		return c.hmac.sign(req, time.Now())
+	}
+	if c.scope == "" {
+		return nil
+	}
+	token, err := c.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{c.scope}})
+	if err != nil {
+		return fmt.Errorf("failed to acquire access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	return nil
+}
+
+// tokenRejected reports whether the service rejected the bearer token itself,
+// as opposed to denying the caller access.
+func tokenRejected(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return true
+	case http.StatusForbidden:
+		return strings.Contains(resp.Header.Get("WWW-Authenticate"), "invalid_token")
+	}
+	return false
+}
+
+// retryableStatus reports whether a response status is worth retrying.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || (code >= 500 && code <= 599)
+}
+
+// isClaimRequest reports whether req claims names, which is not safe to
+// repeat once the service may have received it.
+func isClaimRequest(req *http.Request) bool {
+	return req.Method == http.MethodPost &&
+		(strings.HasSuffix(req.URL.Path, "/api/claim") || strings.HasSuffix(req.URL.Path, "/api/claim/batch"))
+}
+
+// attemptTimedOut reports whether err is an attempt that hit the attempt
+// timeout after connecting, rather than one that never connected.
+func attemptTimedOut(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout() && !connectFailed(err)
+}
+
+// rewindBody resets the request body so the request can be sent again.
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	req.Body = body
+	return nil
+}
+
+// requestNotSentError wraps the error of a request that never reached the
+// service: every attempt failed to connect, so the service cannot have acted
+// on it.
+type requestNotSentError struct {
+	err error
+}
+
+func (e *requestNotSentError) Error() string { return e.err.Error() }
+func (e *requestNotSentError) Unwrap() error { return e.err }
+
+// requestNeverSent reports whether err is from a request that never reached
+// the service.
+func requestNeverSent(err error) bool {
+	var notSent *requestNotSentError
+	return errors.As(err, &notSent)
+}
+
+// connectFailed reports whether err means the connection to the service was
+// never made, such as a failed DNS lookup or a refused connection.
+func connectFailed(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// discard drains and closes a response that will not be returned.
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
 func (c *APIClient) doRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
+	retry := c.retryFor(req.Method)
 	attempts := 0
-	backoff := c.retry.MinBackoff
+	reauthorized := false
+	backoff := retry.MinBackoff
+	var deadline time.Time
+	if c.operationTimeout > 0 {
+		deadline = time.Now().Add(c.operationTimeout)
+	}
+	// sent records whether any attempt may have reached the service, since
+	// a request that never did is known to have had no effect.
+	sent := false
+	failed := func(err error) (*http.Response, error) {
+		if !sent {
+			return nil, &requestNotSentError{err: err}
+		}
+		return nil, err
+	}
 	for {
 		attempts++
+		started := time.Now()
 		resp, err := c.http.Do(req)
-		if err == nil && resp.StatusCode < 500 {
-			return resp, nil
+		metrics.observeRequest(req.Method, resp, err, time.Since(started))
+		if err == nil {
+			c.deprecations.note(req, resp)
+		}
+		if err == nil || !connectFailed(err) {
+			sent = true
 		}
 
-		if err == nil {
-			if resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && resp.StatusCode <= 599) {
-				// read body for logging and close before retrying
-				io.Copy(io.Discard, resp.Body)
-				resp.Body.Close()
-			} else {
-				return resp, nil
+		// Tokens are fetched when the request is built and can expire while
+		// waiting between retries; fetch a fresh one and try once more.
+		if err == nil && c.scope != "" && !reauthorized && tokenRejected(resp) {
+			discard(resp)
+			reauthorized = true
+			attempts--
+			if err := c.authorize(ctx, req); err != nil {
+				return nil, err
+			}
+			if err := rewindBody(req); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
-		if attempts >= c.retry.MaxAttempts {
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		// A claim whose attempt timed out may still be running on the
+		// service, and sending it again could claim a second name.
+		if err != nil && isClaimRequest(req) && attemptTimedOut(err) {
+			return nil, err
+		}
+
+		outOfTime := !deadline.IsZero() && time.Now().Add(backoff).After(deadline)
+		if attempts >= retry.MaxAttempts || outOfTime {
 			if err != nil {
-				return nil, err
+				return failed(err)
 			}
 			return resp, nil
 		}
 
-		select {
-		case <-time.After(backoff):
-		case <-ctx.Done():
+		fields := map[string]any{
+			"method":       req.Method,
+			"path":         req.URL.Path,
+			"attempt":      attempts,
+			"max_attempts": retry.MaxAttempts,
+			"backoff":      backoff.String(),
+		}
+		last := ""
+		if err == nil {
+			last = fmt.Sprintf("last status %d", resp.StatusCode)
+			fields["status"] = resp.StatusCode
+			discard(resp)
+		} else {
+			last = "last error: " + err.Error()
+			fields["error"] = err.Error()
+		}
+		metrics.observeRetry(req.Method)
+		tflog.Warn(ctx, fmt.Sprintf("attempt %d/%d, backing off %s, %s", attempts, retry.MaxAttempts, backoff, last), fields)
+
+		if waitErr := waitBackoff(ctx, backoff, fields); waitErr != nil {
 			if err != nil {
-				return nil, err
+				return failed(err)
 			}
-			return nil, ctx.Err()
+			return nil, waitErr
 		}
 
 		backoff *= 2
-		if backoff > c.retry.MaxBackoff {
-			backoff = c.retry.MaxBackoff
+		if backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+
+		if err := rewindBody(req); err != nil {
+			return nil, err
+		}
+		// Signatures carry a timestamp, so re-sign after waiting.
+		if c.hmac != nil {
+			if err := c.hmac.sign(req, time.Now()); err != nil {
+				return nil, err
+			}
 		}
 	}
 }
 
+// retryProgressInterval is how often a back-off logs that it is still waiting.
+var retryProgressInterval = 10 * time.Second
+
+// waitBackoff waits for backoff, logging every retryProgressInterval so that
+// long waits do not look like a hang. It returns early with ctx's error if
+// ctx ends first.
+func waitBackoff(ctx context.Context, backoff time.Duration, fields map[string]any) error {
+	for remaining := backoff; remaining > 0; {
+		step := min(remaining, retryProgressInterval)
+		timer := time.NewTimer(step)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		if remaining -= step; remaining > 0 {
+			tflog.Warn(ctx, fmt.Sprintf("still backing off, %s remaining", remaining), fields)
+		}
+	}
+	return nil
+}
+
+// FieldError describes a validation failure for a single request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// APIError is returned for unexpected HTTP statuses from the service.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Fields     []FieldError
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Message)
+}
+
 func decodeError(resp *http.Response) error {
 	if resp == nil {
 		return errors.New("no response received")
 	}
 	defer resp.Body.Close()
 	content, _ := io.ReadAll(resp.Body)
-	if len(content) == 0 {
-		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(content))}
+
+	// Validation failures may carry a JSON body with per-field errors.
+	var body struct {
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(content, &body); err == nil && (body.Message != "" || len(body.Errors) > 0) {
+		apiErr.Fields = body.Errors
+		if body.Message != "" {
+			apiErr.Message = body.Message
+		}
 	}
-	return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(content)))
+	return apiErr
 }
 
+// routeMissing reports whether a 404 came from the Functions host because no
+// function serves the route. Handlers always explain their own 404s, while
+// the host answers an unknown route with an empty body. The body stays
+// readable for decodeError.
+func routeMissing(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusNotFound {
+		return false
+	}
+	content, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+	return len(bytes.TrimSpace(content)) == 0
+}
+
+// indexReusePolicies are the index_reuse values the service accepts: never
+// allocate the index of a released name, only once the environment's
+// released-name retention has passed, or as soon as it is released.
+var indexReusePolicies = []string{"never", "after_hold", "always"}
+
 // ClaimNameRequest describes the payload for claim endpoint.
 type ClaimNameRequest struct {
-	ResourceType string            `json:"resource_type"`
-	Region       string            `json:"region"`
-	Environment  string            `json:"environment"`
-	Project      *string           `json:"project,omitempty"`
-	Purpose      *string           `json:"purpose,omitempty"`
-	Subsystem    *string           `json:"subsystem,omitempty"`
-	System       *string           `json:"system,omitempty"`
-	Index        *string           `json:"index,omitempty"`
-	SessionID    *string           `json:"sessionId,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
+	ResourceType string         `json:"resource_type"`
+	Region       string         `json:"region"`
+	Environment  string         `json:"environment"`
+	Project      *string        `json:"project,omitempty"`
+	Purpose      *string        `json:"purpose,omitempty"`
+	Subsystem    *string        `json:"subsystem,omitempty"`
+	System       *string        `json:"system,omitempty"`
+	Index        *string        `json:"index,omitempty"`
+	SessionID    *string        `json:"sessionId,omitempty"`
+	Suffix       *string        `json:"suffix,omitempty"`
+	Template     *string        `json:"template,omitempty"`
+	ExpiresAt    *string        `json:"expires_at,omitempty"`
+	ReleaseAfter *string        `json:"release_after,omitempty"`
+	IndexReuse   *string        `json:"index_reuse,omitempty"`
+	Metadata     map[string]any `json:"metadata,omitempty"`
+	// IdempotencyKey makes the service answer a repeated claim with the name
+	// it already gave instead of claiming another.
+	IdempotencyKey *string `json:"idempotency_key,omitempty"`
+	CorrelationID  *string `json:"correlation_id,omitempty"`
 }
 
 // ClaimNameResponse describes the response from claim endpoint.
@@ -178,28 +572,118 @@ type ClaimNameResponse struct {
 	Subsystem    string `json:"subsystem"`
 	System       string `json:"system"`
 	Index        string `json:"index"`
+	ReleaseAt    string `json:"releaseAt"`
 }
 
 // ClaimName performs the claim request and returns the response model.
 func (c *APIClient) ClaimName(ctx context.Context, payload ClaimNameRequest) (*ClaimNameResponse, error) {
-	req, err := c.buildRequest(ctx, http.MethodPost, "/api/claim", payload)
+	return c.recordedClaim(ctx, "claim", payload)
+}
+
+// recordedClaim claims a name and records it in the audit log as action.
+func (c *APIClient) recordedClaim(ctx context.Context, action string, payload ClaimNameRequest) (*ClaimNameResponse, error) {
+	result, err := c.claimName(ctx, c.tagWorkspace(payload))
+
+	entry := auditLogEntry{
+		Action:       action,
+		ResourceType: payload.ResourceType,
+		Region:       payload.Region,
+		Environment:  payload.Environment,
+	}
+	if result != nil {
+		entry.Name = result.Name
+	}
+	if payload.Template != nil {
+		entry.Template = *payload.Template
+	}
+	c.auditLog.record(ctx, entry, err)
+	return result, err
+}
+
+func (c *APIClient) claimName(ctx context.Context, payload ClaimNameRequest) (*ClaimNameResponse, error) {
+	if c.scheduler != nil {
+		if err := c.scheduler.wait(ctx, claimPriority(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	if c.batcher != nil {
+		return c.batcher.claim(ctx, payload)
+	}
+	return c.postClaim(ctx, "/api/claim", payload)
+}
+
+// SetClaimRateLimit queues claims and sends at most perSecond of them each
+// second, highest WithClaimPriority first. Aliases for the same service share
+// one limiter, so it returns the rate actually enforced, which is the first
+// one configured.
+func (c *APIClient) SetClaimRateLimit(perSecond float64) float64 {
+	scheduler, rate := c.shared.claimScheduler(perSecond)
+	c.scheduler = scheduler
+	return rate
+}
+
+// EnableClaimBatching coalesces ClaimName calls made within window of each
+// other into /api/claim/batch requests.
+func (c *APIClient) EnableClaimBatching(window time.Duration) {
+	c.batcher = newClaimBatcher(c, window)
+}
+
+// PreviewName returns the name the service would generate without claiming it.
+func (c *APIClient) PreviewName(ctx context.Context, payload ClaimNameRequest) (*ClaimNameResponse, error) {
+	return c.postClaim(ctx, "/api/preview", payload)
+}
+
+// withSession sets the provider session and index reuse policy on claims that
+// do not carry their own, and pads their index to index_width.
+func (c *APIClient) withSession(payload ClaimNameRequest) ClaimNameRequest {
+	if payload.SessionID == nil && c.sessionID != "" {
+		sessionID := c.sessionID
+		payload.SessionID = &sessionID
+	}
+	if payload.IndexReuse == nil && c.indexReuse != "" {
+		indexReuse := c.indexReuse
+		payload.IndexReuse = &indexReuse
+	}
+	if payload.Index != nil {
+		index := padIndex(*payload.Index, c.claimIndexWidth())
+		payload.Index = &index
+	}
+	return payload
+}
+
+func (c *APIClient) postClaim(ctx context.Context, path string, payload ClaimNameRequest) (*ClaimNameResponse, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, path, c.withSession(payload))
 	if err != nil {
 		return nil, err
 	}
+	if c.asyncClaims && path == "/api/claim" {
+		req.Header.Set("Prefer", "respond-async")
+	}
 
 	resp, err := c.doRequest(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	return c.readClaimResponse(ctx, resp)
+}
 
-	if resp.StatusCode != http.StatusOK {
+// readClaimResponse decodes the response to a claim, waiting for the
+// operation when the service accepted the claim to finish later.
+func (c *APIClient) readClaimResponse(ctx context.Context, resp *http.Response) (*ClaimNameResponse, error) {
+	var claim ClaimNameResponse
+	var err error
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		err = c.decodeResponse(resp, "claim", &claim, "name")
+	case http.StatusAccepted:
+		// Claims that need slow checks, such as Azure availability, run
+		// as operations the service reports on until they finish.
+		err = c.awaitOperation(ctx, resp, "claim", &claim, "name")
+	default:
 		return nil, decodeError(resp)
 	}
-
-	defer resp.Body.Close()
-	var claim ClaimNameResponse
-	if err := json.NewDecoder(resp.Body).Decode(&claim); err != nil {
-		return nil, fmt.Errorf("failed to decode claim response: %w", err)
+	if err != nil {
+		return nil, err
 	}
 	return &claim, nil
 }
@@ -214,6 +698,48 @@ type ReleaseRequest struct {
 
 // ReleaseName releases a previously claimed name.
 func (c *APIClient) ReleaseName(ctx context.Context, payload ReleaseRequest) error {
+	err := c.releaseName(ctx, payload)
+	c.auditLog.record(ctx, auditLogEntry{
+		Action:      "release",
+		Name:        payload.Name,
+		Region:      payload.Region,
+		Environment: payload.Environment,
+		Reason:      payload.Reason,
+	}, err)
+	return err
+}
+
+// EnsureReleased releases a name, succeeding as well if it was already
+// released, so a destroy that failed part way can be run again. A 410 means
+// the name is retired. A 404 is only trusted once the audit record confirms
+// the name is not in use, since it could also mean the wrong region or
+// environment.
+func (c *APIClient) EnsureReleased(ctx context.Context, payload ReleaseRequest) error {
+	err := c.ReleaseName(ctx, payload)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusGone:
+	case http.StatusNotFound:
+		record, auditErr := c.GetAudit(ctx, payload.Region, payload.Environment, payload.Name)
+		if auditErr != nil || (record != nil && record.InUse && !record.Retired) {
+			return err
+		}
+	default:
+		return err
+	}
+
+	tflog.Info(ctx, "name already released", map[string]any{
+		"name":   payload.Name,
+		"status": apiErr.StatusCode,
+	})
+	return nil
+}
+
+func (c *APIClient) releaseName(ctx context.Context, payload ReleaseRequest) error {
 	req, err := c.buildRequest(ctx, http.MethodPost, "/api/release", payload)
 	if err != nil {
 		return err
@@ -231,12 +757,41 @@ func (c *APIClient) ReleaseName(ctx context.Context, payload ReleaseRequest) err
 	return nil
 }
 
+// RenewRequest moves the expiry of an existing claim.
+type RenewRequest struct {
+	Name        string `json:"name"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+}
+
+// RenewClaim sets a new expiry on a claim without releasing it. An empty
+// ExpiresAt removes the expiry.
+func (c *APIClient) RenewClaim(ctx context.Context, payload RenewRequest) error {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/claim/renew", payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
+
 // AuditRecord represents the audit endpoint response.
 type AuditRecord struct {
 	Name        string `json:"name"`
 	Resource    string `json:"resource_type"`
 	InUse       bool   `json:"in_use"`
 	ClaimedBy   string `json:"claimed_by"`
+	ClaimedAt   string `json:"claimed_at"`
 	Region      string `json:"region"`
 	Environment string `json:"environment"`
 	Slug        string `json:"slug"`
@@ -245,6 +800,91 @@ type AuditRecord struct {
 	Subsystem   string `json:"subsystem"`
 	System      string `json:"system"`
 	Index       string `json:"index"`
+	ExpiresAt   string `json:"expires_at"`
+	ReleaseAt   string `json:"release_at"`
+
+	// AzureResourceID is the ARM ID of the resource linked to the claim, if
+	// any.
+	AzureResourceID string `json:"azure_resource_id"`
+
+	// Retired is set for names the service has tombstoned. They are not in
+	// use and can never be claimed again.
+	Retired       bool   `json:"retired"`
+	RetiredReason string `json:"retired_reason"`
+
+	// Metadata holds the custom metadata stored with the claim.
+	Metadata map[string]string `json:"-"`
+}
+
+// auditRecordFields lists the fields of the audit response that are not
+// custom claim metadata: the standard fields and the claim request fields
+// the provider models as attributes.
+var auditRecordFields = map[string]bool{
+	"name": true, "resource_type": true, "in_use": true, "claimed_by": true,
+	"claimed_at": true, "released_by": true, "released_at": true, "release_reason": true,
+	"region": true, "environment": true, "slug": true, "project": true,
+	"purpose": true, "subsystem": true, "system": true, "index": true,
+	"expires_at": true, "release_at": true, "release_after": true, "retired": true,
+	"retired_reason": true, "azure_resource_id": true, "template": true, "suffix": true,
+	"transferred_by": true, "transferred_at": true, "correlation_id": true,
+	"session_id": true, "metadata": true,
+}
+
+// sensitiveMetadataKeysKey is the metadata key under which claims list the
+// names of their sensitive_metadata keys, so their values are never read
+// back into metadata.
+const sensitiveMetadataKeysKey = "sanmar_sensitive_keys"
+
+// UnmarshalJSON decodes the standard fields and collects the claim's custom
+// metadata: the fields the service stored at the top level and the
+// "metadata" object, which the service returns as a JSON string. Keys listed
+// as sensitive are dropped.
+func (r *AuditRecord) UnmarshalJSON(data []byte) error {
+	type plain AuditRecord
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	metadata := map[string]string{}
+	for key, value := range fields {
+		if auditRecordFields[key] || value == nil {
+			continue
+		}
+		metadata[key] = fmt.Sprint(value)
+	}
+
+	nested := fields["metadata"]
+	if s, ok := nested.(string); ok {
+		// Claims store metadata as written, so a value that is not an
+		// object is kept as a single entry instead of failing the read.
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			metadata["metadata"] = s
+		}
+		nested = decoded
+	}
+	if object, ok := nested.(map[string]any); ok {
+		for key, value := range object {
+			if value != nil {
+				metadata[key] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	if sensitive, ok := metadata[sensitiveMetadataKeysKey]; ok {
+		for _, key := range strings.Split(sensitive, ",") {
+			delete(metadata, key)
+		}
+		delete(metadata, sensitiveMetadataKeysKey)
+	}
+	if len(metadata) > 0 {
+		r.Metadata = metadata
+	}
+	return nil
 }
 
 // GetAudit retrieves the audit record for a claimed name.
@@ -270,18 +910,48 @@ func (c *APIClient) GetAudit(ctx context.Context, region, environment, name stri
 		return nil, nil
 	}
 
+	// Unlike a missing name, a retired one is reported so claims can be
+	// replaced instead of silently dropped from state.
+	if resp.StatusCode == http.StatusGone {
+		return retiredRecord(resp, region, environment, name), nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, decodeError(resp)
 	}
 
-	defer resp.Body.Close()
 	var record AuditRecord
-	if err := json.NewDecoder(resp.Body).Decode(&record); err != nil {
-		return nil, fmt.Errorf("failed to decode audit response: %w", err)
+	if err := c.decodeResponse(resp, "audit", &record, "name"); err != nil {
+		return nil, err
 	}
 	return &record, nil
 }
 
+// retiredRecord builds the record for a 410 audit response. The body is
+// optional; when it is a JSON audit record or error its details are kept.
+func retiredRecord(resp *http.Response, region, environment, name string) *AuditRecord {
+	defer resp.Body.Close()
+	content, _ := io.ReadAll(resp.Body)
+
+	var record AuditRecord
+	var body struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(content, &record) != nil {
+		record = AuditRecord{}
+	}
+	json.Unmarshal(content, &body)
+	delete(record.Metadata, "message")
+
+	record.Name, record.Region, record.Environment = name, region, environment
+	record.InUse = false
+	record.Retired = true
+	if record.RetiredReason == "" {
+		record.RetiredReason = body.Message
+	}
+	return &record
+}
+
 // SlugResponse captures slug lookup response.
 type SlugResponse struct {
 	ResourceType string `json:"resourceType"`
@@ -289,12 +959,28 @@ type SlugResponse struct {
 	FullName     string `json:"fullName"`
 	Source       string `json:"source"`
 	UpdatedAt    string `json:"updatedAt"`
+
+	// Defaults holds the segment values, keyed by segment name such as
+	// "purpose", the service applies to claims that leave them unset.
+	Defaults map[string]string `json:"defaults,omitempty"`
 }
 
 // LookupSlug retrieves slug information for a resource type.
 func (c *APIClient) LookupSlug(ctx context.Context, resourceType string) (*SlugResponse, error) {
 	q := url.Values{}
 	q.Set("resource_type", resourceType)
+	return c.getSlug(ctx, q)
+}
+
+// LookupSlugValue retrieves the resource type and metadata that use slug,
+// for mapping slugs in existing names back to resource types.
+func (c *APIClient) LookupSlugValue(ctx context.Context, slug string) (*SlugResponse, error) {
+	q := url.Values{}
+	q.Set("slug", slug)
+	return c.getSlug(ctx, q)
+}
+
+func (c *APIClient) getSlug(ctx context.Context, q url.Values) (*SlugResponse, error) {
 	path := "/api/slug?" + q.Encode()
 
 	req, err := c.buildRequest(ctx, http.MethodGet, path, nil)
@@ -316,10 +1002,9 @@ func (c *APIClient) LookupSlug(ctx context.Context, resourceType string) (*SlugR
 		return nil, decodeError(resp)
 	}
 
-	defer resp.Body.Close()
 	var slug SlugResponse
-	if err := json.NewDecoder(resp.Body).Decode(&slug); err != nil {
-		return nil, fmt.Errorf("failed to decode slug response: %w", err)
+	if err := c.decodeResponse(resp, "slug", &slug, "slug"); err != nil {
+		return nil, err
 	}
 	return &slug, nil
 }
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// inUseHeader carries whether a name is claimed on HEAD /api/audit responses.
+const inUseHeader = "X-Sanmar-In-Use"
+
+// NameState describes a name as reported by CheckName.
+type NameState struct {
+	// Exists is set when the service has any record of the name, including
+	// released and retired ones.
+	Exists  bool
+	InUse   bool
+	Retired bool
+}
+
+// CheckName reports whether a name is claimed without transferring its audit
+// record, using HEAD /api/audit. Services that do not answer HEAD, or do not
+// send the X-Sanmar-In-Use header, are asked with GetAudit instead. That
+// includes a 404 without the header, since Azure Functions answers HEAD on a
+// GET-only route with 404 whether or not the name exists.
+func (c *APIClient) CheckName(ctx context.Context, region, environment, name string) (NameState, error) {
+	q := url.Values{}
+	q.Set("region", region)
+	q.Set("environment", environment)
+	q.Set("name", name)
+
+	req, err := c.buildRequest(ctx, http.MethodHead, "/api/audit?"+q.Encode(), nil)
+	if err != nil {
+		return NameState{}, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return NameState{}, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		if resp.Header.Get(inUseHeader) != "" {
+			return NameState{}, nil
+		}
+	case http.StatusGone:
+		return NameState{Exists: true, Retired: true}, nil
+	case http.StatusOK:
+		if inUse, err := strconv.ParseBool(resp.Header.Get(inUseHeader)); err == nil {
+			return NameState{Exists: true, InUse: inUse}, nil
+		}
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+	default:
+		// HEAD responses have no body to describe the error.
+		return NameState{}, &APIError{StatusCode: resp.StatusCode}
+	}
+
+	record, err := c.GetAudit(ctx, region, environment, name)
+	if err != nil || record == nil {
+		return NameState{}, err
+	}
+	return NameState{Exists: true, InUse: record.InUse, Retired: record.Retired}, nil
+}
@@ -0,0 +1,340 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// claimBatchMaxSize caps the number of claims sent in one batch request.
+const claimBatchMaxSize = 25
+
+// ClaimBatchResult is the outcome of one claim within a batch, in request order.
+type ClaimBatchResult struct {
+	Claim *ClaimNameResponse `json:"claim,omitempty"`
+	// Status is 202 for a claim the service finishes as an operation, at
+	// the URL in Operation, as a single claim's 202 response would.
+	Status    int    `json:"status,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Error     *struct {
+		Status  int          `json:"status"`
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors,omitempty"`
+	} `json:"error,omitempty"`
+}
+
+// response returns the result as the response a single claim would have
+// had, so it is handled exactly as postClaim handles one.
+func (r ClaimBatchResult) response() (*http.Response, error) {
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	var body any
+	switch {
+	case r.Error != nil:
+		resp.StatusCode = r.Error.Status
+		body = r.Error
+	case r.Status == http.StatusAccepted:
+		resp.StatusCode = http.StatusAccepted
+		resp.Header.Set("Operation-Location", r.Operation)
+	case r.Claim != nil:
+		body = r.Claim
+	default:
+		return nil, errors.New("claim batch returned no result for the claim")
+	}
+
+	var content []byte
+	if body != nil {
+		var err error
+		if content, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+	return resp, nil
+}
+
+// maxPooledBatchBuffer is the largest batch body buffer kept for reuse, so
+// one unusually large batch does not pin its memory for the whole run.
+const maxPooledBatchBuffer = 4 << 20
+
+// batchBuffers holds the buffers batch request bodies are encoded into, so
+// batch calls reuse memory instead of allocating a body per request.
+var batchBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// batchBody is a JSON request body encoded into a pooled buffer. The buffer
+// returns to the pool once the caller has released the body and the
+// transport has closed every reader it was given, since the transport may
+// still be writing the body after the response arrives.
+type batchBody struct {
+	mu   sync.Mutex
+	buf  *bytes.Buffer
+	refs int
+}
+
+// encodeClaimBatch encodes the claims one at a time into a pooled buffer,
+// without building the whole request body as a value first. The caller holds
+// a reference and must call release when done with the request.
+func (c *APIClient) encodeClaimBatch(payloads []ClaimNameRequest) (*batchBody, error) {
+	buf := batchBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.WriteString(`{"claims":[`)
+	enc := json.NewEncoder(buf)
+	for i, payload := range payloads {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(c.withSession(payload)); err != nil {
+			putBatchBuffer(buf)
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+	buf.WriteString("]}")
+	return &batchBody{buf: buf, refs: 1}, nil
+}
+
+// open returns a reader over the body that holds a reference until closed.
+func (b *batchBody) open() io.ReadCloser {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refs++
+	return &batchBodyReader{Reader: bytes.NewReader(b.buf.Bytes()), body: b}
+}
+
+// release drops a reference, returning the buffer to the pool with the last.
+func (b *batchBody) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refs--
+	if b.refs == 0 {
+		putBatchBuffer(b.buf)
+		b.buf = nil
+	}
+}
+
+type batchBodyReader struct {
+	*bytes.Reader
+	body  *batchBody
+	close sync.Once
+}
+
+func (r *batchBodyReader) Close() error {
+	r.close.Do(r.body.release)
+	return nil
+}
+
+func putBatchBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBatchBuffer {
+		return
+	}
+	buf.Reset()
+	batchBuffers.Put(buf)
+}
+
+// buildBatchRequest builds a request that sends body. Retries and HMAC
+// signing read it again through GetBody, without copying it.
+func (c *APIClient) buildBatchRequest(ctx context.Context, method, path string, body *batchBody) (*http.Request, error) {
+	reader := body.open()
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reader)
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.ContentLength = int64(body.buf.Len())
+	req.GetBody = func() (io.ReadCloser, error) {
+		return body.open(), nil
+	}
+	if req, err = c.prepareRequest(ctx, req, true); err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return req, nil
+}
+
+// ClaimNames claims several names in a single request. It returns nil
+// results and a nil error when the service has no batch route.
+func (c *APIClient) ClaimNames(ctx context.Context, payloads []ClaimNameRequest) ([]ClaimBatchResult, error) {
+	body, err := c.encodeClaimBatch(payloads)
+	if err != nil {
+		return nil, err
+	}
+	defer body.release()
+
+	req, err := c.buildBatchRequest(ctx, http.MethodPost, "/api/claim/batch", body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a missing route means the service cannot batch; a 404 from the
+	// batch handler itself is an error like any other.
+	if routeMissing(resp) {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMultiStatus {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var out struct {
+		Results []ClaimBatchResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode claim batch response: %w", err)
+	}
+	if len(out.Results) != len(payloads) {
+		return nil, fmt.Errorf("claim batch returned %d results for %d claims", len(out.Results), len(payloads))
+	}
+	return out.Results, nil
+}
+
+// claimBatcher coalesces concurrent claims made within a short window into
+// batch requests, so large applies cost fewer Functions invocations.
+type claimBatcher struct {
+	client *APIClient
+	window time.Duration
+
+	mu          sync.Mutex
+	pending     []*pendingClaim
+	timer       *time.Timer
+	unsupported bool
+}
+
+type pendingClaim struct {
+	ctx     context.Context
+	payload ClaimNameRequest
+	done    chan claimOutcome
+}
+
+// claimOutcome is what the batch learned about one claim. The claiming
+// caller handles it with its own context.
+type claimOutcome struct {
+	// result is the claim's part of the batch response.
+	result *ClaimBatchResult
+	// err is set when the batch request as a whole failed.
+	err error
+	// single asks the caller to claim on its own, for a batch of one or when
+	// the service has no batch endpoint.
+	single bool
+}
+
+func newClaimBatcher(client *APIClient, window time.Duration) *claimBatcher {
+	return &claimBatcher{client: client, window: window}
+}
+
+// claim queues the payload and waits for its batch to complete. It does not
+// give up on a batch that is already claiming its name, since the name would
+// then be claimed with nothing recording it; the batch is only cancelled once
+// every claim in it is.
+func (b *claimBatcher) claim(ctx context.Context, payload ClaimNameRequest) (*ClaimNameResponse, error) {
+	b.mu.Lock()
+	if b.unsupported {
+		b.mu.Unlock()
+		return b.client.postClaim(ctx, "/api/claim", payload)
+	}
+
+	p := &pendingClaim{ctx: ctx, payload: payload, done: make(chan claimOutcome, 1)}
+	b.pending = append(b.pending, p)
+	switch {
+	case len(b.pending) >= claimBatchMaxSize:
+		batch := b.take()
+		b.mu.Unlock()
+		go b.send(batch)
+	case len(b.pending) == 1:
+		b.timer = time.AfterFunc(b.window, b.flush)
+		b.mu.Unlock()
+	default:
+		b.mu.Unlock()
+	}
+
+	out := <-p.done
+	switch {
+	case out.single:
+		return b.client.postClaim(ctx, "/api/claim", payload)
+	case out.err != nil:
+		return nil, out.err
+	}
+	resp, err := out.result.response()
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s", err, payload.ResourceType)
+	}
+	return b.client.readClaimResponse(ctx, resp)
+}
+
+// take removes the pending claims. The caller must hold b.mu.
+func (b *claimBatcher) take() []*pendingClaim {
+	batch := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return batch
+}
+
+func (b *claimBatcher) flush() {
+	b.mu.Lock()
+	batch := b.take()
+	b.mu.Unlock()
+	if len(batch) > 0 {
+		b.send(batch)
+	}
+}
+
+func (b *claimBatcher) send(batch []*pendingClaim) {
+	if len(batch) == 1 {
+		batch[0].done <- claimOutcome{single: true}
+		return
+	}
+
+	// The batch serves every caller in it, so it is cancelled only once all
+	// of their contexts are done.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var remaining atomic.Int64
+	remaining.Store(int64(len(batch)))
+	for _, p := range batch {
+		stop := context.AfterFunc(p.ctx, func() {
+			if remaining.Add(-1) == 0 {
+				cancel()
+			}
+		})
+		defer stop()
+	}
+
+	payloads := make([]ClaimNameRequest, len(batch))
+	for i, p := range batch {
+		payloads[i] = p.payload
+	}
+
+	results, err := b.client.ClaimNames(ctx, payloads)
+	if err == nil && results == nil {
+		// The service predates the batch endpoint; claim individually from now on.
+		b.mu.Lock()
+		b.unsupported = true
+		b.mu.Unlock()
+		for _, p := range batch {
+			p.done <- claimOutcome{single: true}
+		}
+		return
+	}
+
+	for i, p := range batch {
+		if err != nil {
+			p.done <- claimOutcome{err: err}
+			continue
+		}
+		p.done <- claimOutcome{result: &results[i]}
+	}
+}
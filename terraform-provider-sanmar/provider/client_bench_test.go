@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBenchClient starts an in-process naming service that answers the happy
+// paths with fixed records, and returns a client for it. When failEvery is
+// above zero, every failEvery-th claim fails with 503 so the retry loop runs.
+func newBenchClient(b *testing.B, failEvery int64) *APIClient {
+	b.Helper()
+	claim, _ := json.Marshal(ClaimNameResponse{
+		Name:         "wus2prdstatlas01",
+		ResourceType: "storage_account",
+		Region:       "wus2",
+		Environment:  "prd",
+		Slug:         "st",
+		ClaimedBy:    "user@example.com",
+	})
+	audit, _ := json.Marshal(AuditRecord{
+		Name:        "wus2prdstatlas01",
+		Resource:    "storage_account",
+		InUse:       true,
+		ClaimedBy:   "user@example.com",
+		Region:      "wus2",
+		Environment: "prd",
+		Slug:        "st",
+	})
+	slug, _ := json.Marshal(SlugResponse{ResourceType: "storage_account", Slug: "st", FullName: "Storage Account"})
+
+	var claims atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		if failEvery > 0 && claims.Add(1)%failEvery == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(claim)
+	})
+	mux.HandleFunc("/api/claim/batch", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Claims []json.RawMessage `json:"claims"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		results := make([]json.RawMessage, len(body.Claims))
+		for i := range results {
+			results[i] = json.RawMessage(`{"claim":` + string(claim) + `}`)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(audit)
+	})
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(slug)
+	})
+
+	srv := httptest.NewServer(mux)
+	b.Cleanup(srv.Close)
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 3, MinBackoff: time.Microsecond, MaxBackoff: time.Microsecond})
+	if err != nil {
+		b.Fatalf("NewAPIClient: %v", err)
+	}
+	return client
+}
+
+func benchClaimRequest() ClaimNameRequest {
+	system, index := "atlas", "01"
+	return ClaimNameRequest{ResourceType: "storage_account", Region: "wus2", Environment: "prd", System: &system, Index: &index}
+}
+
+func BenchmarkClaimName(b *testing.B) {
+	client := newBenchClient(b, 0)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.ClaimName(ctx, benchClaimRequest()); err != nil {
+			b.Fatalf("ClaimName: %v", err)
+		}
+	}
+}
+
+func BenchmarkClaimNameParallel(b *testing.B) {
+	client := newBenchClient(b, 0)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.ClaimName(ctx, benchClaimRequest()); err != nil {
+				b.Errorf("ClaimName: %v", err)
+				return
+			}
+		}
+	})
+}
+
+// BenchmarkClaimNameRetry fails every other claim once, so each operation
+// includes a retry with its body rewind and backoff bookkeeping.
+func BenchmarkClaimNameRetry(b *testing.B) {
+	client := newBenchClient(b, 2)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.ClaimName(ctx, benchClaimRequest()); err != nil {
+			b.Fatalf("ClaimName: %v", err)
+		}
+	}
+}
+
+func BenchmarkClaimNames(b *testing.B) {
+	client := newBenchClient(b, 0)
+	ctx := context.Background()
+	payloads := make([]ClaimNameRequest, claimBatchMaxSize)
+	for i := range payloads {
+		payloads[i] = benchClaimRequest()
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.ClaimNames(ctx, payloads); err != nil {
+			b.Fatalf("ClaimNames: %v", err)
+		}
+	}
+}
+
+func BenchmarkGetAudit(b *testing.B) {
+	client := newBenchClient(b, 0)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		record, err := client.GetAudit(ctx, "wus2", "prd", fmt.Sprintf("wus2prdstatlas%02d", i%100))
+		if err != nil || record == nil {
+			b.Fatalf("GetAudit: %v", err)
+		}
+	}
+}
+
+func BenchmarkLookupSlug(b *testing.B) {
+	client := newBenchClient(b, 0)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		slug, err := client.LookupSlug(ctx, "storage_account")
+		if err != nil || slug == nil {
+			b.Fatalf("LookupSlug: %v", err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+)
+
+// CatalogEntry is a registered system or subsystem segment value.
+type CatalogEntry struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Owner       string `json:"owner"`
+	// System is the parent system of a subsystem; empty for systems.
+	System string `json:"system,omitempty"`
+}
+
+// ListSystems returns the registered systems.
+func (c *APIClient) ListSystems(ctx context.Context) ([]CatalogEntry, error) {
+	return c.listCatalog(ctx, "/api/systems", "systems")
+}
+
+// ListSubsystems returns the registered subsystems, limited to one system
+// when system is set.
+func (c *APIClient) ListSubsystems(ctx context.Context, system string) ([]CatalogEntry, error) {
+	path := "/api/subsystems"
+	if system != "" {
+		path += "?" + url.Values{"system": {system}}.Encode()
+	}
+	return c.listCatalog(ctx, path, "subsystems")
+}
+
+func (c *APIClient) listCatalog(ctx context.Context, path, key string) ([]CatalogEntry, error) {
+	return listAll[CatalogEntry](ctx, c, path, key, key)
+}
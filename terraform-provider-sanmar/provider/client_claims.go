@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+)
+
+// ClaimFilter narrows audit and claim listings. Empty fields are not sent.
+type ClaimFilter struct {
+	User        string
+	Project     string
+	Purpose     string
+	Region      string
+	Environment string
+	Action      string
+	Start       string
+	End         string
+}
+
+func (f ClaimFilter) query() url.Values {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"user":        f.User,
+		"project":     f.Project,
+		"purpose":     f.Purpose,
+		"region":      f.Region,
+		"environment": f.Environment,
+		"action":      f.Action,
+		"start":       f.Start,
+		"end":         f.End,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	return q
+}
+
+// AuditEvent is a single claim or release entry from the bulk audit endpoint.
+type AuditEvent struct {
+	Name         string `json:"name"`
+	EventID      string `json:"event_id"`
+	User         string `json:"user"`
+	Action       string `json:"action"`
+	Note         string `json:"note"`
+	Timestamp    string `json:"timestamp"`
+	Region       string `json:"region"`
+	Environment  string `json:"environment"`
+	Project      string `json:"project"`
+	Purpose      string `json:"purpose"`
+	ResourceType string `json:"resource_type"`
+	Slug         string `json:"slug"`
+	System       string `json:"system"`
+	Subsystem    string `json:"subsystem"`
+	Index        string `json:"index"`
+	// NewOwner is who a "transferred" event moved the claim to.
+	NewOwner string `json:"new_owner"`
+}
+
+// ListAuditEvents returns audit events matching the filter, newest first.
+func (c *APIClient) ListAuditEvents(ctx context.Context, filter ClaimFilter) ([]AuditEvent, error) {
+	path := "/api/audit_bulk"
+	if q := filter.query(); len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	return listAll[AuditEvent](ctx, c, path, "audit_bulk", "results")
+}
+
+// ClaimSummary describes a name that is currently claimed.
+type ClaimSummary struct {
+	Name         string `json:"name"`
+	ResourceType string `json:"resource_type"`
+	Region       string `json:"region"`
+	Environment  string `json:"environment"`
+	Project      string `json:"project"`
+	Purpose      string `json:"purpose"`
+	Slug         string `json:"slug"`
+	System       string `json:"system"`
+	Subsystem    string `json:"subsystem"`
+	Index        string `json:"index"`
+	ClaimedBy    string `json:"claimed_by"`
+	ClaimedAt    string `json:"claimed_at"`
+}
+
+// Identity returns the "<region>/<environment>/<name>" import identifier.
+func (s ClaimSummary) Identity() string {
+	return claimIdentity{Region: s.Region, Environment: s.Environment, Name: s.Name}.String()
+}
+
+// ListClaims returns the names whose most recent audit event is a claim.
+// The service has no dedicated listing endpoint, so claims are reconstructed
+// from the audit history.
+func (c *APIClient) ListClaims(ctx context.Context, filter ClaimFilter) ([]ClaimSummary, error) {
+	filter.Action = ""
+	events, err := c.ListAuditEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return activeClaims(events), nil
+}
+
+// activeClaims keeps the newest event per claim identity and returns those
+// that are claims, owned by whoever the newest transfer since the claim moved
+// them to. Events must be ordered newest first.
+func activeClaims(events []AuditEvent) []ClaimSummary {
+	seen := make(map[claimIdentity]bool, len(events))
+	owners := map[claimIdentity]string{}
+	var claims []ClaimSummary
+	for _, event := range events {
+		id := claimIdentity{Region: event.Region, Environment: event.Environment, Name: event.Name}
+		// A transfer keeps the claim but changes its owner.
+		if event.Action == "transferred" {
+			if _, ok := owners[id]; !ok && !seen[id] && event.NewOwner != "" {
+				owners[id] = event.NewOwner
+			}
+			continue
+		}
+		// Only claims, releases and purges change whether a name is claimed.
+		if event.Action != "claimed" && event.Action != "released" && event.Action != "purged" {
+			continue
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if event.Action != "claimed" {
+			continue
+		}
+		claimedBy := event.User
+		if owner, ok := owners[id]; ok {
+			claimedBy = owner
+		}
+		claims = append(claims, ClaimSummary{
+			Name:         event.Name,
+			ResourceType: event.ResourceType,
+			Region:       event.Region,
+			Environment:  event.Environment,
+			Project:      event.Project,
+			Purpose:      event.Purpose,
+			Slug:         event.Slug,
+			System:       event.System,
+			Subsystem:    event.Subsystem,
+			Index:        event.Index,
+			ClaimedBy:    claimedBy,
+			ClaimedAt:    event.Timestamp,
+		})
+	}
+	return claims
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// decodeResponse decodes the body of resp into out and checks it against
+// what the provider expects, so version skew between the provider and the
+// service is reported instead of leaving zero values in state.
+//
+// Missing or null required fields are an error. With strict decoding
+// enabled, fields out does not declare produce a warning once per response
+// kind. Types that implement json.Unmarshaler handle extra fields themselves
+// and are not checked for them.
+func (c *APIClient) decodeResponse(resp *http.Response, kind string, out any, required ...string) error {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", kind, err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", kind, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		// Not an object; the typed decode above already succeeded.
+		return nil
+	}
+
+	for _, name := range required {
+		if raw, ok := fields[name]; !ok || string(raw) == "null" {
+			return fmt.Errorf("%s response is missing required field %q; the naming service may be running an incompatible version", kind, name)
+		}
+	}
+
+	if !c.strictDecoding {
+		return nil
+	}
+	if _, custom := out.(json.Unmarshaler); custom {
+		return nil
+	}
+	known := jsonFieldNames(reflect.TypeOf(out))
+	var unknown []string
+	for name := range fields {
+		if !known[strings.ToLower(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		c.deprecations.add("fields "+kind, "Unexpected naming service response fields",
+			fmt.Sprintf("The %s response contains fields this provider does not recognise: %s. The naming service is probably newer than the provider; upgrade the provider to use them.", kind, strings.Join(unknown, ", ")))
+	}
+	return nil
+}
+
+// jsonFieldNames returns the lower-cased JSON object keys a struct type
+// decodes; encoding/json matches keys case-insensitively.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	names := map[string]bool{}
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// successorLink matches a Link header entry with rel="successor-version".
+var successorLink = regexp.MustCompile(`<([^>]+)>\s*;[^,]*rel="?successor-version"?`)
+
+// deprecationNotices collects Deprecation and Sunset signals and response
+// schema drift from the service so each is reported once per provider process.
+type deprecationNotices struct {
+	mu      sync.Mutex
+	seen    map[string]bool
+	pending []serviceNotice
+}
+
+// serviceNotice is a warning waiting to be reported.
+type serviceNotice struct {
+	summary string
+	detail  string
+}
+
+// add queues a warning unless one with the same key was already queued.
+func (n *deprecationNotices) add(key, summary, detail string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.seen[key] {
+		return
+	}
+	if n.seen == nil {
+		n.seen = map[string]bool{}
+	}
+	n.seen[key] = true
+	n.pending = append(n.pending, serviceNotice{summary: summary, detail: detail})
+}
+
+// note records a warning if resp marks the endpoint of req as deprecated.
+func (n *deprecationNotices) note(req *http.Request, resp *http.Response) {
+	deprecation := resp.Header.Get("Deprecation")
+	sunset := resp.Header.Get("Sunset")
+	if deprecation == "" && sunset == "" {
+		return
+	}
+
+	endpoint := req.Method + " " + req.URL.Path
+	var b strings.Builder
+	fmt.Fprintf(&b, "The naming service has deprecated %s", endpoint)
+	if sunset != "" {
+		fmt.Fprintf(&b, " and will remove it on %s", sunset)
+	}
+	b.WriteString(".")
+	if m := successorLink.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		fmt.Fprintf(&b, " The replacement API is %s; upgrade the provider before then.", m[1])
+	} else {
+		b.WriteString(" Upgrade the provider to a release that uses the replacement API.")
+	}
+	n.add(endpoint, "Naming service API deprecated", b.String())
+}
+
+// report adds a warning for each notice not yet reported.
+func (n *deprecationNotices) report(diags *diag.Diagnostics) {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	n.mu.Unlock()
+
+	for _, notice := range pending {
+		diags.AddWarning(notice.summary, notice.detail)
+	}
+}
+
+// reportDeprecations adds warnings for deprecated endpoints and unexpected
+// response fields the client has seen since the last report. Call it deferred
+// from each CRUD method.
+func (c *APIClient) reportDeprecations(diags *diag.Diagnostics) {
+	c.deprecations.report(diags)
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Environment is an entry in the service's environment catalog.
+type Environment struct {
+	Code           string   `json:"code"`
+	DisplayName    string   `json:"display_name"`
+	AllowedRegions []string `json:"allowed_regions,omitempty"`
+	// ReleasedRetention is how long released names stay reserved before
+	// they can be claimed again, as a Go duration such as "720h".
+	ReleasedRetention string `json:"released_retention,omitempty"`
+}
+
+// CreateEnvironment registers an environment code.
+func (c *APIClient) CreateEnvironment(ctx context.Context, payload Environment) (*Environment, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/environments", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var environment Environment
+	if err := json.NewDecoder(resp.Body).Decode(&environment); err != nil {
+		return nil, fmt.Errorf("failed to decode environment response: %w", err)
+	}
+	return &environment, nil
+}
+
+// GetEnvironment retrieves a registered environment by code.
+func (c *APIClient) GetEnvironment(ctx context.Context, code string) (*Environment, error) {
+	req, err := c.buildRequest(ctx, http.MethodGet, "/api/environments/"+url.PathEscape(code), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var environment Environment
+	if err := json.NewDecoder(resp.Body).Decode(&environment); err != nil {
+		return nil, fmt.Errorf("failed to decode environment response: %w", err)
+	}
+	return &environment, nil
+}
+
+// UpdateEnvironment replaces the settings of a registered environment.
+func (c *APIClient) UpdateEnvironment(ctx context.Context, code string, payload Environment) (*Environment, error) {
+	req, err := c.buildRequest(ctx, http.MethodPut, "/api/environments/"+url.PathEscape(code), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var environment Environment
+	if err := json.NewDecoder(resp.Body).Decode(&environment); err != nil {
+		return nil, fmt.Errorf("failed to decode environment response: %w", err)
+	}
+	return &environment, nil
+}
+
+// DeleteEnvironment removes an environment from the catalog. Missing
+// environments are treated as deleted.
+func (c *APIClient) DeleteEnvironment(ctx context.Context, code string) error {
+	req, err := c.buildRequest(ctx, http.MethodDelete, "/api/environments/"+url.PathEscape(code), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
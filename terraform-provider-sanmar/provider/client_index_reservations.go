@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// IndexReservation describes a block of indices set aside for a team within a scope.
+type IndexReservation struct {
+	ID           string `json:"id,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Region       string `json:"region"`
+	Environment  string `json:"environment"`
+	Project      string `json:"project,omitempty"`
+	Team         string `json:"team"`
+	Start        int64  `json:"start"`
+	End          int64  `json:"end"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+}
+
+// CreateIndexReservation reserves a range of indices.
+func (c *APIClient) CreateIndexReservation(ctx context.Context, payload IndexReservation) (*IndexReservation, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/index_reservations", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var reservation IndexReservation
+	if err := json.NewDecoder(resp.Body).Decode(&reservation); err != nil {
+		return nil, fmt.Errorf("failed to decode index reservation response: %w", err)
+	}
+	return &reservation, nil
+}
+
+// GetIndexReservation retrieves a reservation by identifier.
+func (c *APIClient) GetIndexReservation(ctx context.Context, id string) (*IndexReservation, error) {
+	req, err := c.buildRequest(ctx, http.MethodGet, "/api/index_reservations/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var reservation IndexReservation
+	if err := json.NewDecoder(resp.Body).Decode(&reservation); err != nil {
+		return nil, fmt.Errorf("failed to decode index reservation response: %w", err)
+	}
+	return &reservation, nil
+}
+
+// DeleteIndexReservation returns the reserved indices to the shared pool.
+func (c *APIClient) DeleteIndexReservation(ctx context.Context, id string) error {
+	req, err := c.buildRequest(ctx, http.MethodDelete, "/api/index_reservations/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
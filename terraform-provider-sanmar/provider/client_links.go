@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+)
+
+// azureResourceIDPattern matches Azure Resource Manager resource IDs.
+var azureResourceIDPattern = regexp.MustCompile(`(?i)^/subscriptions/[^/]+/`)
+
+// linkRequest is the body of an audit record PATCH.
+type linkRequest struct {
+	AzureResourceID string `json:"azure_resource_id"`
+}
+
+// LinkAzureResource records the ID of the Azure resource that uses a claimed
+// name on its audit record, so GetAudit reports the linkage. An empty ID
+// removes the link.
+func (c *APIClient) LinkAzureResource(ctx context.Context, region, environment, name, azureResourceID string) error {
+	q := url.Values{}
+	q.Set("region", region)
+	q.Set("environment", environment)
+	q.Set("name", name)
+
+	req, err := c.buildRequest(ctx, http.MethodPatch, "/api/audit?"+q.Encode(), linkRequest{AzureResourceID: azureResourceID})
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
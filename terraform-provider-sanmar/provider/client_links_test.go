@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLinkAzureResource(t *testing.T) {
+	const armID = "/subscriptions/0000/resourceGroups/rg-atlas/providers/Microsoft.KeyVault/vaults/kvsanmaratlas01"
+	linked := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/audit" || r.URL.Query().Get("name") != "kvsanmaratlas01" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPatch:
+			var body linkRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode link: %v", err)
+			}
+			linked = body.AzureResourceID
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "kvsanmaratlas01", "in_use": true, "azure_resource_id": linked})
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := client.LinkAzureResource(ctx, "wus2", "prd", "kvsanmaratlas01", armID); err != nil {
+		t.Fatalf("LinkAzureResource: %v", err)
+	}
+	record, err := client.GetAudit(ctx, "wus2", "prd", "kvsanmaratlas01")
+	if err != nil {
+		t.Fatalf("GetAudit: %v", err)
+	}
+	if record.AzureResourceID != armID || len(record.Metadata) != 0 {
+		t.Fatalf("expected the link on the record and not in metadata, got %+v", record)
+	}
+	if !azureResourceIDPattern.MatchString(armID) || azureResourceIDPattern.MatchString("kvsanmaratlas01") {
+		t.Fatalf("unexpected resource ID validation")
+	}
+}
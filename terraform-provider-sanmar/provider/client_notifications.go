@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NotificationSubscription describes a webhook fired on claim and release events.
+type NotificationSubscription struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	Enabled bool     `json:"enabled"`
+}
+
+// CreateNotification registers a new notification subscription.
+func (c *APIClient) CreateNotification(ctx context.Context, payload NotificationSubscription) (*NotificationSubscription, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/notifications", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var sub NotificationSubscription
+	if err := json.NewDecoder(resp.Body).Decode(&sub); err != nil {
+		return nil, fmt.Errorf("failed to decode notification response: %w", err)
+	}
+	return &sub, nil
+}
+
+// GetNotification retrieves a notification subscription by identifier.
+func (c *APIClient) GetNotification(ctx context.Context, id string) (*NotificationSubscription, error) {
+	req, err := c.buildRequest(ctx, http.MethodGet, "/api/notifications/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var sub NotificationSubscription
+	if err := json.NewDecoder(resp.Body).Decode(&sub); err != nil {
+		return nil, fmt.Errorf("failed to decode notification response: %w", err)
+	}
+	return &sub, nil
+}
+
+// UpdateNotification replaces the settings of an existing subscription.
+func (c *APIClient) UpdateNotification(ctx context.Context, id string, payload NotificationSubscription) (*NotificationSubscription, error) {
+	req, err := c.buildRequest(ctx, http.MethodPut, "/api/notifications/"+url.PathEscape(id), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var sub NotificationSubscription
+	if err := json.NewDecoder(resp.Body).Decode(&sub); err != nil {
+		return nil, fmt.Errorf("failed to decode notification response: %w", err)
+	}
+	return &sub, nil
+}
+
+// DeleteNotification removes a subscription. Missing subscriptions are treated as deleted.
+func (c *APIClient) DeleteNotification(ctx context.Context, id string) error {
+	req, err := c.buildRequest(ctx, http.MethodDelete, "/api/notifications/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
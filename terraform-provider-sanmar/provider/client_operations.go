@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TransferRequest moves a claim to a new owner without releasing the name.
+type TransferRequest struct {
+	Name        string `json:"name"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	NewOwner    string `json:"new_owner"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// PurgeRequest removes a released name's record so it can be claimed fresh.
+type PurgeRequest struct {
+	Name        string `json:"name"`
+	Region      string `json:"region"`
+	Environment string `json:"environment"`
+	Reason      string `json:"reason"`
+}
+
+// TransferClaim reassigns a claim to another user or group.
+func (c *APIClient) TransferClaim(ctx context.Context, payload TransferRequest) error {
+	return c.postOperation(ctx, "/api/claim/transfer", payload)
+}
+
+// PurgeClaim deletes the record of a released name. Purging requires the
+// admin role and cannot be undone.
+func (c *APIClient) PurgeClaim(ctx context.Context, payload PurgeRequest) error {
+	return c.postOperation(ctx, "/api/claim/purge", payload)
+}
+
+// SyncSlugs refreshes the service's slug table from its upstream source and
+// returns the service's summary message. It requires the admin role.
+func (c *APIClient) SyncSlugs(ctx context.Context) (string, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/slug_sync", nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode slug_sync response: %w", err)
+	}
+	return body.Message, nil
+}
+
+func (c *APIClient) postOperation(ctx context.Context, path string, payload any) error {
+	req, err := c.buildRequest(ctx, http.MethodPost, path, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
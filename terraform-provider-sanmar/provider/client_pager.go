@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxListPages bounds how many pages a listing follows, so a service that
+// keeps returning next links cannot keep a plan running forever.
+const maxListPages = 1000
+
+// pageLinks holds the ways a list response can point to its next page: a
+// next link to GET as is, or a continuation token to send back as the
+// continuation query parameter of the original request.
+type pageLinks struct {
+	NextLink          string `json:"next_link"`
+	NextLinkCamel     string `json:"nextLink"`
+	ContinuationToken string `json:"continuation_token"`
+}
+
+// pager walks a paginated list endpoint one page at a time. Items are read
+// from the key field of each page; see pageLinks for how pages are chained.
+// Endpoints that do not paginate return a single page.
+type pager[T any] struct {
+	client   *APIClient
+	path     string
+	kind     string
+	key      string
+	next     string
+	pages    int
+	maxPages int
+	done     bool
+}
+
+// newPager returns a pager for the list at path. kind names the listing in
+// errors, as in "failed to decode <kind> response".
+func newPager[T any](c *APIClient, path, kind, key string) *pager[T] {
+	return &pager[T]{client: c, path: path, kind: kind, key: key, next: path, maxPages: maxListPages}
+}
+
+// More reports whether there are pages left to fetch.
+func (p *pager[T]) More() bool {
+	return !p.done
+}
+
+// NextPage fetches the next page. Non-200 responses are returned as an
+// *APIError so callers can treat 404 as "no such listing".
+func (p *pager[T]) NextPage(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, fmt.Errorf("no more %s pages", p.kind)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if p.pages >= p.maxPages {
+		return nil, fmt.Errorf("stopped listing %s after %d pages; narrow the query or check the service's paging", p.kind, p.maxPages)
+	}
+
+	req, err := p.client.buildRequest(ctx, http.MethodGet, p.next, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", p.kind, err)
+	}
+	var body map[string]json.RawMessage
+	var links pageLinks
+	if err := json.Unmarshal(content, &body); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", p.kind, err)
+	}
+	if err := json.Unmarshal(content, &links); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", p.kind, err)
+	}
+	var items []T
+	if raw, ok := body[p.key]; ok {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("failed to decode %s response: %w", p.kind, err)
+		}
+	}
+	p.pages++
+
+	next, err := p.nextPath(links)
+	if err != nil {
+		return nil, err
+	}
+	if next == "" {
+		p.done = true
+	} else if next == p.next {
+		return nil, fmt.Errorf("%s response links back to the same page", p.kind)
+	}
+	p.next = next
+	return items, nil
+}
+
+// nextPath returns the path of the page links point to, or "" on the last page.
+func (p *pager[T]) nextPath(links pageLinks) (string, error) {
+	switch {
+	case links.NextLink != "":
+		return p.client.endpointPath(links.NextLink)
+	case links.NextLinkCamel != "":
+		return p.client.endpointPath(links.NextLinkCamel)
+	case links.ContinuationToken != "":
+		u, err := url.Parse(p.path)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s path %q: %w", p.kind, p.path, err)
+		}
+		q := u.Query()
+		q.Set("continuation", links.ContinuationToken)
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	}
+	return "", nil
+}
+
+// listAll fetches every page of a listing and returns the items in order.
+func listAll[T any](ctx context.Context, c *APIClient, path, kind, key string) ([]T, error) {
+	p := newPager[T](c, path, kind, key)
+	var all []T
+	for p.More() {
+		items, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestListAllFollowsNextLinks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/systems", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprintf(w, `{"systems":[{"code":"erp"}],"next_link":"http://%s/api/systems?page=2"}`, r.Host)
+		case "2":
+			fmt.Fprint(w, `{"systems":[{"code":"crm"}],"nextLink":"/api/systems?page=3"}`)
+		default:
+			fmt.Fprint(w, `{"systems":[{"code":"pos"}],"next_link":null}`)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	systems, err := client.ListSystems(context.Background())
+	if err != nil {
+		t.Fatalf("ListSystems: %v", err)
+	}
+	var codes []string
+	for _, s := range systems {
+		codes = append(codes, s.Code)
+	}
+	if strings.Join(codes, ",") != "erp,crm,pos" {
+		t.Fatalf("unexpected systems %v", codes)
+	}
+}
+
+func TestListAllSendsContinuationTokens(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit_bulk", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("user") != "alice" {
+			t.Fatalf("filter dropped from page request: %s", r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("continuation") == "" {
+			fmt.Fprint(w, `{"results":[{"name":"a","action":"claimed"}],"continuation_token":"abc=="}`)
+			return
+		}
+		if token := r.URL.Query().Get("continuation"); token != "abc==" {
+			t.Fatalf("unexpected continuation %q", token)
+		}
+		fmt.Fprint(w, `{"results":[{"name":"b","action":"claimed"}]}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	events, err := client.ListAuditEvents(context.Background(), ClaimFilter{User: "alice"})
+	if err != nil {
+		t.Fatalf("ListAuditEvents: %v", err)
+	}
+	if len(events) != 2 || events[1].Name != "b" {
+		t.Fatalf("unexpected events %#v", events)
+	}
+}
+
+func TestPagerSafeguards(t *testing.T) {
+	page := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rules", func(w http.ResponseWriter, r *http.Request) {
+		page++
+		fmt.Fprintf(w, `{"rules":[],"next_link":"/api/rules?page=%d"}`, page)
+	})
+	mux.HandleFunc("/api/loop", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items":[],"next_link":"/api/loop"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	p := newPager[NamingRule](client, "/api/rules", "rules", "rules")
+	p.maxPages = 3
+	var pageErr error
+	for p.More() && pageErr == nil {
+		_, pageErr = p.NextPage(context.Background())
+	}
+	if pageErr == nil || !strings.Contains(pageErr.Error(), "after 3 pages") {
+		t.Fatalf("expected the page limit error, got %v", pageErr)
+	}
+
+	if _, err := listAll[string](context.Background(), client, "/api/loop", "loop", "items"); err == nil || !strings.Contains(err.Error(), "same page") {
+		t.Fatalf("expected the loop to be detected, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := listAll[NamingRule](ctx, client, "/api/rules", "rules", "rules"); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultOperationPollInterval is how often a long-running claim is polled
+// when operation_poll_interval is not set.
+const defaultOperationPollInterval = 2 * time.Second
+
+// Terminal states of a long-running operation. Any other status, such as
+// "running" or "queued", means the operation is still in progress.
+const (
+	operationSucceeded = "succeeded"
+	operationFailed    = "failed"
+	operationCanceled  = "canceled"
+)
+
+// operationStatus is the body returned when polling a long-running operation.
+type operationStatus struct {
+	Status          string          `json:"status"`
+	PercentComplete *int            `json:"percent_complete,omitempty"`
+	Message         string          `json:"message,omitempty"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           *struct {
+		Status  int          `json:"status"`
+		Message string       `json:"message"`
+		Errors  []FieldError `json:"errors"`
+	} `json:"error,omitempty"`
+}
+
+// SetOperationPollInterval sets how often long-running claims are polled.
+func (c *APIClient) SetOperationPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultOperationPollInterval
+	}
+	c.operationPollInterval = interval
+}
+
+// operationPath returns the request path of the operation a 202 response
+// points to, from its Operation-Location or Location header.
+func (c *APIClient) operationPath(resp *http.Response) (string, error) {
+	location := resp.Header.Get("Operation-Location")
+	if location == "" {
+		location = resp.Header.Get("Location")
+	}
+	if location == "" {
+		return "", fmt.Errorf("service accepted the request but did not return an operation URL")
+	}
+	return c.endpointPath(location)
+}
+
+// retryAfter returns the delay requested by a Retry-After header in seconds,
+// or zero when there is none.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// awaitOperation polls the operation a 202 response points to until it
+// finishes, and decodes its result into out. Each poll waits for the poll
+// interval or the service's Retry-After, whichever is longer, and logs the
+// operation's progress. It gives up when ctx is done.
+func (c *APIClient) awaitOperation(ctx context.Context, accepted *http.Response, kind string, out any, required ...string) error {
+	discard(accepted)
+	target, err := c.operationPath(accepted)
+	if err != nil {
+		return err
+	}
+
+	wait := retryAfter(accepted)
+	for {
+		delay := c.operationPollInterval
+		if delay <= 0 {
+			delay = defaultOperationPollInterval
+		}
+		if wait > delay {
+			delay = wait
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for %s operation %s: %w", kind, target, ctx.Err())
+		}
+
+		req, err := c.buildRequest(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := c.doRequest(ctx, req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return decodeError(resp)
+		}
+		wait = retryAfter(resp)
+
+		var op operationStatus
+		if err := c.decodeResponse(resp, kind+" operation", &op, "status"); err != nil {
+			return err
+		}
+
+		fields := map[string]any{"operation": target, "status": op.Status}
+		if op.PercentComplete != nil {
+			fields["percent_complete"] = *op.PercentComplete
+		}
+		if op.Message != "" {
+			fields["message"] = op.Message
+		}
+		tflog.Info(ctx, "waiting for naming service operation", fields)
+
+		switch strings.ToLower(op.Status) {
+		case operationSucceeded:
+			return c.decodeOperationResult(op, kind, out, required...)
+		case operationFailed, operationCanceled:
+			return operationError(op)
+		}
+	}
+}
+
+// decodeOperationResult decodes the result of a succeeded operation the same
+// way a synchronous response would be decoded.
+func (c *APIClient) decodeOperationResult(op operationStatus, kind string, out any, required ...string) error {
+	if len(op.Result) == 0 || string(op.Result) == "null" {
+		return fmt.Errorf("%s operation succeeded without a result; the naming service may be running an incompatible version", kind)
+	}
+	resp := &http.Response{Body: io.NopCloser(bytes.NewReader(op.Result))}
+	return c.decodeResponse(resp, kind, out, required...)
+}
+
+// operationError converts a failed or canceled operation into an APIError,
+// keeping any per-field errors so they are reported against attributes.
+// Operations that fail without a status are reported as 422, since the
+// service accepted the request but could not complete it.
+func operationError(op operationStatus) error {
+	apiErr := &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "operation " + strings.ToLower(op.Status)}
+	if op.Message != "" {
+		apiErr.Message = op.Message
+	}
+	if op.Error != nil {
+		if op.Error.Status != 0 {
+			apiErr.StatusCode = op.Error.Status
+		}
+		if op.Error.Message != "" {
+			apiErr.Message = op.Error.Message
+		}
+		apiErr.Fields = op.Error.Errors
+	}
+	return apiErr
+}
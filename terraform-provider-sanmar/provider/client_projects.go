@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Project is an entry in the service's project registry.
+type Project struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+	Owner       string `json:"owner"`
+	CostCenter  string `json:"cost_center,omitempty"`
+	IndexPolicy string `json:"index_policy,omitempty"`
+}
+
+// ListProjects returns the registered projects. It returns nil without an
+// error when the service does not serve the project registry routes; a 404
+// from the registry itself is still an error.
+func (c *APIClient) ListProjects(ctx context.Context) ([]Project, error) {
+	projects, err := listAll[Project](ctx, c, "/api/projects", "projects", "projects")
+	var apiErr *APIError
+	// The Functions host answers unknown routes with an empty 404; see routeMissing.
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Message == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if projects == nil {
+		projects = []Project{}
+	}
+	return projects, nil
+}
+
+// projectCache holds the registered project codes so a plan lists the
+// registry once rather than once per claim.
+type projectCache struct {
+	mu     sync.Mutex
+	loaded bool
+	// codes is nil when the service has no project registry or an empty one.
+	codes map[string]bool
+	// planned holds codes of sanmar_project resources planned or created in
+	// this run.
+	planned map[string]bool
+}
+
+// addPlannedProject records that a sanmar_project resource registers code.
+func (c *APIClient) addPlannedProject(code string) {
+	c.projects.mu.Lock()
+	defer c.projects.mu.Unlock()
+	if c.projects.planned == nil {
+		c.projects.planned = map[string]bool{}
+	}
+	c.projects.planned[strings.ToLower(code)] = true
+}
+
+// plannedProject reports whether a sanmar_project resource planned so far
+// registers code. Resources are planned concurrently, so a miss only means
+// the project has not been planned yet.
+func (c *APIClient) plannedProject(code string) bool {
+	c.projects.mu.Lock()
+	defer c.projects.mu.Unlock()
+	return c.projects.planned[strings.ToLower(code)]
+}
+
+// knownProject reports whether code is registered. Every code is known when
+// the service has no project registry or an empty one. The registry is
+// listed once; codes missing from the listing are looked up on their own,
+// since a sanmar_project resource may have registered them since. Failed
+// lookups are not cached.
+func (c *APIClient) knownProject(ctx context.Context, code string) (bool, error) {
+	code = strings.ToLower(code)
+	c.projects.mu.Lock()
+	defer c.projects.mu.Unlock()
+
+	if !c.projects.loaded {
+		projects, err := c.ListProjects(ctx)
+		if err != nil {
+			return false, err
+		}
+		if len(projects) > 0 {
+			c.projects.codes = make(map[string]bool, len(projects))
+			for _, project := range projects {
+				c.projects.codes[strings.ToLower(project.Code)] = true
+			}
+		}
+		c.projects.loaded = true
+	}
+	if c.projects.codes == nil || c.projects.codes[code] {
+		return true, nil
+	}
+
+	project, err := c.GetProject(ctx, code)
+	if err != nil {
+		return false, err
+	}
+	if project == nil {
+		return false, nil
+	}
+	c.projects.codes[code] = true
+	return true, nil
+}
+
+// CreateProject registers a project code.
+func (c *APIClient) CreateProject(ctx context.Context, payload Project) (*Project, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/projects", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var project Project
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("failed to decode project response: %w", err)
+	}
+	return &project, nil
+}
+
+// GetProject retrieves a registered project by code.
+func (c *APIClient) GetProject(ctx context.Context, code string) (*Project, error) {
+	req, err := c.buildRequest(ctx, http.MethodGet, "/api/projects/"+url.PathEscape(code), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var project Project
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("failed to decode project response: %w", err)
+	}
+	return &project, nil
+}
+
+// UpdateProject replaces the settings of a registered project.
+func (c *APIClient) UpdateProject(ctx context.Context, code string, payload Project) (*Project, error) {
+	req, err := c.buildRequest(ctx, http.MethodPut, "/api/projects/"+url.PathEscape(code), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var project Project
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("failed to decode project response: %w", err)
+	}
+	return &project, nil
+}
+
+// DeleteProject removes a project from the registry. Missing projects are treated as deleted.
+func (c *APIClient) DeleteProject(ctx context.Context, code string) error {
+	req, err := c.buildRequest(ctx, http.MethodDelete, "/api/projects/"+url.PathEscape(code), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Region is an entry in the service's region catalog, which maps the short
+// codes used in names to Azure regions.
+type Region struct {
+	Code        string `json:"code"`
+	Location    string `json:"location"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+// CreateRegion adds a region short code to the catalog.
+func (c *APIClient) CreateRegion(ctx context.Context, payload Region) (*Region, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/regions", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var region Region
+	if err := json.NewDecoder(resp.Body).Decode(&region); err != nil {
+		return nil, fmt.Errorf("failed to decode region response: %w", err)
+	}
+	return &region, nil
+}
+
+// GetRegion retrieves a catalog entry by short code.
+func (c *APIClient) GetRegion(ctx context.Context, code string) (*Region, error) {
+	req, err := c.buildRequest(ctx, http.MethodGet, "/api/regions/"+url.PathEscape(code), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var region Region
+	if err := json.NewDecoder(resp.Body).Decode(&region); err != nil {
+		return nil, fmt.Errorf("failed to decode region response: %w", err)
+	}
+	return &region, nil
+}
+
+// UpdateRegion replaces the catalog entry for a short code.
+func (c *APIClient) UpdateRegion(ctx context.Context, code string, payload Region) (*Region, error) {
+	req, err := c.buildRequest(ctx, http.MethodPut, "/api/regions/"+url.PathEscape(code), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var region Region
+	if err := json.NewDecoder(resp.Body).Decode(&region); err != nil {
+		return nil, fmt.Errorf("failed to decode region response: %w", err)
+	}
+	return &region, nil
+}
+
+// DeleteRegion removes a short code from the catalog. Missing regions are
+// treated as deleted.
+func (c *APIClient) DeleteRegion(ctx context.Context, code string) error {
+	req, err := c.buildRequest(ctx, http.MethodDelete, "/api/regions/"+url.PathEscape(code), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	resp.Body.Close()
+	return nil
+}
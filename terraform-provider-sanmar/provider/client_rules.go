@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// NamingRule describes the naming rule the service applies to a resource type.
+type NamingRule struct {
+	ResourceType        string   `json:"resourceType"`
+	MaxLength           int      `json:"maxLength"`
+	RequireSanmarPrefix bool     `json:"requireSanmarPrefix"`
+	Segments            []string `json:"segments"`
+	OptionalSegments    []string `json:"optionalSegments"`
+	NameTemplate        string   `json:"nameTemplate"`
+	SummaryTemplate     string   `json:"summaryTemplate"`
+	// ValidCharacters is the regular expression character class, without
+	// brackets, names must stay within, such as "a-z0-9".
+	ValidCharacters string `json:"validCharacters,omitempty"`
+	// CaseRule is the case names must use, such as "lower".
+	CaseRule string `json:"caseRule,omitempty"`
+	// UniquenessScope is where Azure requires names to be unique: "global",
+	// "resource-group" or "parent". Empty when the rule does not say.
+	UniquenessScope string `json:"uniquenessScope,omitempty"`
+	// Canonicalization lists, per segment, the steps the service applies to
+	// claim input before using it, such as "trim" and "lower".
+	Canonicalization map[string][]string `json:"canonicalization,omitempty"`
+}
+
+// ListNamingRules returns the rule for every resource type the service
+// knows, including the "default" rule applied to all other types.
+func (c *APIClient) ListNamingRules(ctx context.Context) ([]NamingRule, error) {
+	return listAll[NamingRule](ctx, c, "/api/rules?expand=details", "rules", "rules")
+}
+
+// GetNamingRule returns the rule the service applies to resourceType. Types
+// without a rule of their own get the "default" rule, as they do when
+// claiming. It returns nil without an error when the service does not serve
+// the rules routes.
+func (c *APIClient) GetNamingRule(ctx context.Context, resourceType string) (*NamingRule, error) {
+	for _, name := range []string{resourceType, "default"} {
+		req, err := c.buildRequest(ctx, http.MethodGet, "/api/rules/"+url.PathEscape(name), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.doRequest(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if routeMissing(resp) {
+			resp.Body.Close()
+			return nil, nil
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, decodeError(resp)
+		}
+
+		var rule NamingRule
+		if err := c.decodeResponse(resp, "naming rule", &rule, "maxLength"); err != nil {
+			return nil, err
+		}
+		return &rule, nil
+	}
+	return nil, nil
+}
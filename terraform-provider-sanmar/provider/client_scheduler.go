@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type claimPriorityKey struct{}
+
+// WithClaimPriority attaches a scheduling priority to claims made with ctx.
+// Higher priorities are sent first when claims are rate limited.
+func WithClaimPriority(ctx context.Context, priority int64) context.Context {
+	return context.WithValue(ctx, claimPriorityKey{}, priority)
+}
+
+func claimPriority(ctx context.Context) int64 {
+	priority, _ := ctx.Value(claimPriorityKey{}).(int64)
+	return priority
+}
+
+// claimScheduler releases queued claims one at a time, highest priority
+// first, no faster than one per interval.
+type claimScheduler struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	queue   claimQueue
+	seq     int
+	running bool
+}
+
+type queuedClaim struct {
+	priority  int64
+	seq       int
+	ready     chan struct{}
+	cancelled bool
+}
+
+func newClaimScheduler(perSecond float64) *claimScheduler {
+	return &claimScheduler{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait blocks until the claim may be sent or ctx is done.
+func (s *claimScheduler) wait(ctx context.Context, priority int64) error {
+	item := &queuedClaim{priority: priority, ready: make(chan struct{})}
+
+	s.mu.Lock()
+	s.seq++
+	item.seq = s.seq
+	heap.Push(&s.queue, item)
+	if !s.running {
+		s.running = true
+		go s.drain()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-item.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		item.cancelled = true
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// drain releases queued claims until the queue is empty.
+func (s *claimScheduler) drain() {
+	for {
+		s.mu.Lock()
+		var next *queuedClaim
+		for s.queue.Len() > 0 && next == nil {
+			if item := heap.Pop(&s.queue).(*queuedClaim); !item.cancelled {
+				next = item
+			}
+		}
+		if next == nil {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		close(next.ready)
+		s.mu.Unlock()
+
+		time.Sleep(s.interval)
+	}
+}
+
+// claimQueue orders claims by priority, then arrival.
+type claimQueue []*queuedClaim
+
+func (q claimQueue) Len() int { return len(q) }
+func (q claimQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority > q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q claimQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *claimQueue) Push(x any)   { *q = append(*q, x.(*queuedClaim)) }
+func (q *claimQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
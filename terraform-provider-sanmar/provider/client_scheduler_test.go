@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClaimSchedulerPriority(t *testing.T) {
+	s := newClaimScheduler(20)
+	ctx := context.Background()
+
+	// The first claim is released at once and starts the 50ms spacing.
+	if err := s.wait(ctx, 0); err != nil {
+		t.Fatalf("wait: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for name, priority := range map[string]int64{"low": 1, "high": 5} {
+		wg.Add(1)
+		go func(name string, priority int64) {
+			defer wg.Done()
+			if err := s.wait(ctx, priority); err != nil {
+				t.Errorf("wait: %v", err)
+			}
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+		}(name, priority)
+	}
+	wg.Wait()
+
+	if len(order) != 2 || order[0] != "high" {
+		t.Fatalf("expected high priority claim first, got %v", order)
+	}
+
+	cancelled, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	s.wait(ctx, 0)
+	if err := s.wait(cancelled, 0); err == nil {
+		t.Fatalf("expected cancelled wait to fail")
+	}
+}
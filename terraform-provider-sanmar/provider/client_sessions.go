@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SessionDefaults captures the segment defaults stored for a session.
+type SessionDefaults struct {
+	SessionID string            `json:"sessionId"`
+	Defaults  map[string]string `json:"defaults"`
+	LastSeen  string            `json:"lastSeen"`
+}
+
+// GetSessionDefaults retrieves the defaults the service applies for a session.
+func (c *APIClient) GetSessionDefaults(ctx context.Context, sessionID string) (*SessionDefaults, error) {
+	q := url.Values{}
+	q.Set("session_id", sessionID)
+	path := "/api/session?" + q.Encode()
+
+	req, err := c.buildRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var session SessionDefaults
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return nil, fmt.Errorf("failed to decode session response: %w", err)
+	}
+	return &session, nil
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// sharedConnection is shared by every APIClient for the same endpoint and
+// scope in this process, so provider aliases pointing at one service reuse
+// its connection pool, token cache, and claim rate limiter.
+type sharedConnection struct {
+	http *http.Client
+	cred azcore.TokenCredential
+
+	mu        sync.Mutex
+	scheduler *claimScheduler
+	rate      float64
+}
+
+var (
+	sharedConnectionsMu sync.Mutex
+	sharedConnections   = map[string]*sharedConnection{}
+)
+
+// sharedConnectionFor returns the connection for endpoint and scope, creating
+// it on first use. endpoint is the raw configured value, so unix:// sockets
+// get their own connection.
+func sharedConnectionFor(endpoint, scope string) (*sharedConnection, error) {
+	key := endpoint + "|" + scope
+
+	sharedConnectionsMu.Lock()
+	defer sharedConnectionsMu.Unlock()
+	if conn, ok := sharedConnections[key]; ok {
+		return conn, nil
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize DefaultAzureCredential: %w", err)
+	}
+
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if socketPath, ok := splitUnixEndpoint(endpoint); ok {
+		httpClient.Transport = unixSocketTransport(socketPath)
+	}
+
+	conn := &sharedConnection{http: httpClient, cred: cred}
+	sharedConnections[key] = conn
+	return conn, nil
+}
+
+// claimScheduler returns the connection's rate limiter and the rate it
+// enforces. The first configured rate applies to every alias sharing the
+// connection, so callers asking for another rate should say so.
+func (s *sharedConnection) claimScheduler(perSecond float64) (*claimScheduler, float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scheduler == nil {
+		s.scheduler = newClaimScheduler(perSecond)
+		s.rate = perSecond
+	}
+	return s.scheduler, s.rate
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SuggestionRequest asks the service for candidate names within a scope.
+type SuggestionRequest struct {
+	ClaimNameRequest
+	Purposes []string `json:"purposes,omitempty"`
+	Count    int      `json:"count,omitempty"`
+}
+
+// NameSuggestion is a single candidate returned by the suggestions endpoint.
+type NameSuggestion struct {
+	Name      string `json:"name"`
+	Purpose   string `json:"purpose"`
+	Index     string `json:"index"`
+	Available bool   `json:"available"`
+}
+
+type suggestionsResponse struct {
+	Suggestions []NameSuggestion `json:"suggestions"`
+}
+
+// SuggestNames returns candidate names for different purposes and indices.
+func (c *APIClient) SuggestNames(ctx context.Context, payload SuggestionRequest) ([]NameSuggestion, error) {
+	req, err := c.buildRequest(ctx, http.MethodPost, "/api/suggestions", payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, decodeError(resp)
+	}
+
+	defer resp.Body.Close()
+	var out suggestionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("failed to decode suggestions response: %w", err)
+	}
+	return out.Suggestions, nil
+}
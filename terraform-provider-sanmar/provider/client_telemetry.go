@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Environment variables that set partner_id and disable_telemetry when the
+// provider block leaves them out, so a platform team can set them once per
+// runner the way ARM_PARTNER_ID works for AzureRM.
+const (
+	PartnerIDEnv        = "SANMAR_PARTNER_ID"
+	DisableTelemetryEnv = "SANMAR_DISABLE_TELEMETRY"
+)
+
+// appendUserAgentEnv is the variable HashiCorp providers read for text to
+// append to their User-Agent header.
+const appendUserAgentEnv = "TF_APPEND_USER_AGENT"
+
+// userAgentProduct is the product token the provider sends in User-Agent.
+const userAgentProduct = "terraform-provider-sanmar"
+
+var partnerIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// telemetry decides what the provider reports about itself to the service.
+// It is sent as the User-Agent header and a few run headers, nothing else.
+type telemetry struct {
+	version          string
+	terraformVersion string
+	partnerID        string
+	disabled         bool
+	// appendix is operator-supplied text added to the end of User-Agent.
+	appendix string
+	// workspace and execution describe the run for the service's audit
+	// records; see currentWorkspace and currentExecution.
+	workspace string
+	execution string
+}
+
+// Execution environments reported in the X-Sanmar-Execution header.
+const (
+	executionCLI        = "cli"
+	executionAutomation = "automation"
+	executionTFC        = "tfc"
+)
+
+// apply sets the User-Agent and, unless telemetry is disabled, the run
+// headers the service records with each audit entry.
+func (t telemetry) apply(h http.Header) {
+	h.Set("User-Agent", t.userAgent())
+	if t.disabled {
+		return
+	}
+	if t.terraformVersion != "" {
+		h.Set("X-Sanmar-Terraform-Version", t.terraformVersion)
+	}
+	if t.workspace != "" {
+		h.Set("X-Sanmar-Workspace", t.workspace)
+	}
+	if t.execution != "" {
+		h.Set("X-Sanmar-Execution", t.execution)
+	}
+}
+
+// userAgent returns the User-Agent header value, for example
+// "terraform-provider-sanmar/1.4.0 (linux; amd64) Terraform/1.9.5", so
+// service logs can break traffic down by provider and Terraform version.
+// Disabling telemetry leaves only the product name; a partner ID and
+// appended text are still sent because the operator asked for them.
+func (t telemetry) userAgent() string {
+	parts := []string{userAgentProduct}
+	if !t.disabled {
+		if t.version != "" {
+			parts[0] += "/" + t.version
+		}
+		parts = append(parts, fmt.Sprintf("(%s; %s)", runtime.GOOS, runtime.GOARCH))
+		if t.terraformVersion != "" {
+			parts = append(parts, "Terraform/"+t.terraformVersion)
+		}
+	}
+	if t.partnerID != "" {
+		parts = append(parts, "pid-"+strings.ToLower(t.partnerID))
+	}
+	if appendix := strings.TrimSpace(t.appendix); appendix != "" {
+		parts = append(parts, appendix)
+	}
+	return strings.Join(parts, " ")
+}
+
+// SetTelemetry configures the provider version and partner ID reported in
+// the User-Agent header, or suppresses the version and platform when
+// disabled is set.
+func (c *APIClient) SetTelemetry(version, partnerID string, disabled bool) error {
+	if partnerID != "" && !partnerIDPattern.MatchString(partnerID) {
+		return fmt.Errorf("partner ID %q must be a GUID", partnerID)
+	}
+	c.telemetry = telemetry{version: version, partnerID: partnerID, disabled: disabled}
+	return nil
+}
+
+// currentWorkspace returns the Terraform workspace of the run. Terraform
+// does not pass it to providers, so it comes from TF_WORKSPACE, which users
+// and wrappers set, or TFC_WORKSPACE_NAME, which HCP Terraform sets.
+func currentWorkspace() string {
+	if ws := os.Getenv("TF_WORKSPACE"); ws != "" {
+		return ws
+	}
+	return os.Getenv("TFC_WORKSPACE_NAME")
+}
+
+// currentExecution reports whether the run is on HCP Terraform or Terraform
+// Enterprise, in other automation that sets TF_IN_AUTOMATION, or on the CLI.
+func currentExecution() string {
+	switch {
+	case os.Getenv("TFC_RUN_ID") != "":
+		return executionTFC
+	case os.Getenv("TF_IN_AUTOMATION") != "":
+		return executionAutomation
+	default:
+		return executionCLI
+	}
+}
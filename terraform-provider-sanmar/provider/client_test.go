@@ -1,23 +1,57 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 )
 
 type tokenProvider struct{}
 
+// rotatingTokens hands out a new token on every call.
+type rotatingTokens struct {
+	calls int
+}
+
+func (p *rotatingTokens) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	p.calls++
+	return azcore.AccessToken{Token: fmt.Sprintf("token-%d", p.calls), ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
 func TestClaimLifecycle(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
 			t.Fatalf("expected POST, got %s", r.Method)
 		}
+		// The service answers claims with 201 Created.
 		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(ClaimNameResponse{
 			Name:         "wus2prdfoo",
 			ResourceType: "storage_account",
@@ -115,6 +149,42 @@ func TestGetAuditNotFound(t *testing.T) {
 	}
 }
 
+func TestGetAuditRetired(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == "plain" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusGone)
+		fmt.Fprint(w, `{"message":"name retired after a security incident"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	record, err := client.GetAudit(context.Background(), "wus2", "prd", "sanmar-app")
+	if err != nil {
+		t.Fatalf("GetAudit: %v", err)
+	}
+	if record == nil || !record.Retired || record.InUse || record.Name != "sanmar-app" || record.RetiredReason != "name retired after a security incident" {
+		t.Fatalf("expected a retired record, got %#v", record)
+	}
+	if len(record.Metadata) != 0 {
+		t.Fatalf("expected no metadata, got %v", record.Metadata)
+	}
+
+	record, err = client.GetAudit(context.Background(), "wus2", "prd", "plain")
+	if err != nil || record == nil || !record.Retired {
+		t.Fatalf("expected a retired record without a body, got %#v, %v", record, err)
+	}
+}
+
 func TestRetryLogic(t *testing.T) {
 	attempts := 0
 	mux := http.NewServeMux()
@@ -143,3 +213,1723 @@ func TestRetryLogic(t *testing.T) {
 		t.Fatalf("expected 2 attempts, got %d", attempts)
 	}
 }
+
+func TestRetryLogsProgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	defer func(interval time.Duration) { retryProgressInterval = interval }(retryProgressInterval)
+	retryProgressInterval = 5 * time.Millisecond
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 2, MinBackoff: 12 * time.Millisecond, MaxBackoff: 12 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+	if _, err := client.ClaimName(ctx, ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err == nil {
+		t.Fatalf("expected ClaimName to fail")
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&logs)
+	if err != nil {
+		t.Fatalf("decode logs: %v", err)
+	}
+	var messages []string
+	for _, entry := range entries {
+		messages = append(messages, fmt.Sprint(entry["@message"]))
+	}
+	got := strings.Join(messages, "\n")
+	if !strings.Contains(got, "attempt 1/2, backing off 12ms, last status 503") || !strings.Contains(got, "still backing off, 7ms remaining") {
+		t.Fatalf("unexpected retry logs:\n%s", got)
+	}
+
+	// A cancelled context ends a long back-off straight away.
+	slow, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 2, MinBackoff: time.Hour, MaxBackoff: time.Hour})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	cancelCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if _, err := slow.ClaimName(cancelCtx, ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected cancellation to end the back-off, took %s", elapsed)
+	}
+}
+
+func TestEnsureReleased(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/release", func(w http.ResponseWriter, r *http.Request) {
+		var payload ReleaseRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch payload.Name {
+		case "retired":
+			w.WriteHeader(http.StatusGone)
+		default:
+			http.Error(w, `{"message":"name not found"}`, http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		switch name := r.URL.Query().Get("name"); name {
+		case "released":
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": name, "in_use": false})
+		case "claimed":
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": name, "in_use": true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	for _, name := range []string{"retired", "released", "unknown"} {
+		if err := client.EnsureReleased(ctx, ReleaseRequest{Name: name, Region: "wus2", Environment: "prd"}); err != nil {
+			t.Errorf("%s: expected success, got %v", name, err)
+		}
+	}
+	var apiErr *APIError
+	if err := client.EnsureReleased(ctx, ReleaseRequest{Name: "claimed", Region: "wus2", Environment: "prd"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected the 404 for a name the audit still shows claimed, got %v", err)
+	}
+}
+
+func TestNotificationLifecycle(t *testing.T) {
+	deleted := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/notifications", func(w http.ResponseWriter, r *http.Request) {
+		var sub NotificationSubscription
+		if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		sub.ID = "n-1"
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(sub)
+	})
+	mux.HandleFunc("/api/notifications/n-1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if deleted {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(NotificationSubscription{ID: "n-1", Name: "ops", Kind: "teams", Events: []string{"claim"}, Enabled: true})
+		case http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	created, err := client.CreateNotification(context.Background(), NotificationSubscription{Name: "ops", Kind: "teams", URL: "https://example.com/hook", Events: []string{"claim"}, Enabled: true})
+	if err != nil {
+		t.Fatalf("CreateNotification: %v", err)
+	}
+	if created.ID != "n-1" {
+		t.Fatalf("unexpected id: %s", created.ID)
+	}
+
+	if err := client.DeleteNotification(context.Background(), "n-1"); err != nil {
+		t.Fatalf("DeleteNotification: %v", err)
+	}
+
+	sub, err := client.GetNotification(context.Background(), "n-1")
+	if err != nil {
+		t.Fatalf("GetNotification: %v", err)
+	}
+	if sub != nil {
+		t.Fatalf("expected nil subscription, got %#v", sub)
+	}
+}
+
+func TestProjectLifecycle(t *testing.T) {
+	var stored *Project
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		var project Project
+		if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		project.IndexPolicy = "auto"
+		stored = &project
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(project)
+	})
+	mux.HandleFunc("/api/projects/atlas", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if stored == nil {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+		case http.MethodPut:
+			var project Project
+			if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			stored = &project
+			json.NewEncoder(w).Encode(project)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	created, err := client.CreateProject(ctx, Project{Code: "atlas", Owner: "platform", CostCenter: "cc-100"})
+	if err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if created.IndexPolicy != "auto" || created.CostCenter != "cc-100" {
+		t.Fatalf("unexpected project: %#v", created)
+	}
+
+	updated, err := client.UpdateProject(ctx, "atlas", Project{Code: "atlas", Owner: "finops", IndexPolicy: "manual"})
+	if err != nil {
+		t.Fatalf("UpdateProject: %v", err)
+	}
+	if updated.Owner != "finops" || updated.IndexPolicy != "manual" {
+		t.Fatalf("unexpected project: %#v", updated)
+	}
+
+	if err := client.DeleteProject(ctx, "atlas"); err != nil {
+		t.Fatalf("DeleteProject: %v", err)
+	}
+	project, err := client.GetProject(ctx, "atlas")
+	if err != nil {
+		t.Fatalf("GetProject: %v", err)
+	}
+	if project != nil {
+		t.Fatalf("expected nil project, got %#v", project)
+	}
+}
+
+func TestEnvironmentLifecycle(t *testing.T) {
+	var stored *Environment
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/environments", func(w http.ResponseWriter, r *http.Request) {
+		var environment Environment
+		if err := json.NewDecoder(r.Body).Decode(&environment); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		stored = &environment
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(environment)
+	})
+	mux.HandleFunc("/api/environments/prd", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if stored == nil {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(stored)
+		case http.MethodPut:
+			var environment Environment
+			if err := json.NewDecoder(r.Body).Decode(&environment); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			stored = &environment
+			json.NewEncoder(w).Encode(environment)
+		case http.MethodDelete:
+			stored = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	created, err := client.CreateEnvironment(ctx, Environment{Code: "prd", DisplayName: "Production", AllowedRegions: []string{"wus2", "eus2"}})
+	if err != nil {
+		t.Fatalf("CreateEnvironment: %v", err)
+	}
+	if created.DisplayName != "Production" || len(created.AllowedRegions) != 2 {
+		t.Fatalf("unexpected environment: %#v", created)
+	}
+
+	updated, err := client.UpdateEnvironment(ctx, "prd", Environment{Code: "prd", DisplayName: "Production", ReleasedRetention: "2160h"})
+	if err != nil {
+		t.Fatalf("UpdateEnvironment: %v", err)
+	}
+	if updated.ReleasedRetention != "2160h" || updated.AllowedRegions != nil {
+		t.Fatalf("unexpected environment: %#v", updated)
+	}
+
+	if err := client.DeleteEnvironment(ctx, "prd"); err != nil {
+		t.Fatalf("DeleteEnvironment: %v", err)
+	}
+	environment, err := client.GetEnvironment(ctx, "prd")
+	if err != nil {
+		t.Fatalf("GetEnvironment: %v", err)
+	}
+	if environment != nil {
+		t.Fatalf("expected nil environment, got %#v", environment)
+	}
+}
+
+func TestRegionCatalog(t *testing.T) {
+	var created Region
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/regions", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(created)
+	})
+	mux.HandleFunc("/api/regions/mxc", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	region, err := client.CreateRegion(ctx, Region{Code: "mxc", Location: "mexicocentral", DisplayName: "Mexico Central"})
+	if err != nil {
+		t.Fatalf("CreateRegion: %v", err)
+	}
+	if region.Location != "mexicocentral" || created.DisplayName != "Mexico Central" {
+		t.Fatalf("unexpected region: %#v", region)
+	}
+	if err := client.DeleteRegion(ctx, "mxc"); err != nil {
+		t.Fatalf("DeleteRegion: %v", err)
+	}
+	if missing, err := client.GetRegion(ctx, "nope"); err != nil || missing != nil {
+		t.Fatalf("expected a missing region to return nil, got %#v, %v", missing, err)
+	}
+}
+
+func TestClaimNameUsesGeneratedSession(t *testing.T) {
+	var received ClaimNameRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		json.NewEncoder(w).Encode(ClaimNameResponse{Name: "ok"})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.sessionID, err = newSessionID()
+	if err != nil {
+		t.Fatalf("newSessionID: %v", err)
+	}
+
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if received.SessionID == nil || *received.SessionID != client.sessionID {
+		t.Fatalf("expected generated session %s, got %v", client.sessionID, received.SessionID)
+	}
+
+	explicit := "terraform-prd"
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd", SessionID: &explicit}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if received.SessionID == nil || *received.SessionID != explicit {
+		t.Fatalf("expected explicit session to win, got %v", received.SessionID)
+	}
+}
+
+func TestClaimNameUsesIndexReuseDefault(t *testing.T) {
+	var received ClaimNameRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		received = ClaimNameRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		json.NewEncoder(w).Encode(ClaimNameResponse{Name: "ok"})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if received.IndexReuse != nil {
+		t.Fatalf("expected no index_reuse without a default, got %q", *received.IndexReuse)
+	}
+
+	client.indexReuse = "never"
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if received.IndexReuse == nil || *received.IndexReuse != "never" {
+		t.Fatalf("expected provider default never, got %v", received.IndexReuse)
+	}
+
+	explicit := "always"
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd", IndexReuse: &explicit}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if received.IndexReuse == nil || *received.IndexReuse != explicit {
+		t.Fatalf("expected claim index_reuse to win, got %v", received.IndexReuse)
+	}
+}
+
+func TestDecodeErrorFieldErrors(t *testing.T) {
+	// The body the service's handle_name_generation_error sends for an
+	// InvalidRequestError that names its fields.
+	const message = "Field 'suffix' must be 1-16 letters or digits."
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message": "` + message + `", "errors": [{"field": "suffix", "message": "` + message + `"}]}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	_, err = client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != message {
+		t.Fatalf("unexpected error: %#v", apiErr)
+	}
+
+	var diags diag.Diagnostics
+	addClaimError(&diags, "Failed to claim name", err)
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diags)
+	}
+	withPath, ok := diags[0].(diag.DiagnosticWithPath)
+	if !ok || !withPath.Path().Equal(path.Root("unique_suffix")) || diags[0].Detail() != message {
+		t.Fatalf("expected the error on unique_suffix, got %v", diags)
+	}
+}
+
+func TestListClaimsFromAuditHistory(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit_bulk", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("environment") != "prd" || r.URL.Query().Has("action") {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": []AuditEvent{
+			{Name: "kvwus2prdatlas", Action: "released", Region: "wus2", Environment: "prd"},
+			{Name: "stwus2prdatlas", Action: "transferred", Region: "wus2", Environment: "prd", User: "admin", NewOwner: "carol"},
+			{Name: "stwus2prdatlas", Action: "transferred", Region: "wus2", Environment: "prd", User: "alice", NewOwner: "bob"},
+			{Name: "stwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd", ResourceType: "storage_account", User: "alice"},
+			{Name: "kvwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd"},
+			// A transfer before the name was last claimed no longer applies.
+			{Name: "appwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd", User: "dave"},
+			{Name: "appwus2prdatlas", Action: "released", Region: "wus2", Environment: "prd"},
+			{Name: "appwus2prdatlas", Action: "transferred", Region: "wus2", Environment: "prd", User: "erin", NewOwner: "frank"},
+			{Name: "appwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd", User: "erin"},
+		}})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	claims, err := client.ListClaims(context.Background(), ClaimFilter{Environment: "prd", Action: "released"})
+	if err != nil {
+		t.Fatalf("ListClaims: %v", err)
+	}
+	if len(claims) != 2 || claims[0].Name != "stwus2prdatlas" || claims[0].ClaimedBy != "carol" {
+		t.Fatalf("expected the claim to be owned by its newest transferee, got %#v", claims)
+	}
+	if claims[1].Name != "appwus2prdatlas" || claims[1].ClaimedBy != "dave" {
+		t.Fatalf("expected the claimant of the current claim, got %#v", claims[1])
+	}
+	if claims[0].Identity() != "wus2/prd/stwus2prdatlas" {
+		t.Fatalf("unexpected identity %q", claims[0].Identity())
+	}
+}
+
+func TestGetAuditCustomMetadata(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"kvwus2prdatlas","resource_type":"key_vault","in_use":true,"region":"wus2","environment":"prd","project":"atlas","released_by":null,"owner":"finops","cost_center":4200}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	record, err := client.GetAudit(context.Background(), "wus2", "prd", "kvwus2prdatlas")
+	if err != nil {
+		t.Fatalf("GetAudit: %v", err)
+	}
+	if record.Project != "atlas" || record.Resource != "key_vault" {
+		t.Fatalf("unexpected record: %#v", record)
+	}
+	if len(record.Metadata) != 2 || record.Metadata["owner"] != "finops" || record.Metadata["cost_center"] != "4200" {
+		t.Fatalf("unexpected metadata: %#v", record.Metadata)
+	}
+}
+
+func TestAuditRecordNestedMetadata(t *testing.T) {
+	// The service stores the claim's metadata object as a JSON string and
+	// returns the other request fields it does not know as snake_case keys.
+	body := `{"name":"stwus2prdatlas01","resource_type":"storage_account","in_use":true,"region":"wus2","environment":"prd",` +
+		`"system":"atlas","expires_at":"2026-11-01t00:00:00z","template":"{slug}{system}","suffix":"a1b2","release_after":"720h",` +
+		`"transferred_by":"u2","transferred_at":"2026-01-01t00:00:00","correlation_id":"c-1","team":"orion",` +
+		`"metadata":"{\"owner\": \"FinOps\", \"api_token\": \"s3cret\", \"sanmar_workspace\": \"Prod-East\", \"sanmar_sensitive_keys\": \"api_token\"}"}`
+
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(body), &record); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := map[string]string{"owner": "FinOps", "team": "orion", CleanupMetadataKey: "Prod-East"}
+	if len(record.Metadata) != len(want) {
+		t.Fatalf("unexpected metadata: %#v", record.Metadata)
+	}
+	for k, v := range want {
+		if record.Metadata[k] != v {
+			t.Fatalf("unexpected metadata: %#v", record.Metadata)
+		}
+	}
+}
+
+func TestClaimPayloadListsSensitiveKeys(t *testing.T) {
+	ctx := context.Background()
+	plan := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		SensitiveMetadata: types.MapValueMust(types.StringType, map[string]attr.Value{
+			"api_token": types.StringValue("s3cret"),
+			"db_secret": types.StringValue("hunter2"),
+		}),
+	}
+	payload, diags := buildClaimPayload(ctx, plan)
+	if diags.HasError() {
+		t.Fatalf("buildClaimPayload: %v", diags)
+	}
+	if payload.Metadata[sensitiveMetadataKeysKey] != "api_token,db_secret" {
+		t.Fatalf("unexpected metadata: %#v", payload.Metadata)
+	}
+}
+
+func TestClaimPayloadForwardsIndexReuse(t *testing.T) {
+	ctx := context.Background()
+	for _, policy := range indexReusePolicies {
+		plan := claimResourceModel{
+			ResourceType: types.StringValue("storage_account"),
+			Region:       regionType.value("wus2"),
+			Environment:  environmentType.value("prd"),
+			IndexReuse:   types.StringValue(policy),
+		}
+		payload, diags := buildClaimPayload(ctx, plan)
+		if diags.HasError() {
+			t.Fatalf("buildClaimPayload: %v", diags)
+		}
+		if payload.IndexReuse == nil || *payload.IndexReuse != policy {
+			t.Fatalf("expected index_reuse %s, got %v", policy, payload.IndexReuse)
+		}
+	}
+}
+
+func TestClaimBatching(t *testing.T) {
+	var mu sync.Mutex
+	batches, singles := 0, 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim/batch", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Claims []ClaimNameRequest `json:"claims"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		batches++
+		mu.Unlock()
+
+		results := make([]map[string]any, len(body.Claims))
+		for i, c := range body.Claims {
+			switch c.ResourceType {
+			case "bad":
+				results[i] = map[string]any{"error": map[string]any{"status": 409, "message": "name exhausted"}}
+			case "invalid":
+				results[i] = map[string]any{"error": map[string]any{"status": 400, "message": "validation failed", "errors": []FieldError{{Field: "purpose", Message: "required"}}}}
+			case "slow":
+				results[i] = map[string]any{"status": 202, "operation": "/api/operations/op-slow"}
+			default:
+				results[i] = map[string]any{"claim": ClaimNameResponse{Name: c.ResourceType + "wus2prd"}}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		singles++
+		mu.Unlock()
+		json.NewEncoder(w).Encode(ClaimNameResponse{Name: "single"})
+	})
+	mux.HandleFunc("/api/operations/op-slow", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"succeeded","result":{"name":"slowwus2prd"}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.EnableClaimBatching(50 * time.Millisecond)
+	client.SetOperationPollInterval(time.Millisecond)
+
+	resourceTypes := []string{"kv", "st", "bad", "invalid", "slow"}
+	names := make([]string, len(resourceTypes))
+	errs := make([]error, len(resourceTypes))
+	var wg sync.WaitGroup
+	for i, rt := range resourceTypes {
+		wg.Add(1)
+		go func(i int, rt string) {
+			defer wg.Done()
+			claim, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: rt, Region: "wus2", Environment: "prd"})
+			errs[i] = err
+			if claim != nil {
+				names[i] = claim.Name
+			}
+		}(i, rt)
+	}
+	wg.Wait()
+
+	if batches != 1 || singles != 0 {
+		t.Fatalf("expected one batch request, got %d batches and %d single claims", batches, singles)
+	}
+	if names[0] != "kvwus2prd" || names[1] != "stwus2prd" || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("unexpected results: %v %v", names, errs)
+	}
+	var apiErr *APIError
+	if !errors.As(errs[2], &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("expected per-claim APIError, got %v", errs[2])
+	}
+	if !errors.As(errs[3], &apiErr) || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "purpose" {
+		t.Fatalf("expected per-claim field errors, got %v", errs[3])
+	}
+	if errs[4] != nil || names[4] != "slowwus2prd" {
+		t.Fatalf("expected the accepted claim to wait for its operation, got %q, %v", names[4], errs[4])
+	}
+}
+
+func TestClaimBatchingKeepsClaimOfCancelledCaller(t *testing.T) {
+	claiming, release := make(chan struct{}), make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim/batch", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Claims []ClaimNameRequest `json:"claims"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		close(claiming)
+		<-release
+		results := make([]map[string]any, len(body.Claims))
+		for i, c := range body.Claims {
+			results[i] = map[string]any{"claim": ClaimNameResponse{Name: c.ResourceType + "wus2prd"}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.EnableClaimBatching(10 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	claims := make(chan *ClaimNameResponse, 2)
+	for _, c := range []struct {
+		ctx context.Context
+		rt  string
+	}{{ctx, "kv"}, {context.Background(), "st"}} {
+		go func() {
+			claim, _ := client.ClaimName(c.ctx, ClaimNameRequest{ResourceType: c.rt, Region: "wus2", Environment: "prd"})
+			claims <- claim
+		}()
+	}
+
+	// Cancel one caller while its batch is claiming; the batch carries on
+	// for the other, and the cancelled caller still learns its name.
+	<-claiming
+	cancel()
+	close(release)
+	for i := 0; i < 2; i++ {
+		if claim := <-claims; claim == nil {
+			t.Fatal("expected both claims to be returned")
+		}
+	}
+}
+
+func TestClaimBatchingNotFound(t *testing.T) {
+	var batchBody string
+	singles := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim/batch", func(w http.ResponseWriter, r *http.Request) {
+		if batchBody == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.Error(w, batchBody, http.StatusNotFound)
+	})
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		singles++
+		json.NewEncoder(w).Encode(ClaimNameResponse{Name: "single"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	payloads := []ClaimNameRequest{{ResourceType: "kv", Region: "wus2", Environment: "prd"}, {ResourceType: "st", Region: "wus2", Environment: "prd"}}
+
+	// The host answers a route it does not serve with an empty 404.
+	if results, err := client.ClaimNames(context.Background(), payloads); results != nil || err != nil {
+		t.Fatalf("expected a missing batch route to report no support, got %v, %v", results, err)
+	}
+
+	// A 404 the batch handler explains is an error, not a missing route.
+	batchBody = "Rule not found for resource type 'kv'."
+	_, err = client.ClaimNames(context.Background(), payloads)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != batchBody {
+		t.Fatalf("expected the handler's 404 as an error, got %v", err)
+	}
+	if singles != 0 {
+		t.Fatalf("expected no single claims, got %d", singles)
+	}
+}
+
+func TestClaimNamesReusesBodyAcrossRetries(t *testing.T) {
+	var bodies [][]byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim/batch", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Sanmar-Content-SHA256") != hex.EncodeToString(sum[:]) {
+			t.Errorf("attempt %d: content hash mismatch", len(bodies))
+		}
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("attempt %d: Content-Length %d for a %d byte body", len(bodies), r.ContentLength, len(body))
+		}
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var decoded struct {
+			Claims []ClaimNameRequest `json:"claims"`
+		}
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			t.Errorf("invalid batch body: %v", err)
+		}
+		results := make([]map[string]any, len(decoded.Claims))
+		for i, c := range decoded.Claims {
+			results[i] = map[string]any{"claim": ClaimNameResponse{Name: c.ResourceType}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.hmac = &HMACCredentials{KeyID: "gw-1", Secret: "s3cret"}
+
+	payloads := make([]ClaimNameRequest, claimBatchMaxSize)
+	for i := range payloads {
+		payloads[i] = ClaimNameRequest{ResourceType: fmt.Sprintf("st%02d", i), Region: "wus2", Environment: "prd"}
+	}
+	for round := 0; round < 2; round++ {
+		bodies = nil
+		results, err := client.ClaimNames(context.Background(), payloads)
+		if err != nil {
+			t.Fatalf("round %d: ClaimNames: %v", round, err)
+		}
+		if len(bodies) != 2 || !bytes.Equal(bodies[0], bodies[1]) {
+			t.Fatalf("round %d: expected the retry to resend the same body, got %q", round, bodies)
+		}
+		for i, result := range results {
+			if result.Claim == nil || result.Claim.Name != payloads[i].ResourceType {
+				t.Fatalf("round %d: unexpected result %d: %#v", round, i, result)
+			}
+		}
+	}
+}
+
+func TestReauthorizeOnRejectedToken(t *testing.T) {
+	attempts := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		var body ClaimNameRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ResourceType != "vm" {
+			t.Errorf("attempt %d: unexpected body %#v (%v)", attempts, body, err)
+		}
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(ClaimNameResponse{Name: "ok"})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "api://naming/.default", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	tokens := &rotatingTokens{}
+	client.cred = tokens
+
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if attempts != 2 || tokens.calls != 2 {
+		t.Fatalf("expected 2 attempts and 2 tokens, got %d and %d", attempts, tokens.calls)
+	}
+
+	// A token that is rejected again is reported rather than retried forever.
+	tokens.calls = 5
+	_, err = client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 APIError, got %v", err)
+	}
+}
+
+func TestDeprecationWarningsReportedOnce(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@1767225600")
+		w.Header().Set("Sunset", "Mon, 01 Mar 2027 00:00:00 GMT")
+		w.Header().Set("Link", `</api/v2/slug>; rel="successor-version"`)
+		json.NewEncoder(w).Encode(SlugResponse{Slug: "st"})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.LookupSlug(context.Background(), "storage_account"); err != nil {
+			t.Fatalf("LookupSlug: %v", err)
+		}
+	}
+
+	var diags diag.Diagnostics
+	client.reportDeprecations(&diags)
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("expected one warning, got %v", diags)
+	}
+	detail := diags[0].Detail()
+	if !strings.Contains(detail, "GET /api/slug") || !strings.Contains(detail, "/api/v2/slug") || !strings.Contains(detail, "01 Mar 2027") {
+		t.Fatalf("unexpected warning detail %q", detail)
+	}
+
+	diags = nil
+	client.reportDeprecations(&diags)
+	if len(diags) != 0 {
+		t.Fatalf("expected deprecation to be reported once, got %v", diags)
+	}
+}
+
+func TestHMACSignedRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(body)
+		if r.Header.Get("X-Sanmar-Content-SHA256") != hex.EncodeToString(sum[:]) {
+			t.Errorf("content hash mismatch")
+		}
+
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(hmacStringToSign(r.Method, r.URL.RequestURI(), r.Header.Get("X-Sanmar-Date"), hex.EncodeToString(sum[:]))))
+		want := "HMAC-SHA256 KeyId=gw-1, SignedHeaders=x-sanmar-date;x-sanmar-content-sha256, Signature=" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		if got := r.Header.Get("Authorization"); got != want {
+			t.Errorf("unexpected Authorization %q", got)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(ClaimNameResponse{Name: "ok"})
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.hmac = &HMACCredentials{KeyID: "gw-1", Secret: "s3cret"}
+
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+}
+
+func TestUnixSocketEndpoint(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "sanmar.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(SlugResponse{Slug: "st"})
+	})
+	srv := httptest.NewUnstartedServer(mux)
+	srv.Listener = listener
+	srv.Start()
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), "unix://"+socketPath, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	slug, err := client.LookupSlug(context.Background(), "storage_account")
+	if err != nil {
+		t.Fatalf("LookupSlug: %v", err)
+	}
+	if slug == nil || slug.Slug != "st" {
+		t.Fatalf("unexpected slug %#v", slug)
+	}
+}
+
+func TestHostOverride(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Host, "naming.example.test:") {
+			t.Errorf("unexpected Host %q", r.Host)
+		}
+		json.NewEncoder(w).Encode(SlugResponse{Slug: "st"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, port, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	client, err := NewAPIClient(context.Background(), "http://naming.example.test:"+port, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	if err := client.SetHostOverride("127.0.0.1"); err != nil {
+		t.Fatalf("SetHostOverride: %v", err)
+	}
+
+	if _, err := client.LookupSlug(context.Background(), "storage_account"); err != nil {
+		t.Fatalf("LookupSlug: %v", err)
+	}
+
+	cases := map[[2]string]string{
+		{"naming.example.test:443", "10.0.0.4"}:      "10.0.0.4:443",
+		{"naming.example.test:443", "10.0.0.4:8443"}: "10.0.0.4:8443",
+		{"naming.example.test:443", "fd00::4"}:       "[fd00::4]:443",
+	}
+	for in, want := range cases {
+		if got := overrideDialAddress(in[0], in[1]); got != want {
+			t.Fatalf("overrideDialAddress(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
+
+func TestWriteRetryPolicy(t *testing.T) {
+	claims, audits := 0, 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		claims++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		audits++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetWriteRetry(RetryConfig{MaxAttempts: 1})
+
+	if _, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"}); err == nil {
+		t.Fatalf("expected claim error")
+	}
+	if _, err := client.GetAudit(context.Background(), "wus2", "prd", "vm"); err == nil {
+		t.Fatalf("expected audit error")
+	}
+	if claims != 1 || audits != 3 {
+		t.Fatalf("expected 1 claim attempt and 3 audit attempts, got %d and %d", claims, audits)
+	}
+}
+
+func TestClientsShareConnectionPerEndpoint(t *testing.T) {
+	ctx := context.Background()
+	retry := RetryConfig{MaxAttempts: 1}
+
+	a, err := NewAPIClient(ctx, "https://naming.shared.test/", "api://naming/.default", retry)
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	b, err := NewAPIClient(ctx, "https://naming.shared.test", "api://naming/.default", retry)
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	other, err := NewAPIClient(ctx, "https://naming.shared.test", "api://other/.default", retry)
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	if a.http != b.http || a.cred != b.cred {
+		t.Fatalf("expected aliases for one endpoint and scope to share a connection")
+	}
+	if a.http == other.http {
+		t.Fatalf("expected a different scope to get its own connection")
+	}
+
+	a.SetClaimRateLimit(5)
+	if rate := b.SetClaimRateLimit(10); rate != 5 || a.scheduler != b.scheduler {
+		t.Fatalf("expected aliases to share one claim rate limiter at the first rate, got %g", rate)
+	}
+
+	if err := b.SetHostOverride("10.0.0.4"); err != nil {
+		t.Fatalf("SetHostOverride: %v", err)
+	}
+	if a.http == b.http {
+		t.Fatalf("expected host_override to leave the shared connection untouched")
+	}
+}
+
+func TestRenewClaim(t *testing.T) {
+	var got RenewRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/claim/renew" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	err = client.RenewClaim(context.Background(), RenewRequest{Name: "app-wus2-dev", Region: "wus2", Environment: "dev", ExpiresAt: "2024-06-01T00:00:00Z"})
+	if err != nil {
+		t.Fatalf("RenewClaim: %v", err)
+	}
+	if got.Name != "app-wus2-dev" || got.ExpiresAt != "2024-06-01T00:00:00Z" {
+		t.Fatalf("unexpected renew payload: %+v", got)
+	}
+}
+
+func TestKnownProjectCachesRegistry(t *testing.T) {
+	lists, lookups := 0, 0
+	registered := map[string]bool{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		lists++
+		json.NewEncoder(w).Encode(map[string]any{"projects": []Project{{Code: "Atlas", Owner: "platform"}}})
+	})
+	mux.HandleFunc("/api/projects/", func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		code := strings.TrimPrefix(r.URL.Path, "/api/projects/")
+		if !registered[code] {
+			http.Error(w, "Project not found.", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(Project{Code: code, Owner: "platform"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	for code, want := range map[string]bool{"atlas": true, "ATLAS": true, "orion": false} {
+		known, err := client.knownProject(ctx, code)
+		if err != nil {
+			t.Fatalf("knownProject(%q): %v", code, err)
+		}
+		if known != want {
+			t.Fatalf("knownProject(%q) = %v, want %v", code, known, want)
+		}
+	}
+
+	// A project registered after the listing is found by looking it up.
+	registered["orion"] = true
+	if known, err := client.knownProject(ctx, "orion"); err != nil || !known {
+		t.Fatalf("expected the newly registered project to be known, got %v, %v", known, err)
+	}
+	if known, _ := client.knownProject(ctx, "orion"); !known || lookups != 2 {
+		t.Fatalf("expected the lookup to be cached, got %v after %d lookups", known, lookups)
+	}
+	if lists != 1 {
+		t.Fatalf("expected the registry to be listed once, got %d", lists)
+	}
+}
+
+func TestKnownProjectWithoutRegistry(t *testing.T) {
+	cases := map[string]http.HandlerFunc{
+		"no routes": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		},
+		"empty registry": func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(map[string]any{"projects": []Project{}})
+		},
+	}
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+			if err != nil {
+				t.Fatalf("NewAPIClient: %v", err)
+			}
+			known, err := client.knownProject(context.Background(), "anything")
+			if err != nil || !known {
+				t.Fatalf("expected every project to be known, got %v, %v", known, err)
+			}
+		})
+	}
+}
+
+func TestKnownProjectReportsRegistryErrors(t *testing.T) {
+	// A handler's own 404 is not a missing registry.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Table not found.", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	if _, err := client.knownProject(context.Background(), "atlas"); err == nil {
+		t.Fatal("expected the registry error to be returned")
+	}
+}
+
+func TestListSubsystemsFiltersBySystem(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/subsystems", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("system"); got != "erp" {
+			t.Errorf("expected system=erp, got %q", got)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"subsystems": []CatalogEntry{
+			{Code: "billing", System: "erp", Description: "Invoicing", Owner: "finance"},
+		}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	subsystems, err := client.ListSubsystems(context.Background(), "erp")
+	if err != nil {
+		t.Fatalf("ListSubsystems: %v", err)
+	}
+	if len(subsystems) != 1 || subsystems[0].Code != "billing" || subsystems[0].System != "erp" {
+		t.Fatalf("unexpected subsystems: %#v", subsystems)
+	}
+}
+
+func TestGetNamingRule(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/rules/", func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/api/rules/") {
+		case "key_vault":
+			_, _ = w.Write([]byte(`{"resourceType":"key_vault","maxLength":24,"validCharacters":"a-z0-9-","caseRule":"lower","uniquenessScope":"global"}`))
+		case "default":
+			_, _ = w.Write([]byte(`{"resourceType":"default","maxLength":80,"validCharacters":"a-z0-9-","caseRule":"lower","uniquenessScope":null}`))
+		default:
+			http.Error(w, "Unknown resource type 'virtual_machine'.", http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	rule, err := client.GetNamingRule(context.Background(), "key_vault")
+	if err != nil || rule == nil || rule.MaxLength != 24 || rule.UniquenessScope != "global" {
+		t.Fatalf("unexpected key_vault rule: %+v, %v", rule, err)
+	}
+	// Types without a rule of their own get the default rule.
+	rule, err = client.GetNamingRule(context.Background(), "virtual_machine")
+	if err != nil || rule == nil || rule.ResourceType != "default" || rule.MaxLength != 80 || rule.UniquenessScope != "" {
+		t.Fatalf("expected the default rule, got %+v, %v", rule, err)
+	}
+}
+
+func TestRuleConstraints(t *testing.T) {
+	maxLength, caseRule := ruleConstraints("key_vault", &NamingRule{MaxLength: 24, CaseRule: "lower"})
+	if maxLength.ValueInt64() != 24 || caseRule.ValueString() != "lower" {
+		t.Fatalf("unexpected constraints: %v %v", maxLength, caseRule)
+	}
+
+	// Without the service's rules, built-in rules fill in for known types.
+	maxLength, caseRule = ruleConstraints("storage_account", nil)
+	if maxLength.ValueInt64() != 24 || caseRule.ValueString() != "lower" {
+		t.Fatalf("expected built-in storage_account rules, got %v %v", maxLength, caseRule)
+	}
+	maxLength, caseRule = ruleConstraints("custom_widget", nil)
+	if !maxLength.IsNull() || !caseRule.IsNull() {
+		t.Fatalf("expected null constraints for unknown types, got %v %v", maxLength, caseRule)
+	}
+}
+
+func TestLookupSlugValue(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slug") != "st" || r.URL.Query().Has("resource_type") {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(SlugResponse{ResourceType: "storage_account", Slug: "st"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	slug, err := client.LookupSlugValue(context.Background(), "st")
+	if err != nil {
+		t.Fatalf("LookupSlugValue: %v", err)
+	}
+	if slug == nil || slug.ResourceType != "storage_account" {
+		t.Fatalf("unexpected slug: %#v", slug)
+	}
+
+	missing, err := client.LookupSlugValue(context.Background(), "zz")
+	if err != nil || missing != nil {
+		t.Fatalf("expected no mapping for unknown slug, got %#v, %v", missing, err)
+	}
+}
+
+func TestSyncSlugs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug_sync", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"message": "Slugs synchronised: 212 updated."})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	message, err := client.SyncSlugs(context.Background())
+	if err != nil {
+		t.Fatalf("SyncSlugs: %v", err)
+	}
+	if message != "Slugs synchronised: 212 updated." {
+		t.Fatalf("unexpected message: %q", message)
+	}
+}
+
+func TestDecodeResponseReportsSchemaDrift(t *testing.T) {
+	body := `{"name":"kvwus2prdatlas","resourceType":"key_vault","Region":"wus2","lease":"P30D","owners":["a"]}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	ctx := context.Background()
+	payload := ClaimNameRequest{ResourceType: "key_vault", Region: "wus2", Environment: "prd"}
+
+	if _, err := client.ClaimName(ctx, payload); err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	var diags diag.Diagnostics
+	client.reportDeprecations(&diags)
+	if diags.WarningsCount() != 0 {
+		t.Fatalf("expected no warnings without strict decoding, got %v", diags)
+	}
+
+	client.strictDecoding = true
+	for i := 0; i < 2; i++ {
+		if _, err := client.ClaimName(ctx, payload); err != nil {
+			t.Fatalf("ClaimName: %v", err)
+		}
+	}
+	client.reportDeprecations(&diags)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "lease, owners") {
+		t.Fatalf("expected one warning naming the unknown fields, got %v", diags)
+	}
+
+	body = `{"resourceType":"key_vault","name":null}`
+	if _, err := client.ClaimName(ctx, payload); err == nil || !strings.Contains(err.Error(), `required field "name"`) {
+		t.Fatalf("expected missing name error, got %v", err)
+	}
+}
+
+func TestClaimNameReturnsEffectiveSegments(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"name":"vmwus2prdatlas01","project":"atlas","purpose":"","system":"core","subsystem":"","index":"01"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	claim, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "vm", Region: "wus2", Environment: "prd"})
+	if err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+
+	var model claimResourceModel
+	model.setEffectiveSegments(claim.Project, claim.Purpose, claim.System, claim.Subsystem, claim.Index)
+	if model.EffectiveProject.ValueString() != "atlas" || model.EffectiveSystem.ValueString() != "core" || model.EffectiveIndex.ValueInt64() != 1 {
+		t.Fatalf("unexpected effective segments: %+v", model)
+	}
+	if !model.EffectivePurpose.IsNull() || !model.EffectiveSubsystem.IsNull() {
+		t.Fatalf("expected segments the service left empty to be null, got %q and %q", model.EffectivePurpose, model.EffectiveSubsystem)
+	}
+}
+
+func TestUserAgentTelemetry(t *testing.T) {
+	var agent string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug_sync", func(w http.ResponseWriter, r *http.Request) {
+		agent = r.UserAgent()
+		io.WriteString(w, `{"message":"ok"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	const partner = "3F2504E0-4F89-11D3-9A0C-0305E82C3301"
+	platform := fmt.Sprintf("(%s; %s)", runtime.GOOS, runtime.GOARCH)
+	cases := []struct {
+		partnerID string
+		disabled  bool
+		appendix  string
+		want      string
+	}{
+		{"", false, "", "terraform-provider-sanmar/1.4.0 " + platform + " Terraform/1.9.5"},
+		{partner, false, "team-payments", "terraform-provider-sanmar/1.4.0 " + platform + " Terraform/1.9.5 pid-3f2504e0-4f89-11d3-9a0c-0305e82c3301 team-payments"},
+		{"", true, "", "terraform-provider-sanmar"},
+		{partner, true, "team-payments", "terraform-provider-sanmar pid-3f2504e0-4f89-11d3-9a0c-0305e82c3301 team-payments"},
+	}
+	for _, tc := range cases {
+		if err := client.SetTelemetry("1.4.0", tc.partnerID, tc.disabled); err != nil {
+			t.Fatalf("SetTelemetry: %v", err)
+		}
+		client.telemetry.terraformVersion = "1.9.5"
+		client.telemetry.appendix = tc.appendix
+		if _, err := client.SyncSlugs(context.Background()); err != nil {
+			t.Fatalf("SyncSlugs: %v", err)
+		}
+		if agent != tc.want {
+			t.Errorf("User-Agent = %q, want %q", agent, tc.want)
+		}
+	}
+
+	if err := client.SetTelemetry("1.4.0", "acme", false); err == nil {
+		t.Fatal("expected an error for a partner ID that is not a GUID")
+	}
+}
+
+func TestRunHeaders(t *testing.T) {
+	t.Setenv("TF_WORKSPACE", "")
+	t.Setenv("TFC_WORKSPACE_NAME", "payments-prd")
+	t.Setenv("TFC_RUN_ID", "run-abc123")
+
+	var header http.Header
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug_sync", func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		io.WriteString(w, `{"message":"ok"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.telemetry = telemetry{terraformVersion: "1.9.5", workspace: currentWorkspace(), execution: currentExecution()}
+
+	if _, err := client.SyncSlugs(context.Background()); err != nil {
+		t.Fatalf("SyncSlugs: %v", err)
+	}
+	want := map[string]string{
+		"X-Sanmar-Terraform-Version": "1.9.5",
+		"X-Sanmar-Workspace":         "payments-prd",
+		"X-Sanmar-Execution":         executionTFC,
+	}
+	for name, value := range want {
+		if got := header.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	client.telemetry.disabled = true
+	if _, err := client.SyncSlugs(context.Background()); err != nil {
+		t.Fatalf("SyncSlugs: %v", err)
+	}
+	for name := range want {
+		if got := header.Get(name); got != "" {
+			t.Errorf("expected %s to be suppressed with telemetry disabled, got %q", name, got)
+		}
+	}
+}
+
+func TestClaimNameWaitsForOperation(t *testing.T) {
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Prefer") != "respond-async" {
+			t.Errorf("expected the claim to ask for an operation, got Prefer %q", r.Header.Get("Prefer"))
+		}
+		w.Header().Set("Operation-Location", "/api/operations/op-1")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/api/operations/op-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("unexpected method %s", r.Method)
+		}
+		polls++
+		if polls < 3 {
+			fmt.Fprintf(w, `{"status":"running","percent_complete":%d}`, polls*40)
+			return
+		}
+		fmt.Fprint(w, `{"status":"succeeded","result":{"name":"sanmar-app","resourceType":"app"}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetOperationPollInterval(time.Millisecond)
+	client.asyncClaims = true
+
+	claim, err := client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "app", Region: "wus2", Environment: "dev"})
+	if err != nil {
+		t.Fatalf("ClaimName: %v", err)
+	}
+	if claim.Name != "sanmar-app" || polls != 3 {
+		t.Fatalf("expected the operation result after 3 polls, got %#v after %d", claim, polls)
+	}
+}
+
+func TestClaimOperationFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "http://"+r.Host+"/api/operations/op-2")
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/api/operations/op-2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"failed","error":{"status":409,"message":"name is taken in Azure","errors":[{"field":"name","message":"taken"}]}}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetOperationPollInterval(time.Millisecond)
+
+	_, err = client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "app", Region: "wus2", Environment: "dev"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || len(apiErr.Fields) != 1 {
+		t.Fatalf("expected the operation error, got %v", err)
+	}
+}
+
+func TestOperationPathStaysOnEndpoint(t *testing.T) {
+	client, err := NewAPIClient(context.Background(), "https://naming.example.com/svc", "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	cases := map[string]string{
+		"https://naming.example.com/svc/api/operations/1": "/api/operations/1",
+		"/svc/api/operations/2?wait=1":                    "/api/operations/2?wait=1",
+		"https://attacker.example.com/api/operations/3":   "",
+	}
+	for location, want := range cases {
+		resp := &http.Response{Header: http.Header{"Operation-Location": []string{location}}}
+		got, err := client.operationPath(resp)
+		if want == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", location, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("%s: got %q, %v; want %q", location, got, err, want)
+		}
+	}
+}
+
+func TestAttemptTimeoutRetriesSlowAttempts(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// A cold start: the first attempt hangs past the attempt timeout.
+			time.Sleep(200 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"name": "sanmar-app"})
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetAttemptTimeout(50 * time.Millisecond)
+
+	audit, err := client.GetAudit(context.Background(), "wus2", "dev", "sanmar-app")
+	if err != nil {
+		t.Fatalf("GetAudit: %v", err)
+	}
+	if audit.Name != "sanmar-app" || atomic.LoadInt32(&calls) != 2 {
+		t.Fatalf("expected the second attempt to succeed, got %#v after %d calls", audit, calls)
+	}
+}
+
+func TestAttemptTimeoutDoesNotRetryClaims(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		// The service is still making the claim when the attempt times out.
+		time.Sleep(200 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]string{"name": "sanmar-app"})
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetAttemptTimeout(50 * time.Millisecond)
+
+	_, err = client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "app", Region: "wus2", Environment: "dev"})
+	if err == nil || requestNeverSent(err) {
+		t.Fatalf("expected the timed-out claim to fail as sent, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("expected the claim to be sent once, got %d attempts", n)
+	}
+}
+
+func TestOperationTimeoutStopsRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 10, MinBackoff: 40 * time.Millisecond, MaxBackoff: 40 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetOperationTimeout(100 * time.Millisecond)
+
+	_, err = client.GetAudit(context.Background(), "wus2", "dev", "sanmar-app")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the last 503, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n < 2 || n > 3 {
+		t.Fatalf("expected retries to stop at the deadline, got %d attempts", n)
+	}
+}
+
+func TestCheckNameUsesHead(t *testing.T) {
+	var gets int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if r.Method == http.MethodGet {
+			atomic.AddInt32(&gets, 1)
+			json.NewEncoder(w).Encode(map[string]any{"name": name, "in_use": true})
+			return
+		}
+		switch name {
+		case "claimed":
+			w.Header().Set("X-Sanmar-In-Use", "true")
+		case "released":
+			w.Header().Set("X-Sanmar-In-Use", "false")
+		case "retired":
+			w.WriteHeader(http.StatusGone)
+		case "legacy":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case "get-only":
+			// Azure Functions answers HEAD on a GET-only route with 404.
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Header().Set("X-Sanmar-In-Use", "false")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	want := map[string]NameState{
+		"claimed":  {Exists: true, InUse: true},
+		"released": {Exists: true},
+		"retired":  {Exists: true, Retired: true},
+		"legacy":   {Exists: true, InUse: true},
+		"get-only": {Exists: true, InUse: true},
+		"free":     {},
+	}
+	for name, expected := range want {
+		got, err := client.CheckName(context.Background(), "wus2", "dev", name)
+		if err != nil {
+			t.Fatalf("CheckName(%s): %v", name, err)
+		}
+		if got != expected {
+			t.Errorf("CheckName(%s) = %+v, want %+v", name, got, expected)
+		}
+	}
+	if n := atomic.LoadInt32(&gets); n != 2 {
+		t.Fatalf("expected only the services without HEAD support to get a full audit read, got %d", n)
+	}
+}
+
+func TestWithSessionPadsIndex(t *testing.T) {
+	client := &APIClient{}
+	index := "7"
+	if got := client.withSession(ClaimNameRequest{Index: &index}); *got.Index != "07" {
+		t.Fatalf("expected the index padded to two digits by default, got %q", *got.Index)
+	}
+	client.indexWidth = 3
+	if got := client.withSession(ClaimNameRequest{Index: &index}); *got.Index != "007" {
+		t.Fatalf("expected the index padded to index_width, got %q", *got.Index)
+	}
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// unixSocketPrefix marks endpoints served over a Unix domain socket, such as
+// the local emulator or a sidecar proxy in a dev container.
+const unixSocketPrefix = "unix://"
+
+// unixSocketHost is the placeholder host used in request URLs for socket endpoints.
+const unixSocketHost = "http://localhost"
+
+// unixSocketTransport dials socketPath for every request regardless of the URL host.
+func unixSocketTransport(socketPath string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socketPath)
+	}
+	return transport
+}
+
+// splitUnixEndpoint returns the socket path of a unix:// endpoint.
+func splitUnixEndpoint(endpoint string) (string, bool) {
+	if !strings.HasPrefix(endpoint, unixSocketPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(endpoint, unixSocketPrefix), true
+}
+
+// hostOverrideTransport dials address instead of resolving the URL host, so a
+// private endpoint IP can be reached while TLS SNI and the Host header still
+// carry the service hostname.
+func hostOverrideTransport(address string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	dial := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, network, overrideDialAddress(addr, address))
+	}
+	return transport
+}
+
+// overrideDialAddress swaps the host of addr for override, keeping the port
+// unless override specifies its own.
+func overrideDialAddress(addr, override string) string {
+	if _, _, err := net.SplitHostPort(override); err == nil {
+		return override
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return override
+	}
+	return net.JoinHostPort(override, port)
+}
+
+// SetHostOverride routes every connection to address (an IP or host, with an
+// optional port) while requests keep the configured endpoint hostname.
+func (c *APIClient) SetHostOverride(address string) error {
+	if c.endpoint == unixSocketHost {
+		return errors.New("host_override cannot be combined with a unix:// endpoint")
+	}
+	// Other clients share the default connection, so give this one its own.
+	c.http = &http.Client{
+		Timeout:   c.http.Timeout,
+		Transport: hostOverrideTransport(address),
+	}
+	return nil
+}
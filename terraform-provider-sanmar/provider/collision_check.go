@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// checkCollision warns when the name a new claim would take is already held
+// by a claim from another workspace or owner, so the conflict shows at plan
+// time with its holder instead of failing the apply. It only runs with
+// collision_check set, since it costs a preview and a lookup per new claim.
+func (r *ClaimResource) checkCollision(ctx context.Context, state tfsdk.State, plan claimResourceModel, retired bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.client == nil || !r.client.collisionCheck || plan.DryRun.ValueBool() {
+		return diags
+	}
+	if !state.Raw.IsNull() && !retired && !plan.Name.IsUnknown() {
+		return diags
+	}
+
+	name := r.previewName(ctx, plan)
+	if name == "" {
+		return diags
+	}
+	region, environment := plan.Region.ValueString(), plan.Environment.ValueString()
+	nameState, err := r.client.CheckName(ctx, region, environment, name)
+	if err != nil {
+		tflog.Warn(ctx, "failed to check planned name for collisions", map[string]any{
+			"name":  name,
+			"error": err.Error(),
+		})
+		return diags
+	}
+
+	switch {
+	case nameState.Retired:
+		diags.AddAttributeWarning(path.Root("name"), "Planned name retired",
+			fmt.Sprintf("%q, the name this claim would take, has been retired by the naming service, so the claim will fail at apply. Change a segment such as index.", name))
+	case nameState.InUse:
+		record, err := r.client.GetAudit(ctx, region, environment, name)
+		if err != nil || record == nil {
+			diags.AddAttributeWarning(path.Root("name"), "Planned name already claimed",
+				fmt.Sprintf("%q, the name this claim would take, is already claimed, so the claim will fail at apply unless that claim is released first.", name))
+			return diags
+		}
+
+		workspace := record.Metadata[CleanupMetadataKey]
+		if TaggedWith(record, r.client.cleanupWorkspace) {
+			// Held by this configuration, for example a claim being moved
+			// between resources.
+			return diags
+		}
+		holder := "a workspace that is not recorded on the claim"
+		if workspace != "" {
+			holder = fmt.Sprintf("workspace %q", workspace)
+		}
+		owner := record.ClaimedBy
+		if owner == "" {
+			owner = "an unknown user"
+		}
+		detail := fmt.Sprintf("%q, the name this claim would take, was claimed by %s from %s", name, owner, holder)
+		if record.ClaimedAt != "" {
+			detail += " at " + record.ClaimedAt
+		}
+		diags.AddAttributeWarning(path.Root("name"), "Planned name already claimed",
+			detail+", so the claim will fail at apply unless that claim is released first.")
+	}
+	return diags
+}
@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestCheckCollision(t *testing.T) {
+	mux := http.NewServeMux()
+	// Without /api/preview the planned name is rendered from the slug.
+	var checked string
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"resourceType": "storage_account", "slug": "st"})
+	})
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		checked = r.URL.Query().Get("name")
+		if r.Method == http.MethodHead {
+			w.Header().Set(inUseHeader, "true")
+			return
+		}
+		// The service stores the workspace lowercased in the nested metadata.
+		_ = json.NewEncoder(w).Encode(auditResponse(checked, true, map[string]string{CleanupMetadataKey: "payments-prd"}))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	newClaim := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	plan := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		System:       types.StringValue("atlas"),
+		Index:        indexNumber("01"),
+		Name:         types.StringUnknown(),
+	}
+
+	if diags := r.checkCollision(ctx, newClaim, plan, false); len(diags) != 0 {
+		t.Fatalf("expected no check without collision_check, got %v", diags)
+	}
+
+	client.collisionCheck = true
+	diags := r.checkCollision(ctx, newClaim, plan, false)
+	if len(diags) != 1 || diags.HasError() {
+		t.Fatalf("expected one warning, got %v", diags)
+	}
+	detail := diags[0].Detail()
+	if checked != "wus2prdstsanmaratlas01" {
+		t.Fatalf("expected the rendered name to be checked, got %q", checked)
+	}
+	if !strings.Contains(detail, `workspace "payments-prd"`) || !strings.Contains(detail, "ci@sanmar.com") {
+		t.Fatalf("expected the holder in the warning, got %q", detail)
+	}
+
+	// Names held by this configuration's own workspace are not collisions.
+	if err := client.SetCleanupWorkspace("Payments-PRD"); err != nil {
+		t.Fatalf("SetCleanupWorkspace: %v", err)
+	}
+	if diags := r.checkCollision(ctx, newClaim, plan, false); len(diags) != 0 {
+		t.Fatalf("expected no warning for the same workspace, got %v", diags)
+	}
+}
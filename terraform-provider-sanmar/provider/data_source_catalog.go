@@ -0,0 +1,276 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*SystemsDataSource)(nil)
+var _ datasource.DataSource = (*SubsystemsDataSource)(nil)
+
+// NewSystemsDataSource returns the system catalog data source.
+func NewSystemsDataSource() datasource.DataSource {
+	return &SystemsDataSource{}
+}
+
+// NewSubsystemsDataSource returns the subsystem catalog data source.
+func NewSubsystemsDataSource() datasource.DataSource {
+	return &SubsystemsDataSource{}
+}
+
+// SystemsDataSource lists the registered system segment values.
+type SystemsDataSource struct {
+	client *APIClient
+}
+
+// SubsystemsDataSource lists the registered subsystem segment values.
+type SubsystemsDataSource struct {
+	client *APIClient
+}
+
+type systemsDataSourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Codes   types.List   `tfsdk:"codes"`
+	Systems types.List   `tfsdk:"systems"`
+}
+
+type subsystemsDataSourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	System     types.String `tfsdk:"system"`
+	Codes      types.List   `tfsdk:"codes"`
+	Subsystems types.List   `tfsdk:"subsystems"`
+}
+
+type catalogEntryModel struct {
+	Code        types.String `tfsdk:"code"`
+	Description types.String `tfsdk:"description"`
+	Owner       types.String `tfsdk:"owner"`
+}
+
+type subsystemEntryModel struct {
+	Code        types.String `tfsdk:"code"`
+	System      types.String `tfsdk:"system"`
+	Description types.String `tfsdk:"description"`
+	Owner       types.String `tfsdk:"owner"`
+}
+
+var catalogEntryAttrTypes = map[string]attr.Type{
+	"code":        types.StringType,
+	"description": types.StringType,
+	"owner":       types.StringType,
+}
+
+var subsystemEntryAttrTypes = map[string]attr.Type{
+	"code":        types.StringType,
+	"system":      types.StringType,
+	"description": types.StringType,
+	"owner":       types.StringType,
+}
+
+// catalogEntryAttributes returns the nested attributes shared by system and
+// subsystem entries.
+func catalogEntryAttributes(kind string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"code": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: fmt.Sprintf("Value to use as the `%s` segment.", kind),
+		},
+		"description": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: fmt.Sprintf("Description of the %s.", kind),
+		},
+		"owner": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: fmt.Sprintf("Team or person that owns the %s.", kind),
+		},
+	}
+}
+
+func catalogCodes(ctx context.Context, entries []CatalogEntry) (types.List, diag.Diagnostics) {
+	codes := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		codes = append(codes, entry.Code)
+	}
+	return types.ListValueFrom(ctx, types.StringType, codes)
+}
+
+func configureCatalogClient(providerData any, diags *diag.Diagnostics) *APIClient {
+	if providerData == nil {
+		return nil
+	}
+	client, ok := providerData.(*APIClient)
+	if !ok {
+		diags.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", providerData))
+		return nil
+	}
+	return client
+}
+
+func (d *SystemsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_systems"
+}
+
+func (d *SystemsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the systems registered with the SanMar naming service, for validating the `system` segment in module variables.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state.",
+			},
+			"codes": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Registered system codes, for use with `contains()` in variable validation.",
+			},
+			"systems": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Registered systems.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: catalogEntryAttributes("system"),
+				},
+			},
+		},
+	}
+}
+
+func (d *SystemsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if client := configureCatalogClient(req.ProviderData, &resp.Diagnostics); client != nil {
+		d.client = client
+	}
+}
+
+func (d *SystemsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data systemsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	systems, err := d.client.ListSystems(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list systems", err.Error())
+		return
+	}
+
+	models := make([]catalogEntryModel, 0, len(systems))
+	for _, s := range systems {
+		models = append(models, catalogEntryModel{
+			Code:        types.StringValue(s.Code),
+			Description: optionalString(s.Description),
+			Owner:       optionalString(s.Owner),
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: catalogEntryAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	codes, diags := catalogCodes(ctx, systems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue("systems")
+	data.Codes = codes
+	data.Systems = list
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (d *SubsystemsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subsystems"
+}
+
+func (d *SubsystemsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	entry := catalogEntryAttributes("subsystem")
+	entry["system"] = schema.StringAttribute{
+		Computed:            true,
+		MarkdownDescription: "System the subsystem belongs to.",
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the subsystems registered with the SanMar naming service, for validating the `subsystem` segment in module variables.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as subsystems:<system>.",
+			},
+			"system": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list subsystems of this system.",
+			},
+			"codes": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Registered subsystem codes, for use with `contains()` in variable validation.",
+			},
+			"subsystems": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Registered subsystems.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: entry,
+				},
+			},
+		},
+	}
+}
+
+func (d *SubsystemsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if client := configureCatalogClient(req.ProviderData, &resp.Diagnostics); client != nil {
+		d.client = client
+	}
+}
+
+func (d *SubsystemsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data subsystemsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	subsystems, err := d.client.ListSubsystems(ctx, data.System.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list subsystems", err.Error())
+		return
+	}
+
+	models := make([]subsystemEntryModel, 0, len(subsystems))
+	for _, s := range subsystems {
+		models = append(models, subsystemEntryModel{
+			Code:        types.StringValue(s.Code),
+			System:      optionalString(s.System),
+			Description: optionalString(s.Description),
+			Owner:       optionalString(s.Owner),
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: subsystemEntryAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	codes, diags := catalogCodes(ctx, subsystems)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue("subsystems:" + data.System.ValueString())
+	data.Codes = codes
+	data.Subsystems = list
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,235 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*ClaimsDataSource)(nil)
+
+// NewClaimsDataSource returns the claims listing data source.
+func NewClaimsDataSource() datasource.DataSource {
+	return &ClaimsDataSource{}
+}
+
+// ClaimsDataSource lists the names currently claimed within a scope.
+type ClaimsDataSource struct {
+	client *APIClient
+}
+
+type claimsDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Project     types.String `tfsdk:"project"`
+	Environment types.String `tfsdk:"environment"`
+	Region      types.String `tfsdk:"region"`
+	Purpose     types.String `tfsdk:"purpose"`
+	User        types.String `tfsdk:"user"`
+	Claims      types.List   `tfsdk:"claims"`
+}
+
+// claimSummaryModel mirrors ClaimSummary, which is also the row shape of
+// `sanmarctl export --format=json|csv`.
+type claimSummaryModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	ResourceType types.String `tfsdk:"resource_type"`
+	Region       types.String `tfsdk:"region"`
+	Environment  types.String `tfsdk:"environment"`
+	Project      types.String `tfsdk:"project"`
+	Purpose      types.String `tfsdk:"purpose"`
+	Slug         types.String `tfsdk:"slug"`
+	System       types.String `tfsdk:"system"`
+	Subsystem    types.String `tfsdk:"subsystem"`
+	Index        types.String `tfsdk:"index"`
+	ClaimedBy    types.String `tfsdk:"claimed_by"`
+	ClaimedAt    types.String `tfsdk:"claimed_at"`
+}
+
+var claimSummaryAttrTypes = map[string]attr.Type{
+	"id":            types.StringType,
+	"name":          types.StringType,
+	"resource_type": types.StringType,
+	"region":        types.StringType,
+	"environment":   types.StringType,
+	"project":       types.StringType,
+	"purpose":       types.StringType,
+	"slug":          types.StringType,
+	"system":        types.StringType,
+	"subsystem":     types.StringType,
+	"index":         types.StringType,
+	"claimed_by":    types.StringType,
+	"claimed_at":    types.StringType,
+}
+
+func (d *ClaimsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claims"
+}
+
+func (d *ClaimsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the names currently claimed in the SanMar naming service, optionally filtered by scope.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as claims:<project>:<environment>:<region>:<purpose>:<user>.",
+			},
+			"project": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this project.",
+			},
+			"environment": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this environment.",
+			},
+			"region": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this region.",
+			},
+			"purpose": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this purpose.",
+			},
+			"user": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims made by this user. Listing other users' claims requires an elevated role.",
+			},
+			"claims": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Active claims, most recently claimed first.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Import identifier formatted as <region>/<environment>/<name>.",
+						},
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Claimed name.",
+						},
+						"resource_type": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Resource type the name was claimed for.",
+						},
+						"region": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Region segment.",
+						},
+						"environment": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Environment segment.",
+						},
+						"project": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Project segment.",
+						},
+						"purpose": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Purpose segment.",
+						},
+						"slug": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Slug of the resource type when the name was claimed.",
+						},
+						"system": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "System segment.",
+						},
+						"subsystem": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Subsystem segment.",
+						},
+						"index": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Index segment, as it appears in the name.",
+						},
+						"claimed_by": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Current owner of the claim: the user that claimed the name, or the user it was last transferred to.",
+						},
+						"claimed_at": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "ISO 8601 timestamp of the claim.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ClaimsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *ClaimsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data claimsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := ClaimFilter{
+		Project:     data.Project.ValueString(),
+		Environment: data.Environment.ValueString(),
+		Region:      data.Region.ValueString(),
+		Purpose:     data.Purpose.ValueString(),
+		User:        data.User.ValueString(),
+	}
+
+	claims, err := d.client.ListClaims(ctx, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list claims", err.Error())
+		return
+	}
+
+	models := make([]claimSummaryModel, 0, len(claims))
+	for _, c := range claims {
+		models = append(models, claimSummaryModel{
+			ID:           types.StringValue(c.Identity()),
+			Name:         types.StringValue(c.Name),
+			ResourceType: types.StringValue(c.ResourceType),
+			Region:       types.StringValue(c.Region),
+			Environment:  types.StringValue(c.Environment),
+			Project:      optionalString(c.Project),
+			Purpose:      optionalString(c.Purpose),
+			Slug:         optionalString(c.Slug),
+			System:       optionalString(c.System),
+			Subsystem:    optionalString(c.Subsystem),
+			Index:        optionalString(c.Index),
+			ClaimedBy:    types.StringValue(c.ClaimedBy),
+			ClaimedAt:    types.StringValue(c.ClaimedAt),
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: claimSummaryAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"claims", filter.Project, filter.Environment, filter.Region, filter.Purpose, filter.User}, ":"))
+	data.Claims = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
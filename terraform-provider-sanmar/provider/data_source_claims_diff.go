@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*ClaimsDiffDataSource)(nil)
+
+// NewClaimsDiffDataSource returns the claims reconciliation data source.
+func NewClaimsDiffDataSource() datasource.DataSource {
+	return &ClaimsDiffDataSource{}
+}
+
+// ClaimsDiffDataSource compares the claims registered within a scope with a
+// desired list, for reconciliation dashboards and drift reports.
+type ClaimsDiffDataSource struct {
+	client *APIClient
+}
+
+type claimsDiffDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Project     types.String `tfsdk:"project"`
+	Environment types.String `tfsdk:"environment"`
+	Region      types.String `tfsdk:"region"`
+	Purpose     types.String `tfsdk:"purpose"`
+	User        types.String `tfsdk:"user"`
+	Desired     types.Set    `tfsdk:"desired"`
+	Missing     types.Set    `tfsdk:"missing"`
+	Extra       types.Set    `tfsdk:"extra"`
+	Matching    types.Set    `tfsdk:"matching"`
+}
+
+// claimsDiff is the outcome of comparing desired identities with claims.
+type claimsDiff struct {
+	missing  []string
+	extra    []string
+	matching []string
+}
+
+// diffClaims compares desired "<region>/<environment>/<name>" identities
+// with the registered claims. Identities match case-insensitively, like the
+// region and environment segments of sanmar_claim. Missing entries
+// keep the desired spelling; the others use the service's.
+func diffClaims(desired []string, claims []ClaimSummary) claimsDiff {
+	wanted := make(map[string]string, len(desired))
+	for _, id := range desired {
+		wanted[strings.ToLower(id)] = id
+	}
+
+	// Empty rather than nil, so the outputs are empty sets instead of null.
+	diff := claimsDiff{missing: []string{}, extra: []string{}, matching: []string{}}
+	seen := make(map[string]bool, len(claims))
+	for _, c := range claims {
+		id := c.Identity()
+		key := strings.ToLower(id)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, ok := wanted[key]; ok {
+			diff.matching = append(diff.matching, id)
+		} else {
+			diff.extra = append(diff.extra, id)
+		}
+	}
+	for key, id := range wanted {
+		if !seen[key] {
+			diff.missing = append(diff.missing, id)
+		}
+	}
+
+	sort.Strings(diff.missing)
+	sort.Strings(diff.extra)
+	sort.Strings(diff.matching)
+	return diff
+}
+
+func (d *ClaimsDiffDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claims_diff"
+}
+
+func (d *ClaimsDiffDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	identities := func(description string) schema.SetAttribute {
+		return schema.SetAttribute{
+			Computed:            true,
+			ElementType:         types.StringType,
+			MarkdownDescription: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Compares the names claimed within a scope with a desired list, for reconciliation dashboards and naming drift reports.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as claims_diff:<project>:<environment>:<region>:<purpose>:<user>.",
+			},
+			"project": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only compare claims for this project.",
+			},
+			"environment": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only compare claims for this environment.",
+			},
+			"region": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only compare claims for this region.",
+			},
+			"purpose": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only compare claims for this purpose.",
+			},
+			"user": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only compare claims made by this user. Listing other users' claims requires an elevated role.",
+			},
+			"desired": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names that should be claimed in the scope, each as `<region>/<environment>/<name>` (the `id` of `sanmar_claims` entries and the format `sanmarctl export` writes).",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(claimIdentityPattern, "must be <region>/<environment>/<name>")),
+				},
+			},
+			"missing":  identities("Desired names that are not claimed."),
+			"extra":    identities("Claimed names in the scope that are not desired."),
+			"matching": identities("Desired names that are claimed."),
+		},
+	}
+}
+
+func (d *ClaimsDiffDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *ClaimsDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data claimsDiffDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var desired []string
+	resp.Diagnostics.Append(data.Desired.ElementsAs(ctx, &desired, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := ClaimFilter{
+		Project:     data.Project.ValueString(),
+		Environment: data.Environment.ValueString(),
+		Region:      data.Region.ValueString(),
+		Purpose:     data.Purpose.ValueString(),
+		User:        data.User.ValueString(),
+	}
+
+	claims, err := d.client.ListClaims(ctx, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list claims", err.Error())
+		return
+	}
+
+	diff := diffClaims(desired, claims)
+	missing, diags := types.SetValueFrom(ctx, types.StringType, diff.missing)
+	resp.Diagnostics.Append(diags...)
+	extra, diags := types.SetValueFrom(ctx, types.StringType, diff.extra)
+	resp.Diagnostics.Append(diags...)
+	matching, diags := types.SetValueFrom(ctx, types.StringType, diff.matching)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"claims_diff", filter.Project, filter.Environment, filter.Region, filter.Purpose, filter.User}, ":"))
+	data.Missing = missing
+	data.Extra = extra
+	data.Matching = matching
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,33 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffClaims(t *testing.T) {
+	claims := []ClaimSummary{
+		{Name: "stwus2prdatlas01", Region: "wus2", Environment: "prd"},
+		{Name: "kvwus2prdatlas", Region: "wus2", Environment: "prd"},
+		{Name: "vmwus2prdlegacy", Region: "wus2", Environment: "prd"},
+	}
+	desired := []string{
+		"WUS2/prd/stwus2prdatlas01",
+		"wus2/prd/kvwus2prdatlas",
+		"wus2/prd/aks-wus2-prd-atlas",
+	}
+
+	diff := diffClaims(desired, claims)
+	want := claimsDiff{
+		missing:  []string{"wus2/prd/aks-wus2-prd-atlas"},
+		extra:    []string{"wus2/prd/vmwus2prdlegacy"},
+		matching: []string{"wus2/prd/kvwus2prdatlas", "wus2/prd/stwus2prdatlas01"},
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("diffClaims = %+v, want %+v", diff, want)
+	}
+
+	if empty := diffClaims(nil, nil); empty.missing == nil || empty.extra == nil || empty.matching == nil {
+		t.Fatalf("expected empty, non-nil results, got %+v", empty)
+	}
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClaimsDataSourceRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/audit_bulk" || r.URL.Query().Get("environment") != "prd" {
+			http.Error(w, "unexpected call", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[
+			{"name":"wus2prdstsanmarerp02","event_id":"e3","user":"bob","action":"released","note":"","timestamp":"2026-01-03T00:00:00","region":"wus2","environment":"prd","project":null,"purpose":null,"resource_type":"storage_account","slug":"st","system":"erp","subsystem":null,"index":"02"},
+			{"name":"wus2-prd-app-atlas-web-01","event_id":"e2","user":"alice","action":"claimed","note":"","timestamp":"2026-01-02T00:00:00","region":"wus2","environment":"prd","project":"orion","purpose":null,"resource_type":"app_service","slug":"app","system":"atlas","subsystem":"web","index":"01"},
+			{"name":"wus2prdstsanmarerp02","event_id":"e1","user":"bob","action":"claimed","note":"","timestamp":"2026-01-01T00:00:00","region":"wus2","environment":"prd","project":null,"purpose":null,"resource_type":"storage_account","slug":"st","system":"erp","subsystem":null,"index":"02"}
+		]}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	resp := readDataSource(ctx, t, &ClaimsDataSource{client: client}, map[string]string{"environment": "prd"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	var state claimsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	var claims []claimSummaryModel
+	resp.Diagnostics.Append(state.Claims.ElementsAs(ctx, &claims, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("state: %v", resp.Diagnostics)
+	}
+
+	if state.ID.ValueString() != "claims::prd:::" || len(claims) != 1 {
+		t.Fatalf("expected only the active claim, got %q and %#v", state.ID, claims)
+	}
+	c := claims[0]
+	if c.ID.ValueString() != "wus2/prd/wus2-prd-app-atlas-web-01" || c.ClaimedBy.ValueString() != "alice" || c.Project.ValueString() != "orion" {
+		t.Fatalf("unexpected claim: %#v", c)
+	}
+	if c.Slug.ValueString() != "app" || c.System.ValueString() != "atlas" || c.Subsystem.ValueString() != "web" || c.Index.ValueString() != "01" {
+		t.Fatalf("unexpected segments: %#v", c)
+	}
+	if !c.Purpose.IsNull() {
+		t.Fatalf("expected an unset purpose to be null, got %s", c.Purpose)
+	}
+}
@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*ComplianceDataSource)(nil)
+
+// NewComplianceDataSource returns the naming compliance data source.
+func NewComplianceDataSource() datasource.DataSource {
+	return &ComplianceDataSource{}
+}
+
+// ComplianceDataSource reports which existing resource names break the
+// naming convention or are not registered with the service, for use in
+// check blocks.
+type ComplianceDataSource struct {
+	client *APIClient
+}
+
+type complianceDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Region       types.String `tfsdk:"region"`
+	Environment  types.String `tfsdk:"environment"`
+	ResourceType types.String `tfsdk:"resource_type"`
+	Names        types.Set    `tfsdk:"names"`
+	Violations   types.Map    `tfsdk:"violations"`
+	Unregistered types.Set    `tfsdk:"unregistered"`
+	Compliant    types.Bool   `tfsdk:"compliant"`
+}
+
+func (d *ComplianceDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_compliance"
+}
+
+func (d *ComplianceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports which existing Azure resource names, for example from `azurerm` data sources, break the naming convention or are not claimed in the SanMar naming service. Designed for `check` blocks.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as compliance:<region>:<environment>:<resource_type>.",
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Region the names should be claimed in.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Environment the names should be claimed in.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"resource_type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Canonical resource type of the names, such as `storage_account`, used to pick the convention rules. Leave unset to check against the base rules.",
+			},
+			"names": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Existing resource names to check.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"violations": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.ListType{ElemType: types.StringType},
+				MarkdownDescription: "Convention violations by name, as returned by `provider::sanmar::validate_name`. Names without violations are left out.",
+			},
+			"unregistered": schema.SetAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names that are not currently claimed in the region and environment, including released and retired names.",
+			},
+			"compliant": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "True when every name follows the convention and is claimed.",
+			},
+		},
+	}
+}
+
+func (d *ComplianceDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *ComplianceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data complianceDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var names []string
+	resp.Diagnostics.Append(data.Names.ElementsAs(ctx, &names, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sort.Strings(names)
+
+	violations, unregistered, err := checkCompliance(ctx, d.client, data.Region.ValueString(), data.Environment.ValueString(), data.ResourceType.ValueString(), names)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to check name", err.Error())
+		return
+	}
+
+	violationsMap, diags := types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, violations)
+	resp.Diagnostics.Append(diags...)
+	unregisteredSet, diags := types.SetValueFrom(ctx, types.StringType, unregistered)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"compliance", data.Region.ValueString(), data.Environment.ValueString(), data.ResourceType.ValueString()}, ":"))
+	data.Violations = violationsMap
+	data.Unregistered = unregisteredSet
+	data.Compliant = types.BoolValue(len(violations) == 0 && len(unregistered) == 0)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// checkCompliance lints each name and checks that it is claimed in region and
+// environment. It returns the violations by name, leaving out names without
+// any, and the names that are not claimed.
+func checkCompliance(ctx context.Context, client *APIClient, region, environment, resourceType string, names []string) (map[string][]string, []string, error) {
+	violations := map[string][]string{}
+	// Empty rather than nil, so the output is an empty set instead of null.
+	unregistered := []string{}
+	for _, name := range names {
+		if found := LintName(name, resourceType); len(found) > 0 {
+			violations[name] = found
+		}
+		state, err := client.CheckName(ctx, region, environment, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if !state.InUse {
+			unregistered = append(unregistered, name)
+		}
+	}
+	return violations, unregistered, nil
+}
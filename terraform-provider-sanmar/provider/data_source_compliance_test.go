@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestCheckCompliance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("name") {
+		case "wus2prdstsanmarerp01", "wus2-prd-st-erp":
+			w.Header().Set("X-Sanmar-In-Use", "true")
+		case "wus2prdstsanmarold01":
+			w.Header().Set("X-Sanmar-In-Use", "false")
+		default:
+			w.Header().Set("X-Sanmar-In-Use", "false")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	names := []string{"wus2-prd-st-erp", "wus2prdstsanmarerp01", "wus2prdstsanmarold01", "wus2prdstsanmarnew01"}
+	violations, unregistered, err := checkCompliance(context.Background(), client, "wus2", "prd", "storage_account", names)
+	if err != nil {
+		t.Fatalf("checkCompliance: %v", err)
+	}
+
+	if len(violations) != 1 || len(violations["wus2-prd-st-erp"]) == 0 {
+		t.Fatalf("expected only the hyphenated name to break the convention, got %v", violations)
+	}
+	if want := []string{"wus2prdstsanmarold01", "wus2prdstsanmarnew01"}; !reflect.DeepEqual(unregistered, want) {
+		t.Fatalf("unregistered = %v, want %v", unregistered, want)
+	}
+}
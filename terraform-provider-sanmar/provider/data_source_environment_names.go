@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*EnvironmentNamesDataSource)(nil)
+
+// NewEnvironmentNamesDataSource returns the environment name map data source.
+func NewEnvironmentNamesDataSource() datasource.DataSource {
+	return &EnvironmentNamesDataSource{}
+}
+
+// EnvironmentNamesDataSource maps resource types to the names already claimed
+// for them in one project and environment, for read-only stacks that
+// reference resources other stacks created.
+type EnvironmentNamesDataSource struct {
+	client *APIClient
+}
+
+type environmentNamesDataSourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Project       types.String `tfsdk:"project"`
+	Environment   types.String `tfsdk:"environment"`
+	Region        types.String `tfsdk:"region"`
+	Purpose       types.String `tfsdk:"purpose"`
+	ResourceTypes types.Set    `tfsdk:"resource_types"`
+	AllowMissing  types.Bool   `tfsdk:"allow_missing"`
+	Names         types.Map    `tfsdk:"names"`
+	AllNames      types.Map    `tfsdk:"all_names"`
+	Missing       types.List   `tfsdk:"missing"`
+}
+
+func (d *EnvironmentNamesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment_names"
+}
+
+func (d *EnvironmentNamesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns the names already claimed for a set of resource types in one project and environment, as a map from resource type to name, so read-only stacks such as monitoring dashboards and DNS can reference everything with one data source. Nothing is claimed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as environment_names:<project>:<environment>:<region>:<purpose>.",
+			},
+			"project": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Project the names were claimed for.",
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Environment the names were claimed for.",
+			},
+			"region": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only consider claims in this region. Set it when the environment spans regions.",
+			},
+			"purpose": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only consider claims for this purpose.",
+			},
+			"resource_types": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Resource types to look up, for example `storage_account` and `key_vault`.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+			},
+			"allow_missing": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Leave resource types with no claim out of `names` instead of failing. They are listed in `missing` either way.",
+			},
+			"names": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Claimed name for each resource type that has exactly one claim in scope. Types with several are left out with a warning; narrow the scope with `region` or `purpose`, or use `all_names`.",
+			},
+			"all_names": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.ListType{ElemType: types.StringType},
+				MarkdownDescription: "Every claimed name for each requested resource type, sorted, for types claimed more than once such as indexed storage accounts.",
+			},
+			"missing": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Requested resource types with no claim in scope, sorted.",
+			},
+		},
+	}
+}
+
+func (d *EnvironmentNamesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+// environmentNames groups the claims of the requested resource types. It
+// returns every name per type, sorted, and the types with no claim.
+func environmentNames(claims []ClaimSummary, resourceTypes []string) (map[string][]string, []string) {
+	all := make(map[string][]string, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		all[resourceType] = []string{}
+	}
+	for _, claim := range claims {
+		if names, ok := all[claim.ResourceType]; ok {
+			all[claim.ResourceType] = append(names, claim.Name)
+		}
+	}
+
+	missing := []string{}
+	for resourceType, names := range all {
+		sort.Strings(names)
+		if len(names) == 0 {
+			missing = append(missing, resourceType)
+		}
+	}
+	sort.Strings(missing)
+	return all, missing
+}
+
+func (d *EnvironmentNamesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data environmentNamesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var resourceTypes []string
+	resp.Diagnostics.Append(data.ResourceTypes.ElementsAs(ctx, &resourceTypes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := ClaimFilter{
+		Project:     data.Project.ValueString(),
+		Environment: data.Environment.ValueString(),
+		Region:      data.Region.ValueString(),
+		Purpose:     data.Purpose.ValueString(),
+	}
+	claims, err := d.client.ListClaims(ctx, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to list claims", err.Error())
+		return
+	}
+
+	all, missing := environmentNames(claims, resourceTypes)
+	names := make(map[string]string, len(all))
+	for resourceType, claimed := range all {
+		switch {
+		case len(claimed) == 1:
+			names[resourceType] = claimed[0]
+		case len(claimed) > 1:
+			resp.Diagnostics.AddAttributeWarning(path.Root("resource_types"), "Ambiguous resource type",
+				fmt.Sprintf("%s has %d names claimed in scope (%s), so it is left out of names. Narrow the scope with region or purpose, or read all_names.", resourceType, len(claimed), strings.Join(claimed, ", ")))
+		}
+	}
+	if len(missing) > 0 && !data.AllowMissing.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("resource_types"), "No claimed name",
+			fmt.Sprintf("No names are claimed for %s in project %q, environment %q. Set allow_missing to leave them out.", strings.Join(missing, ", "), filter.Project, filter.Environment))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namesValue, diags := types.MapValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	allValue, diags := types.MapValueFrom(ctx, types.ListType{ElemType: types.StringType}, all)
+	resp.Diagnostics.Append(diags...)
+	missingValue, diags := types.ListValueFrom(ctx, types.StringType, missing)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"environment_names", filter.Project, filter.Environment, filter.Region, filter.Purpose}, ":"))
+	data.Names = namesValue
+	data.AllNames = allValue
+	data.Missing = missingValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
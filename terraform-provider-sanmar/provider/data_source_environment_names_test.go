@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvironmentNames(t *testing.T) {
+	claims := []ClaimSummary{
+		{Name: "stwus2prdatlas02", ResourceType: "storage_account"},
+		{Name: "kvwus2prdatlas", ResourceType: "key_vault"},
+		{Name: "stwus2prdatlas01", ResourceType: "storage_account"},
+		{Name: "vmwus2prdatlas", ResourceType: "virtual_machine"},
+	}
+
+	all, missing := environmentNames(claims, []string{"key_vault", "storage_account", "sql_server"})
+	wantAll := map[string][]string{
+		"key_vault":       {"kvwus2prdatlas"},
+		"storage_account": {"stwus2prdatlas01", "stwus2prdatlas02"},
+		"sql_server":      {},
+	}
+	if !reflect.DeepEqual(all, wantAll) {
+		t.Fatalf("all = %v, want %v", all, wantAll)
+	}
+	if !reflect.DeepEqual(missing, []string{"sql_server"}) {
+		t.Fatalf("missing = %v, want [sql_server]", missing)
+	}
+
+	if _, missing := environmentNames(claims, []string{"key_vault"}); missing == nil || len(missing) != 0 {
+		t.Fatalf("expected empty, non-nil missing, got %v", missing)
+	}
+}
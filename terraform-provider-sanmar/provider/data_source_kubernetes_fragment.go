@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Labels recording where a Kubernetes object's Azure name is claimed. The
+// sanmar-webhook admission webhook reads them to check the claim.
+const (
+	KubernetesRegionLabel      = "sanmar.io/region"
+	KubernetesEnvironmentLabel = "sanmar.io/environment"
+)
+
+// crossplaneExternalName sets the Azure name of a Crossplane managed resource.
+const crossplaneExternalName = "crossplane.io/external-name"
+
+var _ datasource.DataSource = (*KubernetesFragmentDataSource)(nil)
+
+// NewKubernetesFragmentDataSource returns the Kubernetes manifest fragment
+// data source.
+func NewKubernetesFragmentDataSource() datasource.DataSource {
+	return &KubernetesFragmentDataSource{}
+}
+
+// KubernetesFragmentDataSource renders the labels, annotations and spec
+// fields that hand a claimed name to Azure Service Operator or Crossplane.
+type KubernetesFragmentDataSource struct {
+	client *APIClient
+}
+
+type kubernetesFragmentDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Region       types.String `tfsdk:"region"`
+	Environment  types.String `tfsdk:"environment"`
+	Operator     types.String `tfsdk:"operator"`
+	ResourceType types.String `tfsdk:"resource_type"`
+	Labels       types.Map    `tfsdk:"labels"`
+	Annotations  types.Map    `tfsdk:"annotations"`
+	Spec         types.Map    `tfsdk:"spec"`
+	YAML         types.String `tfsdk:"yaml"`
+}
+
+func (d *KubernetesFragmentDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kubernetes_fragment"
+}
+
+func (d *KubernetesFragmentDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	fields := func(description string) schema.MapAttribute {
+		return schema.MapAttribute{
+			Computed:            true,
+			ElementType:         types.StringType,
+			MarkdownDescription: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders the manifest fields Azure Service Operator or Crossplane need to create a resource with a claimed name, for GitOps repositories that deploy resources named by Terraform. The name must be claimed in the region and environment.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as kubernetes_fragment:<operator>:<region>:<environment>:<name>.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Claimed name, usually `sanmar_claim.<name>.name`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Region the name is claimed in.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Environment the name is claimed in.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"operator": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Operator that creates the resource: `aso` for Azure Service Operator or `crossplane`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("aso", "crossplane"),
+				},
+			},
+			"resource_type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Canonical resource type the name was claimed for.",
+			},
+			"labels":      fields("Labels recording the claim's region and environment, read by the `sanmar-webhook` admission webhook."),
+			"annotations": fields("Annotations to set on the object. For Crossplane this is `crossplane.io/external-name`."),
+			"spec":        fields("Spec fields to set on the object. For Azure Service Operator this is `azureName`."),
+			"yaml": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The labels, annotations and spec fields as a YAML fragment with `metadata` and `spec` keys, ready to merge into a manifest or Kustomize patch.",
+			},
+		},
+	}
+}
+
+func (d *KubernetesFragmentDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *KubernetesFragmentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data kubernetesFragmentDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+	region := data.Region.ValueString()
+	environment := data.Environment.ValueString()
+	record, err := d.client.GetAudit(ctx, region, environment, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read claim", err.Error())
+		return
+	}
+	if record == nil || !record.InUse {
+		resp.Diagnostics.AddError("Name not claimed", fmt.Sprintf("%s is not claimed in %s/%s; claim it before rendering manifests that use it.", name, region, environment))
+		return
+	}
+
+	fragment := newKubernetesFragment(data.Operator.ValueString(), name, region, environment)
+	content, err := fragment.render()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render fragment", err.Error())
+		return
+	}
+
+	labels, diags := types.MapValueFrom(ctx, types.StringType, fragment.Metadata.Labels)
+	resp.Diagnostics.Append(diags...)
+	annotations, diags := types.MapValueFrom(ctx, types.StringType, fragment.Metadata.Annotations)
+	resp.Diagnostics.Append(diags...)
+	spec, diags := types.MapValueFrom(ctx, types.StringType, fragment.Spec)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"kubernetes_fragment", data.Operator.ValueString(), region, environment, name}, ":"))
+	data.ResourceType = types.StringValue(record.Resource)
+	data.Labels = labels
+	data.Annotations = annotations
+	data.Spec = spec
+	data.YAML = types.StringValue(content)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// kubernetesFragment is the part of a Kubernetes object that carries a
+// claimed name.
+type kubernetesFragment struct {
+	Metadata struct {
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations,omitempty"`
+	} `yaml:"metadata"`
+	Spec map[string]string `yaml:"spec,omitempty"`
+}
+
+// newKubernetesFragment returns the fields operator reads the Azure name
+// from, plus the claim labels.
+func newKubernetesFragment(operator, name, region, environment string) kubernetesFragment {
+	var fragment kubernetesFragment
+	fragment.Metadata.Labels = map[string]string{
+		KubernetesRegionLabel:      region,
+		KubernetesEnvironmentLabel: environment,
+	}
+	// Empty rather than nil, so the outputs are empty maps instead of null.
+	fragment.Metadata.Annotations = map[string]string{}
+	fragment.Spec = map[string]string{}
+
+	switch operator {
+	case "aso":
+		fragment.Spec["azureName"] = name
+	case "crossplane":
+		fragment.Metadata.Annotations[crossplaneExternalName] = name
+	}
+	return fragment
+}
+
+// render returns the fragment as YAML indented the way Kubernetes manifests
+// usually are.
+func (f kubernetesFragment) render() (string, error) {
+	var b strings.Builder
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
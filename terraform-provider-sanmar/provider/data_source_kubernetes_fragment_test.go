@@ -0,0 +1,28 @@
+package provider
+
+import "testing"
+
+func TestNewKubernetesFragment(t *testing.T) {
+	aso := newKubernetesFragment("aso", "wus2-prd-aks-atlas", "wus2", "prd")
+	if aso.Spec["azureName"] != "wus2-prd-aks-atlas" || len(aso.Metadata.Annotations) != 0 {
+		t.Fatalf("unexpected aso fragment: %+v", aso)
+	}
+	if aso.Metadata.Labels[KubernetesRegionLabel] != "wus2" || aso.Metadata.Labels[KubernetesEnvironmentLabel] != "prd" {
+		t.Fatalf("unexpected labels: %+v", aso.Metadata.Labels)
+	}
+
+	content, err := newKubernetesFragment("crossplane", "wus2prdstsanmaratlas", "wus2", "prd").render()
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := `metadata:
+  labels:
+    sanmar.io/environment: prd
+    sanmar.io/region: wus2
+  annotations:
+    crossplane.io/external-name: wus2prdstsanmaratlas
+`
+	if content != want {
+		t.Fatalf("unexpected yaml:\n%s", content)
+	}
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*ManifestDataSource)(nil)
+
+// NewManifestDataSource returns the naming manifest data source.
+func NewManifestDataSource() datasource.DataSource {
+	return &ManifestDataSource{}
+}
+
+// ManifestDataSource previews the names for every resource in a manifest.
+type ManifestDataSource struct {
+	client *APIClient
+}
+
+type manifestDataSourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Document types.String `tfsdk:"document"`
+	Names    types.Map    `tfsdk:"names"`
+}
+
+func (d *ManifestDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_manifest"
+}
+
+func (d *ManifestDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Previews the names for a whole set of resources described by a YAML or JSON manifest without claiming them.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as manifest:<sha256 of the document>.",
+			},
+			"document": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "YAML or JSON manifest with optional `defaults` and a `resources` map of logical keys to `resource_type`, segments, and `count`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"names": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Previewed name for each logical key. Entries with a count expand to `<key>_1` through `<key>_N`.",
+			},
+		},
+	}
+}
+
+func (d *ManifestDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *ManifestDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data manifestDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	requests, err := parseManifest(data.Document.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("document"), "Invalid manifest", err.Error())
+		return
+	}
+
+	keys := make([]string, 0, len(requests))
+	for key := range requests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	names := make(map[string]string, len(requests))
+	for _, key := range keys {
+		preview, err := d.client.PreviewName(ctx, requests[key])
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to preview manifest name", fmt.Sprintf("%s: %s", key, err))
+			return
+		}
+		names[key] = preview.Name
+	}
+
+	namesValue, diags := types.MapValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(data.Document.ValueString()))
+	data.ID = types.StringValue("manifest:" + hex.EncodeToString(sum[:]))
+	data.Names = namesValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
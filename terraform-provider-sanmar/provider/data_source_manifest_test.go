@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestManifestDataSourceRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ClaimNameRequest
+		if r.URL.Path != "/api/preview" || json.NewDecoder(r.Body).Decode(&req) != nil {
+			http.Error(w, "unexpected call", http.StatusInternalServerError)
+			return
+		}
+		slug := map[string]string{"key_vault": "kv", "app_service": "app"}[req.ResourceType]
+		name := strings.Join([]string{req.Region, req.Environment, slug, *req.System}, "-")
+		if req.Index != nil {
+			name += "-" + *req.Index
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name": name, "resourceType": req.ResourceType, "region": req.Region, "environment": req.Environment,
+			"slug": slug, "system": *req.System, "index": req.Index, "available": true, "display": []any{},
+		})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	document := `
+defaults:
+  region: wus2
+  environment: prd
+  system: erp
+resources:
+  vault:
+    resource_type: key_vault
+  web:
+    resource_type: app_service
+    count: 2
+`
+	resp := readDataSource(ctx, t, &ManifestDataSource{client: client}, map[string]string{"document": document})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	var state manifestDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	names := map[string]string{}
+	resp.Diagnostics.Append(state.Names.ElementsAs(ctx, &names, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("state: %v", resp.Diagnostics)
+	}
+	want := map[string]string{"vault": "wus2-prd-kv-erp", "web_1": "wus2-prd-app-erp-01", "web_2": "wus2-prd-app-erp-02"}
+	if len(names) != len(want) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+	for key, name := range want {
+		if names[key] != name {
+			t.Fatalf("%s: got %q, want %q", key, names[key], name)
+		}
+	}
+	if !strings.HasPrefix(state.ID.ValueString(), "manifest:") {
+		t.Fatalf("unexpected id: %q", state.ID.ValueString())
+	}
+}
@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*NameAvailabilityDataSource)(nil)
+
+// NewNameAvailabilityDataSource returns the name availability data source.
+func NewNameAvailabilityDataSource() datasource.DataSource {
+	return &NameAvailabilityDataSource{}
+}
+
+// NameAvailabilityDataSource checks whether names are free to claim in a
+// region and environment.
+type NameAvailabilityDataSource struct {
+	client *APIClient
+}
+
+type nameAvailabilityDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Names       types.Set    `tfsdk:"names"`
+	Available   types.Set    `tfsdk:"available"`
+	Taken       types.Set    `tfsdk:"taken"`
+	Retired     types.Set    `tfsdk:"retired"`
+}
+
+func (d *NameAvailabilityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_name_availability"
+}
+
+func (d *NameAvailabilityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	names := func(description string) schema.SetAttribute {
+		return schema.SetAttribute{
+			Computed:            true,
+			ElementType:         types.StringType,
+			MarkdownDescription: description,
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks whether names are free to claim in a region and environment, without claiming them. Each name costs one `HEAD /api/audit` request, so large lists stay cheap.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as name_availability:<region>:<environment>.",
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Region the names would be claimed in.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Environment the names would be claimed in.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"names": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names to check.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"available": names("Names that are not claimed and can be."),
+			"taken":     names("Names that are currently claimed."),
+			"retired":   names("Names the service has retired, which can never be claimed."),
+		},
+	}
+}
+
+func (d *NameAvailabilityDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *NameAvailabilityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data nameAvailabilityDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var names []string
+	resp.Diagnostics.Append(data.Names.ElementsAs(ctx, &names, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sort.Strings(names)
+
+	// Empty rather than nil, so the outputs are empty sets instead of null.
+	available, taken, retired := []string{}, []string{}, []string{}
+	for _, name := range names {
+		state, err := d.client.CheckName(ctx, data.Region.ValueString(), data.Environment.ValueString(), name)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to check name", fmt.Sprintf("%s: %s", name, err))
+			return
+		}
+		switch {
+		case state.Retired:
+			retired = append(retired, name)
+		case state.InUse:
+			taken = append(taken, name)
+		default:
+			available = append(available, name)
+		}
+	}
+
+	availableSet, diags := types.SetValueFrom(ctx, types.StringType, available)
+	resp.Diagnostics.Append(diags...)
+	takenSet, diags := types.SetValueFrom(ctx, types.StringType, taken)
+	resp.Diagnostics.Append(diags...)
+	retiredSet, diags := types.SetValueFrom(ctx, types.StringType, retired)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"name_availability", data.Region.ValueString(), data.Environment.ValueString()}, ":"))
+	data.Available = availableSet
+	data.Taken = takenSet
+	data.Retired = retiredSet
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*SessionDataSource)(nil)
+
+// NewSessionDataSource returns the session defaults data source.
+func NewSessionDataSource() datasource.DataSource {
+	return &SessionDataSource{}
+}
+
+// SessionDataSource exposes the segment defaults stored for a session.
+type SessionDataSource struct {
+	client *APIClient
+}
+
+type sessionDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	SessionID   types.String `tfsdk:"session_id"`
+	Defaults    types.Map    `tfsdk:"defaults"`
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Project     types.String `tfsdk:"project"`
+	Purpose     types.String `tfsdk:"purpose"`
+	Subsystem   types.String `tfsdk:"subsystem"`
+	System      types.String `tfsdk:"system"`
+	Index       types.String `tfsdk:"index"`
+	LastSeen    types.String `tfsdk:"last_seen"`
+}
+
+func (d *SessionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_session"
+}
+
+func (d *SessionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns the segment defaults the SanMar naming service applies to claims made with a session.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as session:<session_id>.",
+			},
+			"session_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Session identifier whose defaults should be resolved.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"defaults": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "All default values stored for the session, keyed by segment name.",
+			},
+			"region": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default region applied by the session, if any.",
+			},
+			"environment": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default environment applied by the session, if any.",
+			},
+			"project": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default project segment applied by the session, if any.",
+			},
+			"purpose": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default purpose segment applied by the session, if any.",
+			},
+			"subsystem": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default subsystem segment applied by the session, if any.",
+			},
+			"system": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default system segment applied by the session, if any.",
+			},
+			"index": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Default index segment applied by the session, if any.",
+			},
+			"last_seen": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Timestamp of the most recent use of the session.",
+			},
+		},
+	}
+}
+
+func (d *SessionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *SessionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data sessionDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	session, err := d.client.GetSessionDefaults(ctx, data.SessionID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read session defaults", err.Error())
+		return
+	}
+
+	if session == nil {
+		resp.Diagnostics.AddWarning("Session not found", fmt.Sprintf("No defaults are stored for session %s; claims will not be defaulted server-side.", data.SessionID.ValueString()))
+		session = &SessionDefaults{SessionID: data.SessionID.ValueString()}
+	}
+
+	defaults := session.Defaults
+	if defaults == nil {
+		defaults = map[string]string{}
+	}
+
+	mapValue, diags := types.MapValueFrom(ctx, types.StringType, defaults)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue("session:" + data.SessionID.ValueString())
+	data.Defaults = mapValue
+	data.Region = optionalString(defaults["region"])
+	data.Environment = optionalString(defaults["environment"])
+	data.Project = optionalString(defaults["project"])
+	data.Purpose = optionalString(defaults["purpose"])
+	data.Subsystem = optionalString(defaults["subsystem"])
+	data.System = optionalString(defaults["system"])
+	data.Index = optionalString(defaults["index"])
+	data.LastSeen = optionalString(session.LastSeen)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// optionalString maps empty service values to null so unset segments stay unset in state.
+func optionalString(v string) types.String {
+	if v == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(v)
+}
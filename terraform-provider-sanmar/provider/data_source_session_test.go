@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readDataSource runs Read for d with the given string attributes set and
+// every other attribute null, and returns the response.
+func readDataSource(ctx context.Context, t *testing.T, d datasource.DataSource, values map[string]string) datasource.ReadResponse {
+	t.Helper()
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	typ := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	raw := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		raw[name] = tftypes.NewValue(attrType, nil)
+	}
+	for name, value := range values {
+		raw[name] = tftypes.NewValue(tftypes.String, value)
+	}
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(typ, raw)}
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+	return resp
+}
+
+func TestSessionDataSourceRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/session" || r.URL.Query().Get("session_id") != "s-1" {
+			http.Error(w, "Session not found or expired.", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sessionId":"s-1","defaults":{"region":"wus2","project":"orion","system":"erp"},"lastSeen":"2026-01-01T00:00:00+00:00"}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	d := &SessionDataSource{client: client}
+
+	resp := readDataSource(ctx, t, d, map[string]string{"session_id": "s-1"})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", resp.Diagnostics)
+	}
+	var state sessionDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueString() != "session:s-1" || state.Region.ValueString() != "wus2" || state.Project.ValueString() != "orion" || state.System.ValueString() != "erp" {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+	if !state.Environment.IsNull() || !state.Index.IsNull() {
+		t.Fatalf("expected unset defaults to be null, got %s and %s", state.Environment, state.Index)
+	}
+	if state.LastSeen.ValueString() != "2026-01-01T00:00:00+00:00" || len(state.Defaults.Elements()) != 3 {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+
+	// Unknown sessions warn and report no defaults.
+	resp = readDataSource(ctx, t, d, map[string]string{"session_id": "gone"})
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected one warning, got %v", resp.Diagnostics)
+	}
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if len(state.Defaults.Elements()) != 0 || !state.Project.IsNull() {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -29,6 +31,12 @@ type slugDataSourceModel struct {
 	FullName     types.String `tfsdk:"full_name"`
 	Source       types.String `tfsdk:"source"`
 	UpdatedAt    types.String `tfsdk:"updated_at"`
+
+	MaxLength       types.Int64  `tfsdk:"max_length"`
+	ValidCharacters types.String `tfsdk:"valid_characters"`
+	CaseRule        types.String `tfsdk:"case_rule"`
+	UniquenessScope types.String `tfsdk:"uniqueness_scope"`
+	Defaults        types.Map    `tfsdk:"defaults"`
 }
 
 func (d *SlugDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -44,15 +52,21 @@ func (d *SlugDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				MarkdownDescription: "Identifier used in state, formatted as slug:<resource_type>.",
 			},
 			"resource_type": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Canonical resource type to look up.",
-				Validators: []schema.AttributeValidator{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Canonical resource type to look up. Set either this or `slug`; when looking up by slug it is the type the slug belongs to.",
+				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("slug")),
 				},
 			},
 			"slug": schema.StringAttribute{
+				Optional:            true,
 				Computed:            true,
-				MarkdownDescription: "Short slug resolved by the service.",
+				MarkdownDescription: "Short slug resolved by the service. Set it instead of `resource_type` to look up the resource type that uses the slug, for example when parsing existing names.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"full_name": schema.StringAttribute{
 				Computed:            true,
@@ -66,6 +80,27 @@ func (d *SlugDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, r
 				Computed:            true,
 				MarkdownDescription: "Timestamp of the most recent sync entry.",
 			},
+			"max_length": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Maximum name length for the resource type, from the service's naming rule.",
+			},
+			"valid_characters": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Regular expression character class, without brackets, that names of the type stay within (for example `a-z0-9`), from the service's naming rule.",
+			},
+			"case_rule": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Case names must use, such as `lower`.",
+			},
+			"uniqueness_scope": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Scope within which Azure requires names to be unique: `global`, `resource-group`, or `parent`. Null when the naming rule does not say.",
+			},
+			"defaults": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Segment values, keyed by segment name such as `purpose`, the service applies to claims that leave them unset. Empty when the slug table declares none.",
+			},
 		},
 	}
 }
@@ -88,6 +123,7 @@ func (d *SlugDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
 		return
 	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
 
 	var data slugDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -95,23 +131,86 @@ func (d *SlugDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
-	slug, err := d.client.LookupSlug(ctx, data.ResourceType.ValueString())
+	var slug *SlugResponse
+	var err error
+	if data.ResourceType.IsNull() {
+		slug, err = d.client.LookupSlugValue(ctx, data.Slug.ValueString())
+	} else {
+		slug, err = d.client.LookupSlug(ctx, data.ResourceType.ValueString())
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to lookup slug", err.Error())
 		return
 	}
 
 	if slug == nil {
-		resp.Diagnostics.AddWarning("Slug not found", fmt.Sprintf("No slug mapping returned for resource type %s", data.ResourceType.ValueString()))
+		if data.ResourceType.IsNull() {
+			resp.Diagnostics.AddWarning("Slug not found", fmt.Sprintf("No resource type uses slug %s", data.Slug.ValueString()))
+		} else {
+			resp.Diagnostics.AddWarning("Slug not found", fmt.Sprintf("No slug mapping returned for resource type %s", data.ResourceType.ValueString()))
+		}
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	if data.ResourceType.IsNull() {
+		data.ResourceType = types.StringValue(slug.ResourceType)
+	}
 	data.ID = types.StringValue("slug:" + data.ResourceType.ValueString())
 	data.Slug = types.StringValue(slug.Slug)
 	data.FullName = types.StringValue(slug.FullName)
 	data.Source = types.StringValue(slug.Source)
 	data.UpdatedAt = types.StringValue(slug.UpdatedAt)
 
+	rule, err := d.client.GetNamingRule(ctx, data.ResourceType.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read naming rule", err.Error())
+		return
+	}
+	data.MaxLength, data.CaseRule = ruleConstraints(data.ResourceType.ValueString(), rule)
+	data.ValidCharacters, data.UniquenessScope = types.StringNull(), types.StringNull()
+	if rule != nil {
+		data.ValidCharacters = optionalString(rule.ValidCharacters)
+		data.UniquenessScope = optionalString(rule.UniquenessScope)
+	}
+
+	// Empty rather than nil, so the output is an empty map instead of null.
+	defaults := slug.Defaults
+	if defaults == nil {
+		defaults = map[string]string{}
+	}
+	defaultsMap, diags := types.MapValueFrom(ctx, types.StringType, defaults)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Defaults = defaultsMap
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// ruleConstraints returns the length and case constraints of the service's
+// naming rule, falling back to the provider's built-in rules for known types
+// when the service does not serve its rules.
+func ruleConstraints(resourceType string, rule *NamingRule) (types.Int64, types.String) {
+	if rule != nil {
+		maxLength := types.Int64Null()
+		if rule.MaxLength > 0 {
+			maxLength = types.Int64Value(int64(rule.MaxLength))
+		}
+		return maxLength, optionalString(rule.CaseRule)
+	}
+
+	builtIn, ok := resourceTypeRules[resourceType]
+	if !ok {
+		return types.Int64Null(), types.StringNull()
+	}
+	maxLength, caseRule := types.Int64Null(), types.StringNull()
+	if builtIn.MaxLength > 0 {
+		maxLength = types.Int64Value(int64(builtIn.MaxLength))
+	}
+	if builtIn.Lowercase {
+		caseRule = types.StringValue("lower")
+	}
+	return maxLength, caseRule
+}
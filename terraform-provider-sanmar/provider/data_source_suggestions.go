@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*SuggestionsDataSource)(nil)
+
+// NewSuggestionsDataSource returns the name suggestions data source.
+func NewSuggestionsDataSource() datasource.DataSource {
+	return &SuggestionsDataSource{}
+}
+
+// SuggestionsDataSource returns candidate names without claiming any of them.
+type SuggestionsDataSource struct {
+	client *APIClient
+}
+
+type suggestionsDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	ResourceType types.String `tfsdk:"resource_type"`
+	Region       types.String `tfsdk:"region"`
+	Environment  types.String `tfsdk:"environment"`
+	Project      types.String `tfsdk:"project"`
+	System       types.String `tfsdk:"system"`
+	Subsystem    types.String `tfsdk:"subsystem"`
+	Purposes     types.List   `tfsdk:"purposes"`
+	Count        types.Int64  `tfsdk:"count"`
+	Suggestions  types.List   `tfsdk:"suggestions"`
+}
+
+type suggestionModel struct {
+	Name      types.String `tfsdk:"name"`
+	Purpose   types.String `tfsdk:"purpose"`
+	Index     types.String `tfsdk:"index"`
+	Available types.Bool   `tfsdk:"available"`
+}
+
+var suggestionAttrTypes = map[string]attr.Type{
+	"name":      types.StringType,
+	"purpose":   types.StringType,
+	"index":     types.StringType,
+	"available": types.BoolType,
+}
+
+func (d *SuggestionsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_suggestions"
+}
+
+func (d *SuggestionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Returns several candidate names (different purposes and indices) for a resource type and scope without claiming them.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier used in state, formatted as suggestions:<resource_type>:<region>:<environment>.",
+			},
+			"resource_type": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Azure resource type identifier used for slug resolution.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Azure region short code (for example, wus2).",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 8),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Deployment environment such as dev, stg, or prd.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(2),
+				},
+			},
+			"project": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Optional project segment.",
+			},
+			"system": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Optional system segment.",
+			},
+			"subsystem": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Optional subsystem segment.",
+			},
+			"purposes": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Candidate purpose segments to try. When omitted the service varies only the index.",
+			},
+			"count": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Maximum number of suggestions to return (default 5).",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 50),
+				},
+			},
+			"suggestions": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "Candidate names in the order returned by the service.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Candidate name.",
+						},
+						"purpose": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Purpose segment used for the candidate.",
+						},
+						"index": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Index segment used for the candidate.",
+						},
+						"available": schema.BoolAttribute{
+							Computed:            true,
+							MarkdownDescription: "Whether the candidate is currently unclaimed.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *SuggestionsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	d.client = client
+}
+
+func (d *SuggestionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if d.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer d.client.reportDeprecations(&resp.Diagnostics)
+
+	var data suggestionsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload := SuggestionRequest{
+		ClaimNameRequest: ClaimNameRequest{
+			ResourceType: data.ResourceType.ValueString(),
+			Region:       data.Region.ValueString(),
+			Environment:  data.Environment.ValueString(),
+			Project:      data.Project.ValueStringPointer(),
+			System:       data.System.ValueStringPointer(),
+			Subsystem:    data.Subsystem.ValueStringPointer(),
+		},
+		Count: 5,
+	}
+	if !data.Count.IsNull() && !data.Count.IsUnknown() {
+		payload.Count = int(data.Count.ValueInt64())
+	}
+	if !data.Purposes.IsNull() && !data.Purposes.IsUnknown() {
+		resp.Diagnostics.Append(data.Purposes.ElementsAs(ctx, &payload.Purposes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	suggestions, err := d.client.SuggestNames(ctx, payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to fetch name suggestions", err.Error())
+		return
+	}
+
+	models := make([]suggestionModel, 0, len(suggestions))
+	for _, s := range suggestions {
+		models = append(models, suggestionModel{
+			Name:      types.StringValue(s.Name),
+			Purpose:   types.StringValue(s.Purpose),
+			Index:     types.StringValue(s.Index),
+			Available: types.BoolValue(s.Available),
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: suggestionAttrTypes}, models)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ID = types.StringValue(strings.Join([]string{"suggestions", payload.ResourceType, payload.Region, payload.Environment}, ":"))
+	data.Suggestions = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
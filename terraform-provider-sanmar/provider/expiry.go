@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultExpiryWarningWindow is how long before expires_at a claim starts
+// warning when the provider does not set expiry_warning_window.
+const defaultExpiryWarningWindow = 72 * time.Hour
+
+// parseTimestamp parses an RFC 3339 timestamp. RFC 3339 allows a lowercase
+// "t" and "z", which Go's parser rejects; claims made before the service
+// stored expiries unchanged hold them lowercased.
+func parseTimestamp(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, strings.ToUpper(value))
+}
+
+// parseExpiresAt validates expires_at as an RFC 3339 timestamp. Null and
+// unknown values are accepted.
+func parseExpiresAt(expiresAt types.String) (time.Time, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if expiresAt.IsNull() || expiresAt.IsUnknown() {
+		return time.Time{}, diags
+	}
+
+	t, err := parseTimestamp(expiresAt.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("expires_at"), "Invalid expires_at",
+			fmt.Sprintf("expires_at must be an RFC 3339 timestamp such as 2025-01-31T00:00:00Z: %v", err))
+	}
+	return t, diags
+}
+
+// recordedExpiry returns the expiry the service holds for a claim, keeping
+// current when it is the same instant. Lowercased expiries are read back in
+// upper case.
+func recordedExpiry(current types.String, recorded string) types.String {
+	if recorded == "" {
+		return types.StringNull()
+	}
+	recorded = strings.ToUpper(recorded)
+	t, err := parseTimestamp(recorded)
+	if err != nil {
+		return types.StringValue(recorded)
+	}
+	if c, err := parseTimestamp(current.ValueString()); err == nil && c.Equal(t) {
+		return current
+	}
+	return types.StringValue(recorded)
+}
+
+// scheduledRelease returns when the service will release a claim made with
+// release_after set, as the service reported it.
+func scheduledRelease(plan claimResourceModel, claim *ClaimNameResponse) types.String {
+	if plan.DryRun.ValueBool() || plan.ReleaseAfter.IsNull() || plan.ReleaseAfter.IsUnknown() {
+		return types.StringNull()
+	}
+	return optionalString(claim.ReleaseAt)
+}
+
+// claimExpiry returns the time left until expires_at, rounded to the minute
+// and zero once expired, with a warning when less than window remains.
+func claimExpiry(name string, expiresAt types.String, now time.Time, window time.Duration) (types.String, diag.Diagnostics) {
+	if expiresAt.IsNull() || expiresAt.IsUnknown() {
+		return types.StringNull(), nil
+	}
+
+	t, diags := parseExpiresAt(expiresAt)
+	if diags.HasError() {
+		return types.StringNull(), diags
+	}
+
+	left := t.Sub(now).Round(time.Minute)
+	if left <= 0 {
+		diags.AddAttributeWarning(path.Root("expires_at"), "Claim has expired",
+			fmt.Sprintf("%s expired at %s; extend expires_at to renew it before the service releases the name.", name, expiresAt.ValueString()))
+		return types.StringValue("0s"), diags
+	}
+	if left <= window {
+		diags.AddAttributeWarning(path.Root("expires_at"), "Claim is about to expire",
+			fmt.Sprintf("%s expires in %s (at %s); extend expires_at to renew it.", name, left, expiresAt.ValueString()))
+	}
+	return types.StringValue(left.String()), diags
+}
+
+// leaseExpiry warns at plan time when the lease a claim will hold after apply
+// ends within horizon of now, so it is renewed before an apply that depends
+// on the name fails. A plan that moves expires_at past the horizon renews the
+// lease and does not warn.
+func leaseExpiry(plan claimResourceModel, now time.Time, horizon time.Duration) diag.Diagnostics {
+	if plan.DryRun.ValueBool() {
+		return nil
+	}
+	name := describePlannedName(plan)
+	if !plan.Name.IsNull() && !plan.Name.IsUnknown() {
+		name = plan.Name.ValueString()
+	}
+	// Renewing outside Terraform does not help: the next apply sets the
+	// lease back to the configured expires_at.
+	return leaseWarning("Claim lease", "The lease on "+name, plan.ExpiresAt, now, horizon, "Extend expires_at")
+}
+
+// reservationExpiry is leaseExpiry for index reservations. A reservation
+// cannot be renewed in place, so extending expires_at replaces it.
+func reservationExpiry(plan indexReservationResourceModel, now time.Time, horizon time.Duration) diag.Diagnostics {
+	block := fmt.Sprintf("The reservation of indices %d-%d for %s", plan.Start.ValueInt64(), plan.End.ValueInt64(), plan.Team.ValueString())
+	return leaseWarning("Index reservation", block, plan.ExpiresAt, now, horizon, "Extend expires_at, which replaces the reservation,")
+}
+
+// leaseWarning warns on expires_at when it falls within horizon of now.
+// Unparsable values are left to the expires_at validation.
+func leaseWarning(kind, subject string, expiresAt types.String, now time.Time, horizon time.Duration, advice string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if horizon <= 0 || expiresAt.IsNull() || expiresAt.IsUnknown() {
+		return diags
+	}
+	t, err := parseTimestamp(expiresAt.ValueString())
+	if err != nil || !t.Before(now.Add(horizon)) {
+		return diags
+	}
+
+	when := fmt.Sprintf("ends in %s", t.Sub(now).Round(time.Minute))
+	if !t.After(now) {
+		when = "has already ended"
+	}
+	diags.AddAttributeWarning(path.Root("expires_at"), kind+" ends within lease_horizon",
+		fmt.Sprintf("%s %s (at %s), within the provider's lease_horizon of %s. %s before an apply that depends on it.",
+			subject, when, expiresAt.ValueString(), horizon, advice))
+	return diags
+}
@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestClaimExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	left, diags := claimExpiry("app", types.StringNull(), now, time.Hour)
+	if !left.IsNull() || len(diags) != 0 {
+		t.Fatalf("expected no expiry, got %s and %v", left, diags)
+	}
+
+	left, diags = claimExpiry("app", types.StringValue("2024-05-04T12:00:00Z"), now, 24*time.Hour)
+	if left.ValueString() != "72h0m0s" || len(diags) != 0 {
+		t.Fatalf("expected 72h without warning, got %s and %v", left, diags)
+	}
+
+	left, diags = claimExpiry("app", types.StringValue("2024-05-01T18:30:00Z"), now, 24*time.Hour)
+	if left.ValueString() != "6h30m0s" || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a warning inside the window, got %s and %v", left, diags)
+	}
+
+	left, diags = claimExpiry("app", types.StringValue("2024-04-30T00:00:00Z"), now, 24*time.Hour)
+	if left.ValueString() != "0s" || diags.WarningsCount() != 1 {
+		t.Fatalf("expected an expired warning, got %s and %v", left, diags)
+	}
+
+	if _, diags := parseExpiresAt(types.StringValue("next tuesday")); !diags.HasError() {
+		t.Fatalf("expected an invalid timestamp error")
+	}
+}
+
+func TestRecordedExpiry(t *testing.T) {
+	if got := recordedExpiry(types.StringNull(), ""); !got.IsNull() {
+		t.Fatalf("expected no expiry, got %s", got)
+	}
+	if got := recordedExpiry(types.StringNull(), "2025-01-31t00:00:00z"); got.ValueString() != "2025-01-31T00:00:00Z" {
+		t.Fatalf("expected the lowercased expiry restored, got %s", got)
+	}
+	current := types.StringValue("2025-01-31T02:00:00+02:00")
+	if got := recordedExpiry(current, "2025-01-31T00:00:00Z"); !got.Equal(current) {
+		t.Fatalf("expected the same instant to keep its configured form, got %s", got)
+	}
+	if got := recordedExpiry(current, "2025-02-28T00:00:00Z"); got.ValueString() != "2025-02-28T00:00:00Z" {
+		t.Fatalf("expected a moved expiry, got %s", got)
+	}
+}
+
+func TestScheduledRelease(t *testing.T) {
+	plan := claimResourceModel{ReleaseAfter: types.StringValue("720h"), DryRun: types.BoolValue(false)}
+
+	if got := scheduledRelease(plan, &ClaimNameResponse{ReleaseAt: "2024-05-31T00:00:00Z"}); got.ValueString() != "2024-05-31T00:00:00Z" {
+		t.Fatalf("expected the service's release time, got %s", got)
+	}
+	// Nothing is invented when the service does not report a release time.
+	if got := scheduledRelease(plan, &ClaimNameResponse{}); !got.IsNull() {
+		t.Fatalf("expected no release time, got %s", got)
+	}
+
+	plan.DryRun = types.BoolValue(true)
+	if got := scheduledRelease(plan, &ClaimNameResponse{ReleaseAt: "2024-05-31T00:00:00Z"}); !got.IsNull() {
+		t.Fatalf("expected no release time for previews, got %s", got)
+	}
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	plan := claimResourceModel{
+		Name:      types.StringValue("kvwus2prdatlas"),
+		ExpiresAt: types.StringValue("2024-05-08T12:00:00Z"),
+	}
+
+	if diags := leaseExpiry(plan, now, 0); len(diags) != 0 {
+		t.Fatalf("expected no check without a horizon, got %v", diags)
+	}
+	if diags := leaseExpiry(plan, now, 72*time.Hour); len(diags) != 0 {
+		t.Fatalf("expected no warning for a lease past the horizon, got %v", diags)
+	}
+	diags := leaseExpiry(plan, now, 14*24*time.Hour)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "kvwus2prdatlas ends in 168h0m0s") {
+		t.Fatalf("expected a warning naming the claim, got %v", diags)
+	}
+
+	plan.DryRun = types.BoolValue(true)
+	if diags := leaseExpiry(plan, now, 14*24*time.Hour); len(diags) != 0 {
+		t.Fatalf("expected previews to be ignored, got %v", diags)
+	}
+}
+
+func TestLeaseExpiryLowercaseTimestamp(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	plan := claimResourceModel{
+		Name:      types.StringValue("kvwus2prdatlas"),
+		ExpiresAt: types.StringValue("2024-05-08t12:00:00z"),
+	}
+	diags := leaseExpiry(plan, now, 14*24*time.Hour)
+	if diags.WarningsCount() != 1 || strings.Contains(diags[0].Detail(), "sanmarctl renew") {
+		t.Fatalf("expected a warning advising only expires_at, got %v", diags)
+	}
+	if _, diags := parseExpiresAt(plan.ExpiresAt); diags.HasError() {
+		t.Fatalf("expected a lowercased timestamp to parse, got %v", diags)
+	}
+}
+
+func TestReservationExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	plan := indexReservationResourceModel{
+		Team:      types.StringValue("atlas"),
+		Start:     types.Int64Value(10),
+		End:       types.Int64Value(19),
+		ExpiresAt: types.StringValue("2024-04-30T12:00:00Z"),
+	}
+	diags := reservationExpiry(plan, now, 72*time.Hour)
+	if diags.WarningsCount() != 1 || !strings.Contains(diags[0].Detail(), "indices 10-19 for atlas has already ended") {
+		t.Fatalf("expected a warning naming the block, got %v", diags)
+	}
+	plan.ExpiresAt = types.StringNull()
+	if diags := reservationExpiry(plan, now, 72*time.Hour); len(diags) != 0 {
+		t.Fatalf("expected no warning without an expiry, got %v", diags)
+	}
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ function.Function = (*ValidateNameFunction)(nil)
+
+// ValidateNameFunction implements provider::sanmar::validate_name.
+type ValidateNameFunction struct{}
+
+// NewValidateNameFunction returns the name linting function.
+func NewValidateNameFunction() function.Function {
+	return &ValidateNameFunction{}
+}
+
+func (f *ValidateNameFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "validate_name"
+}
+
+func (f *ValidateNameFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Lint a name against the SanMar naming convention",
+		MarkdownDescription: "Returns the ways `name` breaks the naming rules for `resource_type`: its length, characters, and the shape of the naming convention. An empty list means no violations were found. The checks run without calling the naming service, so use it in `check` blocks and `precondition`s for names supplied from outside Terraform.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "Name to check.",
+			},
+			function.StringParameter{
+				Name:                "resource_type",
+				MarkdownDescription: "Canonical resource type the name is for, such as `storage_account`. Unknown types are checked against the base rules.",
+			},
+		},
+		Return: function.ListReturn{ElementType: types.StringType},
+	}
+}
+
+func (f *ValidateNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name, resourceType string
+	resp.Error = req.Arguments.Get(ctx, &name, &resourceType)
+	if resp.Error != nil {
+		return
+	}
+
+	violations, diags := types.ListValueFrom(ctx, types.StringType, LintName(name, resourceType))
+	resp.Error = function.FuncErrorFromDiags(ctx, diags)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, violations)
+}
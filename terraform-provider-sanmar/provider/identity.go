@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// claimIdentity is the stable key of a claim in the naming service. The
+// generated name alone is not unique across regions and environments.
+type claimIdentity struct {
+	Region      string
+	Environment string
+	Name        string
+}
+
+// String formats the identity as "<region>/<environment>/<name>".
+func (i claimIdentity) String() string {
+	return i.Region + "/" + i.Environment + "/" + i.Name
+}
+
+// parseClaimIdentity parses "<region>/<environment>/<name>".
+func parseClaimIdentity(id string) (claimIdentity, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return claimIdentity{}, fmt.Errorf("expected <region>/<environment>/<name>, got %q", id)
+	}
+	return claimIdentity{Region: parts[0], Environment: parts[1], Name: parts[2]}, nil
+}
+
+// claimIdentityModel is the Terraform resource identity of a sanmar_claim.
+type claimIdentityModel struct {
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Name        types.String `tfsdk:"name"`
+}
+
+func claimIdentitySchema() identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"region": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Region code the name was claimed in.",
+			},
+			"environment": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Environment code the name was claimed in.",
+			},
+			"name": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       "Name as registered with the naming service.",
+			},
+		},
+	}
+}
+
+// setClaimIdentity records m's identity. A claim that is still pending has
+// no name yet, so its identity carries only the region and environment.
+func setClaimIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, m claimResourceModel) diag.Diagnostics {
+	if identity == nil {
+		return nil
+	}
+	model := claimIdentityModel{
+		Region:      types.StringValue(m.Region.ValueString()),
+		Environment: types.StringValue(m.Environment.ValueString()),
+		Name:        types.StringNull(),
+	}
+	if !m.ID.IsNull() && !m.ID.IsUnknown() {
+		model.Name = m.ID
+	}
+	return identity.Set(ctx, model)
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestParseClaimIdentity(t *testing.T) {
+	got, err := parseClaimIdentity("wus2/prd/kvwus2prdatlas")
+	if err != nil {
+		t.Fatalf("parseClaimIdentity: %v", err)
+	}
+	want := claimIdentity{Region: "wus2", Environment: "prd", Name: "kvwus2prdatlas"}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if got.String() != "wus2/prd/kvwus2prdatlas" {
+		t.Fatalf("unexpected string form %q", got.String())
+	}
+
+	for _, bad := range []string{"kvwus2prdatlas", "wus2//kv", "wus2/prd/kv/extra"} {
+		if _, err := parseClaimIdentity(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestImportClaimState(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit_bulk", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"results": []AuditEvent{
+			{Name: "kvwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd"},
+			{Name: "stshared", Action: "claimed", Region: "wus2", Environment: "prd"},
+			{Name: "stshared", Action: "claimed", Region: "eus", Environment: "dev"},
+		}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	var identityResp resource.IdentitySchemaResponse
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &identityResp)
+
+	importClaim := func(req resource.ImportStateRequest) (*resource.ImportStateResponse, claimIdentityModel) {
+		t.Helper()
+		resp := &resource.ImportStateResponse{
+			State:    tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)},
+			Identity: &tfsdk.ResourceIdentity{Schema: identityResp.IdentitySchema, Raw: tftypes.NewValue(identityResp.IdentitySchema.Type().TerraformType(ctx), nil)},
+		}
+		r.ImportState(ctx, req, resp)
+		var identity claimIdentityModel
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.Identity.Get(ctx, &identity)...)
+		}
+		return resp, identity
+	}
+	want := claimIdentityModel{Region: types.StringValue("wus2"), Environment: types.StringValue("prd"), Name: types.StringValue("kvwus2prdatlas")}
+
+	for _, id := range []string{"wus2/prd/kvwus2prdatlas", "KVWUS2PRDATLAS"} {
+		resp, identity := importClaim(resource.ImportStateRequest{ID: id})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("import %q: %v", id, resp.Diagnostics)
+		}
+		if identity != want {
+			t.Fatalf("import %q: unexpected identity %+v", id, identity)
+		}
+		var state claimResourceModel
+		if diags := resp.State.Get(ctx, &state); diags.HasError() {
+			t.Fatalf("import %q: %v", id, diags)
+		}
+		if state.Region.ValueString() != "wus2" || state.Environment.ValueString() != "prd" || state.ID.ValueString() != "kvwus2prdatlas" {
+			t.Fatalf("import %q: unexpected state %s/%s/%s", id, state.Region.ValueString(), state.Environment.ValueString(), state.ID.ValueString())
+		}
+	}
+
+	identity := &tfsdk.ResourceIdentity{Schema: identityResp.IdentitySchema, Raw: tftypes.NewValue(identityResp.IdentitySchema.Type().TerraformType(ctx), nil)}
+	identity.Set(ctx, want)
+	if resp, got := importClaim(resource.ImportStateRequest{Identity: identity}); resp.Diagnostics.HasError() || got != want {
+		t.Fatalf("identity import: %v %+v", resp.Diagnostics, got)
+	}
+
+	for _, id := range []string{"stshared", "kvmissing"} {
+		if resp, _ := importClaim(resource.ImportStateRequest{ID: id}); !resp.Diagnostics.HasError() {
+			t.Fatalf("expected %q to be rejected", id)
+		}
+	}
+}
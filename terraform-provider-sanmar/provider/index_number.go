@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.Int64Typable  = indexNumberType{}
+	_ basetypes.Int64Valuable = indexNumberValue{}
+)
+
+// indexNumberType stores a claim index as a number, so configurations can do
+// arithmetic on it (`effective_index + 1`) and `01` and `1` are the same
+// index, while the provider renders it as the zero-padded segment that
+// appears in names.
+type indexNumberType struct {
+	basetypes.Int64Type
+}
+
+func (t indexNumberType) Equal(o attr.Type) bool {
+	_, ok := o.(indexNumberType)
+	return ok
+}
+
+func (t indexNumberType) String() string {
+	return "indexNumberType"
+}
+
+func (t indexNumberType) ValueFromInt64(_ context.Context, in basetypes.Int64Value) (basetypes.Int64Valuable, diag.Diagnostics) {
+	return indexNumberValue{Int64Value: in}, nil
+}
+
+func (t indexNumberType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.Int64Type.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := v.(basetypes.Int64Value)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type %T", v)
+	}
+	return indexNumberValue{Int64Value: n}, nil
+}
+
+func (t indexNumberType) ValueType(context.Context) attr.Value {
+	return indexNumberValue{}
+}
+
+// indexNumberValue is a value of indexNumberType.
+type indexNumberValue struct {
+	basetypes.Int64Value
+}
+
+func (v indexNumberValue) Equal(o attr.Value) bool {
+	other, ok := o.(indexNumberValue)
+	return ok && v.Int64Value.Equal(other.Int64Value)
+}
+
+func (v indexNumberValue) Type(context.Context) attr.Type {
+	return indexNumberType{}
+}
+
+// format renders the index padded with zeros to width digits, or "" when it
+// is null or unknown.
+func (v indexNumberValue) format(width int) string {
+	if v.IsNull() || v.IsUnknown() {
+		return ""
+	}
+	return fmt.Sprintf("%0*d", width, v.ValueInt64())
+}
+
+// segment returns the index as an unpadded segment string, null or unknown
+// when v is.
+func (v indexNumberValue) segment() types.String {
+	switch {
+	case v.IsNull():
+		return types.StringNull()
+	case v.IsUnknown():
+		return types.StringUnknown()
+	}
+	return types.StringValue(strconv.FormatInt(v.ValueInt64(), 10))
+}
+
+// spelledIn returns the index the way name spells it: the longest
+// zero-padded form of it that name contains, or the unpadded number when
+// name contains none. It returns "" when v is null or unknown.
+func (v indexNumberValue) spelledIn(name string) string {
+	plain := v.segment().ValueString()
+	if plain == "" {
+		return ""
+	}
+	name = strings.ToLower(name)
+	for width := 10; width > len(plain); width-- {
+		if padded := v.format(width); strings.Contains(name, padded) {
+			return padded
+		}
+	}
+	return plain
+}
+
+// indexNumber parses an index segment such as "07". Segments that are empty
+// or not a number give null, the same as an index the name does not use.
+func indexNumber(s string) indexNumberValue {
+	if !indexPattern.MatchString(s) {
+		return indexNumberValue{Int64Value: types.Int64Null()}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return indexNumberValue{Int64Value: types.Int64Null()}
+	}
+	return indexNumberValue{Int64Value: types.Int64Value(n)}
+}
+
+// padIndex renders an index segment with width digits, so `7`, `07`, and
+// `007` reach the service the same way. Segments that are not a number are
+// returned unchanged for the service to reject.
+func padIndex(s string, width int) string {
+	v := indexNumber(s)
+	if v.IsNull() {
+		return s
+	}
+	return v.format(width)
+}
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ list.ListResource = (*ClaimListResource)(nil)
+var _ list.ListResourceWithConfigure = (*ClaimListResource)(nil)
+
+// NewClaimListResource returns the list resource `terraform query` uses to
+// find existing claims.
+func NewClaimListResource() list.ListResource {
+	return &ClaimListResource{}
+}
+
+// ClaimListResource lists the active claims within a scope as sanmar_claim
+// instances, so they can be imported in bulk.
+type ClaimListResource struct {
+	client *APIClient
+}
+
+type claimListConfigModel struct {
+	Project     types.String `tfsdk:"project"`
+	Environment types.String `tfsdk:"environment"`
+	Region      types.String `tfsdk:"region"`
+	Purpose     types.String `tfsdk:"purpose"`
+	User        types.String `tfsdk:"user"`
+}
+
+func (r *ClaimListResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim"
+}
+
+func (r *ClaimListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = listschema.Schema{
+		MarkdownDescription: "Lists the names currently claimed in the SanMar naming service, optionally filtered by scope.",
+		Attributes: map[string]listschema.Attribute{
+			"project": listschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this project.",
+			},
+			"environment": listschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this environment.",
+			},
+			"region": listschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this region.",
+			},
+			"purpose": listschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims for this purpose.",
+			},
+			"user": listschema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list claims made by this user. Listing other users' claims requires an elevated role.",
+			},
+		},
+	}
+}
+
+func (r *ClaimListResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+// List streams the active claims, which the service reconstructs from the
+// audit history as ListClaims does for the sanmar_claims data source.
+func (r *ClaimListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var result list.ListResult
+	if r.client == nil {
+		result.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		stream.Results = list.ListResultsStreamDiagnostics(result.Diagnostics)
+		return
+	}
+
+	var config claimListConfigModel
+	result.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if result.Diagnostics.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(result.Diagnostics)
+		return
+	}
+
+	claims, err := r.client.ListClaims(ctx, ClaimFilter{
+		Project:     config.Project.ValueString(),
+		Environment: config.Environment.ValueString(),
+		Region:      config.Region.ValueString(),
+		Purpose:     config.Purpose.ValueString(),
+		User:        config.User.ValueString(),
+	})
+	// List results carry no shared diagnostics, so deprecation warnings go
+	// out on a result of their own ahead of the claims.
+	r.client.reportDeprecations(&result.Diagnostics)
+	if err != nil {
+		result.Diagnostics.AddError("Failed to list claims", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(result.Diagnostics)
+		return
+	}
+
+	warnings := result.Diagnostics
+	stream.Results = func(push func(list.ListResult) bool) {
+		if len(warnings) > 0 && !push(list.ListResult{Diagnostics: warnings}) {
+			return
+		}
+		for i, claim := range claims {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+			if !push(r.listResult(ctx, req, claim)) {
+				return
+			}
+		}
+	}
+}
+
+// listResult describes one claim. Only its identity and the values the
+// audit history holds are set; importing it fills in the rest.
+func (r *ClaimListResource) listResult(ctx context.Context, req list.ListRequest, claim ClaimSummary) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = claim.Identity()
+	result.Diagnostics.Append(result.Identity.Set(ctx, claimIdentityModel{
+		Region:      types.StringValue(claim.Region),
+		Environment: types.StringValue(claim.Environment),
+		Name:        types.StringValue(claim.Name),
+	})...)
+	if !req.IncludeResource {
+		return result
+	}
+
+	values := map[string]types.String{
+		"id":            types.StringValue(claim.Name),
+		"name":          types.StringValue(claim.Name),
+		"resource_type": types.StringValue(claim.ResourceType),
+		"region":        types.StringValue(claim.Region),
+		"environment":   types.StringValue(claim.Environment),
+		"project":       optionalString(claim.Project),
+		"purpose":       optionalString(claim.Purpose),
+		"claimed_by":    types.StringValue(claim.ClaimedBy),
+	}
+	for name, value := range values {
+		result.Diagnostics.Append(result.Resource.SetAttribute(ctx, path.Root(name), value)...)
+	}
+	return result
+}
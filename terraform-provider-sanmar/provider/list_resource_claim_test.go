@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestClaimListResourceList(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit_bulk", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("environment") != "prd" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": []AuditEvent{
+			{Name: "kvwus2prdatlas", Action: "released", Region: "wus2", Environment: "prd"},
+			{Name: "stwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd", ResourceType: "storage_account", Project: "atlas"},
+			{Name: "vmwus2prdatlas", Action: "claimed", Region: "wus2", Environment: "prd", ResourceType: "virtual_machine"},
+		}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	claims := &ClaimResource{}
+	var schemaResp resource.SchemaResponse
+	claims.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	var identityResp resource.IdentitySchemaResponse
+	claims.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &identityResp)
+
+	lr := &ClaimListResource{client: client}
+	var configResp list.ListResourceSchemaResponse
+	lr.ListResourceConfigSchema(ctx, list.ListResourceSchemaRequest{}, &configResp)
+	configType := configResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	configValues := map[string]tftypes.Value{}
+	for name, typ := range configType.AttributeTypes {
+		configValues[name] = tftypes.NewValue(typ, nil)
+	}
+	configValues["environment"] = tftypes.NewValue(tftypes.String, "prd")
+
+	req := list.ListRequest{
+		Config:                 tfsdk.Config{Schema: configResp.Schema, Raw: tftypes.NewValue(configType, configValues)},
+		IncludeResource:        true,
+		ResourceSchema:         schemaResp.Schema,
+		ResourceIdentitySchema: identityResp.IdentitySchema,
+	}
+	var stream list.ListResultsStream
+	lr.List(ctx, req, &stream)
+
+	var results []list.ListResult
+	for result := range stream.Results {
+		results = append(results, result)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the two active claims, got %d", len(results))
+	}
+	first := results[0]
+	if first.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", first.Diagnostics)
+	}
+	var identity claimIdentityModel
+	first.Identity.Get(ctx, &identity)
+	if first.DisplayName != "wus2/prd/stwus2prdatlas" || identity.Name.ValueString() != "stwus2prdatlas" || identity.Region.ValueString() != "wus2" {
+		t.Fatalf("unexpected result %q, identity %+v", first.DisplayName, identity)
+	}
+	var state claimResourceModel
+	if diags := first.Resource.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("resource: %v", diags)
+	}
+	if state.ResourceType.ValueString() != "storage_account" || state.Project.ValueString() != "atlas" || !state.Purpose.IsNull() {
+		t.Fatalf("unexpected resource %+v", state)
+	}
+
+	req.Limit = 1
+	lr.List(ctx, req, &stream)
+	count := 0
+	for range stream.Results {
+		count++
+	}
+	if count != 1 {
+		t.Fatalf("expected the limit to stop the listing, got %d results", count)
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// namingManifest describes a set of resources whose names are generated
+// together. YAML is a superset of JSON, so both formats are accepted.
+type namingManifest struct {
+	Defaults  manifestEntry            `yaml:"defaults"`
+	Resources map[string]manifestEntry `yaml:"resources"`
+}
+
+// manifestEntry is one logical resource; unset segments inherit the defaults.
+type manifestEntry struct {
+	ResourceType string `yaml:"resource_type"`
+	Region       string `yaml:"region"`
+	Environment  string `yaml:"environment"`
+	Project      string `yaml:"project"`
+	Purpose      string `yaml:"purpose"`
+	System       string `yaml:"system"`
+	Subsystem    string `yaml:"subsystem"`
+	Index        string `yaml:"index"`
+	Count        *int   `yaml:"count"`
+}
+
+// manifestMaxCount bounds count so a typo cannot trigger thousands of previews.
+const manifestMaxCount = 50
+
+// parseManifest expands a manifest document into claim requests keyed by
+// logical name. Entries with a count above one expand to <key>_1..<key>_N with
+// the index segment set to 01..N; expanded keys must not clash with other keys.
+func parseManifest(doc string) (map[string]ClaimNameRequest, error) {
+	var manifest namingManifest
+	if err := yaml.Unmarshal([]byte(doc), &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if len(manifest.Resources) == 0 {
+		return nil, fmt.Errorf("manifest has no resources")
+	}
+
+	keys := make([]string, 0, len(manifest.Resources))
+	for key := range manifest.Resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	requests := make(map[string]ClaimNameRequest)
+	add := func(key, from string, request ClaimNameRequest) error {
+		if _, ok := requests[key]; ok {
+			return fmt.Errorf("resource %q: key %q is already used by another resource or count expansion", from, key)
+		}
+		requests[key] = request
+		return nil
+	}
+	for _, key := range keys {
+		entry := manifest.Resources[key].withDefaults(manifest.Defaults)
+		if entry.ResourceType == "" || entry.Region == "" || entry.Environment == "" {
+			return nil, fmt.Errorf("resource %q needs resource_type, region and environment (directly or via defaults)", key)
+		}
+
+		count := 1
+		if entry.Count != nil {
+			count = *entry.Count
+		}
+		switch {
+		case count < 1 || count > manifestMaxCount:
+			return nil, fmt.Errorf("resource %q: count must be between 1 and %d", key, manifestMaxCount)
+		case count == 1:
+			if err := add(key, key, entry.request()); err != nil {
+				return nil, err
+			}
+		default:
+			if entry.Index != "" {
+				return nil, fmt.Errorf("resource %q: index cannot be combined with count", key)
+			}
+			for i := 1; i <= count; i++ {
+				entry.Index = fmt.Sprintf("%02d", i)
+				if err := add(fmt.Sprintf("%s_%d", key, i), key, entry.request()); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return requests, nil
+}
+
+func (e manifestEntry) withDefaults(d manifestEntry) manifestEntry {
+	fill := func(v *string, def string) {
+		if *v == "" {
+			*v = def
+		}
+	}
+	fill(&e.ResourceType, d.ResourceType)
+	fill(&e.Region, d.Region)
+	fill(&e.Environment, d.Environment)
+	fill(&e.Project, d.Project)
+	fill(&e.Purpose, d.Purpose)
+	fill(&e.System, d.System)
+	fill(&e.Subsystem, d.Subsystem)
+	return e
+}
+
+func (e manifestEntry) request() ClaimNameRequest {
+	optional := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+	return ClaimNameRequest{
+		ResourceType: e.ResourceType,
+		Region:       e.Region,
+		Environment:  e.Environment,
+		Project:      optional(e.Project),
+		Purpose:      optional(e.Purpose),
+		System:       optional(e.System),
+		Subsystem:    optional(e.Subsystem),
+		Index:        optional(e.Index),
+	}
+}
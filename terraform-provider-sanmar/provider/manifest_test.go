@@ -0,0 +1,54 @@
+package provider
+
+import "testing"
+
+func TestParseManifest(t *testing.T) {
+	doc := `
+defaults:
+  region: wus2
+  environment: prd
+  project: atlas
+resources:
+  vault:
+    resource_type: key_vault
+    purpose: sec
+  web:
+    resource_type: app_service
+    environment: stg
+    count: 2
+`
+	requests, err := parseManifest(doc)
+	if err != nil {
+		t.Fatalf("parseManifest: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d: %#v", len(requests), requests)
+	}
+
+	vault := requests["vault"]
+	if vault.ResourceType != "key_vault" || vault.Region != "wus2" || *vault.Project != "atlas" || *vault.Purpose != "sec" || vault.Index != nil {
+		t.Fatalf("unexpected vault request: %#v", vault)
+	}
+
+	web2, ok := requests["web_2"]
+	if !ok || web2.Environment != "stg" || *web2.Index != "02" {
+		t.Fatalf("unexpected web_2 request: %#v", web2)
+	}
+
+	// JSON documents are accepted too.
+	if _, err := parseManifest(`{"resources": {"kv": {"resource_type": "key_vault", "region": "wus2", "environment": "dev"}}}`); err != nil {
+		t.Fatalf("parseManifest JSON: %v", err)
+	}
+
+	for name, bad := range map[string]string{
+		"empty":         `resources: {}`,
+		"missing scope": `resources: {kv: {resource_type: key_vault}}`,
+		"index+count":   `resources: {kv: {resource_type: key_vault, region: wus2, environment: dev, index: "01", count: 2}}`,
+		"zero count":    `resources: {kv: {resource_type: key_vault, region: wus2, environment: dev, count: 0}}`,
+		"key collision": `{defaults: {resource_type: key_vault, region: wus2, environment: dev}, resources: {kv: {count: 2}, kv_2: {}}}`,
+	} {
+		if _, err := parseManifest(bad); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dynamicMetadata converts a dynamic object or map into metadata values,
+// keeping numbers and booleans typed so they round-trip to the service.
+func dynamicMetadata(attrPath path.Path, value types.Dynamic) (map[string]any, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if value.IsNull() || value.IsUnknown() || value.IsUnderlyingValueNull() || value.IsUnderlyingValueUnknown() {
+		return nil, diags
+	}
+
+	var elements map[string]attr.Value
+	switch v := value.UnderlyingValue().(type) {
+	case types.Object:
+		elements = v.Attributes()
+	case types.Map:
+		elements = v.Elements()
+	default:
+		diags.AddAttributeError(attrPath, "Invalid metadata", fmt.Sprintf("expected an object or map, got %s", v.Type(nil)))
+		return nil, diags
+	}
+
+	out := make(map[string]any, len(elements))
+	for key, element := range elements {
+		converted, err := metadataScalar(element)
+		if err != nil {
+			diags.AddAttributeError(attrPath.AtMapKey(key), "Invalid metadata value", err.Error())
+			continue
+		}
+		if converted != nil {
+			out[key] = converted
+		}
+	}
+	return out, diags
+}
+
+func metadataScalar(value attr.Value) (any, error) {
+	if value.IsNull() {
+		return nil, nil
+	}
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("value must be known before apply")
+	}
+
+	switch v := value.(type) {
+	case types.Dynamic:
+		return metadataScalar(v.UnderlyingValue())
+	case types.String:
+		return v.ValueString(), nil
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.Int64:
+		return v.ValueInt64(), nil
+	case types.Float64:
+		return v.ValueFloat64(), nil
+	case types.Number:
+		return numberValue(v.ValueBigFloat()), nil
+	default:
+		return nil, fmt.Errorf("metadata values must be strings, numbers, or booleans, got %s", value.Type(nil))
+	}
+}
+
+// numberValue keeps integral numbers as integers in the JSON payload.
+func numberValue(f *big.Float) any {
+	if f.IsInt() {
+		if i, accuracy := f.Int64(); accuracy == big.Exact {
+			return i
+		}
+	}
+	out, _ := f.Float64()
+	return out
+}
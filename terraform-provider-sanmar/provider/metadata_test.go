@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDynamicMetadata(t *testing.T) {
+	obj := types.ObjectValueMust(
+		map[string]attr.Type{
+			"cost_center": types.NumberType,
+			"critical":    types.BoolType,
+			"owner":       types.StringType,
+			"ratio":       types.NumberType,
+		},
+		map[string]attr.Value{
+			"cost_center": types.NumberValue(big.NewFloat(4200)),
+			"critical":    types.BoolValue(true),
+			"owner":       types.StringValue("finops"),
+			"ratio":       types.NumberValue(big.NewFloat(0.5)),
+		},
+	)
+
+	got, diags := dynamicMetadata(path.Root("metadata_values"), types.DynamicValue(obj))
+	if diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got["cost_center"] != int64(4200) || got["critical"] != true || got["owner"] != "finops" || got["ratio"] != 0.5 {
+		t.Fatalf("unexpected metadata: %#v", got)
+	}
+
+	nested := types.ObjectValueMust(
+		map[string]attr.Type{"tags": types.ListType{ElemType: types.StringType}},
+		map[string]attr.Value{"tags": types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a")})},
+	)
+	if _, diags := dynamicMetadata(path.Root("metadata_values"), types.DynamicValue(nested)); !diags.HasError() {
+		t.Fatalf("expected nested values to be rejected")
+	}
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MetricsAddrEnv names the environment variable that enables the metrics
+// endpoint, for example SANMAR_METRICS_ADDR=127.0.0.1:9464.
+const MetricsAddrEnv = "SANMAR_METRICS_ADDR"
+
+// latencyBuckets are the histogram upper bounds, in seconds, for request latency.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type requestKey struct {
+	method string
+	code   string
+}
+
+type latencyHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// providerMetrics collects request and cache counters for the lifetime of the
+// plugin process and renders them in the Prometheus text format.
+type providerMetrics struct {
+	mu          sync.Mutex
+	requests    map[requestKey]uint64
+	latency     map[string]*latencyHistogram
+	retries     map[string]uint64
+	cacheHits   uint64
+	cacheMisses uint64
+}
+
+var metrics = newProviderMetrics()
+
+func newProviderMetrics() *providerMetrics {
+	return &providerMetrics{
+		requests: map[requestKey]uint64{},
+		latency:  map[string]*latencyHistogram{},
+		retries:  map[string]uint64{},
+	}
+}
+
+// observeRequest records one HTTP attempt. A transport error is counted with
+// the code "error".
+func (m *providerMetrics) observeRequest(method string, resp *http.Response, err error, elapsed time.Duration) {
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{method: method, code: code}]++
+
+	h, ok := m.latency[method]
+	if !ok {
+		h = &latencyHistogram{buckets: make([]uint64, len(latencyBuckets))}
+		m.latency[method] = h
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+func (m *providerMetrics) observeRetry(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[method]++
+}
+
+func (m *providerMetrics) observeReadCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+// write renders the metrics in the Prometheus text exposition format.
+func (m *providerMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP sanmar_requests_total HTTP requests sent to the naming service, including retries.")
+	fmt.Fprintln(w, "# TYPE sanmar_requests_total counter")
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].code < keys[j].code
+	})
+	for _, k := range keys {
+		fmt.Fprintf(w, "sanmar_requests_total{method=%q,code=%q} %d\n", k.method, k.code, m.requests[k])
+	}
+
+	fmt.Fprintln(w, "# HELP sanmar_request_duration_seconds Latency of requests to the naming service.")
+	fmt.Fprintln(w, "# TYPE sanmar_request_duration_seconds histogram")
+	for _, method := range sortedKeys(m.latency) {
+		h := m.latency[method]
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "sanmar_request_duration_seconds_bucket{method=%q,le=%q} %d\n", method, strconv.FormatFloat(bound, 'g', -1, 64), h.buckets[i])
+		}
+		fmt.Fprintf(w, "sanmar_request_duration_seconds_bucket{method=%q,le=\"+Inf\"} %d\n", method, h.count)
+		fmt.Fprintf(w, "sanmar_request_duration_seconds_sum{method=%q} %g\n", method, h.sum)
+		fmt.Fprintf(w, "sanmar_request_duration_seconds_count{method=%q} %d\n", method, h.count)
+	}
+
+	fmt.Fprintln(w, "# HELP sanmar_request_retries_total Requests retried after a throttling or server error.")
+	fmt.Fprintln(w, "# TYPE sanmar_request_retries_total counter")
+	for _, method := range sortedKeys(m.retries) {
+		fmt.Fprintf(w, "sanmar_request_retries_total{method=%q} %d\n", method, m.retries[method])
+	}
+
+	fmt.Fprintln(w, "# HELP sanmar_read_cache_total Claim refreshes answered from or missing the read cache.")
+	fmt.Fprintln(w, "# TYPE sanmar_read_cache_total counter")
+	fmt.Fprintf(w, "sanmar_read_cache_total{result=\"hit\"} %d\n", m.cacheHits)
+	fmt.Fprintf(w, "sanmar_read_cache_total{result=\"miss\"} %d\n", m.cacheMisses)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ServeMetrics exposes the provider metrics at /metrics on addr for the rest
+// of the process lifetime and returns the address it is listening on.
+func ServeMetrics(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics.write(w)
+	})
+
+	go func() {
+		_ = http.Serve(listener, mux)
+	}()
+	return listener.Addr(), nil
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsExposition(t *testing.T) {
+	m := newProviderMetrics()
+	m.observeRequest(http.MethodGet, &http.Response{StatusCode: http.StatusOK}, nil, 120*time.Millisecond)
+	m.observeRequest(http.MethodPost, nil, io.ErrUnexpectedEOF, time.Second)
+	m.observeRetry(http.MethodPost)
+	m.observeReadCache(true)
+	m.observeReadCache(false)
+	m.observeReadCache(true)
+
+	var out strings.Builder
+	m.write(&out)
+	text := out.String()
+
+	for _, want := range []string{
+		`sanmar_requests_total{method="GET",code="200"} 1`,
+		`sanmar_requests_total{method="POST",code="error"} 1`,
+		`sanmar_request_duration_seconds_bucket{method="GET",le="0.1"} 0`,
+		`sanmar_request_duration_seconds_bucket{method="GET",le="0.25"} 1`,
+		`sanmar_request_duration_seconds_count{method="POST"} 1`,
+		`sanmar_request_retries_total{method="POST"} 1`,
+		`sanmar_read_cache_total{result="hit"} 2`,
+		`sanmar_read_cache_total{result="miss"} 1`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in metrics output:\n%s", want, text)
+		}
+	}
+}
+
+func TestServeMetrics(t *testing.T) {
+	addr, err := ServeMetrics("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ServeMetrics: %v", err)
+	}
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "# TYPE sanmar_requests_total counter") {
+		t.Fatalf("unexpected metrics response %d: %s", resp.StatusCode, body)
+	}
+}
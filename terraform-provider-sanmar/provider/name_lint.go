@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// minNameSegments is the number of hyphen-separated parts in the base name
+// template, "{region}-{environment}-{slug}-{system}...".
+const minNameSegments = 4
+
+// LintName returns the ways name breaks the naming rules the provider knows
+// for resourceType: its length, characters, and the shape of the naming
+// convention. Unknown resource types are checked against the base rules. An
+// empty result means no violations were found; the service may still reject
+// the name for reasons that need a lookup, such as an unknown slug.
+func LintName(name, resourceType string) []string {
+	violations := []string{}
+	if name == "" {
+		return append(violations, "name is empty")
+	}
+
+	rule, ok := resourceTypeRules[resourceType]
+	if !ok {
+		rule = resourceTypeRule{MaxLength: defaultMaxNameLength}
+	}
+	label := resourceType
+	if label == "" {
+		label = "resource"
+	}
+
+	maxLength := rule.MaxLength
+	if rule.DNSLabel && dnsLabelMaxLength < maxLength {
+		maxLength = dnsLabelMaxLength
+	}
+	if len(name) > maxLength {
+		violations = append(violations, fmt.Sprintf("name is %d characters long, but %s names are limited to %d", len(name), label, maxLength))
+	}
+
+	if invalid := invalidNameCharacters(name); invalid != "" {
+		violations = append(violations, fmt.Sprintf("name contains %s; only letters, digits, and hyphens are allowed", invalid))
+	}
+	if rule.Lowercase && name != strings.ToLower(name) {
+		violations = append(violations, fmt.Sprintf("%s names must be lowercase", label))
+	}
+	if rule.DNSLabel {
+		if dns := dnsLabel(name); dns != strings.ToLower(name) {
+			violations = append(violations, fmt.Sprintf("%s names must be valid DNS labels (starting with a letter, not ending with a hyphen), but name would become %q", label, dns))
+		}
+	}
+
+	if rule.Compact {
+		if strings.Contains(name, "-") {
+			violations = append(violations, fmt.Sprintf("%s names join their segments without separators, but name contains hyphens", label))
+		}
+	} else {
+		parts := strings.Split(name, "-")
+		for _, part := range parts {
+			if part == "" {
+				violations = append(violations, "name has an empty segment (a leading, trailing, or doubled hyphen)")
+				break
+			}
+		}
+		if len(parts) < minNameSegments {
+			violations = append(violations, fmt.Sprintf("name has %d hyphen-separated segments, but the naming convention has at least %d (region, environment, slug, system)", len(parts), minNameSegments))
+		}
+	}
+	if rule.SanmarPrefix && !strings.Contains(strings.ToLower(name), "sanmar") {
+		violations = append(violations, fmt.Sprintf("%s names must include the sanmar prefix", label))
+	}
+	return violations
+}
+
+// invalidNameCharacters lists, quoted and sorted, the characters of name that
+// are not letters, digits, or hyphens, or returns "" when there are none.
+func invalidNameCharacters(name string) string {
+	seen := map[rune]bool{}
+	var invalid []string
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || seen[r] {
+			continue
+		}
+		seen[r] = true
+		invalid = append(invalid, fmt.Sprintf("%q", r))
+	}
+	sort.Strings(invalid)
+	return strings.Join(invalid, ", ")
+}
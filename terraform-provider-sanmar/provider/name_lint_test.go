@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestLintName(t *testing.T) {
+	cases := []struct {
+		name         string
+		resourceType string
+		want         []string
+	}{
+		{"wus2-prd-app-erp-01", "app_service", nil},
+		{"wus2prdstsanmarerp01", "storage_account", nil},
+		{"wus2-prd-app", "app_service", []string{"3 hyphen-separated segments"}},
+		{"wus2--prd-app-erp", "app_service", []string{"empty segment"}},
+		{"wus2_prd_app_erp", "app_service", []string{`'_'`, "1 hyphen-separated segments"}},
+		{"wus2-prd-st-erp", "storage_account", []string{"without separators", "sanmar prefix"}},
+		{"WUS2PRDSTSANMARERP01", "storage_account", []string{"must be lowercase"}},
+		{"wus2prdstsanmarerp0123456789", "storage_account", []string{"28 characters long"}},
+		{"wus2prdkvsanmarerp", "key_vault", nil},
+		{"", "key_vault", []string{"empty"}},
+	}
+
+	for _, tc := range cases {
+		got := LintName(tc.name, tc.resourceType)
+		if len(got) != len(tc.want) {
+			t.Errorf("LintName(%q, %q) = %q, want %d violations", tc.name, tc.resourceType, got, len(tc.want))
+			continue
+		}
+		for i, fragment := range tc.want {
+			if !strings.Contains(got[i], fragment) {
+				t.Errorf("LintName(%q, %q)[%d] = %q, want it to mention %q", tc.name, tc.resourceType, i, got[i], fragment)
+			}
+		}
+	}
+}
+
+func TestValidateNameFunction(t *testing.T) {
+	req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{
+		types.StringValue("wus2-prd-st-erp"),
+		types.StringValue("storage_account"),
+	})}
+	resp := &function.RunResponse{Result: function.NewResultData(types.ListUnknown(types.StringType))}
+
+	NewValidateNameFunction().Run(context.Background(), req, resp)
+	if resp.Error != nil {
+		t.Fatalf("Run: %v", resp.Error)
+	}
+
+	result, ok := resp.Result.Value().(types.List)
+	if !ok || len(result.Elements()) != 2 {
+		t.Fatalf("expected two violations, got %#v", resp.Result.Value())
+	}
+}
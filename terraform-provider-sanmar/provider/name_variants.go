@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	dnsLabelMaxLength    = 63
+	storageSafeMaxLength = 24
+)
+
+// pairedEndpoints lists, by resource type, the Azure public cloud endpoints
+// and host names derived from a resource's name, in the form the azurerm
+// provider reports them. Each format takes the lowercased name.
+var pairedEndpoints = map[string]map[string]string{
+	"storage_account": {
+		"blob":  "https://%s.blob.core.windows.net/",
+		"queue": "https://%s.queue.core.windows.net/",
+		"table": "https://%s.table.core.windows.net/",
+		"file":  "https://%s.file.core.windows.net/",
+		"dfs":   "https://%s.dfs.core.windows.net/",
+	},
+	"key_vault": {
+		"vault_uri": "https://%s.vault.azure.net/",
+		"dns_name":  "%s.vault.azure.net",
+	},
+	"container_registry": {"login_server": "%s.azurecr.io"},
+	"sql_server":         {"fqdn": "%s.database.windows.net"},
+	"app_service":        {"default_hostname": "%s.azurewebsites.net"},
+	"function_app":       {"default_hostname": "%s.azurewebsites.net"},
+}
+
+// nameEndpoints returns the endpoints a resourceType resource named name
+// gets, or an empty map for resource types without any.
+func nameEndpoints(resourceType, name string) map[string]string {
+	endpoints := make(map[string]string, len(pairedEndpoints[resourceType]))
+	for key, format := range pairedEndpoints[resourceType] {
+		endpoints[key] = fmt.Sprintf(format, strings.ToLower(name))
+	}
+	return endpoints
+}
+
+// hyphenateName splits name into the supplied segment values and joins them
+// with hyphens. Names that cannot be segmented have their existing
+// separators normalised to hyphens instead.
+func hyphenateName(name string, segments []string) string {
+	candidates := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		if seg = strings.ToLower(seg); seg != "" {
+			candidates = append(candidates, seg)
+		}
+	}
+	// Prefer the longest match so "prd" wins over "p" when both are segments.
+	sort.Slice(candidates, func(i, j int) bool { return len(candidates[i]) > len(candidates[j]) })
+
+	lower := strings.ToLower(name)
+	var parts []string
+	for i := 0; i < len(lower); {
+		if isNameSeparator(lower[i]) {
+			i++
+			continue
+		}
+		matched := ""
+		for _, seg := range candidates {
+			if strings.HasPrefix(lower[i:], seg) {
+				matched = seg
+				break
+			}
+		}
+		if matched == "" {
+			return normaliseSeparators(name)
+		}
+		parts = append(parts, name[i:i+len(matched)])
+		i += len(matched)
+	}
+	if len(parts) == 0 {
+		return normaliseSeparators(name)
+	}
+	return strings.Join(parts, "-")
+}
+
+// dnsLabel converts name into an RFC 1035 label: lowercase letters, digits,
+// and hyphens, starting with a letter, ending with a letter or digit, and at
+// most 63 characters long.
+func dnsLabel(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0:
+			b.WriteByte('-')
+		}
+	}
+	label := strings.TrimLeft(b.String(), "-0123456789")
+	label = collapseHyphens(label)
+	if len(label) > dnsLabelMaxLength {
+		label = label[:dnsLabelMaxLength]
+	}
+	return strings.TrimRight(label, "-")
+}
+
+// storageSafeName keeps only lowercase alphanumerics and truncates to the
+// 24 character limit shared by storage accounts and similar resources.
+func storageSafeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	if len(out) > storageSafeMaxLength {
+		out = out[:storageSafeMaxLength]
+	}
+	return out
+}
+
+// uniqueSuffix returns a deterministic lowercase hex hash of the inputs,
+// truncated to length characters.
+func uniqueSuffix(length int, inputs ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(inputs, "|")))
+	out := hex.EncodeToString(sum[:])
+	if length > 0 && length < len(out) {
+		out = out[:length]
+	}
+	return out
+}
+
+func isNameSeparator(c byte) bool {
+	return c == '-' || c == '_' || c == '.' || c == ' '
+}
+
+func normaliseSeparators(name string) string {
+	out := strings.Map(func(r rune) rune {
+		if r < 128 && isNameSeparator(byte(r)) {
+			return '-'
+		}
+		return r
+	}, name)
+	return strings.Trim(collapseHyphens(out), "-")
+}
+
+func collapseHyphens(s string) string {
+	for strings.Contains(s, "--") {
+		s = strings.ReplaceAll(s, "--", "-")
+	}
+	return s
+}
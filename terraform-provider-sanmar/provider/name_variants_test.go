@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHyphenateName(t *testing.T) {
+	cases := []struct {
+		name     string
+		segments []string
+		want     string
+	}{
+		{"stwus2prdatlas01", []string{"st", "wus2", "prd", "atlas", "01"}, "st-wus2-prd-atlas-01"},
+		{"kv_wus2_prd", []string{"kv", "wus2", "prd"}, "kv-wus2-prd"},
+		{"unknown_name", []string{"st"}, "unknown-name"},
+		{"ATLAS", []string{"atlas"}, "ATLAS"},
+	}
+	for _, tc := range cases {
+		if got := hyphenateName(tc.name, tc.segments); got != tc.want {
+			t.Errorf("hyphenateName(%q) = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDNSLabel(t *testing.T) {
+	if got := dnsLabel("01_AKS.wus2--prd_"); got != "aks-wus2-prd" {
+		t.Fatalf("unexpected label: %q", got)
+	}
+	long := dnsLabel(strings.Repeat("a", 62) + "-b")
+	if len(long) > dnsLabelMaxLength || strings.HasSuffix(long, "-") {
+		t.Fatalf("label not truncated safely: %q", long)
+	}
+}
+
+func TestStorageSafeName(t *testing.T) {
+	if got := storageSafeName("st-WUS2-prd-atlas-orders-01"); got != "stwus2prdatlasorders01" {
+		t.Fatalf("unexpected storage name: %q", got)
+	}
+	if got := storageSafeName(strings.Repeat("x", 30)); len(got) != storageSafeMaxLength {
+		t.Fatalf("expected %d characters, got %d", storageSafeMaxLength, len(got))
+	}
+}
+
+func TestUniqueSuffixDeterministic(t *testing.T) {
+	a := uniqueSuffix(6, "sub-1", "storage_account", "wus2", "prd")
+	b := uniqueSuffix(6, "sub-1", "storage_account", "wus2", "prd")
+	c := uniqueSuffix(6, "sub-2", "storage_account", "wus2", "prd")
+	if a != b {
+		t.Fatalf("expected stable suffix, got %q and %q", a, b)
+	}
+	if a == c {
+		t.Fatalf("expected different seeds to produce different suffixes")
+	}
+	if len(a) != 6 {
+		t.Fatalf("expected 6 characters, got %q", a)
+	}
+}
+
+func TestNameEndpoints(t *testing.T) {
+	storage := nameEndpoints("storage_account", "stsanmarwus2prdatlas")
+	if len(storage) != 5 || storage["blob"] != "https://stsanmarwus2prdatlas.blob.core.windows.net/" {
+		t.Fatalf("unexpected storage endpoints: %v", storage)
+	}
+	vault := nameEndpoints("key_vault", "KVSanmarAtlas")
+	if vault["vault_uri"] != "https://kvsanmaratlas.vault.azure.net/" || vault["dns_name"] != "kvsanmaratlas.vault.azure.net" {
+		t.Fatalf("unexpected key vault endpoints: %v", vault)
+	}
+	if other := nameEndpoints("virtual_network", "vnet-wus2-prd"); other == nil || len(other) != 0 {
+		t.Fatalf("expected an empty map for other types, got %v", other)
+	}
+}
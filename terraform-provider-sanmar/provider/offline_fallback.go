@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// fallbackOffline is the fallback value that generates names locally when
+// the service cannot be reached.
+const fallbackOffline = "offline"
+
+// Name templates mirroring rules/base.json and the overlays for resource
+// types whose names join their segments without separators.
+const (
+	baseNameTemplate    = "{region}-{environment}-{slug}-{system}{subsystem_segment}{index_segment}"
+	compactNameTemplate = "{region}{environment}{slug}{sanmar_prefix}{system}{subsystem}{index}"
+)
+
+// offlineGeneratedAtKey is the metadata key that records on the service's
+// audit record when a name registered after the fact was generated offline.
+const offlineGeneratedAtKey = "sanmar_offline_generated_at"
+
+// defaultIndexWidth is how many digits claim indices are padded to when
+// index_width is unset.
+const defaultIndexWidth = 2
+
+var repeatedHyphens = regexp.MustCompile(`-{2,}`)
+
+// renderNameTemplate fills template the way the service does: optional
+// segments also have a "<segment>_segment" form with a leading hyphen, stray
+// hyphens are removed, and the name is lowercased.
+func renderNameTemplate(template string, values map[string]string, sanmarPrefix bool) string {
+	rendered := placeholderPattern.ReplaceAllStringFunc(normalizeTemplate(template), func(match string) string {
+		name := strings.Trim(match, "{}")
+		if segment := strings.TrimSuffix(name, "_segment"); segment != name {
+			if v := values[segment]; v != "" {
+				return "-" + v
+			}
+			return ""
+		}
+		if name == "sanmar_prefix" {
+			if sanmarPrefix {
+				return "sanmar"
+			}
+			return ""
+		}
+		return values[name]
+	})
+	name := strings.ToLower(repeatedHyphens.ReplaceAllString(strings.Trim(rendered, "-"), "-"))
+	if sanmarPrefix && !strings.Contains(template, "{sanmar_prefix}") && !strings.HasPrefix(name, "sanmar") {
+		name = "sanmar-" + name
+	}
+	return name
+}
+
+// claimIndexWidth is the number of digits claim indices are padded to, so an
+// index renders the same way online, offline, and when it is registered.
+func (c *APIClient) claimIndexWidth() int {
+	if c.indexWidth > 0 {
+		return c.indexWidth
+	}
+	return defaultIndexWidth
+}
+
+// offlineClaim generates the name the service would give plan, without
+// asking it. The slug comes from offline_slugs, and claims without an index
+// take index 1, since the indices in use are unknown. Without index_reuse the
+// service allocates no index either, so such a name is registered as index 1
+// even if another claim already holds it. Claims that leave their index to
+// index_reuse are refused, since the service would pick a different one.
+func (c *APIClient) offlineClaim(plan claimResourceModel) (*ClaimNameResponse, error) {
+	resourceType := plan.ResourceType.ValueString()
+	if !plan.Suffix.IsNull() && plan.Suffix.ValueString() != "" {
+		return nil, errors.New("names with a unique suffix cannot be generated offline")
+	}
+	if !plan.Template.IsNull() && plan.Template.ValueString() != "" {
+		return nil, errors.New("names with a custom template cannot be generated offline")
+	}
+	slug := c.offlineSlugs[resourceType]
+	if slug == "" {
+		return nil, fmt.Errorf("the provider's offline_slugs has no slug for %s", resourceType)
+	}
+
+	index := plan.resolveSegments().Index
+	if index.IsNull() && (!plan.IndexReuse.IsNull() || c.indexReuse != "") {
+		return nil, errors.New("names whose index the service allocates with index_reuse cannot be generated offline")
+	}
+	if index.IsNull() {
+		index = indexNumberValue{Int64Value: types.Int64Value(1)}
+	}
+	return c.renderClaim(plan, slug, nil, index)
+}
+
+// renderClaim renders the name plan would get with slug and index, filling
+// the segments plan leaves unset from defaults as the service does with the
+// slug table's defaults.
+func (c *APIClient) renderClaim(plan claimResourceModel, slug string, defaults map[string]string, index indexNumberValue) (*ClaimNameResponse, error) {
+	resourceType := plan.ResourceType.ValueString()
+	resolved := plan.resolveSegments()
+	values := map[string]string{
+		"region":      normalizeSegment(segmentRegion, plan.Region.ValueString()),
+		"environment": normalizeSegment(segmentEnvironment, plan.Environment.ValueString()),
+		"slug":        slug,
+		"project":     resolved.Project.ValueString(),
+		"purpose":     resolved.Purpose.ValueString(),
+		"system":      resolved.System.ValueString(),
+		"subsystem":   resolved.Subsystem.ValueString(),
+		"index":       index.format(c.claimIndexWidth()),
+	}
+	for _, segment := range []string{"project", "purpose", "system", "subsystem"} {
+		if values[segment] == "" {
+			values[segment] = defaults[segment]
+		}
+	}
+
+	name := renderNameTemplate(plan.nameTemplate(), values, resourceTypeRules[resourceType].SanmarPrefix)
+	if violations := LintName(name, resourceType); len(violations) > 0 {
+		return nil, fmt.Errorf("the generated name %q breaks the naming convention: %s", name, strings.Join(violations, "; "))
+	}
+
+	return &ClaimNameResponse{
+		Name:         name,
+		ResourceType: resourceType,
+		Region:       values["region"],
+		Environment:  values["environment"],
+		Slug:         slug,
+		Project:      values["project"],
+		Purpose:      values["purpose"],
+		System:       values["system"],
+		Subsystem:    values["subsystem"],
+		Index:        values["index"],
+	}, nil
+}
+
+// RegisterOfflineName claims a name that was generated offline and has been
+// in use since generatedAt, recording that time in the claim's metadata so
+// the service's audit record shows the name predates its claim. The local
+// audit log records it as a registration rather than a claim.
+func (c *APIClient) RegisterOfflineName(ctx context.Context, payload ClaimNameRequest, generatedAt string) (*ClaimNameResponse, error) {
+	if generatedAt != "" {
+		metadata := make(map[string]any, len(payload.Metadata)+1)
+		for k, v := range payload.Metadata {
+			metadata[k] = v
+		}
+		metadata[offlineGeneratedAtKey] = generatedAt
+		payload.Metadata = metadata
+	}
+	return c.recordedClaim(ctx, "register", payload)
+}
+
+// claimOffline generates plan's name locally when its fallback is "offline"
+// and claimErr shows the claim never reached the service after retries. A
+// claim that may have reached it, such as one that timed out, is left to the
+// pending claim path instead, since the service may have given it a name. It
+// returns nil when the fallback does not apply or the name cannot be
+// generated, after adding a warning explaining why.
+func (r *ClaimResource) claimOffline(ctx context.Context, plan claimResourceModel, claimErr error, diags *diag.Diagnostics) *ClaimNameResponse {
+	if plan.Fallback.ValueString() != fallbackOffline || plan.DryRun.ValueBool() || ctx.Err() != nil || !requestNeverSent(claimErr) {
+		return nil
+	}
+
+	claim, err := r.client.offlineClaim(plan)
+	if err != nil {
+		diags.AddAttributeWarning(path.Root("fallback"), "Offline fallback not used",
+			fmt.Sprintf("The naming service could not be reached, and the name could not be generated offline: %s.", err))
+		return nil
+	}
+	tflog.Warn(ctx, "naming service unreachable, generated name offline", map[string]any{
+		"name":  claim.Name,
+		"error": claimErr.Error(),
+	})
+	r.client.auditLog.record(ctx, auditLogEntry{
+		Action:       "offline_claim",
+		Name:         claim.Name,
+		ResourceType: claim.ResourceType,
+		Region:       claim.Region,
+		Environment:  claim.Environment,
+		Reason:       claimErr.Error(),
+	}, nil)
+	diags.AddWarning("Name generated offline",
+		fmt.Sprintf("The naming service could not be reached (%s), so %s was generated locally and is not yet registered. It is registered on the next refresh that reaches the service; until then another claim could be given the same name.", claimErr, claim.Name))
+	return claim
+}
+
+// registerOfflineClaim reconciles a claim whose name was generated offline:
+// once the service can be reached, it claims the name with the claim's saved
+// idempotency key, so retries are safe, and clears pending_registration. The
+// claim stays pending while the service is unreachable, and fails refresh
+// when the service would give the claim a different name.
+func (r *ClaimResource) registerOfflineClaim(ctx context.Context, state claimResourceModel, req resource.ReadRequest, resp *resource.ReadResponse) {
+	payload, diags := buildClaimPayload(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !state.EffectiveIndex.IsNull() {
+		index := state.EffectiveIndex.format(r.client.claimIndexWidth())
+		payload.Index = &index
+	}
+
+	attempt, ok := loadClaimAttempt(ctx, req.Private)
+	if !ok {
+		var err error
+		if attempt, err = newClaimAttempt(); err != nil {
+			resp.Diagnostics.AddError("Failed to register offline name", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(attempt.save(ctx, resp.Private)...)
+	}
+
+	name := state.Name.ValueString()
+	tflog.Info(ctx, "registering offline name via SanMar provider", map[string]any{
+		"name":           name,
+		"correlation_id": attempt.CorrelationID,
+	})
+	claim, err := r.client.RegisterOfflineName(WithClaimPriority(ctx, state.Priority.ValueInt64()), attempt.apply(payload), attempt.OfflineAt)
+	if err != nil {
+		if claimOutcomeUnknown(err) {
+			resp.Diagnostics.AddWarning("Offline name not registered yet",
+				fmt.Sprintf("%s was generated offline and the naming service still could not be reached. Registration is retried on the next refresh: %s", name, err))
+			return
+		}
+		addClaimError(&resp.Diagnostics, "Failed to register offline name", err)
+		return
+	}
+
+	if !strings.EqualFold(claim.Name, state.ID.ValueString()) {
+		// The claim was made for this resource, so it is released again
+		// rather than left behind.
+		releaseErr := r.client.EnsureReleased(ctx, ReleaseRequest{
+			Name:        claim.Name,
+			Region:      state.Region.ValueString(),
+			Environment: state.Environment.ValueString(),
+			Reason:      "Released after it did not match the name generated offline",
+		})
+		detail := fmt.Sprintf("%s was generated offline, but the naming service gives this claim %s, for example because the index or slug differs or the name is taken. Set index to claim the offline name, or taint the claim to rename the resource.", name, claim.Name)
+		if releaseErr != nil {
+			detail += fmt.Sprintf(" %s could not be released: %s", claim.Name, releaseErr)
+		}
+		resp.Diagnostics.AddError("Offline name could not be registered", detail)
+
+		// The service would answer the same key with the released name, so
+		// the next attempt needs a new one.
+		if next, err := newClaimAttempt(); err == nil {
+			next.OfflineAt = attempt.OfflineAt
+			resp.Diagnostics.Append(next.save(ctx, resp.Private)...)
+		}
+		return
+	}
+
+	r.applyClaim(&state, claim, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	attempt.OfflineAt = ""
+	resp.Diagnostics.Append(attempt.save(ctx, resp.Private)...)
+	resp.Diagnostics.Append(markClaimVerified(ctx, resp.Private, time.Now())...)
+	tflog.Info(ctx, "registered offline name", map[string]any{"name": claim.Name})
+}
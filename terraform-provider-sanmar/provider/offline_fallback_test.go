@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestOfflineClaim(t *testing.T) {
+	client := &APIClient{offlineSlugs: map[string]string{"storage_account": "st", "web_app": "app"}}
+	claim := func(resourceType, system, index string) claimResourceModel {
+		m := claimResourceModel{
+			ResourceType: types.StringValue(resourceType),
+			Region:       regionType.value("WUS2"),
+			Environment:  environmentType.value("prd"),
+			System:       types.StringValue(system),
+			Index:        indexNumber(""),
+		}
+		if index != "" {
+			m.Index = indexNumber(index)
+		}
+		return m
+	}
+
+	cases := []struct {
+		plan claimResourceModel
+		want string
+	}{
+		{claim("storage_account", "erp", "3"), "wus2prdstsanmarerp03"},
+		{claim("web_app", "erp", ""), "wus2-prd-app-erp-01"},
+	}
+	for _, tc := range cases {
+		got, err := client.offlineClaim(tc.plan)
+		if err != nil {
+			t.Fatalf("offlineClaim: %v", err)
+		}
+		if got.Name != tc.want {
+			t.Errorf("expected %s, got %s", tc.want, got.Name)
+		}
+	}
+
+	if _, err := client.offlineClaim(claim("key_vault", "erp", "")); err == nil || !strings.Contains(err.Error(), "offline_slugs") {
+		t.Fatalf("expected a missing slug error, got %v", err)
+	}
+	reuse := claim("web_app", "erp", "")
+	reuse.IndexReuse = types.StringValue("never")
+	if _, err := client.offlineClaim(reuse); err == nil || !strings.Contains(err.Error(), "index_reuse") {
+		t.Fatalf("expected claims with index_reuse to be refused, got %v", err)
+	}
+	// Offline names must still follow the convention.
+	if _, err := client.offlineClaim(claim("storage_account", "enterpriseresourceplanning", "")); err == nil {
+		t.Fatalf("expected an over-long storage account name to be refused")
+	}
+}
+
+func TestClaimOffline(t *testing.T) {
+	r := &ClaimResource{client: &APIClient{offlineSlugs: map[string]string{"web_app": "app"}}}
+	plan := claimResourceModel{
+		ResourceType: types.StringValue("web_app"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		System:       types.StringValue("erp"),
+		Index:        indexNumber(""),
+	}
+	unreachable := &requestNotSentError{err: errors.New("dial tcp: connection refused")}
+
+	var diags diag.Diagnostics
+	if claim := r.claimOffline(context.Background(), plan, unreachable, &diags); claim != nil {
+		t.Fatalf("expected no fallback without fallback set, got %s", claim.Name)
+	}
+
+	plan.Fallback = types.StringValue(fallbackOffline)
+	if claim := r.claimOffline(context.Background(), plan, &APIError{StatusCode: http.StatusConflict}, &diags); claim != nil {
+		t.Fatalf("expected no fallback when the service answered, got %s", claim.Name)
+	}
+	if claim := r.claimOffline(context.Background(), plan, errors.New("context deadline exceeded"), &diags); claim != nil {
+		t.Fatalf("expected no fallback for a claim that may have reached the service, got %s", claim.Name)
+	}
+	claim := r.claimOffline(context.Background(), plan, unreachable, &diags)
+	if claim == nil || claim.Name != "wus2-prd-app-erp-01" {
+		t.Fatalf("expected an offline name, got %+v", claim)
+	}
+	if diags.WarningsCount() != 1 {
+		t.Fatalf("expected a warning about the offline name, got %v", diags)
+	}
+}
+
+func TestRegisterOfflineName(t *testing.T) {
+	var sent ClaimNameRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&sent)
+		_ = json.NewEncoder(w).Encode(ClaimNameResponse{Name: "wus2-prd-app-erp-01"})
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	logPath := filepath.Join(t.TempDir(), "sanmar-audit.jsonl")
+	if err := client.EnableAuditLog(logPath); err != nil {
+		t.Fatalf("EnableAuditLog: %v", err)
+	}
+
+	payload := ClaimNameRequest{ResourceType: "web_app", Region: "wus2", Environment: "prd", Metadata: map[string]any{"owner": "ops"}}
+	if _, err := client.RegisterOfflineName(context.Background(), payload, "2024-05-01T12:00:00Z"); err != nil {
+		t.Fatalf("RegisterOfflineName: %v", err)
+	}
+	if sent.Metadata[offlineGeneratedAtKey] != "2024-05-01T12:00:00Z" || sent.Metadata["owner"] != "ops" {
+		t.Fatalf("expected the generation time alongside the claim's metadata, got %v", sent.Metadata)
+	}
+	if _, ok := payload.Metadata[offlineGeneratedAtKey]; ok {
+		t.Fatalf("expected the caller's metadata to be left unchanged")
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	var entry auditLogEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Action != "register" || entry.Name != "wus2-prd-app-erp-01" {
+		t.Fatalf("expected a register entry, got %s (%v)", data, err)
+	}
+}
+
+func TestRequestNeverSent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+	}))
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	client.SetAttemptTimeout(10 * time.Millisecond)
+
+	// A claim that timed out may have been made.
+	_, err = client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "web_app", Region: "wus2", Environment: "prd"})
+	if err == nil || requestNeverSent(err) {
+		t.Fatalf("expected a timeout that may have reached the service, got %v", err)
+	}
+
+	// A refused connection never reached it.
+	srv.Close()
+	_, err = client.ClaimName(context.Background(), ClaimNameRequest{ResourceType: "web_app", Region: "wus2", Environment: "prd"})
+	if !requestNeverSent(err) {
+		t.Fatalf("expected a refused connection to be reported as never sent, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// plannedNames remembers the claims planned by this provider instance whose
+// name is decided by their segments alone, so a second claim in the same plan
+// that would produce the same name fails at plan time instead of with a
+// conflict part way through apply.
+type plannedNames struct {
+	mu     sync.Mutex
+	claims map[string]plannedName
+}
+
+// plannedName is a claim recorded in plannedNames.
+type plannedName struct {
+	description string
+	// claims is false for claims that keep a name they already hold.
+	claims bool
+	// replacing is set for claims planned for replacement until Terraform
+	// plans the replacement, which it does without prior state.
+	replacing bool
+}
+
+// reserve records claim under key and returns the claim already recorded
+// there, if any. A new claim takes over the key of a claim being replaced,
+// since it is that claim's replacement.
+func (p *plannedNames) reserve(key string, claim plannedName, create bool) (plannedName, bool) {
+	if p == nil {
+		return plannedName{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.claims[key]; ok {
+		if create && existing.replacing {
+			existing.replacing = false
+			p.claims[key] = existing
+			return plannedName{}, false
+		}
+		return existing, true
+	}
+	if p.claims == nil {
+		p.claims = map[string]plannedName{}
+	}
+	p.claims[key] = claim
+	return plannedName{}, false
+}
+
+// plannedNameKey returns the segments that decide m's name, normalised the
+// way the service compares them. Claims without an explicit index are left
+// out (ok is false), since the service gives each of them its own index, as
+// are claims whose segments are still unknown.
+func plannedNameKey(m claimResourceModel) (string, bool) {
+	resolved := m.resolveSegments()
+	if resolved.Index.IsNull() || resolved.Index.IsUnknown() {
+		return "", false
+	}
+
+	for _, v := range []attr.Value{m.ResourceType, m.Region, m.Environment, resolved.Project, resolved.Purpose, resolved.System, resolved.Subsystem, m.Suffix, m.Template} {
+		if v.IsUnknown() {
+			return "", false
+		}
+	}
+	parts := []string{
+		m.ResourceType.ValueString(),
+		normalizeSegment(segmentRegion, m.Region.ValueString()),
+		normalizeSegment(segmentEnvironment, m.Environment.ValueString()),
+		strings.ToLower(resolved.Project.ValueString()),
+		strings.ToLower(resolved.Purpose.ValueString()),
+		strings.ToLower(resolved.System.ValueString()),
+		strings.ToLower(resolved.Subsystem.ValueString()),
+		resolved.Index.segment().ValueString(),
+		m.Suffix.ValueString(),
+		m.Template.ValueString(),
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// describePlannedName summarises the segments of m for diagnostics, since
+// providers are not told resource addresses.
+func describePlannedName(m claimResourceModel) string {
+	resolved := m.resolveSegments()
+	description := fmt.Sprintf("a %s claim in %s/%s", m.ResourceType.ValueString(), m.Region.ValueString(), m.Environment.ValueString())
+	var segments []string
+	for _, name := range []string{"project", "purpose", "system", "subsystem", "index"} {
+		if v := resolved.segmentValues()[name]; !v.IsNull() {
+			segments = append(segments, fmt.Sprintf("%s %q", name, v.ValueString()))
+		}
+	}
+	if len(segments) > 0 {
+		description += " with " + strings.Join(segments, ", ")
+	}
+	return description
+}
+
+// checkPlannedNameConflict fails plan when another claim planned in this run
+// would produce the same name. Claims that only keep names they already hold
+// are recorded but never fail against each other, and a replacement never
+// fails against the claim it replaces.
+func (r *ClaimResource) checkPlannedNameConflict(ctx context.Context, state tfsdk.State, plan claimResourceModel, retired bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if r.client == nil || plan.DryRun.ValueBool() || plan.DryRun.IsUnknown() {
+		return diags
+	}
+	key, ok := plannedNameKey(plan)
+	if !ok {
+		return diags
+	}
+
+	create := state.Raw.IsNull()
+	claims, replacing := create || retired, retired
+	if !create {
+		var prior claimResourceModel
+		if d := state.Get(ctx, &prior); d.HasError() {
+			return diags
+		}
+		// Changed keepers replace the claim, like retirement.
+		replacing = replacing || !plan.Keepers.Equal(prior.Keepers)
+		priorKey, ok := plannedNameKey(prior)
+		claims = claims || replacing || !ok || priorKey != key
+	}
+
+	description := describePlannedName(plan)
+	existing, taken := r.client.plannedNames.reserve(key, plannedName{description: description, claims: claims, replacing: replacing}, create)
+	if !taken || (!claims && !existing.claims) {
+		return diags
+	}
+	diags.AddAttributeError(plan.segmentPath("index"), "Duplicate name in plan",
+		fmt.Sprintf("This claim (%s) would produce the same name as another claim in this plan (%s), so one of them would fail to claim it during apply. Change a segment, such as index, on one of them.", description, existing.description))
+	return diags
+}
@@ -0,0 +1,186 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestCheckPlannedNameConflict(t *testing.T) {
+	ctx := context.Background()
+	r := &ClaimResource{client: &APIClient{plannedNames: &plannedNames{}}}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	newClaim := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	claim := func(region, index string) claimResourceModel {
+		m := claimResourceModel{
+			ResourceType: types.StringValue("storage_account"),
+			Region:       regionType.value(region),
+			Environment:  environmentType.value("prd"),
+			Project:      types.StringValue("atlas"),
+			Index:        indexNumber(""),
+		}
+		if index != "" {
+			m.Index = indexNumber(index)
+		}
+		return m
+	}
+
+	if diags := r.checkPlannedNameConflict(ctx, newClaim, claim("wus2", "01"), false); diags.HasError() {
+		t.Fatalf("unexpected diagnostics for the first claim: %v", diags)
+	}
+	// Spelled differently, the same segments still produce the same name.
+	diags := r.checkPlannedNameConflict(ctx, newClaim, claim("WUS2", "1"), false)
+	if !diags.HasError() || !strings.Contains(diags.Errors()[0].Detail(), "in wus2/prd") {
+		t.Fatalf("expected a duplicate name error describing the first claim, got %v", diags)
+	}
+
+	// Another index, and claims without an index, are fine.
+	for _, m := range []claimResourceModel{claim("wus2", "02"), claim("wus2", ""), claim("wus2", "")} {
+		if diags := r.checkPlannedNameConflict(ctx, newClaim, m, false); diags.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", diags)
+		}
+	}
+
+	dryRun := claim("wus2", "02")
+	dryRun.DryRun = types.BoolValue(true)
+	if diags := r.checkPlannedNameConflict(ctx, newClaim, dryRun, false); diags.HasError() {
+		t.Fatalf("expected dry runs to be ignored, got %v", diags)
+	}
+}
+
+func TestModifyPlanReplacementDoesNotConflictWithItself(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	keepers := func(value string) types.Map {
+		return types.MapValueMust(types.StringType, map[string]attr.Value{"image": types.StringValue(value)})
+	}
+	configured := func(keeper string) claimResourceModel {
+		m := nullClaimModel(ctx, t, s)
+		m.ResourceType = types.StringValue("storage_account")
+		m.Region = regionType.value("wus2")
+		m.Environment = environmentType.value("prd")
+		m.Project = types.StringValue("atlas")
+		m.System = types.StringValue("erp")
+		m.Index = indexNumber("01")
+		m.Keepers = keepers(keeper)
+		return m
+	}
+
+	prior := configured("v1")
+	prior.ID = types.StringValue("stwus2prdatlas01")
+	prior.Name = types.StringValue("stwus2prdatlas01")
+	prior.DryRun = types.BoolValue(false)
+	prior.Retired = types.BoolValue(false)
+	state := tfsdk.State{Schema: s}
+	if diags := state.Set(ctx, &prior); diags.HasError() {
+		t.Fatalf("state: %v", diags)
+	}
+	modifyPlan := func(state tfsdk.State, m claimResourceModel) diag.Diagnostics {
+		cfg := tfsdk.State{Schema: s}
+		if diags := cfg.Set(ctx, &m); diags.HasError() {
+			t.Fatalf("config: %v", diags)
+		}
+		plan := tfsdk.Plan{Schema: s, Raw: cfg.Raw}
+		resp := resource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, resource.ModifyPlanRequest{Config: tfsdk.Config{Schema: s, Raw: cfg.Raw}, Plan: plan, State: state}, &resp)
+		return resp.Diagnostics
+	}
+
+	// Changing keepers replaces the claim: Terraform plans it against the
+	// prior state, then again without prior state for the replacement.
+	if diags := modifyPlan(state, configured("v2")); diags.HasError() {
+		t.Fatalf("unexpected diagnostics planning the update: %v", diags)
+	}
+	newClaim := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	if diags := modifyPlan(newClaim, configured("v2")); diags.HasError() {
+		t.Fatalf("unexpected diagnostics planning the replacement: %v", diags)
+	}
+
+	// Another new claim with the same segments is still a duplicate.
+	diags := modifyPlan(newClaim, configured("v2"))
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Duplicate name in plan" {
+		t.Fatalf("expected a duplicate name error, got %v", diags)
+	}
+}
+
+// nullClaimModel returns a claim model with every attribute null.
+func nullClaimModel(ctx context.Context, t *testing.T, s schema.Schema) claimResourceModel {
+	t.Helper()
+	var m claimResourceModel
+	state := tfsdk.State{Schema: s, Raw: nullObject(s.Type().TerraformType(ctx).(tftypes.Object))}
+	if diags := state.Get(ctx, &m); diags.HasError() {
+		t.Fatalf("null model: %v", diags)
+	}
+	return m
+}
+
+// nullObject returns an object of typ whose attributes are all null.
+func nullObject(typ tftypes.Object) tftypes.Value {
+	values := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+	for name, attrType := range typ.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	return tftypes.NewValue(typ, values)
+}
+
+func TestValidateProject(t *testing.T) {
+	ctx := context.Background()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/projects", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"projects":[{"code":"atlas","owner":"platform"}]}`))
+	})
+	mux.HandleFunc("/api/projects/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Project not found.", http.StatusNotFound)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+	claim := func(project string) claimResourceModel {
+		return claimResourceModel{ResourceType: types.StringValue("key_vault"), Project: types.StringValue(project)}
+	}
+
+	if diags := r.validateProject(ctx, tfsdk.State{}, claim("Atlas"), true); len(diags) != 0 {
+		t.Fatalf("expected a registered project to pass, got %v", diags)
+	}
+	// While planning, an unregistered project may still be registered by a
+	// sanmar_project resource that has not been planned yet.
+	diags := r.validateProject(ctx, tfsdk.State{}, claim("orion"), false)
+	if diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a warning while planning, got %v", diags)
+	}
+	client.addPlannedProject("orion")
+	if diags := r.validateProject(ctx, tfsdk.State{}, claim("orion"), false); len(diags) != 0 {
+		t.Fatalf("expected a planned project to pass, got %v", diags)
+	}
+	// Applying looks the project up again and refuses it while it is missing.
+	diags = r.validateProject(ctx, tfsdk.State{}, claim("orion"), true)
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Unknown project" {
+		t.Fatalf("expected an unregistered project to fail when applying, got %v", diags)
+	}
+}
@@ -0,0 +1,331 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Actions recorded in the plan report.
+const (
+	planReportClaim   = "claim"
+	planReportRelease = "release"
+	planReportReplace = "replace"
+)
+
+// planReportChange is one name a run would claim or release.
+type planReportChange struct {
+	Action string `json:"action"`
+	// Name is the name that would be claimed, or released for a release.
+	// Names the service previews at plan time may still change at apply if
+	// another claim takes the index first.
+	Name         string            `json:"name,omitempty"`
+	PreviousName string            `json:"previous_name,omitempty"`
+	ResourceType string            `json:"resource_type"`
+	Region       string            `json:"region"`
+	Environment  string            `json:"environment"`
+	Segments     map[string]string `json:"segments"`
+	// SessionID is the session of the provider configuration that planned
+	// the change, which tells aliased providers apart.
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// planReportDocument is the JSON file written to plan_report_path.
+type planReportDocument struct {
+	GeneratedAt time.Time   `json:"generated_at"`
+	Run         auditLogRun `json:"run"`
+	// TerraformPID and Writers identify the run that wrote the report.
+	// Aliased providers are separate processes of the same Terraform process,
+	// so they merge their changes into one report while any of them is still
+	// running.
+	TerraformPID int                `json:"terraform_pid"`
+	Writers      []int              `json:"writers"`
+	Changes      []planReportChange `json:"changes"`
+}
+
+// planReportLockTimeout is how long a write waits for another provider
+// process to finish writing the report, and how old a lock file must be
+// before it is treated as left behind by a process that died.
+const planReportLockTimeout = 10 * time.Second
+
+// planReport merges the name changes planned by this provider instance into
+// the report file as each one is added.
+type planReport struct {
+	mu        sync.Mutex
+	path      string
+	run       auditLogRun
+	sessionID string
+	pid       int
+	parentPID int
+	alive     func(int) bool
+	now       func() time.Time
+}
+
+// openPlanReport joins the report at path, replacing it when it is from an
+// earlier run.
+func openPlanReport(path, sessionID string) (*planReport, error) {
+	report := &planReport{
+		path:      path,
+		run:       currentRun(),
+		sessionID: sessionID,
+		pid:       os.Getpid(),
+		parentPID: os.Getppid(),
+		alive:     processAlive,
+		now:       time.Now,
+	}
+	if err := report.update(nil); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// record adds change to the report. Write failures are logged rather than
+// failing the plan, which the report only describes.
+func (r *planReport) record(ctx context.Context, change planReportChange) {
+	if r == nil {
+		return
+	}
+
+	change.SessionID = r.sessionID
+	if err := r.update(&change); err != nil {
+		tflog.Warn(ctx, "failed to write plan report", map[string]any{"error": err.Error()})
+	}
+}
+
+// update adds change, if any, to the report file while holding its lock, so
+// the changes of every provider process in the run are kept.
+func (r *planReport) update(change *planReportChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := lockPlanReport(r.path, r.now)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	doc := r.read()
+	if !slices.Contains(doc.Writers, r.pid) {
+		doc.Writers = append(doc.Writers, r.pid)
+	}
+	if change != nil {
+		doc.Changes = append(doc.Changes, *change)
+	}
+	return r.write(doc)
+}
+
+// read returns the report of the current run, or an empty one when the file
+// is missing, unreadable, or from an earlier run or phase of Terraform.
+func (r *planReport) read() planReportDocument {
+	fresh := planReportDocument{Run: r.run, TerraformPID: r.parentPID, Changes: []planReportChange{}}
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fresh
+	}
+	var doc planReportDocument
+	if err := json.Unmarshal(data, &doc); err != nil || doc.TerraformPID != r.parentPID {
+		return fresh
+	}
+	if !slices.ContainsFunc(doc.Writers, func(pid int) bool { return pid == r.pid || r.alive(pid) }) {
+		return fresh
+	}
+	return doc
+}
+
+// lockPlanReport takes the lock file next to the report, waiting while
+// another provider process holds it.
+func lockPlanReport(path string, now func() time.Time) (func(), error) {
+	lock := path + ".lock"
+	deadline := now().Add(planReportLockTimeout)
+	for {
+		file, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock plan report: %w", err)
+		}
+		if info, statErr := os.Stat(lock); statErr == nil && now().Sub(info.ModTime()) > planReportLockTimeout {
+			os.Remove(lock)
+			continue
+		}
+		if now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock plan report: %s is held by another process", lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// write replaces the report file through a rename so readers never see a
+// partial document. Callers must hold the report lock.
+func (r *planReport) write(doc planReportDocument) error {
+	doc.GeneratedAt = r.now().UTC()
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan report: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write plan report: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write plan report: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write plan report: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("failed to write plan report: %w", err)
+	}
+	return nil
+}
+
+// EnablePlanReport writes the names each plan would claim or release to a
+// JSON file at path.
+func (c *APIClient) EnablePlanReport(path string) error {
+	report, err := openPlanReport(path, c.sessionID)
+	if err != nil {
+		return err
+	}
+	c.planReport = report
+	return nil
+}
+
+// reportPlannedChange records in the plan report the name the planned change
+// from state to plan claims or releases. Claims that keep their name, dry
+// runs, and retired claims are left out.
+func (r *ClaimResource) reportPlannedChange(ctx context.Context, state tfsdk.State, plan tfsdk.Plan) {
+	if r.client == nil || r.client.planReport == nil {
+		return
+	}
+
+	var prior claimResourceModel
+	holdsName := false
+	if !state.Raw.IsNull() {
+		if diags := state.Get(ctx, &prior); diags.HasError() {
+			return
+		}
+		holdsName = !prior.Name.IsNull() && prior.Name.ValueString() != "" && !prior.DryRun.ValueBool() && !prior.Retired.ValueBool()
+	}
+
+	if plan.Raw.IsNull() {
+		if holdsName {
+			change := newPlanReportChange(planReportRelease, prior)
+			change.Name = prior.Name.ValueString()
+			r.client.planReport.record(ctx, change)
+		}
+		return
+	}
+
+	var planned claimResourceModel
+	if diags := plan.Get(ctx, &planned); diags.HasError() {
+		return
+	}
+	if planned.DryRun.ValueBool() || (!state.Raw.IsNull() && !planned.Name.IsUnknown()) {
+		return
+	}
+
+	change := newPlanReportChange(planReportClaim, planned)
+	if holdsName {
+		change.Action = planReportReplace
+		change.PreviousName = prior.Name.ValueString()
+	}
+	change.Name = r.previewName(ctx, planned)
+	r.client.planReport.record(ctx, change)
+}
+
+func newPlanReportChange(action string, m claimResourceModel) planReportChange {
+	change := planReportChange{
+		Action:       action,
+		ResourceType: m.ResourceType.ValueString(),
+		Region:       m.Region.ValueString(),
+		Environment:  m.Environment.ValueString(),
+		Segments:     map[string]string{},
+	}
+	resolved := m.resolveSegments()
+	for name, value := range resolved.segmentValues() {
+		if !value.IsNull() && !value.IsUnknown() {
+			change.Segments[name] = value.ValueString()
+		}
+	}
+	return change
+}
+
+// previewName asks the service for the name plan would claim, for the plan
+// report and collision checks. When the preview fails, for example because
+// the service does not serve /api/preview, the name is rendered from the
+// resource type's slug instead. It returns "" while values the name depends
+// on are unknown or neither source gives a name.
+func (r *ClaimResource) previewName(ctx context.Context, plan claimResourceModel) string {
+	if !plan.Name.IsNull() && !plan.Name.IsUnknown() {
+		return plan.Name.ValueString()
+	}
+	if plan.ResourceType.IsUnknown() || plan.Region.IsUnknown() || plan.Environment.IsUnknown() || plan.Template.IsUnknown() {
+		return ""
+	}
+	resolved := plan.resolveSegments()
+	for _, value := range resolved.segmentValues() {
+		if value.IsUnknown() {
+			return ""
+		}
+	}
+
+	payload, diags := buildClaimPayload(ctx, plan)
+	if diags.HasError() {
+		return ""
+	}
+	preview, err := r.client.PreviewName(ctx, payload)
+	if err == nil {
+		return plan.applyCase(preview.Name)
+	}
+	name, renderErr := r.renderPlannedName(ctx, plan)
+	if renderErr != nil {
+		tflog.Warn(ctx, "failed to predict planned name", map[string]any{
+			"resource_type": payload.ResourceType,
+			"error":         err.Error(),
+			"render_error":  renderErr.Error(),
+		})
+		return ""
+	}
+	tflog.Debug(ctx, "preview failed, rendered planned name from the slug table", map[string]any{
+		"name":  name,
+		"error": err.Error(),
+	})
+	return plan.applyCase(name)
+}
+
+// renderPlannedName renders the name plan would claim from the service's
+// slug for its resource type. Session defaults are not applied, and claims
+// without an index render without one, as the service claims them.
+func (r *ClaimResource) renderPlannedName(ctx context.Context, plan claimResourceModel) (string, error) {
+	if !plan.Suffix.IsNull() && plan.Suffix.ValueString() != "" {
+		return "", errors.New("names with a unique suffix cannot be rendered locally")
+	}
+	resourceType := plan.ResourceType.ValueString()
+	slug, err := r.client.LookupSlug(ctx, resourceType)
+	if err != nil {
+		return "", err
+	}
+	if slug == nil || slug.Slug == "" {
+		return "", fmt.Errorf("the service has no slug for %s", resourceType)
+	}
+	claim, err := r.client.renderClaim(plan, slug.Slug, slug.Defaults, plan.resolveSegments().Index)
+	if err != nil {
+		return "", err
+	}
+	return claim.Name, nil
+}
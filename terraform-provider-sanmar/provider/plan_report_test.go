@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPlanReportRecordsPreviewedClaims(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/preview", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ClaimNameResponse{Name: "WUS2-PRD-KV-ATLAS-01", ResourceType: "key_vault"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	reportPath := filepath.Join(t.TempDir(), "sanmar-plan.json")
+	if err := os.WriteFile(reportPath, []byte("stale"), 0o600); err != nil {
+		t.Fatalf("seed report: %v", err)
+	}
+	if err := client.EnablePlanReport(reportPath); err != nil {
+		t.Fatalf("EnablePlanReport: %v", err)
+	}
+
+	plan := claimResourceModel{
+		ResourceType: types.StringValue("key_vault"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		Project:      types.StringNull(),
+		Purpose:      types.StringNull(),
+		System:       types.StringValue("atlas"),
+		Subsystem:    types.StringNull(),
+		Index:        indexNumber("01"),
+		Name:         types.StringUnknown(),
+		Case:         types.StringValue("lower"),
+	}
+	r := &ClaimResource{client: client}
+	ctx := context.Background()
+
+	change := newPlanReportChange(planReportReplace, plan)
+	change.PreviousName = "wus2-prd-kv-atlas-00"
+	change.Name = r.previewName(ctx, plan)
+	client.planReport.record(ctx, change)
+
+	unknown := plan
+	unknown.System = types.StringUnknown()
+	if name := r.previewName(ctx, unknown); name != "" {
+		t.Fatalf("expected no preview while a segment is unknown, got %q", name)
+	}
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	var doc planReportDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode report %q: %v", data, err)
+	}
+	if len(doc.Changes) != 1 {
+		t.Fatalf("expected one change, got %+v", doc.Changes)
+	}
+	got := doc.Changes[0]
+	if got.Action != planReportReplace || got.Name != "wus2-prd-kv-atlas-01" || got.PreviousName != "wus2-prd-kv-atlas-00" {
+		t.Fatalf("unexpected change: %+v", got)
+	}
+	if got.Segments["system"] != "atlas" || got.Segments["index"] != "1" || len(got.Segments) != 2 {
+		t.Fatalf("unexpected segments: %v", got.Segments)
+	}
+}
+
+func TestPreviewNameRendersWithoutPreviewRoute(t *testing.T) {
+	// A service without /api/preview still serves the slug table.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("resource_type") != "key_vault" {
+			http.Error(w, `{"message":"Slug not found."}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"resourceType": "key_vault", "slug": "kv", "fullName": "Key Vault"})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	plan := claimResourceModel{
+		ResourceType: types.StringValue("key_vault"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		Project:      types.StringNull(),
+		Purpose:      types.StringNull(),
+		System:       types.StringValue("atlas"),
+		Subsystem:    types.StringNull(),
+		Index:        indexNumber("1"),
+		Name:         types.StringUnknown(),
+		Case:         types.StringValue("lower"),
+	}
+	r := &ClaimResource{client: client}
+
+	if name := r.previewName(context.Background(), plan); name != "wus2prdkvsanmaratlas01" {
+		t.Fatalf("expected the name rendered from the slug, got %q", name)
+	}
+	unknownType := plan
+	unknownType.ResourceType = types.StringValue("storage_account")
+	if name := r.previewName(context.Background(), unknownType); name != "" {
+		t.Fatalf("expected no prediction without a slug, got %q", name)
+	}
+}
+
+func TestPlanReportMergesAliasedProviders(t *testing.T) {
+	ctx := context.Background()
+	reportPath := filepath.Join(t.TempDir(), "sanmar-plan.json")
+	running := map[int]bool{}
+	open := func(pid int, sessionID string) *planReport {
+		running[pid] = true
+		report := &planReport{path: reportPath, sessionID: sessionID, pid: pid, parentPID: 100, alive: func(pid int) bool { return running[pid] }, now: time.Now}
+		if err := report.update(nil); err != nil {
+			t.Fatalf("open report: %v", err)
+		}
+		return report
+	}
+	read := func() planReportDocument {
+		data, err := os.ReadFile(reportPath)
+		if err != nil {
+			t.Fatalf("read report: %v", err)
+		}
+		var doc planReportDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("decode report %q: %v", data, err)
+		}
+		return doc
+	}
+
+	primary, secondary := open(1, "primary"), open(2, "secondary")
+	primary.record(ctx, planReportChange{Action: planReportClaim, Name: "kvwus2prdatlas"})
+	secondary.record(ctx, planReportChange{Action: planReportClaim, Name: "kveus2prdatlas"})
+
+	doc := read()
+	if len(doc.Changes) != 2 || doc.Changes[0].SessionID != "primary" || doc.Changes[1].SessionID != "secondary" {
+		t.Fatalf("expected both providers' changes, got %+v", doc.Changes)
+	}
+	if _, err := os.Stat(reportPath + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected the lock to be released, got %v", err)
+	}
+
+	// Once the writers have exited, the next phase starts a new report.
+	running[1], running[2] = false, false
+	open(3, "apply")
+	if doc := read(); len(doc.Changes) != 0 || len(doc.Writers) != 1 {
+		t.Fatalf("expected a fresh report, got %+v", doc)
+	}
+}
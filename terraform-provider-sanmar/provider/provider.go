@@ -3,9 +3,18 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -15,6 +24,9 @@ import (
 
 // Ensure Provider satisfies interfaces
 var _ provider.Provider = (*SanmarProvider)(nil)
+var _ provider.ProviderWithFunctions = (*SanmarProvider)(nil)
+var _ provider.ProviderWithListResources = (*SanmarProvider)(nil)
+var _ provider.ProviderWithActions = (*SanmarProvider)(nil)
 
 // New returns a new instance of the provider configured with the supplied version.
 func New(version string) func() provider.Provider {
@@ -30,16 +42,43 @@ type SanmarProvider struct {
 
 // sanmarProviderModel stores provider configuration.
 type sanmarProviderModel struct {
-	Endpoint         types.String `tfsdk:"endpoint"`
-	Scope            types.String `tfsdk:"scope"`
-	RetryMaxAttempts types.Int64  `tfsdk:"retry_max_attempts"`
-	RetryMinBackoff  types.String `tfsdk:"retry_min_backoff"`
-	RetryMaxBackoff  types.String `tfsdk:"retry_max_backoff"`
+	Endpoint            types.String     `tfsdk:"endpoint"`
+	Scope               types.String     `tfsdk:"scope"`
+	RetryMaxAttempts    types.Int64      `tfsdk:"retry_max_attempts"`
+	RetryMinBackoff     types.String     `tfsdk:"retry_min_backoff"`
+	RetryMaxBackoff     types.String     `tfsdk:"retry_max_backoff"`
+	AttemptTimeout      types.String     `tfsdk:"attempt_timeout"`
+	OperationTimeout    types.String     `tfsdk:"operation_timeout"`
+	GenerateSession     types.Bool       `tfsdk:"generate_session"`
+	AllowedEnvironments types.List       `tfsdk:"allowed_environments"`
+	ClaimBatchWindow    types.String     `tfsdk:"claim_batch_window"`
+	ReadCacheTTL        types.String     `tfsdk:"read_cache_ttl"`
+	OperationPoll       types.String     `tfsdk:"operation_poll_interval"`
+	IndexReuse          types.String     `tfsdk:"index_reuse"`
+	IndexWidth          types.Int64      `tfsdk:"index_width"`
+	CollisionCheck      types.Bool       `tfsdk:"collision_check"`
+	ValidationMode      types.String     `tfsdk:"validation_mode"`
+	HMACKeyID           types.String     `tfsdk:"hmac_key_id"`
+	HMACSecret          types.String     `tfsdk:"hmac_secret"`
+	HostOverride        types.String     `tfsdk:"host_override"`
+	ClaimRateLimit      types.Float64    `tfsdk:"claim_rate_limit"`
+	AuditLogPath        types.String     `tfsdk:"audit_log_path"`
+	PlanReportPath      types.String     `tfsdk:"plan_report_path"`
+	ClaimJournalDir     types.String     `tfsdk:"claim_journal_dir"`
+	ExpiryWarningWindow types.String     `tfsdk:"expiry_warning_window"`
+	LeaseHorizon        types.String     `tfsdk:"lease_horizon"`
+	OfflineSlugs        types.Map        `tfsdk:"offline_slugs"`
+	CleanupWorkspace    types.String     `tfsdk:"cleanup_workspace"`
+	StrictDecoding      types.Bool       `tfsdk:"strict_decoding"`
+	PartnerID           types.String     `tfsdk:"partner_id"`
+	DisableTelemetry    types.Bool       `tfsdk:"disable_telemetry"`
+	AppendUserAgent     types.String     `tfsdk:"append_user_agent"`
+	Retry               *retryBlockModel `tfsdk:"retry"`
 }
 
 // Metadata sets the provider type name.
 func (p *SanmarProvider) Metadata(_ context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
-	resp.TypeName = req.TypeName
+	resp.TypeName = "sanmar"
 	resp.Version = p.version
 }
 
@@ -55,6 +94,19 @@ func (p *SanmarProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:    true,
 				Description: "AAD scope or resource identifier to request tokens for (for example, api://client-id/.default).",
 			},
+			"host_override": schema.StringAttribute{
+				Optional:    true,
+				Description: "Address (IP or host, optionally with port) to connect to instead of resolving the endpoint host, for example an Azure Private Endpoint IP. TLS SNI and the Host header still use the endpoint hostname.",
+			},
+			"hmac_key_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Key identifier for gateways that authenticate HMAC-signed requests. Use with hmac_secret instead of scope.",
+			},
+			"hmac_secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Shared secret used to sign requests with HMAC-SHA256 over the method, path, timestamp, and body hash.",
+			},
 			"retry_max_attempts": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Maximum number of attempts for transient HTTP errors (default 4).",
@@ -67,26 +119,142 @@ func (p *SanmarProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:    true,
 				Description: "Maximum backoff duration between retries (default 5s).",
 			},
+			"attempt_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long a single HTTP attempt may take before it is abandoned and retried (default 30s). A short value such as 10s retries a request stuck behind a Functions cold start instead of waiting on it. Claims that time out are not retried.",
+			},
+			"operation_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: "How long a request may keep retrying across attempts, for example 2m. No new attempt starts after this deadline. Unset, retries are limited only by retry_max_attempts.",
+			},
+			"allowed_environments": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Environments that sanmar_claim may target (for example [\"dev\", \"stg\", \"prd\"]). Other values are rejected at plan time.",
+			},
+			"claim_batch_window": schema.StringAttribute{
+				Optional:    true,
+				Description: "When set (for example 50ms), claims created concurrently within this window are sent together to /api/claim/batch. Falls back to individual claims if the service has no batch endpoint.",
+			},
+			"claim_rate_limit": schema.Float64Attribute{
+				Optional:    true,
+				Description: "Maximum claims per second sent to the service. Excess claims are queued and sent in order of the sanmar_claim priority attribute, which avoids bursts of 429 responses on large applies. Aliases for the same endpoint share one limiter at the first rate configured.",
+			},
+			"read_cache_ttl": schema.StringAttribute{
+				Optional:    true,
+				Description: "Skip the audit call when refreshing a sanmar_claim that was created, updated, or read within this duration (for example 15m), such as a plan right after an apply. Disabled by default.",
+			},
+			"operation_poll_interval": schema.StringAttribute{
+				Optional:    true,
+				Description: "Ask the service to run claims as long-running operations (HTTP 202) and poll them this often, for example 5s. A longer Retry-After from the service takes precedence. When unset, claims are answered directly, and a 202 from the service is still polled every 2s.",
+			},
+			"index_reuse": schema.StringAttribute{
+				Optional:    true,
+				Description: "Have the service allocate the lowest free index for claims without an explicit index, and decide whether that may be the index of a released name: never, after_hold (once the environment's released-name retention has passed), or always. Claims can override it with their own index_reuse. When unset, claims without an index are named without one.",
+			},
+			"index_width": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Pad claim indices to this many digits before sending them, so an index computed in configuration (for example effective_index + 1) is claimed as 02 rather than 2. Defaults to 2; set 1 to send indices unpadded.",
+			},
+			"collision_check": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Preview the name each new claim would take during plan and warn when it is already claimed, naming the user and workspace that hold it, so cross-workspace conflicts show before apply. Costs a preview and an availability check per new claim.",
+			},
+			"validation_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "How sanmar_claim reports names that break the naming convention, such as invalid segment characters, missing required segments, names over the length limit, names that are not DNS safe, and environments outside allowed_environments: error (the default) or warn. Use warn while bringing existing names under the provider; the service may still reject a claim it cannot make.",
+			},
+			"audit_log_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Append a JSON line to this file for every claim and release the provider performs, with the time, run metadata, and outcome, as client-side evidence for change records.",
+			},
+			"plan_report_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Write a JSON report of every name the run would claim or release to this file, with the resource type, segments, and predicted name, for change review outside Terraform. The file is replaced each run, including by the planning Terraform does during apply.",
+			},
+			"claim_journal_dir": schema.StringAttribute{
+				Optional:    true,
+				Description: "Journal every claim in this directory until Terraform has recorded it in state. When the provider starts, names left claimed by earlier runs on this host that were killed or failed to write state are released.",
+			},
+			"expiry_warning_window": schema.StringAttribute{
+				Optional:    true,
+				Description: "Warn about sanmar_claim resources whose expires_at is less than this duration away (default 72h).",
+			},
+			"offline_slugs": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Slug for each resource type, such as { storage_account = \"st\" }, used by sanmar_claim resources with fallback = \"offline\" to generate names while the naming service cannot be reached. Keep it in line with the service's slug table.",
+			},
+			"lease_horizon": schema.StringAttribute{
+				Optional:    true,
+				Description: "Warn during plan about sanmar_claim resources whose expires_at, after the planned changes, is less than this duration away (for example 336h), so leases are renewed before the apply that depends on them. Unset disables the check.",
+			},
+			"cleanup_workspace": schema.StringAttribute{
+				Optional:    true,
+				Description: "Tag every claim with this value in the sanmar_workspace metadata key so `sanmarctl cleanup` can find claims that were dropped from state without being released.",
+			},
+			"strict_decoding": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Warn once per response type when the naming service returns fields this provider does not recognise, which usually means the service is newer than the provider. Missing required fields are always an error.",
+			},
+			"partner_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "GUID appended to the User-Agent header as pid-<partner_id>, so the naming service can attribute requests to a team or partner. Defaults to the SANMAR_PARTNER_ID environment variable.",
+			},
+			"disable_telemetry": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Leave the provider version, platform, and Terraform version out of the User-Agent header and stop sending the X-Sanmar-Terraform-Version, X-Sanmar-Workspace, and X-Sanmar-Execution headers. A configured partner_id and append_user_agent are still sent. Defaults to the SANMAR_DISABLE_TELEMETRY environment variable.",
+			},
+			"append_user_agent": schema.StringAttribute{
+				Optional:    true,
+				Description: "Text appended to the User-Agent header, for example a pipeline or team name. The TF_APPEND_USER_AGENT environment variable is appended after it.",
+			},
+			"generate_session": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Generate a session ID for each run and send it with every claim that does not set session_id, so the service can correlate and default segments across one deployment.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"retry": schema.SingleNestedBlock{
+				Description: "Separate retry policies for reads (audit, slug, and other GET requests) and writes (claims, releases). Unset values fall back to the retry_* attributes.",
+				Blocks: map[string]schema.Block{
+					"read":  retryPolicyBlock("Retry policy for read requests, which are safe to retry aggressively."),
+					"write": retryPolicyBlock("Retry policy for write requests. Set max_attempts = 1 to avoid retrying claims that the service may already have processed."),
+				},
+			},
 		},
-		Blocks: map[string]schema.Block{},
 	}
 }
 
 // Configure sets up provider state.
 func (p *SanmarProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data sanmarProviderModel
-	diag := req.Config.Get(ctx, &data)
-	resp.Diagnostics.Append(diag...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// The endpoint or scope may come from another resource that has not been
+	// created yet. Defer when Terraform supports it; otherwise leave the
+	// provider unconfigured until apply instead of targeting localhost.
+	if data.Endpoint.IsUnknown() || data.Scope.IsUnknown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		resp.Diagnostics.AddWarning(
+			"Provider configuration unknown",
+			"The endpoint or scope is not known until apply. Claims that depend on this provider cannot be previewed or validated against the service during this plan.",
+		)
+		return
+	}
+
 	endpoint := ""
-	if !data.Endpoint.IsNull() && !data.Endpoint.IsUnknown() {
+	if !data.Endpoint.IsNull() {
 		endpoint = data.Endpoint.ValueString()
 	}
 	scope := ""
-	if !data.Scope.IsNull() && !data.Scope.IsUnknown() {
+	if !data.Scope.IsNull() {
 		scope = data.Scope.ValueString()
 	}
 
@@ -118,25 +286,271 @@ func (p *SanmarProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		retryConfig.MaxBackoff = duration
 	}
 
-	client, err := NewAPIClient(ctx, endpoint, scope, retryConfig)
+	readRetry, writeRetry := retryConfig, retryConfig
+	if data.Retry != nil {
+		var diags diag.Diagnostics
+		readRetry, diags = data.Retry.Read.apply(retryConfig, path.Root("retry").AtName("read"))
+		resp.Diagnostics.Append(diags...)
+		writeRetry, diags = data.Retry.Write.apply(retryConfig, path.Root("retry").AtName("write"))
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	client, err := NewAPIClient(ctx, endpoint, scope, readRetry)
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to configure provider", err.Error())
 		return
 	}
+	client.SetWriteRetry(writeRetry)
+	trackClient(client)
+
+	if !data.AttemptTimeout.IsNull() && !data.AttemptTimeout.IsUnknown() {
+		timeout, err := time.ParseDuration(data.AttemptTimeout.ValueString())
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("attempt_timeout"), "Invalid attempt_timeout", fmt.Sprintf("attempt_timeout must be a positive duration such as 10s, got %q.", data.AttemptTimeout.ValueString()))
+			return
+		}
+		client.SetAttemptTimeout(timeout)
+	}
+
+	if !data.OperationTimeout.IsNull() && !data.OperationTimeout.IsUnknown() {
+		timeout, err := time.ParseDuration(data.OperationTimeout.ValueString())
+		if err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("operation_timeout"), "Invalid operation_timeout", fmt.Sprintf("operation_timeout must be a positive duration such as 2m, got %q.", data.OperationTimeout.ValueString()))
+			return
+		}
+		client.SetOperationTimeout(timeout)
+	}
+
+	if !data.HostOverride.IsNull() && !data.HostOverride.IsUnknown() {
+		if err := client.SetHostOverride(data.HostOverride.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Invalid host_override", err.Error())
+			return
+		}
+	}
+
+	hmacKeyID := !data.HMACKeyID.IsNull() && !data.HMACKeyID.IsUnknown()
+	hmacSecret := !data.HMACSecret.IsNull() && !data.HMACSecret.IsUnknown()
+	if hmacKeyID != hmacSecret {
+		resp.Diagnostics.AddError("Incomplete HMAC configuration", "hmac_key_id and hmac_secret must be set together.")
+		return
+	}
+	if hmacKeyID {
+		if scope != "" {
+			resp.Diagnostics.AddError("Conflicting authentication", "Set either scope (Entra ID tokens) or hmac_key_id and hmac_secret (signed requests), not both.")
+			return
+		}
+		client.hmac = &HMACCredentials{KeyID: data.HMACKeyID.ValueString(), Secret: data.HMACSecret.ValueString()}
+	}
+
+	if !data.AllowedEnvironments.IsNull() && !data.AllowedEnvironments.IsUnknown() {
+		resp.Diagnostics.Append(data.AllowedEnvironments.ElementsAs(ctx, &client.allowedEnvironments, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !data.ClaimBatchWindow.IsNull() && !data.ClaimBatchWindow.IsUnknown() {
+		window, err := time.ParseDuration(data.ClaimBatchWindow.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid claim_batch_window", fmt.Sprintf("failed to parse duration: %v", err))
+			return
+		}
+		if window > 0 {
+			client.EnableClaimBatching(window)
+		}
+	}
+
+	if !data.ClaimRateLimit.IsNull() && !data.ClaimRateLimit.IsUnknown() {
+		rate := data.ClaimRateLimit.ValueFloat64()
+		if rate <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("claim_rate_limit"), "Invalid claim_rate_limit", "claim_rate_limit must be greater than zero.")
+			return
+		}
+		if enforced := client.SetClaimRateLimit(rate); enforced != rate {
+			resp.Diagnostics.AddAttributeWarning(path.Root("claim_rate_limit"), "claim_rate_limit already set for this endpoint",
+				fmt.Sprintf("Provider configurations for the same endpoint share one claim rate limiter, which another configuration already set to %g claims per second. That rate applies instead of %g.", enforced, rate))
+		}
+	}
+
+	if !data.ReadCacheTTL.IsNull() && !data.ReadCacheTTL.IsUnknown() {
+		ttl, err := time.ParseDuration(data.ReadCacheTTL.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid read_cache_ttl", fmt.Sprintf("failed to parse duration: %v", err))
+			return
+		}
+		client.readCacheTTL = ttl
+	}
+
+	if !data.OperationPoll.IsNull() && !data.OperationPoll.IsUnknown() {
+		interval, err := time.ParseDuration(data.OperationPoll.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid operation_poll_interval", fmt.Sprintf("failed to parse duration: %v", err))
+			return
+		}
+		client.SetOperationPollInterval(interval)
+		client.asyncClaims = true
+	}
+
+	if !data.ExpiryWarningWindow.IsNull() && !data.ExpiryWarningWindow.IsUnknown() {
+		window, err := time.ParseDuration(data.ExpiryWarningWindow.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid expiry_warning_window", fmt.Sprintf("failed to parse duration: %v", err))
+			return
+		}
+		client.expiryWarningWindow = window
+	}
+
+	if !data.OfflineSlugs.IsNull() && !data.OfflineSlugs.IsUnknown() {
+		resp.Diagnostics.Append(data.OfflineSlugs.ElementsAs(ctx, &client.offlineSlugs, false)...)
+	}
+
+	if !data.LeaseHorizon.IsNull() && !data.LeaseHorizon.IsUnknown() {
+		horizon, err := time.ParseDuration(data.LeaseHorizon.ValueString())
+		if err != nil || horizon <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("lease_horizon"), "Invalid lease_horizon", fmt.Sprintf("lease_horizon must be a positive duration such as 336h, got %q.", data.LeaseHorizon.ValueString()))
+			return
+		}
+		client.leaseHorizon = horizon
+	}
+
+	if !data.GenerateSession.IsNull() && !data.GenerateSession.IsUnknown() && data.GenerateSession.ValueBool() {
+		sessionID, err := newSessionID()
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to configure provider", err.Error())
+			return
+		}
+		client.sessionID = sessionID
+	}
+
+	if !data.IndexReuse.IsNull() && !data.IndexReuse.IsUnknown() {
+		policy := data.IndexReuse.ValueString()
+		if !slices.Contains(indexReusePolicies, policy) {
+			resp.Diagnostics.AddAttributeError(path.Root("index_reuse"), "Invalid index_reuse", fmt.Sprintf("index_reuse must be one of %s, got %q.", strings.Join(indexReusePolicies, ", "), policy))
+			return
+		}
+		client.indexReuse = policy
+	}
+	if !data.CollisionCheck.IsNull() && !data.CollisionCheck.IsUnknown() {
+		client.collisionCheck = data.CollisionCheck.ValueBool()
+	}
+	if !data.IndexWidth.IsNull() && !data.IndexWidth.IsUnknown() {
+		width := data.IndexWidth.ValueInt64()
+		if width < 1 || width > 10 {
+			resp.Diagnostics.AddAttributeError(path.Root("index_width"), "Invalid index_width", fmt.Sprintf("index_width must be between 1 and 10, got %d.", width))
+			return
+		}
+		client.indexWidth = int(width)
+	}
+
+	if !data.ValidationMode.IsNull() && !data.ValidationMode.IsUnknown() {
+		mode := data.ValidationMode.ValueString()
+		if !slices.Contains(validationModes, mode) {
+			resp.Diagnostics.AddAttributeError(path.Root("validation_mode"), "Invalid validation_mode", fmt.Sprintf("validation_mode must be one of %s, got %q.", strings.Join(validationModes, ", "), mode))
+			return
+		}
+		client.validationMode = mode
+	}
+
+	// Opened after the session is generated so entries and reports carry its ID.
+	if !data.AuditLogPath.IsNull() && !data.AuditLogPath.IsUnknown() {
+		if err := client.EnableAuditLog(data.AuditLogPath.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("audit_log_path"), "Invalid audit_log_path", err.Error())
+			return
+		}
+	}
+	if !data.PlanReportPath.IsNull() && !data.PlanReportPath.IsUnknown() {
+		if err := client.EnablePlanReport(data.PlanReportPath.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("plan_report_path"), "Invalid plan_report_path", err.Error())
+			return
+		}
+	}
+	if !data.ClaimJournalDir.IsNull() && !data.ClaimJournalDir.IsUnknown() {
+		errs, err := client.EnableClaimJournal(ctx, data.ClaimJournalDir.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("claim_journal_dir"), "Invalid claim_journal_dir", err.Error())
+			return
+		}
+		// Names that could not be released stay journaled for the next run.
+		for _, err := range errs {
+			resp.Diagnostics.AddWarning("Failed to reconcile claim journal", err.Error())
+		}
+	}
+
+	if !data.StrictDecoding.IsNull() && !data.StrictDecoding.IsUnknown() {
+		client.strictDecoding = data.StrictDecoding.ValueBool()
+	}
+
+	partnerID := os.Getenv(PartnerIDEnv)
+	if !data.PartnerID.IsNull() && !data.PartnerID.IsUnknown() {
+		partnerID = data.PartnerID.ValueString()
+	}
+	disableTelemetry, _ := strconv.ParseBool(os.Getenv(DisableTelemetryEnv))
+	if !data.DisableTelemetry.IsNull() && !data.DisableTelemetry.IsUnknown() {
+		disableTelemetry = data.DisableTelemetry.ValueBool()
+	}
+	if err := client.SetTelemetry(p.version, partnerID, disableTelemetry); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("partner_id"), "Invalid partner_id", err.Error())
+		return
+	}
+	client.telemetry.terraformVersion = req.TerraformVersion
+	client.telemetry.workspace = currentWorkspace()
+	client.telemetry.execution = currentExecution()
+	client.telemetry.appendix = strings.TrimSpace(data.AppendUserAgent.ValueString() + " " + os.Getenv(appendUserAgentEnv))
+
+	if !data.CleanupWorkspace.IsNull() && !data.CleanupWorkspace.IsUnknown() {
+		if err := client.SetCleanupWorkspace(data.CleanupWorkspace.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("cleanup_workspace"), "Invalid cleanup_workspace", err.Error())
+			return
+		}
+	}
 
 	tflog.Debug(ctx, "configured SanMar naming provider", map[string]any{
-		"endpoint": endpoint,
-		"scope":    scope,
+		"endpoint":   endpoint,
+		"scope":      scope,
+		"session_id": client.sessionID,
 	})
 
 	resp.DataSourceData = client
 	resp.ResourceData = client
+	resp.ListResourceData = client
+	resp.ActionData = client
+}
+
+// Actions returns the one-off operations configurations can invoke.
+func (p *SanmarProvider) Actions(_ context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewRenewClaimAction,
+		NewTransferClaimAction,
+		NewPurgeClaimAction,
+		NewSyncSlugsAction,
+	}
+}
+
+// ListResources returns the list resources `terraform query` can use.
+func (p *SanmarProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewClaimListResource,
+	}
 }
 
 // DataSources returns configured data sources.
 func (p *SanmarProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewSlugDataSource,
+		NewSessionDataSource,
+		NewSuggestionsDataSource,
+		NewClaimsDataSource,
+		NewClaimsDiffDataSource,
+		NewEnvironmentNamesDataSource,
+		NewNameAvailabilityDataSource,
+		NewComplianceDataSource,
+		NewKubernetesFragmentDataSource,
+		NewManifestDataSource,
+		NewSystemsDataSource,
+		NewSubsystemsDataSource,
 	}
 }
 
@@ -144,5 +558,21 @@ func (p *SanmarProvider) DataSources(_ context.Context) []func() datasource.Data
 func (p *SanmarProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewClaimResource,
+		NewNotificationResource,
+		NewIndexReservationResource,
+		NewClaimRenewalResource,
+		NewClaimLinkResource,
+		NewProjectResource,
+		NewEnvironmentResource,
+		NewRegionResource,
+		NewReleaseBatchResource,
+		NewWorkspaceCleanupResource,
+	}
+}
+
+// Functions returns provider-defined functions.
+func (p *SanmarProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewValidateNameFunction,
 	}
 }
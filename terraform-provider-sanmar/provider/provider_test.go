@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func providerConfig(t *testing.T, endpoint tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp provider.SchemaResponse
+	New("test")().Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	values["endpoint"] = endpoint
+
+	return tfsdk.Config{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(objType, values),
+	}
+}
+
+func TestConfigureUnknownEndpoint(t *testing.T) {
+	ctx := context.Background()
+	config := providerConfig(t, tftypes.NewValue(tftypes.String, tftypes.UnknownValue))
+
+	var deferred provider.ConfigureResponse
+	New("test")().Configure(ctx, provider.ConfigureRequest{
+		Config:             config,
+		ClientCapabilities: provider.ConfigureProviderClientCapabilities{DeferralAllowed: true},
+	}, &deferred)
+
+	if deferred.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", deferred.Diagnostics)
+	}
+	if deferred.Deferred == nil || deferred.Deferred.Reason != provider.DeferredReasonProviderConfigUnknown {
+		t.Fatalf("expected provider config unknown deferral, got %+v", deferred.Deferred)
+	}
+	if deferred.ResourceData != nil {
+		t.Fatalf("expected no client while deferred")
+	}
+
+	var fallback provider.ConfigureResponse
+	New("test")().Configure(ctx, provider.ConfigureRequest{Config: config}, &fallback)
+
+	if fallback.Deferred != nil {
+		t.Fatalf("did not expect deferral without client support")
+	}
+	if fallback.Diagnostics.WarningsCount() != 1 || fallback.Diagnostics.HasError() {
+		t.Fatalf("expected a single warning, got %v", fallback.Diagnostics)
+	}
+	if fallback.ResourceData != nil {
+		t.Fatalf("expected no client for unknown endpoint")
+	}
+}
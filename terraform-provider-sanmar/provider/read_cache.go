@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// claimVerifiedKey is the private state key recording when a claim was last
+// confirmed with the service. Private state survives between Terraform runs,
+// unlike anything cached in the provider process.
+const claimVerifiedKey = "verified_at"
+
+type privateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+type claimVerification struct {
+	At time.Time `json:"at"`
+}
+
+// markClaimVerified records that the claim was confirmed at now.
+func markClaimVerified(ctx context.Context, private privateStateSetter, now time.Time) diag.Diagnostics {
+	value, err := json.Marshal(claimVerification{At: now.UTC()})
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to record claim verification", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, claimVerifiedKey, value)
+}
+
+// claimVerifiedWithin reports whether the claim was confirmed less than ttl
+// before now. Missing or unreadable entries count as not verified.
+func claimVerifiedWithin(ctx context.Context, private privateStateGetter, ttl time.Duration, now time.Time) bool {
+	if ttl <= 0 || private == nil {
+		return false
+	}
+	value, diags := private.GetKey(ctx, claimVerifiedKey)
+	if diags.HasError() || len(value) == 0 {
+		return false
+	}
+	var verified claimVerification
+	if err := json.Unmarshal(value, &verified); err != nil {
+		return false
+	}
+	return now.Sub(verified.At) < ttl
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+type fakePrivateState map[string][]byte
+
+func (f fakePrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return f[key], nil
+}
+
+func (f fakePrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	f[key] = value
+	return nil
+}
+
+func TestClaimVerifiedWithin(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	private := fakePrivateState{}
+
+	if claimVerifiedWithin(ctx, private, time.Minute, now) {
+		t.Fatalf("expected unverified claim without private state")
+	}
+
+	if diags := markClaimVerified(ctx, private, now); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if !claimVerifiedWithin(ctx, private, time.Minute, now.Add(30*time.Second)) {
+		t.Fatalf("expected claim verified within ttl")
+	}
+	if claimVerifiedWithin(ctx, private, time.Minute, now.Add(2*time.Minute)) {
+		t.Fatalf("expected verification to expire after ttl")
+	}
+	if claimVerifiedWithin(ctx, private, 0, now) {
+		t.Fatalf("expected zero ttl to disable the cache")
+	}
+}
@@ -2,22 +2,40 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// forceRefreshEnv, when set, makes claims with skip_read refresh anyway.
+const forceRefreshEnv = "SANMAR_FORCE_REFRESH"
+
 var _ resource.Resource = (*ClaimResource)(nil)
+var _ resource.ResourceWithIdentity = (*ClaimResource)(nil)
 var _ resource.ResourceWithImportState = (*ClaimResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*ClaimResource)(nil)
+var _ resource.ResourceWithValidateConfig = (*ClaimResource)(nil)
 
 // ClaimResource implements the Terraform resource.
 type ClaimResource struct {
@@ -30,24 +48,115 @@ func NewClaimResource() resource.Resource {
 }
 
 type claimResourceModel struct {
-	ID           types.String `tfsdk:"id"`
-	Name         types.String `tfsdk:"name"`
-	ResourceType types.String `tfsdk:"resource_type"`
-	Region       types.String `tfsdk:"region"`
-	Environment  types.String `tfsdk:"environment"`
-	Project      types.String `tfsdk:"project"`
-	Purpose      types.String `tfsdk:"purpose"`
-	Subsystem    types.String `tfsdk:"subsystem"`
-	System       types.String `tfsdk:"system"`
-	Index        types.String `tfsdk:"index"`
-	SessionID    types.String `tfsdk:"session_id"`
-	Metadata     types.Map    `tfsdk:"metadata"`
-	ClaimedBy    types.String `tfsdk:"claimed_by"`
-	Slug         types.String `tfsdk:"slug"`
+	ID                  types.String     `tfsdk:"id"`
+	Name                types.String     `tfsdk:"name"`
+	ResourceType        types.String     `tfsdk:"resource_type"`
+	Region              segmentValue     `tfsdk:"region"`
+	Environment         segmentValue     `tfsdk:"environment"`
+	Project             types.String     `tfsdk:"project"`
+	Purpose             types.String     `tfsdk:"purpose"`
+	Subsystem           types.String     `tfsdk:"subsystem"`
+	System              types.String     `tfsdk:"system"`
+	Index               indexNumberValue `tfsdk:"index"`
+	Segments            types.Object     `tfsdk:"segments"`
+	SessionID           types.String     `tfsdk:"session_id"`
+	Metadata            types.Map        `tfsdk:"metadata"`
+	MetadataValues      types.Dynamic    `tfsdk:"metadata_values"`
+	SensitiveMetadata   types.Map        `tfsdk:"sensitive_metadata"`
+	Keepers             types.Map        `tfsdk:"keepers"`
+	ReleaseReason       types.String     `tfsdk:"release_reason"`
+	SkipRead            types.Bool       `tfsdk:"skip_read"`
+	Priority            types.Int64      `tfsdk:"priority"`
+	ClaimedBy           types.String     `tfsdk:"claimed_by"`
+	Slug                types.String     `tfsdk:"slug"`
+	DryRun              types.Bool       `tfsdk:"dry_run"`
+	UniqueSuffix        types.Bool       `tfsdk:"unique_suffix"`
+	UniqueLength        types.Int64      `tfsdk:"unique_length"`
+	UniqueSeed          types.String     `tfsdk:"unique_seed"`
+	Suffix              types.String     `tfsdk:"suffix"`
+	Template            types.String     `tfsdk:"template"`
+	Case                types.String     `tfsdk:"case"`
+	ExpiresAt           types.String     `tfsdk:"expires_at"`
+	ExpiresIn           types.String     `tfsdk:"expires_in"`
+	ReleaseAfter        types.String     `tfsdk:"release_after"`
+	ReleaseAt           types.String     `tfsdk:"release_at"`
+	IndexReuse          types.String     `tfsdk:"index_reuse"`
+	AzureResourceID     types.String     `tfsdk:"azure_resource_id"`
+	Fallback            types.String     `tfsdk:"fallback"`
+	PendingRegistration types.Bool       `tfsdk:"pending_registration"`
+	Retired             types.Bool       `tfsdk:"retired"`
+	TagContract         types.Map        `tfsdk:"tag_contract"`
+
+	NameHyphenated types.String `tfsdk:"name_hyphenated"`
+	NameUpper      types.String `tfsdk:"name_upper"`
+	DNSLabel       types.String `tfsdk:"dns_label"`
+	DNSPrefix      types.String `tfsdk:"dns_prefix"`
+	StorageSafe    types.String `tfsdk:"storage_safe"`
+	Endpoints      types.Map    `tfsdk:"endpoints"`
+
+	EffectiveProject   types.String     `tfsdk:"effective_project"`
+	EffectivePurpose   types.String     `tfsdk:"effective_purpose"`
+	EffectiveSystem    types.String     `tfsdk:"effective_system"`
+	EffectiveSubsystem types.String     `tfsdk:"effective_subsystem"`
+	EffectiveIndex     indexNumberValue `tfsdk:"effective_index"`
+}
+
+// setEffectiveSegments records the segment values the service used, which
+// include any defaults it applied to segments left out of the configuration.
+func (m *claimResourceModel) setEffectiveSegments(project, purpose, system, subsystem, index string) {
+	m.EffectiveProject = optionalString(project)
+	m.EffectivePurpose = optionalString(purpose)
+	m.EffectiveSystem = optionalString(system)
+	m.EffectiveSubsystem = optionalString(subsystem)
+	m.EffectiveIndex = indexNumber(index)
+}
+
+// keepEffectiveSegments carries the effective segments over from state when
+// the name is kept.
+func (m *claimResourceModel) keepEffectiveSegments(state claimResourceModel) {
+	m.EffectiveProject = state.EffectiveProject
+	m.EffectivePurpose = state.EffectivePurpose
+	m.EffectiveSystem = state.EffectiveSystem
+	m.EffectiveSubsystem = state.EffectiveSubsystem
+	m.EffectiveIndex = state.EffectiveIndex
+}
+
+// setNameVariants derives the convenience name formats from the claimed name.
+func (m *claimResourceModel) setNameVariants() {
+	if m.Name.IsNull() || m.Name.IsUnknown() {
+		return
+	}
+	name := m.Name.ValueString()
+	resolved := m.resolveSegments()
+	segments := []string{
+		m.Slug.ValueString(),
+		m.Region.ValueString(),
+		m.Environment.ValueString(),
+		resolved.Project.ValueString(),
+		resolved.Purpose.ValueString(),
+		resolved.System.ValueString(),
+		resolved.Subsystem.ValueString(),
+		resolved.Index.spelledIn(name),
+	}
+
+	m.NameHyphenated = types.StringValue(hyphenateName(name, segments))
+	m.NameUpper = types.StringValue(strings.ToUpper(name))
+	m.DNSLabel = types.StringValue(dnsLabel(name))
+	m.DNSPrefix = types.StringNull()
+	if resourceTypeRules[m.ResourceType.ValueString()].DNSLabel {
+		m.DNSPrefix = types.StringValue(dnsLabel(name))
+	}
+	m.StorageSafe = types.StringValue(storageSafeName(name))
+	endpoints := map[string]attr.Value{}
+	for key, endpoint := range nameEndpoints(m.ResourceType.ValueString(), name) {
+		endpoints[key] = types.StringValue(endpoint)
+	}
+	m.Endpoints = types.MapValueMust(types.StringType, endpoints)
 }
 
 func buildClaimPayload(ctx context.Context, plan claimResourceModel) (ClaimNameRequest, diag.Diagnostics) {
 	var diags diag.Diagnostics
+	plan = plan.resolveSegments()
 	payload := ClaimNameRequest{
 		ResourceType: plan.ResourceType.ValueString(),
 		Region:       plan.Region.ValueString(),
@@ -71,28 +180,160 @@ func buildClaimPayload(ctx context.Context, plan claimResourceModel) (ClaimNameR
 		payload.System = &v
 	}
 	if !plan.Index.IsNull() && !plan.Index.IsUnknown() {
-		v := plan.Index.ValueString()
+		v := plan.Index.segment().ValueString()
 		payload.Index = &v
 	}
 	if !plan.SessionID.IsNull() && !plan.SessionID.IsUnknown() {
 		v := plan.SessionID.ValueString()
 		payload.SessionID = &v
 	}
+	if !plan.Suffix.IsNull() && !plan.Suffix.IsUnknown() {
+		v := plan.Suffix.ValueString()
+		payload.Suffix = &v
+	}
+	if !plan.ExpiresAt.IsNull() && !plan.ExpiresAt.IsUnknown() {
+		v := plan.ExpiresAt.ValueString()
+		payload.ExpiresAt = &v
+	}
+	if !plan.ReleaseAfter.IsNull() && !plan.ReleaseAfter.IsUnknown() {
+		v := plan.ReleaseAfter.ValueString()
+		payload.ReleaseAfter = &v
+	}
+	if !plan.IndexReuse.IsNull() && !plan.IndexReuse.IsUnknown() {
+		v := plan.IndexReuse.ValueString()
+		payload.IndexReuse = &v
+	}
+	if !plan.Template.IsNull() && !plan.Template.IsUnknown() {
+		v := normalizeTemplate(plan.Template.ValueString())
+		payload.Template = &v
+	}
 	if !plan.Metadata.IsNull() && !plan.Metadata.IsUnknown() {
 		metadata := make(map[string]string)
 		diags = append(diags, plan.Metadata.ElementsAs(ctx, &metadata, false)...)
-		payload.Metadata = metadata
+		payload.Metadata = make(map[string]any, len(metadata))
+		for k, v := range metadata {
+			payload.Metadata[k] = v
+		}
+	}
+
+	typed, typedDiags := dynamicMetadata(path.Root("metadata_values"), plan.MetadataValues)
+	diags = append(diags, typedDiags...)
+	diags = append(diags, mergeMetadata(&payload, path.Root("metadata_values"), typed)...)
+
+	if !plan.SensitiveMetadata.IsNull() && !plan.SensitiveMetadata.IsUnknown() {
+		sensitive := make(map[string]string)
+		diags = append(diags, plan.SensitiveMetadata.ElementsAs(ctx, &sensitive, false)...)
+		values := make(map[string]any, len(sensitive)+1)
+		keys := make([]string, 0, len(sensitive))
+		for k, v := range sensitive {
+			values[k] = v
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values[sensitiveMetadataKeysKey] = strings.Join(keys, ",")
+		diags = append(diags, mergeMetadata(&payload, path.Root("sensitive_metadata"), values)...)
 	}
 
 	return payload, diags
 }
 
+// claimFieldPaths maps service request fields to Terraform attribute paths.
+var claimFieldPaths = map[string]path.Path{
+	"resource_type": path.Root("resource_type"),
+	"region":        path.Root("region"),
+	"environment":   path.Root("environment"),
+	"project":       path.Root("project"),
+	"purpose":       path.Root("purpose"),
+	"subsystem":     path.Root("subsystem"),
+	"system":        path.Root("system"),
+	"index":         path.Root("index"),
+	"sessionId":     path.Root("session_id"),
+	"session_id":    path.Root("session_id"),
+	"suffix":        path.Root("unique_suffix"),
+	"template":      path.Root("template"),
+	"index_reuse":   path.Root("index_reuse"),
+	"expires_at":    path.Root("expires_at"),
+	"release_after": path.Root("release_after"),
+}
+
+// addClaimError reports err against the offending attributes when the service
+// returned field-level validation errors, and as a resource error otherwise.
+func addClaimError(diags *diag.Diagnostics, summary string, err error) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Fields) == 0 {
+		diags.AddError(summary, err.Error())
+		return
+	}
+
+	for _, field := range apiErr.Fields {
+		if p, ok := claimFieldPaths[field.Field]; ok {
+			diags.AddAttributeError(p, summary, field.Message)
+			continue
+		}
+		if key, ok := strings.CutPrefix(field.Field, "metadata."); ok {
+			diags.AddAttributeError(path.Root("metadata").AtMapKey(key), summary, field.Message)
+			continue
+		}
+		diags.AddError(summary, fmt.Sprintf("%s: %s", field.Field, field.Message))
+	}
+}
+
+// maskSensitiveMetadata returns a context whose log output redacts any
+// sensitive_metadata values.
+func maskSensitiveMetadata(ctx context.Context, plan claimResourceModel) context.Context {
+	if plan.SensitiveMetadata.IsNull() || plan.SensitiveMetadata.IsUnknown() {
+		return ctx
+	}
+	var values []string
+	for _, v := range plan.SensitiveMetadata.Elements() {
+		if s, ok := v.(types.String); ok && s.ValueString() != "" {
+			values = append(values, s.ValueString())
+		}
+	}
+	ctx = tflog.MaskAllFieldValuesStrings(ctx, values...)
+	return tflog.MaskMessageStrings(ctx, values...)
+}
+
+// releaseReason returns the configured release reason or the fallback.
+func releaseReason(configured types.String, fallback string) string {
+	if configured.IsNull() || configured.IsUnknown() || configured.ValueString() == "" {
+		return fallback
+	}
+	return configured.ValueString()
+}
+
+// mergeMetadata adds values to the payload metadata, rejecting keys that an
+// earlier metadata attribute already set.
+func mergeMetadata(payload *ClaimNameRequest, attrPath path.Path, values map[string]any) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for k, v := range values {
+		if _, exists := payload.Metadata[k]; exists {
+			diags.AddAttributeError(attrPath.AtMapKey(k), "Duplicate metadata key",
+				fmt.Sprintf("%q is set in more than one metadata attribute.", k))
+			continue
+		}
+		if payload.Metadata == nil {
+			payload.Metadata = make(map[string]any, len(values))
+		}
+		payload.Metadata[k] = v
+	}
+	return diags
+}
+
 func (r *ClaimResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_claim"
+	// A pending claim gets its name when Update resumes it, and an update
+	// that changes the name's segments claims a new name in place.
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+func (r *ClaimResource) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = claimIdentitySchema()
 }
 
 func (r *ClaimResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:             claimSchemaVersion,
 		MarkdownDescription: "Claims a name via the SanMar Azure naming service and manages its lifecycle.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -112,40 +353,82 @@ func (r *ClaimResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 			"resource_type": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Azure resource type identifier used for slug resolution.",
-				Validators: []schema.AttributeValidator{
+				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(1),
 				},
 			},
 			"region": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Azure region short code (for example, wus2).",
-				Validators: []schema.AttributeValidator{
+				CustomType:          regionType,
+				MarkdownDescription: "Azure region short code (for example, wus2). Compared case-insensitively, so changing only the case does not re-claim the name.",
+				Validators: []validator.String{
 					stringvalidator.LengthBetween(2, 8),
 				},
 			},
 			"environment": schema.StringAttribute{
 				Required:            true,
-				MarkdownDescription: "Deployment environment such as dev, stg, or prd.",
-				Validators: []schema.AttributeValidator{
+				CustomType:          environmentType,
+				MarkdownDescription: "Deployment environment such as dev, stg, or prd. Compared case-insensitively.",
+				Validators: []validator.String{
 					stringvalidator.LengthAtLeast(2),
 				},
 			},
 			"project": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Optional project segment.",
+				DeprecationMessage:  "Use segments.project instead.",
 			},
 			"purpose": schema.StringAttribute{
 				Optional:            true,
 				MarkdownDescription: "Optional purpose segment.",
+				DeprecationMessage:  "Use segments.purpose instead.",
 			},
 			"subsystem": schema.StringAttribute{
-				Optional: true,
+				Optional:           true,
+				DeprecationMessage: "Use segments.subsystem instead.",
 			},
 			"system": schema.StringAttribute{
-				Optional: true,
+				Optional:           true,
+				DeprecationMessage: "Use segments.system instead.",
 			},
-			"index": schema.StringAttribute{
-				Optional: true,
+			"index": schema.Int64Attribute{
+				Optional:           true,
+				CustomType:         indexNumberType{},
+				DeprecationMessage: "Use segments.index instead.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"segments": schema.SingleNestedAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Optional naming segments as one object, so a whole naming context can be passed between modules. Replaces the deprecated top-level `project`, `purpose`, `system`, `subsystem`, and `index` attributes, and reflects their values when those are used instead.",
+				Attributes: map[string]schema.Attribute{
+					"project": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Project segment.",
+					},
+					"purpose": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Purpose segment.",
+					},
+					"system": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "System segment.",
+					},
+					"subsystem": schema.StringAttribute{
+						Optional:            true,
+						MarkdownDescription: "Subsystem segment.",
+					},
+					"index": schema.Int64Attribute{
+						Optional:            true,
+						CustomType:          indexNumberType{},
+						MarkdownDescription: "Index segment, as a number. It is padded to the provider's `index_width` in the name, so `index = 2` claims `02` by default.",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+				},
 			},
 			"session_id": schema.StringAttribute{
 				Optional:            true,
@@ -159,6 +442,36 @@ func (r *ClaimResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 				MarkdownDescription: "Additional metadata that will be forwarded to the claim request.",
 			},
+			"metadata_values": schema.DynamicAttribute{
+				Optional:            true,
+				MarkdownDescription: "Object of metadata whose number and boolean values are sent to the service with their types preserved. Keys must not overlap with metadata.",
+			},
+			"sensitive_metadata": schema.MapAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Metadata such as owner emails or billing identifiers that is sent with the claim but hidden from plan output and never logged. Keys must not overlap with metadata or metadata_values.",
+			},
+			"keepers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Arbitrary values that, when changed, release the current name and claim a fresh one. Keepers also feed the unique_suffix hash.",
+			},
+			"release_reason": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Reason recorded in the audit log when the name is released (for example, \"decommission ticket INC-1234\"). Defaults to \"terraform destroy\" or \"terraform update\". Set it and apply before destroying so the value is in state at destroy time.",
+			},
+			"skip_read": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "When true, refresh trusts the state and does not call the audit API, for names treated as immutable once issued. Set the `SANMAR_FORCE_REFRESH` environment variable (for example with `terraform plan -refresh-only`) to check for drift anyway.",
+			},
+			"priority": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Scheduling priority when the provider sets claim_rate_limit. Claims with higher values are sent first, so names on the critical path are not stuck behind the rest. Changing it does not re-claim the name.",
+			},
 			"claimed_by": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Identifier of the caller stored by the service.",
@@ -167,6 +480,170 @@ func (r *ClaimResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				Computed:            true,
 				MarkdownDescription: "Slug resolved for the resource type.",
 			},
+			"azure_resource_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ARM ID of the Azure resource that uses the name. It is recorded on the claim's audit record, so reports and lookups can link the name to the resource. Setting it from the resource that uses this claim's name is a dependency cycle; use `sanmar_claim_link` for that, and set this only for IDs known without the claim, such as adopted resources. Removing it removes the link.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(azureResourceIDPattern, "must be an Azure resource ID starting with /subscriptions/"),
+				},
+			},
+			"tag_contract": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Tags to stamp onto the Azure resource that uses the name so it can be reconciled with its claim: `naming-id` (the claim's `<region>/<environment>/<name>` identity), `claimed-by`, and `claim-date` (UTC, `YYYY-MM-DD`). Merge it into the resource's `tags`. Empty for `dry_run` claims.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"fallback": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "What to do when the naming service cannot be reached after retries. `offline` generates a name that follows the convention locally, using the provider's `offline_slugs`, and marks the claim `pending_registration`, so emergency deploys are not blocked. Claims without an `index` take index 1. Cannot be combined with a custom `template`, or with `index_reuse` when `index` is not set. Unset fails the apply.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(fallbackOffline),
+				},
+			},
+			"pending_registration": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "True while the name was generated offline and has not been registered with the service yet. The next refresh that reaches the service registers it.",
+			},
+			"retired": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "True when the service has retired (tombstoned) the name, so it can no longer be used. A retired claim stays in state and is replaced with a new name on the next apply.",
+			},
+			"effective_project": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Project the service recorded for the claim, including a default it applied when `project` is unset. It is only part of the name when the claim's `template` places it.",
+				PlanModifiers: []planmodifier.String{
+					keepCanonicalSegment{resource: r},
+				},
+			},
+			"effective_purpose": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Purpose the service recorded for the claim, including a default it applied when `purpose` is unset. It is only part of the name when the claim's `template` places it.",
+				PlanModifiers: []planmodifier.String{
+					keepCanonicalSegment{resource: r},
+				},
+			},
+			"effective_system": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "System segment the service used, including a default it applied when `system` is unset.",
+				PlanModifiers: []planmodifier.String{
+					keepCanonicalSegment{resource: r},
+				},
+			},
+			"effective_subsystem": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Subsystem segment the service used, including a default it applied when `subsystem` is unset.",
+				PlanModifiers: []planmodifier.String{
+					keepCanonicalSegment{resource: r},
+				},
+			},
+			"effective_index": schema.Int64Attribute{
+				Computed:            true,
+				CustomType:          indexNumberType{},
+				MarkdownDescription: "Index segment the service used, as a number, or null when the name has none. Use it in arithmetic such as `effective_index + 1`, and `format(\"%02d\", ...)` to render it as it appears in the name.",
+			},
+			"expires_at": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "RFC 3339 time at which the service releases the claim, for ephemeral environments. Changing it renews the claim without releasing the name.",
+			},
+			"expires_in": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time left until `expires_at` as of the last refresh (for example `71h59m0s`), `0s` once expired, or null without an expiry.",
+			},
+			"release_after": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Duration after claiming (for example `720h`) at which the service releases the name on its own, so claims for temporary environments do not outlive a workspace that is never destroyed. Changing it re-claims the name.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("expires_at")),
+				},
+			},
+			"release_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "RFC 3339 time at which the service will release the name because of `release_after`.",
+			},
+			"index_reuse": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "When `index` is not set, have the service allocate the lowest free index, and decide whether that may be the index of a released name: `never`, `after_hold` (once the environment's released-name retention has passed), or `always`. Overrides the provider's `index_reuse`. Only used when the name is allocated, so changing it does not re-claim the name.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(indexReusePolicies...),
+				},
+			},
+			"case": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Case of the returned `name`: `lower`, `upper`, or `preserve` (the default) to keep the service's output. Resource types that require lowercase names, such as storage accounts, are always lowercased and reject `upper` at plan time.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("lower", "upper", "preserve"),
+				},
+			},
+			"dry_run": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "When true, preview the name without claiming it. Nothing is registered with the service and destroy does not release anything.",
+			},
+			"unique_suffix": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Append a deterministic short hash to the name to guarantee global uniqueness (for example, for storage accounts).",
+			},
+			"unique_length": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(4),
+				MarkdownDescription: "Number of hash characters appended when unique_suffix is set (default 4).",
+				Validators: []validator.Int64{
+					int64validator.Between(3, 12),
+				},
+			},
+			"unique_seed": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Extra input mixed into the hash, typically the target subscription ID.",
+			},
+			"suffix": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The hash suffix sent to the service, derived from unique_seed and the name segments.",
+			},
+			"template": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Naming convention override for this claim, for example `{slug}-{project}-{env}-{region}-{index}`, for legacy systems that cannot follow the organization-wide convention. Sent to the service, which records it with the claim. Placeholders: `slug`, `region`, `environment` (or `env`), `project`, `purpose`, `system`, `subsystem`, `index`, `sanmar_prefix`, and `<segment>_segment` for an optional segment with a leading hyphen.",
+			},
+			"name_hyphenated": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The generated name with its segments separated by hyphens.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name_upper": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The generated name in upper case.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dns_label": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "RFC 1035 safe DNS label derived from the name (lowercase, at most 63 characters).",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dns_prefix": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "For resource types whose rules make the name a DNS label (currently storage accounts), the name as a DNS label. Claims whose name would change beyond lowercasing fail at plan time, or at apply for names only known then. Null for other resource types.",
+			},
+			"endpoints": schema.MapAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Azure public cloud endpoints and host names derived from the name, keyed as the azurerm provider names them: `blob`, `queue`, `table`, `file`, and `dfs` for storage accounts, `vault_uri` and `dns_name` for key vaults, `login_server` for container registries, `fqdn` for SQL servers, and `default_hostname` for app services and function apps. Empty for other resource types.",
+			},
+			"storage_safe": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Lowercase alphanumeric form of the name truncated to 24 characters.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -183,11 +660,246 @@ func (r *ClaimResource) Configure(_ context.Context, req resource.ConfigureReque
 	r.client = client
 }
 
+// ValidateConfig checks segment values locally so common mistakes surface
+// at plan time without a network call. Convention violations are left to
+// ModifyPlan, which knows the provider's validation_mode.
+func (r *ClaimResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config claimResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateSegmentConflicts(config)...)
+	_, other := splitConventions(validateClaimModel(config))
+	resp.Diagnostics.Append(other...)
+}
+
+// ModifyPlan computes plan-time values such as the deterministic unique suffix.
+func (r *ClaimResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy beyond reporting the release.
+	if req.Plan.Raw.IsNull() {
+		r.reportPlannedChange(ctx, req.State, req.Plan)
+		return
+	}
+
+	var plan claimResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// New claims are never retired; retired ones are replaced with a new name.
+	var retired types.Bool
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("retired"), &retired)...)
+	}
+	if req.State.Raw.IsNull() || retired.ValueBool() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("retired"), types.BoolValue(false))...)
+	}
+	if retired.ValueBool() {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("retired"))
+	}
+
+	// A claim interrupted during create is resumed by Update, which fills in
+	// everything the claim decides.
+	var priorName types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &priorName)...)
+	}
+	if !req.State.Raw.IsNull() && priorName.IsNull() {
+		if attempt, ok := loadClaimAttempt(ctx, req.Private); ok && attempt.Pending {
+			computed := map[string]bool{}
+			for name, attr := range resp.Plan.Schema.GetAttributes() {
+				computed[name] = attr.IsComputed()
+			}
+			raw, err := resumedClaimPlan(resp.Plan.Raw, computed)
+			if err != nil {
+				resp.Diagnostics.AddError("Failed to plan resumed claim", err.Error())
+				return
+			}
+			resp.Plan.Raw = raw
+			resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	var config claimResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	conventions, _ := splitConventions(validateClaimModel(config))
+	resp.Diagnostics.Append(r.client.conventionDiagnostics(conventions)...)
+
+	if r.client != nil {
+		resp.Diagnostics.Append(r.client.conventionDiagnostics(validateEnvironment(plan.Environment.StringValue, r.client.allowedEnvironments))...)
+		resp.Diagnostics.Append(r.validateProject(ctx, req.State, plan, false)...)
+		if req.State.Raw.IsNull() {
+			r.planSegmentDefaults(ctx, plan, resp)
+		}
+	}
+
+	// Changing case alone keeps the claim, so the new name is known now.
+	if !plan.Name.IsNull() && !plan.Name.IsUnknown() && !plan.Case.IsUnknown() {
+		plan.Name = types.StringValue(plan.applyCase(plan.Name.ValueString()))
+		plan.setNameVariants()
+		resp.Diagnostics.Append(r.client.conventionDiagnostics(validateDNSName(plan))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("name"), plan.Name)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("dns_prefix"), plan.DNSPrefix)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("endpoints"), plan.Endpoints)...)
+	}
+
+	// Without a configured segments object, expose the top-level segments.
+	var configSegments types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("segments"), &configSegments)...)
+	if configSegments.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("segments"), plan.segmentsFromFlat())...)
+	}
+
+	plan.Suffix = r.plannedSuffix(ctx, req.State, plan)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("suffix"), plan.Suffix)...)
+	resp.Diagnostics.Append(r.checkPlannedNameConflict(ctx, req.State, plan, retired.ValueBool())...)
+	resp.Diagnostics.Append(r.checkCollision(ctx, req.State, plan, retired.ValueBool())...)
+	if r.client != nil {
+		resp.Diagnostics.Append(leaseExpiry(plan, time.Now(), r.client.leaseHorizon)...)
+	}
+	if !resp.Diagnostics.HasError() {
+		r.reportPlannedChange(ctx, req.State, resp.Plan)
+	}
+}
+
+// plannedSuffix returns the unique suffix for plan. Segments that only differ
+// from state in spelling the service canonicalizes away are hashed as they
+// are in state, so the suffix, and with it the name, is kept.
+func (r *ClaimResource) plannedSuffix(ctx context.Context, state tfsdk.State, plan claimResourceModel) types.String {
+	if r.client == nil || state.Raw.IsNull() {
+		return plannedSuffix(plan)
+	}
+
+	var prior claimResourceModel
+	if diags := state.Get(ctx, &prior); diags.HasError() {
+		return plannedSuffix(plan)
+	}
+	planned, current := plan.resolveSegments(), prior.resolveSegments()
+	if !r.client.sameCanonicalSegments(ctx, planned, current) {
+		return plannedSuffix(plan)
+	}
+	planned.Project = current.Project
+	planned.Purpose = current.Purpose
+	planned.System = current.System
+	planned.Subsystem = current.Subsystem
+	suffix := plannedSuffix(planned)
+	// State from before index was a number hashed the index as it was
+	// written, such as "01", so keep the suffix while the inputs are unchanged.
+	if !prior.Suffix.IsNull() && suffix.Equal(plannedSuffix(current)) {
+		return prior.Suffix
+	}
+	return suffix
+}
+
+// validateProject checks new or changed project segments against the
+// service's project registry. Claims whose project is unchanged are not
+// checked, so retiring a project does not break existing claims. Resources
+// are planned concurrently, so while planning an unregistered code is only a
+// warning: a sanmar_project resource in the same configuration may not have
+// been planned yet. When applying, the code is looked up again and an
+// unregistered one stops the claim before any name is claimed.
+func (r *ClaimResource) validateProject(ctx context.Context, state tfsdk.State, plan claimResourceModel, applying bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	project := plan.resolveSegments().Project
+	if project.IsNull() || project.IsUnknown() {
+		return diags
+	}
+
+	if !state.Raw.IsNull() {
+		var prior claimResourceModel
+		diags.Append(state.Get(ctx, &prior)...)
+		if diags.HasError() || prior.resolveSegments().Project.Equal(project) {
+			return diags
+		}
+	}
+	if !applying && r.client.plannedProject(project.ValueString()) {
+		return diags
+	}
+
+	known, err := r.client.knownProject(ctx, project.ValueString())
+	if err != nil {
+		next := "The project is checked again when the claim is applied."
+		if applying {
+			next = "The name is claimed without checking the project."
+		}
+		diags.AddWarning("Unable to validate project", fmt.Sprintf("failed to look up registered projects: %v. %s", err, next))
+		return diags
+	}
+	switch {
+	case known:
+	case applying:
+		diags.AddAttributeError(plan.segmentPath("project"), "Unknown project",
+			fmt.Sprintf("project %q is not registered with the naming service. Register it with a sanmar_project resource, referencing its code so it is registered first, or ask the platform team to add it.", project.ValueString()))
+	default:
+		diags.AddAttributeWarning(plan.segmentPath("project"), "Unregistered project",
+			fmt.Sprintf("project %q is not registered with the naming service yet. Applying fails unless it is registered before the name is claimed, for example by a sanmar_project resource whose code this claim references.", project.ValueString()))
+	}
+	return diags
+}
+
+// plannedSuffix derives the unique suffix from the seed and segments, or
+// returns unknown while any of them are still unknown.
+func plannedSuffix(plan claimResourceModel) types.String {
+	plan = plan.resolveSegments()
+	if plan.UniqueSuffix.IsUnknown() {
+		return types.StringUnknown()
+	}
+	if !plan.UniqueSuffix.ValueBool() {
+		return types.StringNull()
+	}
+
+	inputs := []types.String{
+		plan.UniqueSeed,
+		plan.ResourceType,
+		plan.Region.StringValue,
+		plan.Environment.StringValue,
+		plan.Project,
+		plan.Purpose,
+		plan.System,
+		plan.Subsystem,
+		plan.Index.segment(),
+	}
+	values := make([]string, 0, len(inputs))
+	for _, in := range inputs {
+		if in.IsUnknown() {
+			return types.StringUnknown()
+		}
+		values = append(values, in.ValueString())
+	}
+	if plan.UniqueLength.IsUnknown() || plan.Keepers.IsUnknown() {
+		return types.StringUnknown()
+	}
+
+	if !plan.Keepers.IsNull() {
+		keepers := plan.Keepers.Elements()
+		keys := make([]string, 0, len(keepers))
+		for k := range keepers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, ok := keepers[k].(types.String)
+			if !ok || v.IsUnknown() {
+				return types.StringUnknown()
+			}
+			values = append(values, k+"="+v.ValueString())
+		}
+	}
+
+	return types.StringValue(uniqueSuffix(int(plan.UniqueLength.ValueInt64()), values...))
+}
+
 func (r *ClaimResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	if r.client == nil {
 		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
 		return
 	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
 
 	var plan claimResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -195,30 +907,165 @@ func (r *ClaimResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	ctx = maskSensitiveMetadata(ctx, plan)
+	resp.Diagnostics.Append(r.client.conventionDiagnostics(validateClaimModel(plan))...)
 	payload, diags := buildClaimPayload(ctx, plan)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(r.validateProject(ctx, tfsdk.State{}, plan, true)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	if expiresAt, _ := parseExpiresAt(plan.ExpiresAt); !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		resp.Diagnostics.AddAttributeError(path.Root("expires_at"), "Invalid expires_at", "expires_at must be in the future when claiming a name.")
+		return
+	}
+
+	attempt, err := newClaimAttempt()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to claim name", err.Error())
+		return
+	}
+
 	tflog.Info(ctx, "claiming name via SanMar provider", map[string]any{
-		"resource_type": payload.ResourceType,
-		"region":        payload.Region,
-		"environment":   payload.Environment,
+		"resource_type":  payload.ResourceType,
+		"region":         payload.Region,
+		"environment":    payload.Environment,
+		"dry_run":        plan.DryRun.ValueBool(),
+		"correlation_id": attempt.CorrelationID,
 	})
 
-	claim, err := r.client.ClaimName(ctx, payload)
+	claim, journalID, err := r.claimOrPreview(ctx, plan, attempt.apply(payload))
+	offline := false
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to claim name", err.Error())
+		if claim = r.claimOffline(ctx, plan, err, &resp.Diagnostics); claim == nil {
+			if !plan.DryRun.ValueBool() && claimOutcomeUnknown(err) && r.keepPendingClaim(ctx, req.Plan.Raw, attempt, err, resp) {
+				return
+			}
+			addClaimError(&resp.Diagnostics, "Failed to claim name", err)
+			return
+		}
+		offline = true
+	}
+
+	r.applyClaim(&plan, claim, &resp.Diagnostics)
+	plan.PendingRegistration = types.BoolValue(offline)
+	if offline {
+		attempt.OfflineAt = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	if !r.commitState(ctx, &resp.State, plan, journalID, &resp.Diagnostics) {
 		return
 	}
+	resp.Diagnostics.Append(setClaimIdentity(ctx, resp.Identity, plan)...)
+	// The claim is made, so a failed link is only a warning. The next
+	// refresh finds the link missing and the next apply retries it.
+	if !plan.AzureResourceID.IsNull() {
+		if err := r.linkAzureResource(ctx, plan); err != nil {
+			resp.Diagnostics.AddWarning("Failed to link Azure resource", err.Error())
+		}
+	}
+	resp.Diagnostics.Append(attempt.save(ctx, resp.Private)...)
+	resp.Diagnostics.Append(markClaimVerified(ctx, resp.Private, time.Now())...)
+}
+
+// applyClaim fills the values a claim decides into m.
+func (r *ClaimResource) applyClaim(m *claimResourceModel, claim *ClaimNameResponse, diags *diag.Diagnostics) {
+	m.ID = types.StringValue(claim.Name)
+	m.Name = types.StringValue(m.applyCase(claim.Name))
+	m.ClaimedBy = types.StringValue(claim.ClaimedBy)
+	m.Retired = types.BoolValue(false)
+	m.PendingRegistration = types.BoolValue(false)
+	m.Slug = types.StringValue(claim.Slug)
+	m.setEffectiveSegments(claim.Project, claim.Purpose, claim.System, claim.Subsystem, claim.Index)
+	m.setNameVariants()
+	m.setTagContract(time.Now().UTC().Format(claimDateLayout))
+	// The claim is kept in state, tainted, so the next apply replaces it.
+	diags.Append(r.client.conventionDiagnostics(validateDNSName(*m))...)
+	expiresIn, expiryDiags := claimExpiry(claim.Name, m.ExpiresAt, time.Now(), r.client.expiryWarningWindow)
+	diags.Append(expiryDiags...)
+	m.ExpiresIn = expiresIn
+	m.ReleaseAt = scheduledRelease(*m, claim)
+}
+
+// keepPendingClaim saves the planned state without a name, along with the
+// claim attempt, when it is unknown whether the claim was made. The create
+// succeeds so the resource is not tainted; the next plan shows an update, and
+// Update resumes the claim with the same idempotency key, adopting any name
+// the service gave it instead of claiming another. It reports whether the
+// pending claim was saved.
+func (r *ClaimResource) keepPendingClaim(ctx context.Context, planned tftypes.Value, attempt claimAttempt, claimErr error, resp *resource.CreateResponse) bool {
+	raw, err := pendingClaimState(planned)
+	if err != nil {
+		tflog.Warn(ctx, "failed to save interrupted claim for resuming", map[string]any{"error": err.Error()})
+		return false
+	}
+	resp.State.Raw = raw
+	var plan claimResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(setClaimIdentity(ctx, resp.Identity, plan)...)
+	attempt.Pending = true
+	resp.Diagnostics.Append(attempt.save(ctx, resp.Private)...)
+	resp.Diagnostics.AddWarning("Claim will be resumed", fmt.Sprintf("The naming service may have claimed a name before the request failed (correlation ID %s): %s. The resource is kept without a name, so resources that use the name fail in this run. The next apply resumes the claim, adopting that name rather than claiming another.", attempt.CorrelationID, claimErr))
+	return true
+}
+
+// linkAzureResource records plan's azure_resource_id, or removes the link
+// when it is unset. Previewed names are never claimed, so are not linked.
+func (r *ClaimResource) linkAzureResource(ctx context.Context, plan claimResourceModel) error {
+	if plan.DryRun.ValueBool() {
+		return nil
+	}
+	return r.client.LinkAzureResource(ctx, plan.Region.ValueString(), plan.Environment.ValueString(), plan.ID.ValueString(), plan.AzureResourceID.ValueString())
+}
 
-	plan.ID = types.StringValue(claim.Name)
-	plan.Name = types.StringValue(claim.Name)
-	plan.ClaimedBy = types.StringValue(claim.ClaimedBy)
-	plan.Slug = types.StringValue(claim.Slug)
+// claimOrPreview claims the name, or only previews it when dry_run is set.
+// Claims are journaled until commitState records them in state; the returned
+// journal ID is empty for previews or when claim_journal_dir is unset.
+func (r *ClaimResource) claimOrPreview(ctx context.Context, plan claimResourceModel, payload ClaimNameRequest) (*ClaimNameResponse, string, error) {
+	if plan.DryRun.ValueBool() {
+		claim, err := r.client.PreviewName(ctx, payload)
+		return claim, "", err
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	journalID := r.client.journal.begin(ctx, r.client.endpoint, payload)
+	claim, err := r.client.ClaimName(WithClaimPriority(ctx, plan.Priority.ValueInt64()), payload)
+	if err != nil {
+		r.client.journal.finish(ctx, journalID, journalFailed)
+		return nil, "", err
+	}
+	r.client.journal.claimed(ctx, journalID, claim.Name, payload.Region, payload.Environment)
+	return claim, journalID, nil
+}
+
+// commitState writes plan to state and closes its claim in the journal. A
+// name that cannot be written to state is released straight away, since
+// Terraform would never release it. It reports whether state was written.
+func (r *ClaimResource) commitState(ctx context.Context, state *tfsdk.State, plan claimResourceModel, journalID string, diags *diag.Diagnostics) bool {
+	setDiags := state.Set(ctx, &plan)
+	diags.Append(setDiags...)
+	if !setDiags.HasError() {
+		r.client.journal.finish(ctx, journalID, journalCommitted)
+		return true
+	}
+	if plan.DryRun.ValueBool() {
+		return false
+	}
+
+	err := r.client.EnsureReleased(ctx, ReleaseRequest{
+		Name:        plan.ID.ValueString(),
+		Region:      plan.Region.ValueString(),
+		Environment: plan.Environment.ValueString(),
+		Reason:      "Released after Terraform failed to record the claim in state",
+	})
+	if err != nil {
+		// The claim stays open in the journal, so the next provider start
+		// retries the release.
+		diags.AddError("Failed to release unrecorded name", fmt.Sprintf("%s was claimed but could not be recorded in state or released: %s", plan.ID.ValueString(), err))
+		return false
+	}
+	r.client.journal.finish(ctx, journalID, journalReleased)
+	return false
 }
 
 func (r *ClaimResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -226,12 +1073,56 @@ func (r *ClaimResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
 		return
 	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
 
 	var state claimResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	// States written before the resource had an identity get one here.
+	resp.Diagnostics.Append(setClaimIdentity(ctx, resp.Identity, state)...)
+
+	// Time to expiry is refreshed even when the audit call is skipped below.
+	expiresIn, diags := claimExpiry(state.Name.ValueString(), state.ExpiresAt, time.Now(), r.client.expiryWarningWindow)
+	resp.Diagnostics.Append(diags...)
+	state.ExpiresIn = expiresIn
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("expires_in"), expiresIn)...)
+
+	// Previewed names are never registered, so there is no audit record to refresh from.
+	if state.DryRun.ValueBool() {
+		return
+	}
+
+	// A claim interrupted during create has no name to refresh yet. Refresh
+	// never claims, so it is left for Update to resume.
+	if attempt, ok := loadClaimAttempt(ctx, req.Private); ok && attempt.Pending && state.Name.IsNull() {
+		return
+	}
+
+	// Names generated offline are registered before anything else, since the
+	// service has no record of them yet.
+	if state.PendingRegistration.ValueBool() {
+		r.registerOfflineClaim(ctx, state, req, resp)
+		return
+	}
+
+	if state.SkipRead.ValueBool() && os.Getenv(forceRefreshEnv) == "" {
+		return
+	}
+
+	// Claims confirmed recently (for example by the apply just before this
+	// plan) are trusted without another audit call.
+	cached := claimVerifiedWithin(ctx, req.Private, r.client.readCacheTTL, time.Now())
+	if r.client.readCacheTTL > 0 {
+		metrics.observeReadCache(cached)
+	}
+	if cached {
+		tflog.Debug(ctx, "skipping audit refresh for recently verified claim", map[string]any{
+			"name": state.Name.ValueString(),
+		})
+		return
+	}
 
 	record, err := r.client.GetAudit(ctx, state.Region.ValueString(), state.Environment.ValueString(), state.Name.ValueString())
 	if err != nil {
@@ -239,14 +1130,63 @@ func (r *ClaimResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	if record != nil && record.Retired {
+		detail := fmt.Sprintf("The naming service has retired %q, so it can no longer be used. The next apply replaces this claim with a new name.", state.Name.ValueString())
+		if record.RetiredReason != "" {
+			detail += " Reason: " + record.RetiredReason
+		}
+		resp.Diagnostics.AddWarning("Claimed name retired", detail)
+		state.Retired = types.BoolValue(true)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
 	if record == nil || !record.InUse {
 		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	// Imported claims only carry their identity; fill in the segments and
+	// metadata from the audit record so generated configuration is complete.
+	if state.ResourceType.IsNull() {
+		state.ResourceType = types.StringValue(record.Resource)
+		state.Project = optionalString(record.Project)
+		state.Purpose = optionalString(record.Purpose)
+		state.Subsystem = optionalString(record.Subsystem)
+		state.System = optionalString(record.System)
+		state.Index = indexNumber(record.Index)
+		state.ExpiresAt = recordedExpiry(state.ExpiresAt, record.ExpiresAt)
+		state.ReleaseAt = optionalString(record.ReleaseAt)
+		state.AzureResourceID = optionalString(record.AzureResourceID)
+		state.ExpiresIn, diags = claimExpiry(record.Name, state.ExpiresAt, time.Now(), r.client.expiryWarningWindow)
+		resp.Diagnostics.Append(diags...)
+		delete(record.Metadata, CleanupMetadataKey)
+		delete(record.Metadata, offlineGeneratedAtKey)
+		if len(record.Metadata) > 0 {
+			metadata, diags := types.MapValueFrom(ctx, types.StringType, record.Metadata)
+			resp.Diagnostics.Append(diags...)
+			state.Metadata = metadata
+		}
+		state.fillStateDefaults()
+	}
+
 	state.ClaimedBy = types.StringValue(record.ClaimedBy)
 	state.Slug = types.StringValue(record.Slug)
+	state.Retired = types.BoolValue(false)
+	state.setEffectiveSegments(record.Project, record.Purpose, record.System, record.Subsystem, record.Index)
+	state.setNameVariants()
+	claimDate := auditClaimDate(record.ClaimedAt)
+	if claimDate == "" {
+		claimDate = state.claimDate()
+	}
+	state.setTagContract(claimDate)
+	// Only claims that set the link track it, so links made elsewhere are
+	// not removed.
+	if !state.AzureResourceID.IsNull() {
+		state.AzureResourceID = optionalString(record.AzureResourceID)
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(markClaimVerified(ctx, resp.Private, time.Now())...)
 }
 
 func (r *ClaimResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -254,6 +1194,7 @@ func (r *ClaimResource) Update(ctx context.Context, req resource.UpdateRequest,
 		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
 		return
 	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
 
 	var plan claimResourceModel
 	var state claimResourceModel
@@ -263,52 +1204,139 @@ func (r *ClaimResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// If nothing relevant changed, keep existing state.
-	if plan.ResourceType.Equal(state.ResourceType) &&
-		plan.Region.Equal(state.Region) &&
-		plan.Environment.Equal(state.Environment) &&
-		plan.Project.Equal(state.Project) &&
-		plan.Purpose.Equal(state.Purpose) &&
-		plan.Subsystem.Equal(state.Subsystem) &&
-		plan.System.Equal(state.System) &&
-		plan.Index.Equal(state.Index) &&
+	// A claim interrupted during create is resumed with its idempotency key,
+	// adopting the name the service gave it before anything else changes.
+	if attempt, ok := loadClaimAttempt(ctx, req.Private); ok && attempt.Pending && state.Name.IsNull() {
+		claim, err := r.resumeClaim(ctx, state, attempt)
+		switch {
+		case err == nil:
+			r.applyClaim(&state, claim, &resp.Diagnostics)
+			attempt.Pending = false
+			resp.Diagnostics.Append(attempt.save(ctx, resp.Private)...)
+		case claimOutcomeUnknown(err):
+			resp.Diagnostics.AddError("Failed to resume claim", fmt.Sprintf("The interrupted claim (correlation ID %s) is retried on the next apply: %s", attempt.CorrelationID, err))
+			return
+		}
+		// A refused claim never made a name, so a new one is claimed below.
+	}
+
+	// If nothing relevant changed, keep the existing claim. Moving a segment
+	// between the top level and the segments object, or spelling it the way
+	// the service canonicalizes it, is not a change.
+	planned, current := plan.resolveSegments(), state.resolveSegments()
+	if !state.Name.IsNull() &&
+		plan.ResourceType.Equal(state.ResourceType) &&
+		plan.Region.sameSegment(state.Region) &&
+		plan.Environment.sameSegment(state.Environment) &&
+		r.client.sameCanonicalSegments(ctx, planned, current) &&
+		planned.Index.Equal(current.Index) &&
 		plan.SessionID.Equal(state.SessionID) &&
-		plan.Metadata.Equal(state.Metadata) {
-		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		plan.Metadata.Equal(state.Metadata) &&
+		plan.MetadataValues.Equal(state.MetadataValues) &&
+		plan.SensitiveMetadata.Equal(state.SensitiveMetadata) &&
+		plan.Suffix.Equal(state.Suffix) &&
+		plan.ReleaseAfter.Equal(state.ReleaseAfter) &&
+		plan.Template.Equal(state.Template) &&
+		plan.DryRun.ValueBool() == state.DryRun.ValueBool() {
+		// A new expiry renews the claim in place.
+		if !plan.ExpiresAt.Equal(state.ExpiresAt) && !plan.DryRun.ValueBool() {
+			if err := r.client.RenewClaim(ctx, RenewRequest{
+				Name:        state.Name.ValueString(),
+				Region:      state.Region.ValueString(),
+				Environment: state.Environment.ValueString(),
+				ExpiresAt:   plan.ExpiresAt.ValueString(),
+			}); err != nil {
+				resp.Diagnostics.AddError("Failed to renew claim", err.Error())
+				return
+			}
+		}
+
+		plan.ID = state.ID
+		plan.Name = types.StringValue(plan.applyCase(state.Name.ValueString()))
+		plan.ClaimedBy = state.ClaimedBy
+		plan.Retired = types.BoolValue(false)
+		plan.PendingRegistration = state.PendingRegistration
+		plan.Slug = state.Slug
+		plan.ReleaseAt = state.ReleaseAt
+		plan.keepEffectiveSegments(state)
+		plan.setNameVariants()
+		plan.setTagContract(state.claimDate())
+		expiresIn, diags := claimExpiry(plan.Name.ValueString(), plan.ExpiresAt, time.Now(), r.client.expiryWarningWindow)
+		resp.Diagnostics.Append(diags...)
+		plan.ExpiresIn = expiresIn
+		if !plan.AzureResourceID.Equal(state.AzureResourceID) {
+			if err := r.linkAzureResource(ctx, plan); err != nil {
+				resp.Diagnostics.AddError("Failed to link Azure resource", err.Error())
+				return
+			}
+		}
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		resp.Diagnostics.Append(setClaimIdentity(ctx, resp.Identity, plan)...)
 		return
 	}
 
-	// Release the existing claim.
-	releasePayload := ReleaseRequest{
-		Name:        state.Name.ValueString(),
-		Region:      state.Region.ValueString(),
-		Environment: state.Environment.ValueString(),
-		Reason:      "terraform update",
+	// Check a new project before the existing claim is given up.
+	resp.Diagnostics.Append(r.validateProject(ctx, req.State, plan, true)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if err := r.client.ReleaseName(ctx, releasePayload); err != nil {
-		resp.Diagnostics.AddError("Failed to release existing name", err.Error())
-		return
+	// Release the existing claim; previewed names were never claimed, and
+	// refused resumed claims never made one.
+	if !state.DryRun.ValueBool() && !state.Name.IsNull() {
+		releasePayload := ReleaseRequest{
+			Name:        state.Name.ValueString(),
+			Region:      state.Region.ValueString(),
+			Environment: state.Environment.ValueString(),
+			Reason:      releaseReason(plan.ReleaseReason, "terraform update"),
+		}
+
+		if err := r.client.EnsureReleased(ctx, releasePayload); err != nil {
+			resp.Diagnostics.AddError("Failed to release existing name", err.Error())
+			return
+		}
 	}
 
+	ctx = maskSensitiveMetadata(ctx, plan)
+	resp.Diagnostics.Append(r.client.conventionDiagnostics(validateClaimModel(plan))...)
 	payload, diags := buildClaimPayload(ctx, plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	claim, err := r.client.ClaimName(ctx, payload)
+	attempt, err := newClaimAttempt()
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to claim replacement name", err.Error())
 		return
 	}
+	claim, journalID, err := r.claimOrPreview(ctx, plan, attempt.apply(payload))
+	offline := false
+	if err != nil {
+		if claim = r.claimOffline(ctx, plan, err, &resp.Diagnostics); claim == nil {
+			addClaimError(&resp.Diagnostics, "Failed to claim replacement name", err)
+			return
+		}
+		offline = true
+	}
 
-	plan.ID = types.StringValue(claim.Name)
-	plan.Name = types.StringValue(claim.Name)
-	plan.ClaimedBy = types.StringValue(claim.ClaimedBy)
-	plan.Slug = types.StringValue(claim.Slug)
+	r.applyClaim(&plan, claim, &resp.Diagnostics)
+	plan.PendingRegistration = types.BoolValue(offline)
+	if offline {
+		attempt.OfflineAt = time.Now().UTC().Format(time.RFC3339)
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if !r.commitState(ctx, &resp.State, plan, journalID, &resp.Diagnostics) {
+		return
+	}
+	resp.Diagnostics.Append(setClaimIdentity(ctx, resp.Identity, plan)...)
+	if !plan.AzureResourceID.IsNull() {
+		if err := r.linkAzureResource(ctx, plan); err != nil {
+			resp.Diagnostics.AddWarning("Failed to link Azure resource", err.Error())
+		}
+	}
+	resp.Diagnostics.Append(attempt.save(ctx, resp.Private)...)
+	resp.Diagnostics.Append(markClaimVerified(ctx, resp.Private, time.Now())...)
 }
 
 func (r *ClaimResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -316,6 +1344,7 @@ func (r *ClaimResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
 		return
 	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
 
 	var state claimResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -323,7 +1352,21 @@ func (r *ClaimResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	if state.Name.IsNull() || state.Name.ValueString() == "" {
+	// A claim interrupted before its name was known, and not refreshed since,
+	// is resumed to learn the name to release.
+	if attempt, ok := loadClaimAttempt(ctx, req.Private); ok && attempt.Pending && state.Name.IsNull() {
+		claim, err := r.resumeClaim(ctx, state, attempt)
+		if err != nil && claimOutcomeUnknown(err) {
+			resp.Diagnostics.AddError("Failed to resume claim", fmt.Sprintf("The name claimed by the interrupted claim (correlation ID %s) could not be found, so it cannot be released: %s", attempt.CorrelationID, err))
+			return
+		}
+		if err == nil {
+			state.Name = types.StringValue(claim.Name)
+		}
+	}
+
+	// Retired names are no longer claimed, so there is nothing to release.
+	if state.Name.IsNull() || state.Name.ValueString() == "" || state.DryRun.ValueBool() || state.Retired.ValueBool() {
 		resp.State.RemoveResource(ctx)
 		return
 	}
@@ -332,16 +1375,87 @@ func (r *ClaimResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		Name:        state.Name.ValueString(),
 		Region:      state.Region.ValueString(),
 		Environment: state.Environment.ValueString(),
-		Reason:      "terraform destroy",
+		Reason:      releaseReason(state.ReleaseReason, "terraform destroy"),
 	}
 
-	if err := r.client.ReleaseName(ctx, payload); err != nil {
+	// Names released or retired since the last refresh, for example by an
+	// earlier destroy that failed part way, are already gone.
+	if err := r.client.EnsureReleased(ctx, payload); err != nil {
 		resp.Diagnostics.AddError("Failed to release name", err.Error())
 		return
 	}
 	resp.State.RemoveResource(ctx)
 }
 
+// ImportState accepts the claim identity "<region>/<environment>/<name>",
+// which is what the audit lookup in Read is keyed on, either as the import ID
+// or as an identity object. A bare name is resolved to its identity from the
+// active claims.
 func (r *ClaimResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var identity claimIdentity
+	switch {
+	case req.ID == "" && req.Identity != nil:
+		var model claimIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &model)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		identity = claimIdentity{Region: model.Region.ValueString(), Environment: model.Environment.ValueString(), Name: model.Name.ValueString()}
+	case !strings.Contains(req.ID, "/"):
+		var err error
+		if identity, err = r.lookupClaimIdentity(ctx, req.ID); err != nil {
+			resp.Diagnostics.AddError("Invalid import identifier", err.Error())
+			return
+		}
+	default:
+		var err error
+		if identity, err = parseClaimIdentity(req.ID); err != nil {
+			resp.Diagnostics.AddError("Invalid import identifier", err.Error())
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), identity.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("region"), identity.Region)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment"), identity.Environment)...)
+	if resp.Identity != nil {
+		resp.Diagnostics.Append(resp.Identity.Set(ctx, claimIdentityModel{
+			Region:      types.StringValue(identity.Region),
+			Environment: types.StringValue(identity.Environment),
+			Name:        types.StringValue(identity.Name),
+		})...)
+	}
+}
+
+// lookupClaimIdentity finds the region and environment of the active claim
+// called name. Names are only unique per region and environment, so a name
+// claimed in several of them must be imported by its full identity.
+func (r *ClaimResource) lookupClaimIdentity(ctx context.Context, name string) (claimIdentity, error) {
+	if name == "" {
+		return claimIdentity{}, errors.New("expected <region>/<environment>/<name> or a claimed name")
+	}
+	if r.client == nil {
+		return claimIdentity{}, errors.New("the provider must be configured to import a claim by name")
+	}
+	claims, err := r.client.ListClaims(ctx, ClaimFilter{})
+	if err != nil {
+		return claimIdentity{}, fmt.Errorf("failed to look up %q: %w", name, err)
+	}
+	var matches []string
+	var identity claimIdentity
+	for _, claim := range claims {
+		if strings.EqualFold(claim.Name, name) {
+			identity = claimIdentity{Region: claim.Region, Environment: claim.Environment, Name: claim.Name}
+			matches = append(matches, claim.Identity())
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return claimIdentity{}, fmt.Errorf("no active claim is named %q", name)
+	case 1:
+		return identity, nil
+	default:
+		return claimIdentity{}, fmt.Errorf("%q is claimed in more than one region or environment (%s); import it as <region>/<environment>/<name>", name, strings.Join(matches, ", "))
+	}
 }
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestDryRunPreviewsWithoutClaiming(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path != "/api/preview" {
+			http.Error(w, "unexpected call", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"wus2prdstsanmaratlas","resourceType":"storage_account","region":"wus2","environment":"prd","slug":"st","system":"atlas","available":true,"display":[]}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+
+	plan := claimResourceModel{DryRun: types.BoolValue(true)}
+	system := "atlas"
+	claim, journalID, err := r.claimOrPreview(ctx, plan, ClaimNameRequest{ResourceType: "storage_account", Region: "wus2", Environment: "prd", System: &system})
+	if err != nil {
+		t.Fatalf("claimOrPreview: %v", err)
+	}
+	if claim.Name != "wus2prdstsanmaratlas" || claim.System != "atlas" || journalID != "" {
+		t.Fatalf("unexpected preview: %#v %q", claim, journalID)
+	}
+
+	// Destroying a dry run releases nothing.
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	for attr, value := range map[string]any{"name": claim.Name, "region": "wus2", "environment": "prd", "dry_run": true} {
+		if diags := state.SetAttribute(ctx, path.Root(attr), value); diags.HasError() {
+			t.Fatalf("state: %v", diags)
+		}
+	}
+	resp := resource.DeleteResponse{State: state}
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Fatalf("expected the dry run to be removed, got %v", resp.Diagnostics)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("expected only the preview call, got %v", paths)
+	}
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func keepersMap(values map[string]attr.Value) types.Map {
+	return types.MapValueMust(types.StringType, values)
+}
+
+func TestKeepersChangeRequiresReplace(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	(&ClaimResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+	keepersAttr := s.Attributes["keepers"].(schema.MapAttribute)
+
+	withKeepers := func(keepers types.Map) claimResourceModel {
+		m := nullClaimModel(ctx, t, s)
+		m.ResourceType = types.StringValue("storage_account")
+		m.Keepers = keepers
+		return m
+	}
+	requiresReplace := func(prior, planned types.Map) bool {
+		state := tfsdk.State{Schema: s}
+		if diags := state.Set(ctx, withKeepers(prior)); diags.HasError() {
+			t.Fatalf("state: %v", diags)
+		}
+		plan := tfsdk.Plan{Schema: s}
+		if diags := plan.Set(ctx, withKeepers(planned)); diags.HasError() {
+			t.Fatalf("plan: %v", diags)
+		}
+		req := planmodifier.MapRequest{
+			Path:        path.Root("keepers"),
+			State:       state,
+			Plan:        plan,
+			StateValue:  prior,
+			PlanValue:   planned,
+			ConfigValue: planned,
+		}
+		resp := planmodifier.MapResponse{PlanValue: planned}
+		for _, m := range keepersAttr.PlanModifiers {
+			m.PlanModifyMap(ctx, req, &resp)
+		}
+		return resp.RequiresReplace
+	}
+
+	v1 := keepersMap(map[string]attr.Value{"generation": types.StringValue("1")})
+	v2 := keepersMap(map[string]attr.Value{"generation": types.StringValue("2")})
+	if requiresReplace(v1, v1) {
+		t.Fatalf("unchanged keepers should not replace the claim")
+	}
+	if !requiresReplace(v1, v2) {
+		t.Fatalf("changed keepers should replace the claim")
+	}
+	if !requiresReplace(types.MapNull(types.StringType), v1) {
+		t.Fatalf("adding keepers should replace the claim")
+	}
+}
+
+func TestPlannedSuffixMixesKeepers(t *testing.T) {
+	plan := func(keepers types.Map) claimResourceModel {
+		return claimResourceModel{
+			ResourceType: types.StringValue("storage_account"),
+			Region:       regionType.value("wus2"),
+			Environment:  environmentType.value("prd"),
+			Project:      types.StringValue("atlas"),
+			UniqueSuffix: types.BoolValue(true),
+			UniqueLength: types.Int64Value(6),
+			UniqueSeed:   types.StringValue("sub-1"),
+			Keepers:      keepers,
+		}
+	}
+
+	none := plannedSuffix(plan(types.MapNull(types.StringType)))
+	v1 := plannedSuffix(plan(keepersMap(map[string]attr.Value{"generation": types.StringValue("1"), "image": types.StringValue("a")})))
+	v1Again := plannedSuffix(plan(keepersMap(map[string]attr.Value{"image": types.StringValue("a"), "generation": types.StringValue("1")})))
+	v2 := plannedSuffix(plan(keepersMap(map[string]attr.Value{"generation": types.StringValue("2"), "image": types.StringValue("a")})))
+
+	if v1.ValueString() != v1Again.ValueString() {
+		t.Fatalf("expected the suffix to be stable, got %q and %q", v1, v1Again)
+	}
+	if v1.ValueString() == v2.ValueString() || v1.ValueString() == none.ValueString() {
+		t.Fatalf("expected keepers to change the suffix, got %q, %q and %q", none, v1, v2)
+	}
+	if len(v1.ValueString()) != 6 {
+		t.Fatalf("expected 6 characters, got %q", v1)
+	}
+
+	unknown := plannedSuffix(plan(keepersMap(map[string]attr.Value{"generation": types.StringUnknown()})))
+	if !unknown.IsUnknown() {
+		t.Fatalf("expected an unknown keeper to leave the suffix unknown, got %q", unknown)
+	}
+}
@@ -0,0 +1,226 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*ClaimLinkResource)(nil)
+var _ resource.ResourceWithImportState = (*ClaimLinkResource)(nil)
+
+// ClaimLinkResource records the Azure resource that uses a claimed name on
+// the claim's audit record.
+type ClaimLinkResource struct {
+	client *APIClient
+}
+
+// NewClaimLinkResource instantiates the resource.
+func NewClaimLinkResource() resource.Resource {
+	return &ClaimLinkResource{}
+}
+
+type claimLinkResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	Name            types.String `tfsdk:"name"`
+	Region          types.String `tfsdk:"region"`
+	Environment     types.String `tfsdk:"environment"`
+	AzureResourceID types.String `tfsdk:"azure_resource_id"`
+}
+
+func (r *ClaimLinkResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim_link"
+}
+
+func (r *ClaimLinkResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Links a claim to the Azure resource built with its name by recording the resource's ARM ID on the claim's audit record. Unlike `azure_resource_id` on `sanmar_claim`, it can take the ID from the resource that uses the claim's name without a dependency cycle. Destroying it removes the link but does not release the claim.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Claim identity in the form `<region>/<environment>/<name>`.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Name of the claim to link, usually `sanmar_claim.<label>.name`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Region of the claim.",
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Environment of the claim.",
+			},
+			"azure_resource_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ARM ID of the Azure resource that uses the name, usually the `id` of that resource.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(azureResourceIDPattern, "must be an Azure resource ID starting with /subscriptions/"),
+				},
+			},
+		},
+	}
+}
+
+func (r *ClaimLinkResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+// link records the planned ARM ID on the claim.
+func (r *ClaimLinkResource) link(ctx context.Context, plan *claimLinkResourceModel) error {
+	tflog.Info(ctx, "linking claim to Azure resource via SanMar provider", map[string]any{
+		"name":              plan.Name.ValueString(),
+		"azure_resource_id": plan.AzureResourceID.ValueString(),
+	})
+
+	err := r.client.LinkAzureResource(ctx, plan.Region.ValueString(), plan.Environment.ValueString(), plan.Name.ValueString(), plan.AzureResourceID.ValueString())
+	if err != nil {
+		return err
+	}
+
+	identity := claimIdentity{Region: plan.Region.ValueString(), Environment: plan.Environment.ValueString(), Name: plan.Name.ValueString()}
+	plan.ID = types.StringValue(identity.String())
+	return nil
+}
+
+func (r *ClaimLinkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan claimLinkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.link(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to link claim", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ClaimLinkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state claimLinkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	record, err := r.client.GetAudit(ctx, state.Region.ValueString(), state.Environment.ValueString(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read claim", err.Error())
+		return
+	}
+
+	// A released claim has nothing left to link.
+	if record == nil || !record.InUse {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// A link removed or changed outside Terraform is restored on the next apply.
+	state.AzureResourceID = optionalString(record.AzureResourceID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *ClaimLinkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan claimLinkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.link(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to link claim", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the link; the claim itself is kept.
+func (r *ClaimLinkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state claimLinkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.LinkAzureResource(ctx, state.Region.ValueString(), state.Environment.ValueString(), state.Name.ValueString(), "")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone) {
+		// Released or retired since the last refresh.
+		err = nil
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to unlink claim", err.Error())
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+// ImportState takes the claim identity, "<region>/<environment>/<name>".
+func (r *ClaimLinkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	identity, err := parseClaimIdentity(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.String())...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), identity.Name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("region"), identity.Region)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("environment"), identity.Environment)...)
+}
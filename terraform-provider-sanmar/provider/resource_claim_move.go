@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithMoveState = (*ClaimResource)(nil)
+
+// legacyClaimTypeName is the resource type of the previous SDKv2-based provider.
+const legacyClaimTypeName = "sanmar_naming_claim"
+
+// legacyClaimState is the state written by the SDKv2 sanmar_naming_claim
+// resource. It keyed claims by "<region>/<environment>/<name>", used env, type
+// and tags where sanmar_claim uses environment, resource_type and metadata, and
+// stored unset optional strings as "".
+type legacyClaimState struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`
+	Region    string            `json:"region"`
+	Env       string            `json:"env"`
+	Project   string            `json:"project"`
+	Purpose   string            `json:"purpose"`
+	Subsystem string            `json:"subsystem"`
+	System    string            `json:"system"`
+	Index     string            `json:"index"`
+	SessionID string            `json:"session_id"`
+	Tags      map[string]string `json:"tags"`
+	ClaimedBy string            `json:"claimed_by"`
+	Slug      string            `json:"slug"`
+}
+
+// MoveState lets `moved` blocks adopt claims from the legacy SDKv2 provider
+// without re-importing them.
+func (r *ClaimResource) MoveState(_ context.Context) []resource.StateMover {
+	return []resource.StateMover{
+		{StateMover: moveLegacyClaimState},
+	}
+}
+
+func moveLegacyClaimState(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+	// Leave the target state unset for other sources so the framework
+	// reports the move as unsupported.
+	if req.SourceTypeName != legacyClaimTypeName || req.SourceRawState == nil {
+		return
+	}
+
+	state, err := legacyClaimModel(req.SourceRawState.JSON)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to move legacy claim state", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.TargetState.Set(ctx, &state)...)
+}
+
+// legacyClaimModel converts raw SDKv2 state JSON into a sanmar_claim model.
+func legacyClaimModel(raw []byte) (claimResourceModel, error) {
+	var legacy legacyClaimState
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return claimResourceModel{}, fmt.Errorf("failed to decode legacy state: %w", err)
+	}
+
+	name := legacy.Name
+	if name == "" {
+		parts := strings.Split(legacy.ID, "/")
+		name = parts[len(parts)-1]
+	}
+	if name == "" || legacy.Type == "" || legacy.Region == "" || legacy.Env == "" {
+		return claimResourceModel{}, fmt.Errorf("legacy state %q is missing name, type, region or env", legacy.ID)
+	}
+
+	metadata := types.MapNull(types.StringType)
+	if len(legacy.Tags) > 0 {
+		elements := make(map[string]attr.Value, len(legacy.Tags))
+		for k, v := range legacy.Tags {
+			elements[k] = types.StringValue(v)
+		}
+		metadata = types.MapValueMust(types.StringType, elements)
+	}
+
+	state := claimResourceModel{
+		ID:                types.StringValue(name),
+		Name:              types.StringValue(name),
+		ResourceType:      types.StringValue(legacy.Type),
+		Region:            regionType.value(legacy.Region),
+		Environment:       environmentType.value(legacy.Env),
+		Project:           optionalString(legacy.Project),
+		Purpose:           optionalString(legacy.Purpose),
+		Subsystem:         optionalString(legacy.Subsystem),
+		System:            optionalString(legacy.System),
+		Index:             indexNumber(legacy.Index),
+		SessionID:         optionalString(legacy.SessionID),
+		Metadata:          metadata,
+		MetadataValues:    types.DynamicNull(),
+		SensitiveMetadata: types.MapNull(types.StringType),
+		Keepers:           types.MapNull(types.StringType),
+		ClaimedBy:         optionalString(legacy.ClaimedBy),
+		Slug:              optionalString(legacy.Slug),
+	}
+	state.fillStateDefaults()
+	return state, nil
+}
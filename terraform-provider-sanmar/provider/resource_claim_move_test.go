@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestLegacyClaimModel(t *testing.T) {
+	raw := []byte(`{
+		"id": "wus2/prd/kvwus2prdatlas",
+		"name": "",
+		"type": "key_vault",
+		"region": "wus2",
+		"env": "prd",
+		"project": "atlas",
+		"purpose": "",
+		"tags": {"owner": "finops"},
+		"slug": "kv",
+		"timeouts": null
+	}`)
+
+	got, err := legacyClaimModel(raw)
+	if err != nil {
+		t.Fatalf("legacyClaimModel: %v", err)
+	}
+	if got.ID.ValueString() != "kvwus2prdatlas" || got.Name.ValueString() != "kvwus2prdatlas" {
+		t.Fatalf("unexpected id/name: %s %s", got.ID, got.Name)
+	}
+	if got.ResourceType.ValueString() != "key_vault" || got.Environment.ValueString() != "prd" {
+		t.Fatalf("unexpected segments: %s %s", got.ResourceType, got.Environment)
+	}
+	if !got.Purpose.IsNull() {
+		t.Fatalf("expected empty legacy purpose to become null, got %s", got.Purpose)
+	}
+	if owner, ok := got.Metadata.Elements()["owner"]; !ok || owner.String() != `"finops"` {
+		t.Fatalf("expected tags to move to metadata, got %s", got.Metadata)
+	}
+	if got.NameHyphenated.ValueString() != "kv-wus2-prd-atlas" {
+		t.Fatalf("unexpected name_hyphenated %s", got.NameHyphenated)
+	}
+
+	if _, err := legacyClaimModel([]byte(`{"id": "x"}`)); err == nil {
+		t.Fatalf("expected error for incomplete legacy state")
+	}
+}
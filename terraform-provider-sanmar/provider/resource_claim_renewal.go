@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*ClaimRenewalResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*ClaimRenewalResource)(nil)
+var _ resource.ResourceWithValidateConfig = (*ClaimRenewalResource)(nil)
+
+// ClaimRenewalResource extends the lease on an existing claim on every apply.
+type ClaimRenewalResource struct {
+	client *APIClient
+}
+
+// NewClaimRenewalResource instantiates the resource.
+func NewClaimRenewalResource() resource.Resource {
+	return &ClaimRenewalResource{}
+}
+
+type claimRenewalResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Lease       types.String `tfsdk:"lease"`
+	Triggers    types.Map    `tfsdk:"triggers"`
+	RenewedAt   types.String `tfsdk:"renewed_at"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+func (r *ClaimRenewalResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_claim_renewal"
+}
+
+func (r *ClaimRenewalResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renews the lease on a claim every time it is applied, keeping long-lived names alive under lease-based service policies without changing the claim resource. Destroying it stops the renewals but does not release the claim.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Claim identity in the form `<region>/<environment>/<name>`.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Name of the claim to renew, usually `sanmar_claim.<label>.name`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Region of the claim.",
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Environment of the claim.",
+			},
+			"lease": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("720h"),
+				MarkdownDescription: "How long each renewal keeps the claim alive, as a duration (default `720h`).",
+			},
+			"triggers": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values recorded with the renewal, for example a release version, to tie renewals to other changes in the plan.",
+			},
+			"renewed_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "RFC 3339 time of the last renewal.",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "RFC 3339 time at which the lease runs out unless renewed again.",
+			},
+		},
+	}
+}
+
+func (r *ClaimRenewalResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data claimRenewalResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Lease.IsNull() || data.Lease.IsUnknown() {
+		return
+	}
+
+	if lease, err := time.ParseDuration(data.Lease.ValueString()); err != nil || lease <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("lease"), "Invalid lease",
+			fmt.Sprintf("lease must be a positive duration such as 720h, got %q.", data.Lease.ValueString()))
+	}
+}
+
+// ModifyPlan marks the renewal times unknown so every apply renews the claim.
+func (r *ClaimRenewalResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("renewed_at"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expires_at"), types.StringUnknown())...)
+}
+
+func (r *ClaimRenewalResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+// renew extends the claim's lease and records the renewal in plan.
+func (r *ClaimRenewalResource) renew(ctx context.Context, plan *claimRenewalResourceModel) error {
+	lease, err := time.ParseDuration(plan.Lease.ValueString())
+	if err != nil {
+		return fmt.Errorf("invalid lease: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(lease).Format(time.RFC3339)
+
+	tflog.Info(ctx, "renewing claim via SanMar provider", map[string]any{
+		"name":       plan.Name.ValueString(),
+		"expires_at": expiresAt,
+	})
+
+	err = r.client.RenewClaim(ctx, RenewRequest{
+		Name:        plan.Name.ValueString(),
+		Region:      plan.Region.ValueString(),
+		Environment: plan.Environment.ValueString(),
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	identity := claimIdentity{Region: plan.Region.ValueString(), Environment: plan.Environment.ValueString(), Name: plan.Name.ValueString()}
+	plan.ID = types.StringValue(identity.String())
+	plan.RenewedAt = types.StringValue(now.Format(time.RFC3339))
+	plan.ExpiresAt = types.StringValue(expiresAt)
+	return nil
+}
+
+func (r *ClaimRenewalResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan claimRenewalResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.renew(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to renew claim", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ClaimRenewalResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state claimRenewalResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	record, err := r.client.GetAudit(ctx, state.Region.ValueString(), state.Environment.ValueString(), state.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read claim", err.Error())
+		return
+	}
+
+	// Nothing is left to renew once the claim is released or has lapsed.
+	if record == nil || !record.InUse {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if record.ExpiresAt != "" {
+		state.ExpiresAt = recordedExpiry(state.ExpiresAt, record.ExpiresAt)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *ClaimRenewalResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan claimRenewalResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.renew(ctx, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to renew claim", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete stops renewing; the claim itself stays until its lease runs out or
+// it is released.
+func (r *ClaimRenewalResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func claimRenewalTestModel(lease string) claimRenewalResourceModel {
+	return claimRenewalResourceModel{
+		ID:          types.StringUnknown(),
+		Name:        types.StringValue("stwus2prdatlas01"),
+		Region:      types.StringValue("wus2"),
+		Environment: types.StringValue("prd"),
+		Lease:       types.StringValue(lease),
+		Triggers:    types.MapNull(types.StringType),
+		RenewedAt:   types.StringUnknown(),
+		ExpiresAt:   types.StringUnknown(),
+	}
+}
+
+func TestClaimRenewalPlansUnknownTimes(t *testing.T) {
+	ctx := context.Background()
+	r := &ClaimRenewalResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	prior := claimRenewalTestModel("720h")
+	prior.ID = types.StringValue("wus2/prd/stwus2prdatlas01")
+	prior.RenewedAt = types.StringValue("2026-01-01T00:00:00Z")
+	prior.ExpiresAt = types.StringValue("2026-01-31T00:00:00Z")
+	state := tfsdk.State{Schema: s}
+	if diags := state.Set(ctx, &prior); diags.HasError() {
+		t.Fatalf("state: %v", diags)
+	}
+
+	// Nothing in the configuration changed, so only ModifyPlan makes the
+	// resource plan a renewal.
+	plan := tfsdk.Plan{Schema: s, Raw: state.Raw}
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: plan, State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("ModifyPlan: %v", resp.Diagnostics)
+	}
+
+	var planned claimRenewalResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &planned)...)
+	if !planned.RenewedAt.IsUnknown() || !planned.ExpiresAt.IsUnknown() {
+		t.Fatalf("expected renewal times to be unknown, got %s and %s", planned.RenewedAt, planned.ExpiresAt)
+	}
+	if !planned.ID.Equal(prior.ID) {
+		t.Fatalf("expected the id to be kept, got %s", planned.ID)
+	}
+
+	// Destroy plans are left alone.
+	destroy := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	resp = resource.ModifyPlanResponse{Plan: destroy}
+	r.ModifyPlan(ctx, resource.ModifyPlanRequest{Plan: destroy, State: state}, &resp)
+	if resp.Diagnostics.HasError() || !resp.Plan.Raw.IsNull() {
+		t.Fatalf("expected the destroy plan to be unchanged, got %v %v", resp.Plan.Raw, resp.Diagnostics)
+	}
+}
+
+func TestClaimRenewalRenewsOnUpdate(t *testing.T) {
+	var renewals []RenewRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/claim/renew", func(w http.ResponseWriter, r *http.Request) {
+		var payload RenewRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		renewals = append(renewals, payload)
+		if payload.Name == "gone" {
+			http.Error(w, `{"message":"name not found"}`, http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimRenewalResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	update := func(m claimRenewalResourceModel) resource.UpdateResponse {
+		plan := tfsdk.Plan{Schema: s}
+		if diags := plan.Set(ctx, &m); diags.HasError() {
+			t.Fatalf("plan: %v", diags)
+		}
+		resp := resource.UpdateResponse{State: tfsdk.State{Schema: s}}
+		r.Update(ctx, resource.UpdateRequest{Plan: plan}, &resp)
+		return resp
+	}
+
+	before := time.Now().UTC().Truncate(time.Second)
+	resp := update(claimRenewalTestModel("48h"))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update: %v", resp.Diagnostics)
+	}
+	if len(renewals) != 1 {
+		t.Fatalf("expected one renewal, got %d", len(renewals))
+	}
+	sent := renewals[0]
+	if sent.Name != "stwus2prdatlas01" || sent.Region != "wus2" || sent.Environment != "prd" {
+		t.Fatalf("unexpected renewal: %#v", sent)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, sent.ExpiresAt)
+	if err != nil {
+		t.Fatalf("expires_at %q: %v", sent.ExpiresAt, err)
+	}
+	if lease := expiresAt.Sub(before); lease < 48*time.Hour || lease > 48*time.Hour+time.Minute {
+		t.Fatalf("expected a 48h lease, got %s", lease)
+	}
+
+	var state claimRenewalResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	if state.ID.ValueString() != "wus2/prd/stwus2prdatlas01" || state.ExpiresAt.ValueString() != sent.ExpiresAt || state.RenewedAt.IsUnknown() {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+
+	// A failed renewal is reported and leaves no new state.
+	gone := claimRenewalTestModel("48h")
+	gone.Name = types.StringValue("gone")
+	resp = update(gone)
+	if !resp.Diagnostics.HasError() || !resp.State.Raw.IsNull() {
+		t.Fatalf("expected the failed renewal to be reported, got %v", resp.Diagnostics)
+	}
+}
+
+func TestClaimRenewalReadKeepsExpiry(t *testing.T) {
+	expiresAt := "2026-01-31t00:00:00z"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name": "stwus2prdatlas01", "in_use": true, "expires_at": expiresAt,
+		})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimRenewalResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	read := func(expires string) types.String {
+		prior := claimRenewalTestModel("720h")
+		prior.ID = types.StringValue("wus2/prd/stwus2prdatlas01")
+		prior.RenewedAt = types.StringValue("2026-01-01T00:00:00Z")
+		prior.ExpiresAt = types.StringValue(expires)
+		state := tfsdk.State{Schema: s}
+		if diags := state.Set(ctx, &prior); diags.HasError() {
+			t.Fatalf("state: %v", diags)
+		}
+		resp := resource.ReadResponse{State: state}
+		r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Read: %v", resp.Diagnostics)
+		}
+		var got claimRenewalResourceModel
+		resp.Diagnostics.Append(resp.State.Get(ctx, &got)...)
+		return got.ExpiresAt
+	}
+
+	// Older records hold the expiry lowercased; the same instant is no drift.
+	if got := read("2026-01-31T00:00:00Z"); got.ValueString() != "2026-01-31T00:00:00Z" {
+		t.Fatalf("expected the renewed expiry to be kept, got %s", got)
+	}
+	// A lease moved elsewhere, for example by sanmarctl renew, is read back.
+	expiresAt = "2026-03-01T00:00:00Z"
+	if got := read("2026-01-31T00:00:00Z"); got.ValueString() != expiresAt {
+		t.Fatalf("expected the moved expiry, got %s", got)
+	}
+}
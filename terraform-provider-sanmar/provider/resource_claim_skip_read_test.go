@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSkipReadTrustsState(t *testing.T) {
+	audits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/audit" {
+			http.Error(w, "unexpected call", http.StatusInternalServerError)
+			return
+		}
+		audits++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"kvwus2prdatlas01","resource_type":"key_vault","in_use":true,"claimed_by":"bob","region":"wus2","environment":"prd","slug":"kv","system":"atlas","index":"01"}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	for attr, value := range map[string]any{
+		"name": "kvwus2prdatlas01", "resource_type": "key_vault", "region": "wus2", "environment": "prd",
+		"claimed_by": "alice", "skip_read": true,
+	} {
+		if diags := state.SetAttribute(ctx, path.Root(attr), value); diags.HasError() {
+			t.Fatalf("state: %v", diags)
+		}
+	}
+
+	read := func() string {
+		t.Helper()
+		resp := resource.ReadResponse{State: state}
+		r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+		// Only the framework's server can initialize resp.Private, so
+		// recording a verified refresh fails here; anything else is a failure.
+		for _, d := range resp.Diagnostics.Errors() {
+			if !strings.Contains(d.Summary()+d.Detail(), "ProviderData") {
+				t.Fatalf("Read: %v", resp.Diagnostics)
+			}
+		}
+		var model claimResourceModel
+		resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+		return model.ClaimedBy.ValueString()
+	}
+
+	t.Setenv(forceRefreshEnv, "")
+	if claimedBy := read(); audits != 0 || claimedBy != "alice" {
+		t.Fatalf("expected skip_read to keep the state without an audit call, got %d calls and claimed_by %q", audits, claimedBy)
+	}
+
+	// Forcing a refresh checks the service for drift anyway.
+	t.Setenv(forceRefreshEnv, "1")
+	if claimedBy := read(); audits != 1 || claimedBy != "bob" {
+		t.Fatalf("expected a forced refresh to read the audit record, got %d calls and claimed_by %q", audits, claimedBy)
+	}
+}
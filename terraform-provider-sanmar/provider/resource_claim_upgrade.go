@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"maps"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var _ resource.ResourceWithUpgradeState = (*ClaimResource)(nil)
+
+// claimSchemaVersion is the current sanmar_claim state version. Bump it and
+// register an upgrader below whenever stored state needs migrating.
+const claimSchemaVersion = 5
+
+// UpgradeState migrates sanmar_claim state written by older provider releases.
+func (r *ClaimResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	// Earlier versions only gained attributes (segments in version 2,
+	// dns_prefix in version 3) until version 4 stored effective_index as a
+	// number instead of a string, and version 5 did the same for index and
+	// segments.index. Older state decodes against the current schema with
+	// those attributes frozen as strings and the newer attributes left null.
+	// Freeze another copy of the schema here before removing or retyping an
+	// attribute.
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	segments := schemaResp.Schema.Attributes["segments"].(schema.SingleNestedAttribute)
+	segments.Attributes = maps.Clone(segments.Attributes)
+	segments.Attributes["index"] = schema.StringAttribute{Optional: true}
+
+	upgraders := make(map[int64]resource.StateUpgrader, claimSchemaVersion)
+	for version := int64(0); version < claimSchemaVersion; version++ {
+		prior := schemaResp.Schema
+		prior.Version = version
+		prior.Attributes = maps.Clone(schemaResp.Schema.Attributes)
+		prior.Attributes["index"] = schema.StringAttribute{Optional: true}
+		prior.Attributes["segments"] = segments
+		if version < 4 {
+			prior.Attributes["effective_index"] = schema.StringAttribute{Computed: true}
+		}
+		upgraders[version] = resource.StateUpgrader{
+			PriorSchema:   &prior,
+			StateUpgrader: upgradeClaimState,
+		}
+	}
+	return upgraders
+}
+
+// upgradeClaimState converts the indices to numbers and fills the defaults
+// and computed values that older state may lack so the first plan after
+// upgrading shows no changes.
+func upgradeClaimState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	objectType := resp.State.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values, err := upgradeIndices(ctx, req.State.Raw, objectType)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to upgrade claim state", err.Error())
+		return
+	}
+	segments, err := upgradeIndices(ctx, values["segments"], objectType.AttributeTypes["segments"].(tftypes.Object))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to upgrade claim state", err.Error())
+		return
+	}
+	values["segments"] = tftypes.NewValue(objectType.AttributeTypes["segments"], nil)
+	if segments != nil {
+		values["segments"] = tftypes.NewValue(objectType.AttributeTypes["segments"], segments)
+	}
+
+	current := tfsdk.State{Schema: resp.State.Schema, Raw: tftypes.NewValue(objectType, values)}
+	var state claimResourceModel
+	resp.Diagnostics.Append(current.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.fillStateDefaults()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// upgradeIndices returns the attributes of the object value v with the
+// index attributes that objectType declares as numbers converted from the
+// strings older state stored. It returns nil when v is null.
+func upgradeIndices(ctx context.Context, v tftypes.Value, objectType tftypes.Object) (map[string]tftypes.Value, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+	var values map[string]tftypes.Value
+	if err := v.As(&values); err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"index", "effective_index"} {
+		value, ok := values[name]
+		if !ok || !value.Type().Is(tftypes.String) || !objectType.AttributeTypes[name].Is(tftypes.Number) {
+			continue
+		}
+		var s string
+		if !value.IsNull() {
+			if err := value.As(&s); err != nil {
+				return nil, err
+			}
+		}
+		index, err := indexNumber(s).ToTerraformValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = index
+	}
+	return values, nil
+}
+
+// fillStateDefaults sets the defaults and derived values for attributes that
+// migrated state may not carry.
+func (m *claimResourceModel) fillStateDefaults() {
+	if m.DryRun.IsNull() {
+		m.DryRun = types.BoolValue(false)
+	}
+	if m.Retired.IsNull() {
+		m.Retired = types.BoolValue(false)
+	}
+	if m.PendingRegistration.IsNull() {
+		m.PendingRegistration = types.BoolValue(false)
+	}
+	if m.UniqueLength.IsNull() {
+		m.UniqueLength = types.Int64Value(4)
+	}
+	if m.ID.IsNull() {
+		m.ID = m.Name
+	}
+	if m.Segments.IsNull() {
+		m.Segments = m.segmentsFromFlat()
+	}
+	m.setNameVariants()
+	if m.TagContract.IsNull() {
+		m.setTagContract("")
+	}
+}
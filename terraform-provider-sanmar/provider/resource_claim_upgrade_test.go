@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUpgradeClaimStateV0(t *testing.T) {
+	ctx := context.Background()
+	r := &ClaimResource{}
+
+	upgrader, ok := r.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatalf("expected an upgrader for version 0")
+	}
+
+	objType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "stwus2prdatlas")
+	values["name"] = tftypes.NewValue(tftypes.String, "stwus2prdatlas")
+	values["resource_type"] = tftypes.NewValue(tftypes.String, "storage_account")
+	values["region"] = tftypes.NewValue(tftypes.String, "wus2")
+	values["environment"] = tftypes.NewValue(tftypes.String, "prd")
+	values["project"] = tftypes.NewValue(tftypes.String, "atlas")
+	values["slug"] = tftypes.NewValue(tftypes.String, "st")
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(objType, values)},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got claimResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got.DryRun.ValueBool() || got.DryRun.IsNull() {
+		t.Fatalf("expected dry_run false, got %s", got.DryRun)
+	}
+	if got.UniqueLength.ValueInt64() != 4 {
+		t.Fatalf("expected unique_length 4, got %s", got.UniqueLength)
+	}
+	if project, _ := got.Segments.Attributes()["project"].(types.String); project.ValueString() != "atlas" {
+		t.Fatalf("expected segments to carry the project, got %s", got.Segments)
+	}
+	if got.NameHyphenated.ValueString() != "st-wus2-prd-atlas" {
+		t.Fatalf("unexpected name_hyphenated %s", got.NameHyphenated)
+	}
+}
+
+func TestUpgradeClaimStateEffectiveIndex(t *testing.T) {
+	ctx := context.Background()
+	r := &ClaimResource{}
+
+	upgrader := r.UpgradeState(ctx)[3]
+	objType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "stwus2prdatlas07")
+	values["name"] = tftypes.NewValue(tftypes.String, "stwus2prdatlas07")
+	values["resource_type"] = tftypes.NewValue(tftypes.String, "storage_account")
+	values["region"] = tftypes.NewValue(tftypes.String, "wus2")
+	values["environment"] = tftypes.NewValue(tftypes.String, "prd")
+	values["effective_index"] = tftypes.NewValue(tftypes.String, "07")
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(objType, values)},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got claimResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got.EffectiveIndex.ValueInt64() != 7 {
+		t.Fatalf("expected effective_index 7, got %s", got.EffectiveIndex)
+	}
+	if got.EffectiveIndex.format(2) != "07" {
+		t.Fatalf("expected the index to render as 07, got %q", got.EffectiveIndex.format(2))
+	}
+}
+
+func TestUpgradeClaimStateIndex(t *testing.T) {
+	ctx := context.Background()
+	r := &ClaimResource{}
+
+	upgrader := r.UpgradeState(ctx)[4]
+	objType := upgrader.PriorSchema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, typ := range objType.AttributeTypes {
+		values[name] = tftypes.NewValue(typ, nil)
+	}
+	segmentsType := objType.AttributeTypes["segments"].(tftypes.Object)
+	segments := map[string]tftypes.Value{}
+	for name, typ := range segmentsType.AttributeTypes {
+		segments[name] = tftypes.NewValue(typ, nil)
+	}
+	segments["index"] = tftypes.NewValue(tftypes.String, "07")
+	values["id"] = tftypes.NewValue(tftypes.String, "stwus2prdatlas07")
+	values["name"] = tftypes.NewValue(tftypes.String, "stwus2prdatlas07")
+	values["resource_type"] = tftypes.NewValue(tftypes.String, "storage_account")
+	values["region"] = tftypes.NewValue(tftypes.String, "wus2")
+	values["environment"] = tftypes.NewValue(tftypes.String, "prd")
+	values["index"] = tftypes.NewValue(tftypes.String, "07")
+	values["segments"] = tftypes.NewValue(segmentsType, segments)
+	values["effective_index"] = tftypes.NewValue(tftypes.Number, 7)
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(objType, values)},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema},
+	}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var got claimResourceModel
+	if diags := resp.State.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	if got.Index.ValueInt64() != 7 || got.EffectiveIndex.ValueInt64() != 7 {
+		t.Fatalf("expected index and effective_index 7, got %s and %s", got.Index, got.EffectiveIndex)
+	}
+	if index, _ := got.Segments.Attributes()["index"].(indexNumberValue); index.ValueInt64() != 7 {
+		t.Fatalf("expected segments.index 7, got %s", got.Segments)
+	}
+}
+
+func TestPlannedSuffixKeepsUpgradedState(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, "http://127.0.0.1:1", "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ClaimResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	configured := func(index string) claimResourceModel {
+		m := nullClaimModel(ctx, t, schemaResp.Schema)
+		m.ResourceType = types.StringValue("storage_account")
+		m.Region = regionType.value("wus2")
+		m.Environment = environmentType.value("prd")
+		m.Index = indexNumber(index)
+		m.UniqueSuffix = types.BoolValue(true)
+		m.UniqueLength = types.Int64Value(4)
+		return m
+	}
+
+	// Older releases hashed the index as written, so the stored suffix does
+	// not match the one the number gives.
+	prior := configured("01")
+	prior.Suffix = types.StringValue("zzzz")
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, &prior); diags.HasError() {
+		t.Fatalf("state: %v", diags)
+	}
+
+	if got := r.plannedSuffix(ctx, state, configured("1")); got.ValueString() != "zzzz" {
+		t.Fatalf("expected the stored suffix to be kept, got %s", got)
+	}
+	if got := r.plannedSuffix(ctx, state, configured("2")); got.ValueString() == "zzzz" || got.IsUnknown() {
+		t.Fatalf("expected a new suffix for a new index, got %s", got)
+	}
+}
+
+func TestIndexSpelledIn(t *testing.T) {
+	cases := []struct {
+		index, name, want string
+	}{
+		{"7", "stwus2prdatlas07", "07"},
+		{"07", "stwus2prdatlas007", "007"},
+		{"7", "stwus2prdatlas7", "7"},
+		{"12", "stwus2prdatlas12", "12"},
+		{"", "stwus2prdatlas", ""},
+	}
+	for _, tc := range cases {
+		if got := indexNumber(tc.index).spelledIn(tc.name); got != tc.want {
+			t.Errorf("index %q in %q = %q, want %q", tc.index, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestPadIndex(t *testing.T) {
+	cases := map[string]string{"7": "07", "07": "07", "007": "07", "123": "123", "x1": "x1"}
+	for in, want := range cases {
+		if got := padIndex(in, 2); got != want {
+			t.Errorf("padIndex(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var retentionPattern = regexp.MustCompile(`^(\d+(\.\d+)?[hms])+$`)
+
+var _ resource.Resource = (*EnvironmentResource)(nil)
+var _ resource.ResourceWithImportState = (*EnvironmentResource)(nil)
+var _ resource.ResourceWithValidateConfig = (*EnvironmentResource)(nil)
+
+// EnvironmentResource manages an entry in the service's environment catalog.
+type EnvironmentResource struct {
+	client *APIClient
+}
+
+// NewEnvironmentResource instantiates the resource.
+func NewEnvironmentResource() resource.Resource {
+	return &EnvironmentResource{}
+}
+
+type environmentResourceModel struct {
+	Code              types.String `tfsdk:"code"`
+	DisplayName       types.String `tfsdk:"display_name"`
+	AllowedRegions    types.Set    `tfsdk:"allowed_regions"`
+	ReleasedRetention types.String `tfsdk:"released_retention"`
+}
+
+func buildEnvironmentPayload(ctx context.Context, plan environmentResourceModel) (Environment, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	payload := Environment{
+		Code:              plan.Code.ValueString(),
+		DisplayName:       plan.DisplayName.ValueString(),
+		ReleasedRetention: plan.ReleasedRetention.ValueString(),
+	}
+	if !plan.AllowedRegions.IsNull() && !plan.AllowedRegions.IsUnknown() {
+		diags.Append(plan.AllowedRegions.ElementsAs(ctx, &payload.AllowedRegions, false)...)
+	}
+	return payload, diags
+}
+
+// applyEnvironment copies the service's view into model. An empty region
+// list is stored as null, which is how "any region" is configured, and an
+// equivalent retention keeps its configured spelling.
+func applyEnvironment(ctx context.Context, model *environmentResourceModel, environment *Environment) diag.Diagnostics {
+	model.Code = types.StringValue(environment.Code)
+	model.DisplayName = types.StringValue(environment.DisplayName)
+	if !sameDuration(model.ReleasedRetention, environment.ReleasedRetention) {
+		model.ReleasedRetention = optionalString(environment.ReleasedRetention)
+	}
+	if len(environment.AllowedRegions) == 0 {
+		model.AllowedRegions = types.SetNull(types.StringType)
+		return nil
+	}
+	regions, diags := types.SetValueFrom(ctx, types.StringType, environment.AllowedRegions)
+	model.AllowedRegions = regions
+	return diags
+}
+
+// sameDuration reports whether v and s are the same duration, so a service
+// that returns "720h0m0s" for a configured "720h" does not cause a diff.
+func sameDuration(v types.String, s string) bool {
+	if v.IsNull() || v.IsUnknown() {
+		return false
+	}
+	a, errA := time.ParseDuration(v.ValueString())
+	b, errB := time.ParseDuration(s)
+	return errA == nil && errB == nil && a == b
+}
+
+func (r *EnvironmentResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_environment"
+}
+
+func (r *EnvironmentResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an environment in the SanMar naming service catalog: its code, display name, the regions it may use, and how long released names stay reserved.",
+		Attributes: map[string]schema.Attribute{
+			"code": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(catalogCodePattern, "must contain only lowercase letters, numbers, and hyphens"),
+				},
+				MarkdownDescription: "Environment code used as the `environment` segment of claims, such as `prd`. Changing it registers a new environment.",
+			},
+			"display_name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Human-readable name shown in the service's portal and reports.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"allowed_regions": schema.SetAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Region short codes claims in this environment may use. The service rejects claims for other regions. Leave unset to allow every region.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(catalogCodePattern, "must contain only lowercase letters, numbers, and hyphens")),
+				},
+			},
+			"released_retention": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "How long a released name stays reserved before the service lets it be claimed again, as a duration in hours, minutes, and seconds such as `720h`. Leave unset to let released names be claimed again right away.",
+			},
+		},
+	}
+}
+
+func (r *EnvironmentResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *EnvironmentResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var retention types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("released_retention"), &retention)...)
+	if retention.IsNull() || retention.IsUnknown() {
+		return
+	}
+	// The service only reads hours, minutes, and seconds.
+	d, err := time.ParseDuration(retention.ValueString())
+	if err != nil || d <= 0 || !retentionPattern.MatchString(retention.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("released_retention"), "Invalid released_retention",
+			fmt.Sprintf("released_retention must be a positive duration in hours, minutes, and seconds such as 720h, got %q.", retention.ValueString()))
+	}
+}
+
+func (r *EnvironmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan environmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload, diags := buildEnvironmentPayload(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, "registering environment via SanMar provider", map[string]any{
+		"code": payload.Code,
+	})
+
+	environment, err := r.client.CreateEnvironment(ctx, payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to register environment", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(applyEnvironment(ctx, &plan, environment)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *EnvironmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state environmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment, err := r.client.GetEnvironment(ctx, state.Code.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read environment", err.Error())
+		return
+	}
+
+	if environment == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(applyEnvironment(ctx, &state, environment)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *EnvironmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan environmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload, diags := buildEnvironmentPayload(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	environment, err := r.client.UpdateEnvironment(ctx, plan.Code.ValueString(), payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update environment", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(applyEnvironment(ctx, &plan, environment)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *EnvironmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state environmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteEnvironment(ctx, state.Code.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete environment", err.Error())
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *EnvironmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("code"), req, resp)
+}
@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*IndexReservationResource)(nil)
+var _ resource.ResourceWithImportState = (*IndexReservationResource)(nil)
+var _ resource.ResourceWithValidateConfig = (*IndexReservationResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*IndexReservationResource)(nil)
+
+// IndexReservationResource reserves a block of indices for a team within a scope.
+type IndexReservationResource struct {
+	client *APIClient
+}
+
+// NewIndexReservationResource instantiates the resource.
+func NewIndexReservationResource() resource.Resource {
+	return &IndexReservationResource{}
+}
+
+type indexReservationResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	ResourceType types.String `tfsdk:"resource_type"`
+	Region       types.String `tfsdk:"region"`
+	Environment  types.String `tfsdk:"environment"`
+	Project      types.String `tfsdk:"project"`
+	Team         types.String `tfsdk:"team"`
+	Start        types.Int64  `tfsdk:"start"`
+	End          types.Int64  `tfsdk:"end"`
+	ExpiresAt    types.String `tfsdk:"expires_at"`
+}
+
+func (r *IndexReservationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_index_reservation"
+}
+
+func (r *IndexReservationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	replace := []planmodifier.String{stringplanmodifier.RequiresReplace()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reserves a block of indices (for example 10-19) for a team within a naming scope so automatic index assignment for different teams never interleaves.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Identifier assigned to the reservation by the service.",
+			},
+			"resource_type": schema.StringAttribute{
+				Optional:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Restrict the reservation to one resource type. When omitted it applies to every type in the scope.",
+			},
+			"region": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Azure region short code (for example, wus2).",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 8),
+				},
+			},
+			"environment": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Deployment environment such as dev, stg, or prd.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(2),
+				},
+			},
+			"project": schema.StringAttribute{
+				Optional:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Optional project segment that narrows the scope.",
+			},
+			"team": schema.StringAttribute{
+				Required:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "Team that owns the reserved indices.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"start": schema.Int64Attribute{
+				Required: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "First index in the reserved block (inclusive).",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"end": schema.Int64Attribute{
+				Required: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+				MarkdownDescription: "Last index in the reserved block (inclusive).",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"expires_at": schema.StringAttribute{
+				Optional:            true,
+				PlanModifiers:       replace,
+				MarkdownDescription: "RFC 3339 time after which the service stops applying the reservation. Reservations without one never expire. The provider's `lease_horizon` warns during plan when it is near.",
+			},
+		},
+	}
+}
+
+func (r *IndexReservationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data indexReservationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, expiryDiags := parseExpiresAt(data.ExpiresAt)
+	resp.Diagnostics.Append(expiryDiags...)
+
+	if data.Start.IsNull() || data.Start.IsUnknown() || data.End.IsNull() || data.End.IsUnknown() {
+		return
+	}
+
+	if data.End.ValueInt64() < data.Start.ValueInt64() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("end"),
+			"Invalid index range",
+			fmt.Sprintf("end (%d) must be greater than or equal to start (%d).", data.End.ValueInt64(), data.Start.ValueInt64()),
+		)
+	}
+}
+
+// ModifyPlan warns when the reservation ends within the provider's
+// lease_horizon.
+func (r *IndexReservationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+	var plan indexReservationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(reservationExpiry(plan, time.Now(), r.client.leaseHorizon)...)
+}
+
+func (r *IndexReservationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *IndexReservationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan indexReservationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload := IndexReservation{
+		ResourceType: plan.ResourceType.ValueString(),
+		Region:       plan.Region.ValueString(),
+		Environment:  plan.Environment.ValueString(),
+		Project:      plan.Project.ValueString(),
+		Team:         plan.Team.ValueString(),
+		Start:        plan.Start.ValueInt64(),
+		End:          plan.End.ValueInt64(),
+		ExpiresAt:    plan.ExpiresAt.ValueString(),
+	}
+
+	tflog.Info(ctx, "reserving index range via SanMar provider", map[string]any{
+		"region":      payload.Region,
+		"environment": payload.Environment,
+		"team":        payload.Team,
+		"start":       payload.Start,
+		"end":         payload.End,
+	})
+
+	reservation, err := r.client.CreateIndexReservation(ctx, payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to reserve index range", err.Error())
+		return
+	}
+
+	plan.ID = types.StringValue(reservation.ID)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *IndexReservationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state indexReservationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	reservation, err := r.client.GetIndexReservation(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read index reservation", err.Error())
+		return
+	}
+
+	if reservation == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.ResourceType = optionalString(reservation.ResourceType)
+	state.Region = types.StringValue(reservation.Region)
+	state.Environment = types.StringValue(reservation.Environment)
+	state.Project = optionalString(reservation.Project)
+	state.Team = types.StringValue(reservation.Team)
+	state.Start = types.Int64Value(reservation.Start)
+	state.End = types.Int64Value(reservation.End)
+	state.ExpiresAt = recordedExpiry(state.ExpiresAt, reservation.ExpiresAt)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update is only reached when no attribute changed, because every
+// configurable attribute forces replacement.
+func (r *IndexReservationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan indexReservationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *IndexReservationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state indexReservationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteIndexReservation(ctx, state.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete index reservation", err.Error())
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *IndexReservationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
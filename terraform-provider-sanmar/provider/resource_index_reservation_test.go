@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestIndexReservationLifecycle(t *testing.T) {
+	reservations := map[string]IndexReservation{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/index_reservations", func(w http.ResponseWriter, r *http.Request) {
+		var payload IndexReservation
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		for _, existing := range reservations {
+			if existing.Start <= payload.End && payload.Start <= existing.End {
+				http.Error(w, "Indices are already reserved for team 'orion'.", http.StatusConflict)
+				return
+			}
+		}
+		payload.ID = "res-1"
+		payload.Team = strings.ToLower(payload.Team)
+		reservations[payload.ID] = payload
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(payload)
+	})
+	mux.HandleFunc("/api/index_reservations/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/index_reservations/")
+		reservation, ok := reservations[id]
+		if !ok {
+			http.Error(w, "Index reservation not found.", http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodDelete {
+			delete(reservations, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(reservation)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewAPIClient(ctx, srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &IndexReservationResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+
+	model := indexReservationResourceModel{
+		ID:           types.StringUnknown(),
+		ResourceType: types.StringValue("storage_account"),
+		Region:       types.StringValue("wus2"),
+		Environment:  types.StringValue("prd"),
+		Project:      types.StringNull(),
+		Team:         types.StringValue("orion"),
+		Start:        types.Int64Value(10),
+		End:          types.Int64Value(19),
+		ExpiresAt:    types.StringValue("2999-01-01T00:00:00Z"),
+	}
+	plan := tfsdk.Plan{Schema: s}
+	if diags := plan.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("plan: %v", diags)
+	}
+	emptyState := func() tfsdk.State {
+		return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	}
+
+	createResp := resource.CreateResponse{State: emptyState()}
+	r.Create(ctx, resource.CreateRequest{Plan: plan}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatalf("Create: %v", createResp.Diagnostics)
+	}
+
+	readResp := resource.ReadResponse{State: createResp.State}
+	r.Read(ctx, resource.ReadRequest{State: createResp.State}, &readResp)
+	var state indexReservationResourceModel
+	readResp.Diagnostics.Append(readResp.State.Get(ctx, &state)...)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("Read: %v", readResp.Diagnostics)
+	}
+	if state.ID.ValueString() != "res-1" || state.Team.ValueString() != "orion" || state.End.ValueInt64() != 19 || !state.Project.IsNull() ||
+		state.ExpiresAt.ValueString() != "2999-01-01T00:00:00Z" {
+		t.Fatalf("unexpected state: %#v", state)
+	}
+
+	// A second, overlapping block is refused by the service.
+	overlapping := tfsdk.Plan{Schema: s}
+	model.Team, model.Start = types.StringValue("atlas"), types.Int64Value(15)
+	if diags := overlapping.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("plan: %v", diags)
+	}
+	conflictResp := resource.CreateResponse{State: emptyState()}
+	r.Create(ctx, resource.CreateRequest{Plan: overlapping}, &conflictResp)
+	if !conflictResp.Diagnostics.HasError() || !strings.Contains(conflictResp.Diagnostics.Errors()[0].Detail(), "already reserved") {
+		t.Fatalf("expected an overlap error, got %v", conflictResp.Diagnostics)
+	}
+
+	deleteResp := resource.DeleteResponse{State: readResp.State}
+	r.Delete(ctx, resource.DeleteRequest{State: readResp.State}, &deleteResp)
+	if deleteResp.Diagnostics.HasError() || len(reservations) != 0 {
+		t.Fatalf("Delete: %v, remaining %v", deleteResp.Diagnostics, reservations)
+	}
+
+	// Reading a reservation removed outside Terraform drops it from state.
+	goneResp := resource.ReadResponse{State: readResp.State}
+	r.Read(ctx, resource.ReadRequest{State: readResp.State}, &goneResp)
+	if goneResp.Diagnostics.HasError() || !goneResp.State.Raw.IsNull() {
+		t.Fatalf("expected the reservation to be removed, got %v", goneResp.Diagnostics)
+	}
+}
@@ -0,0 +1,265 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*NotificationResource)(nil)
+var _ resource.ResourceWithImportState = (*NotificationResource)(nil)
+
+// NotificationResource manages webhook subscriptions for claim and release events.
+type NotificationResource struct {
+	client *APIClient
+}
+
+// NewNotificationResource instantiates the resource.
+func NewNotificationResource() resource.Resource {
+	return &NotificationResource{}
+}
+
+type notificationResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Name    types.String `tfsdk:"name"`
+	Kind    types.String `tfsdk:"kind"`
+	URL     types.String `tfsdk:"url"`
+	Events  types.Set    `tfsdk:"events"`
+	Enabled types.Bool   `tfsdk:"enabled"`
+}
+
+func buildNotificationPayload(ctx context.Context, plan notificationResourceModel) (NotificationSubscription, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	payload := NotificationSubscription{
+		Name:    plan.Name.ValueString(),
+		Kind:    plan.Kind.ValueString(),
+		URL:     plan.URL.ValueString(),
+		Enabled: plan.Enabled.ValueBool(),
+	}
+
+	if !plan.Events.IsNull() && !plan.Events.IsUnknown() {
+		diags = append(diags, plan.Events.ElementsAs(ctx, &payload.Events, false)...)
+	}
+
+	return payload, diags
+}
+
+func applyNotification(ctx context.Context, model *notificationResourceModel, sub *NotificationSubscription) diag.Diagnostics {
+	model.ID = types.StringValue(sub.ID)
+	model.Name = types.StringValue(sub.Name)
+	model.Kind = types.StringValue(sub.Kind)
+	model.Enabled = types.BoolValue(sub.Enabled)
+
+	events, diags := types.SetValueFrom(ctx, types.StringType, sub.Events)
+	model.Events = events
+	return diags
+}
+
+func (r *NotificationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notification"
+}
+
+func (r *NotificationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a notification subscription (Teams, Slack, or Event Grid webhook) fired by the SanMar naming service on claim and release.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Identifier assigned to the subscription by the service.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Display name for the subscription.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"kind": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Delivery channel: `teams`, `slack`, or `event_grid`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("teams", "slack", "event_grid"),
+				},
+			},
+			"url": schema.StringAttribute{
+				Required:            true,
+				Sensitive:           true,
+				MarkdownDescription: "Webhook or Event Grid topic endpoint that receives the events.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"events": schema.SetAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Default: setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{
+					types.StringValue("claim"),
+					types.StringValue("release"),
+				})),
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.OneOf("claim", "release")),
+				},
+				MarkdownDescription: "Events that trigger the notification (defaults to both `claim` and `release`).",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Whether the subscription is active (default true).",
+			},
+		},
+	}
+}
+
+func (r *NotificationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *NotificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan notificationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload, diags := buildNotificationPayload(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "creating notification subscription via SanMar provider", map[string]any{
+		"name": payload.Name,
+		"kind": payload.Kind,
+	})
+
+	sub, err := r.client.CreateNotification(ctx, payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create notification subscription", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(applyNotification(ctx, &plan, sub)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *NotificationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state notificationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sub, err := r.client.GetNotification(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read notification subscription", err.Error())
+		return
+	}
+
+	if sub == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Adopt the webhook URL the service reports, so changes made outside
+	// Terraform show as drift; keep the value in state when it omits the URL.
+	if sub.URL != "" {
+		state.URL = types.StringValue(sub.URL)
+	}
+	resp.Diagnostics.Append(applyNotification(ctx, &state, sub)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *NotificationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan notificationResourceModel
+	var state notificationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload, diags := buildNotificationPayload(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sub, err := r.client.UpdateNotification(ctx, state.ID.ValueString(), payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update notification subscription", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(applyNotification(ctx, &plan, sub)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *NotificationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state notificationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteNotification(ctx, state.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete notification subscription", err.Error())
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *NotificationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*ProjectResource)(nil)
+var _ resource.ResourceWithImportState = (*ProjectResource)(nil)
+var _ resource.ResourceWithModifyPlan = (*ProjectResource)(nil)
+
+// ProjectResource registers a project code that claims may use as their
+// project segment.
+type ProjectResource struct {
+	client *APIClient
+}
+
+// NewProjectResource instantiates the resource.
+func NewProjectResource() resource.Resource {
+	return &ProjectResource{}
+}
+
+type projectResourceModel struct {
+	Code        types.String `tfsdk:"code"`
+	Description types.String `tfsdk:"description"`
+	Owner       types.String `tfsdk:"owner"`
+	CostCenter  types.String `tfsdk:"cost_center"`
+	IndexPolicy types.String `tfsdk:"index_policy"`
+}
+
+func buildProjectPayload(plan projectResourceModel) Project {
+	return Project{
+		Code:        plan.Code.ValueString(),
+		Description: plan.Description.ValueString(),
+		Owner:       plan.Owner.ValueString(),
+		CostCenter:  plan.CostCenter.ValueString(),
+		IndexPolicy: plan.IndexPolicy.ValueString(),
+	}
+}
+
+func applyProject(model *projectResourceModel, project *Project) {
+	model.Code = types.StringValue(project.Code)
+	model.Description = optionalString(project.Description)
+	model.Owner = types.StringValue(project.Owner)
+	model.CostCenter = optionalString(project.CostCenter)
+	if project.IndexPolicy != "" {
+		model.IndexPolicy = types.StringValue(project.IndexPolicy)
+	}
+}
+
+func (r *ProjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project"
+}
+
+func (r *ProjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Registers a project code with the SanMar naming service so claims can only use known project segments.",
+		Attributes: map[string]schema.Attribute{
+			"code": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(catalogCodePattern, "must contain only lowercase letters, numbers, and hyphens"),
+				},
+				MarkdownDescription: "Project code used as the `project` segment of claims. Changing it registers a new project.",
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Free-form description of the project.",
+			},
+			"owner": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Team or person accountable for names claimed under the project.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"cost_center": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Cost center charged for resources in the project.",
+			},
+			"index_policy": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("auto"),
+				MarkdownDescription: "How the index segment is assigned for the project: `auto` lets claims leave `index` unset, for example to have the service allocate one with `index_reuse`, and `manual` expects claims to set `index` (default `auto`).",
+				Validators: []validator.String{
+					stringvalidator.OneOf("auto", "manual"),
+				},
+			},
+		},
+	}
+}
+
+func (r *ProjectResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+// ModifyPlan records planned project codes so claims in the same run can use
+// them before the project is registered.
+func (r *ProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var code types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("code"), &code)...)
+	if !code.IsNull() && !code.IsUnknown() {
+		r.client.addPlannedProject(code.ValueString())
+	}
+}
+
+func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan projectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload := buildProjectPayload(plan)
+	tflog.Info(ctx, "registering project via SanMar provider", map[string]any{
+		"code":  payload.Code,
+		"owner": payload.Owner,
+	})
+
+	project, err := r.client.CreateProject(ctx, payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to register project", err.Error())
+		return
+	}
+	r.client.addPlannedProject(project.Code)
+
+	applyProject(&plan, project)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state projectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.GetProject(ctx, state.Code.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read project", err.Error())
+		return
+	}
+
+	if project == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	applyProject(&state, project)
+	if state.IndexPolicy.IsNull() {
+		state.IndexPolicy = types.StringValue("auto")
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan projectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.UpdateProject(ctx, plan.Code.ValueString(), buildProjectPayload(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update project", err.Error())
+		return
+	}
+
+	applyProject(&plan, project)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state projectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteProject(ctx, state.Code.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete project", err.Error())
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *ProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("code"), req, resp)
+}
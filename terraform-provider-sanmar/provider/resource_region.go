@@ -0,0 +1,203 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*RegionResource)(nil)
+var _ resource.ResourceWithImportState = (*RegionResource)(nil)
+
+// azureLocationPattern matches Azure location names such as "westus2".
+var azureLocationPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// RegionResource manages an entry in the service's region short-code catalog.
+type RegionResource struct {
+	client *APIClient
+}
+
+// NewRegionResource instantiates the resource.
+func NewRegionResource() resource.Resource {
+	return &RegionResource{}
+}
+
+type regionResourceModel struct {
+	Code        types.String `tfsdk:"code"`
+	Location    types.String `tfsdk:"location"`
+	DisplayName types.String `tfsdk:"display_name"`
+}
+
+func buildRegionPayload(plan regionResourceModel) Region {
+	return Region{
+		Code:        plan.Code.ValueString(),
+		Location:    plan.Location.ValueString(),
+		DisplayName: plan.DisplayName.ValueString(),
+	}
+}
+
+func applyRegion(model *regionResourceModel, region *Region) {
+	model.Code = types.StringValue(region.Code)
+	model.Location = types.StringValue(region.Location)
+	model.DisplayName = optionalString(region.DisplayName)
+}
+
+func (r *RegionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_region"
+}
+
+func (r *RegionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Adds a region short code to the SanMar naming service catalog, mapping it to an Azure region, so new Azure regions can be used in names without editing the service's storage table.",
+		Attributes: map[string]schema.Attribute{
+			"code": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(2, 8),
+					stringvalidator.RegexMatches(azureLocationPattern, "must contain only lowercase letters and digits"),
+				},
+				MarkdownDescription: "Short code used as the `region` segment of claims, such as `wus2`. Changing it adds a new catalog entry.",
+			},
+			"location": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Azure region the code stands for, as the location name used by Azure Resource Manager (for example `westus2`). Changing it does not rename existing claims.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(azureLocationPattern, "must be an Azure location name such as westus2"),
+				},
+			},
+			"display_name": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Human-readable region name, such as `West US 2`.",
+			},
+		},
+	}
+}
+
+func (r *RegionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *RegionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan regionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload := buildRegionPayload(plan)
+	tflog.Info(ctx, "registering region via SanMar provider", map[string]any{
+		"code":     payload.Code,
+		"location": payload.Location,
+	})
+
+	region, err := r.client.CreateRegion(ctx, payload)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to register region", err.Error())
+		return
+	}
+
+	applyRegion(&plan, region)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RegionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state regionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, err := r.client.GetRegion(ctx, state.Code.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read region", err.Error())
+		return
+	}
+
+	if region == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	applyRegion(&state, region)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *RegionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan regionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, err := r.client.UpdateRegion(ctx, plan.Code.ValueString(), buildRegionPayload(plan))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update region", err.Error())
+		return
+	}
+
+	applyRegion(&plan, region)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RegionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state regionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteRegion(ctx, state.Code.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete region", err.Error())
+		return
+	}
+	resp.State.RemoveResource(ctx)
+}
+
+func (r *RegionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("code"), req, resp)
+}
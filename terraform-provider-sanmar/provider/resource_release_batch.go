@@ -0,0 +1,280 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*ReleaseBatchResource)(nil)
+
+// claimIdentityPattern matches "<region>/<environment>/<name>".
+var claimIdentityPattern = regexp.MustCompile(`^[^/]+/[^/]+/[^/]+$`)
+
+// ReleaseBatchResource releases a list of names that were claimed outside
+// Terraform, such as when decommissioning a project.
+type ReleaseBatchResource struct {
+	client *APIClient
+}
+
+// NewReleaseBatchResource instantiates the resource.
+func NewReleaseBatchResource() resource.Resource {
+	return &ReleaseBatchResource{}
+}
+
+type releaseBatchResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Names    types.Set    `tfsdk:"names"`
+	Reason   types.String `tfsdk:"reason"`
+	Released types.Set    `tfsdk:"released"`
+	NotFound types.Set    `tfsdk:"not_found"`
+}
+
+// releaseBatchResult records what happened to each name in a batch.
+type releaseBatchResult struct {
+	released []string
+	notFound []string
+	errs     []error
+}
+
+func (r *ReleaseBatchResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_release_batch"
+}
+
+func (r *ReleaseBatchResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Releases a list of names with a shared reason, for decommissioning projects whose names were claimed outside Terraform. Releases cannot be undone: destroying the resource only removes it from state.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Identifier derived from the names released on creation.",
+			},
+			"names": schema.SetAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Claims to release, each as `<region>/<environment>/<name>` (the format `sanmarctl export` and the `sanmar_claims` data source use). Adding names releases them on the next apply; removing names has no effect.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(claimIdentityPattern, "must be <region>/<environment>/<name>")),
+				},
+			},
+			"reason": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Release reason recorded in the audit history of every name.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"released": schema.SetAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names this resource released.",
+			},
+			"not_found": schema.SetAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Names the service did not know or had already released.",
+			},
+		},
+	}
+}
+
+func (r *ReleaseBatchResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+// releaseAll releases each identity in ids, treating names the service does
+// not know as already released.
+func (r *ReleaseBatchResource) releaseAll(ctx context.Context, ids []string, reason string) releaseBatchResult {
+	var result releaseBatchResult
+	for _, id := range ids {
+		identity, err := parseClaimIdentity(id)
+		if err != nil {
+			result.errs = append(result.errs, err)
+			continue
+		}
+
+		err = r.client.ReleaseName(ctx, ReleaseRequest{
+			Name:        identity.Name,
+			Region:      identity.Region,
+			Environment: identity.Environment,
+			Reason:      reason,
+		})
+		var apiErr *APIError
+		switch {
+		case err == nil:
+			result.released = append(result.released, id)
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			result.notFound = append(result.notFound, id)
+		default:
+			result.errs = append(result.errs, fmt.Errorf("%s: %w", id, err))
+		}
+	}
+
+	tflog.Info(ctx, "released names via SanMar provider", map[string]any{
+		"released":  len(result.released),
+		"not_found": len(result.notFound),
+		"failed":    len(result.errs),
+	})
+	return result
+}
+
+// apply merges result into model. Names that failed to release are left out
+// of names so the next plan retries them.
+func (result releaseBatchResult) apply(ctx context.Context, model *releaseBatchResourceModel, prior []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var released, notFound []string
+	if !model.Released.IsNull() && !model.Released.IsUnknown() {
+		diags.Append(model.Released.ElementsAs(ctx, &released, false)...)
+	}
+	if !model.NotFound.IsNull() && !model.NotFound.IsUnknown() {
+		diags.Append(model.NotFound.ElementsAs(ctx, &notFound, false)...)
+	}
+	released = append(released, result.released...)
+	notFound = append(notFound, result.notFound...)
+
+	names := append(append(append([]string{}, prior...), result.released...), result.notFound...)
+	sort.Strings(names)
+
+	var setDiags diag.Diagnostics
+	model.Names, setDiags = types.SetValueFrom(ctx, types.StringType, names)
+	diags.Append(setDiags...)
+	model.Released, setDiags = types.SetValueFrom(ctx, types.StringType, released)
+	diags.Append(setDiags...)
+	model.NotFound, setDiags = types.SetValueFrom(ctx, types.StringType, notFound)
+	diags.Append(setDiags...)
+
+	for _, err := range result.errs {
+		diags.AddError("Failed to release name", err.Error())
+	}
+	return diags
+}
+
+func (r *ReleaseBatchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan releaseBatchResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var names []string
+	resp.Diagnostics.Append(plan.Names.ElementsAs(ctx, &names, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sort.Strings(names)
+
+	sum := sha256.New()
+	for _, name := range names {
+		fmt.Fprintln(sum, name)
+	}
+	plan.ID = types.StringValue(hex.EncodeToString(sum.Sum(nil))[:16])
+	plan.Released = types.SetNull(types.StringType)
+	plan.NotFound = types.SetNull(types.StringType)
+
+	result := r.releaseAll(ctx, names, plan.Reason.ValueString())
+	resp.Diagnostics.Append(result.apply(ctx, &plan, nil)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the recorded outcome; released names have no state to refresh.
+func (r *ReleaseBatchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state releaseBatchResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *ReleaseBatchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var plan releaseBatchResourceModel
+	var state releaseBatchResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var planned, prior []string
+	resp.Diagnostics.Append(plan.Names.ElementsAs(ctx, &planned, false)...)
+	resp.Diagnostics.Append(state.Names.ElementsAs(ctx, &prior, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	done := make(map[string]bool, len(prior))
+	for _, id := range prior {
+		done[id] = true
+	}
+	var added, kept []string
+	for _, id := range planned {
+		if done[id] {
+			kept = append(kept, id)
+		} else {
+			added = append(added, id)
+		}
+	}
+	sort.Strings(added)
+
+	state.Reason = plan.Reason
+	result := r.releaseAll(ctx, added, plan.Reason.ValueString())
+	resp.Diagnostics.Append(result.apply(ctx, &state, kept)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete only forgets the batch; released names cannot be reclaimed.
+func (r *ReleaseBatchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	resp.State.RemoveResource(ctx)
+}
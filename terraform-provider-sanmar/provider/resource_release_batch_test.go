@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestReleaseBatchKeepsFailedNamesPending(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/release", func(w http.ResponseWriter, r *http.Request) {
+		var payload ReleaseRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		switch payload.Name {
+		case "gone":
+			http.Error(w, `{"message":"name not found"}`, http.StatusNotFound)
+		case "locked":
+			http.Error(w, `{"message":"claim is locked"}`, http.StatusConflict)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	r := &ReleaseBatchResource{client: client}
+
+	ctx := context.Background()
+	result := r.releaseAll(ctx, []string{"wus2/dev/app", "wus2/dev/gone", "wus2/dev/locked"}, "decommissioned")
+	model := releaseBatchResourceModel{
+		Released: types.SetNull(types.StringType),
+		NotFound: types.SetNull(types.StringType),
+	}
+	diags := result.apply(ctx, &model, []string{"eus/prd/earlier"})
+	if !diags.HasError() || diags.ErrorsCount() != 1 {
+		t.Fatalf("expected one release error, got %v", diags)
+	}
+
+	var names, released, notFound []string
+	model.Names.ElementsAs(ctx, &names, false)
+	model.Released.ElementsAs(ctx, &released, false)
+	model.NotFound.ElementsAs(ctx, &notFound, false)
+	if len(names) != 3 || names[0] != "eus/prd/earlier" || names[1] != "wus2/dev/app" || names[2] != "wus2/dev/gone" {
+		t.Fatalf("expected failed names to stay out of state, got %v", names)
+	}
+	if len(released) != 1 || released[0] != "wus2/dev/app" {
+		t.Fatalf("unexpected released: %v", released)
+	}
+	if len(notFound) != 1 || notFound[0] != "wus2/dev/gone" {
+		t.Fatalf("unexpected not_found: %v", notFound)
+	}
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = (*WorkspaceCleanupResource)(nil)
+
+// WorkspaceCleanupResource releases, when it is destroyed, the claims still
+// tagged with its tag. Claims are tagged by referencing the tag, which makes
+// them depend on this resource, so Terraform destroys every tagged claim in
+// state before it; whatever is still tagged afterwards leaked.
+type WorkspaceCleanupResource struct {
+	client *APIClient
+}
+
+// NewWorkspaceCleanupResource instantiates the resource.
+func NewWorkspaceCleanupResource() resource.Resource {
+	return &WorkspaceCleanupResource{}
+}
+
+type workspaceCleanupResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Tag         types.String `tfsdk:"tag"`
+	Region      types.String `tfsdk:"region"`
+	Environment types.String `tfsdk:"environment"`
+	Reason      types.String `tfsdk:"reason"`
+}
+
+func (r *WorkspaceCleanupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_cleanup"
+}
+
+func (r *WorkspaceCleanupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	keep := []planmodifier.String{stringplanmodifier.UseStateForUnknown()}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Releases leaked claims at the end of a destroy. Set `metadata = { sanmar_workspace = sanmar_workspace_cleanup.<label>.tag }` on each claim it covers: the reference makes the claim depend on this resource, so a destroy releases the claims in state first, and this resource then releases any claim still carrying the tag. No attribute forces replacement, since replacing it would release claims that are still in state.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				PlanModifiers:       keep,
+				MarkdownDescription: "Same as `tag`.",
+			},
+			"tag": schema.StringAttribute{
+				Computed:            true,
+				PlanModifiers:       keep,
+				MarkdownDescription: "Random value, fixed when the resource is created, to set as the `sanmar_workspace` metadata of each claim.",
+			},
+			"region": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only look for leaked claims in this region.",
+			},
+			"environment": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only look for leaked claims in this environment.",
+			},
+			"reason": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("Released by sanmar_workspace_cleanup"),
+				MarkdownDescription: "Release reason recorded in the audit history of each leaked claim.",
+			},
+		},
+	}
+}
+
+func (r *WorkspaceCleanupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*APIClient)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected *APIClient got %T", req.ProviderData))
+		return
+	}
+	r.client = client
+}
+
+func (r *WorkspaceCleanupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan workspaceCleanupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := newSessionID()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create cleanup tag", err.Error())
+		return
+	}
+	plan.Tag = types.StringValue("cleanup-" + id)
+	plan.ID = plan.Tag
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read keeps the state; the resource exists only in Terraform.
+func (r *WorkspaceCleanupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+}
+
+func (r *WorkspaceCleanupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan workspaceCleanupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete runs after every claim referencing the tag has been destroyed, and
+// releases the tagged claims that are still active. A failure keeps the
+// resource in state so the next destroy tries again.
+func (r *WorkspaceCleanupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client == nil {
+		resp.Diagnostics.AddError("Unconfigured provider", "The provider has not been configured; call provider block first.")
+		return
+	}
+	defer r.client.reportDeprecations(&resp.Diagnostics)
+
+	var state workspaceCleanupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tag := state.Tag.ValueString()
+	var released []string
+	err := r.client.ReleaseStragglers(ctx, StragglerWalk{
+		Filter: ClaimFilter{Region: state.Region.ValueString(), Environment: state.Environment.ValueString()},
+		Match: func(record *AuditRecord) bool {
+			return TaggedWith(record, tag)
+		},
+		Reason: state.Reason.ValueString(),
+	}, func(outcome StragglerOutcome) {
+		if outcome.Err == nil {
+			released = append(released, outcome.Claim.Identity())
+		}
+	})
+	if len(released) > 0 {
+		tflog.Info(ctx, "released leaked claims", map[string]any{"tag": tag, "claims": released})
+		resp.Diagnostics.AddWarning("Released leaked claims",
+			fmt.Sprintf("Released %d claim(s) tagged %s that were no longer in state: %s.", len(released), tag, strings.Join(released, ", ")))
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to release leaked claims", err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestWorkspaceCleanupReleasesTaggedClaimsOnDelete(t *testing.T) {
+	tag := "cleanup-6f1c2d3e-0000-4000-8000-000000000000"
+	client, released := stragglerServer(t, []string{"vmwus2devleaked", "vmwus2devother"}, map[string]map[string]any{
+		"vmwus2devleaked": auditResponse("vmwus2devleaked", true, map[string]string{CleanupMetadataKey: tag}),
+		"vmwus2devother":  auditResponse("vmwus2devother", true, map[string]string{CleanupMetadataKey: "another-workspace"}),
+	})
+
+	ctx := context.Background()
+	r := &WorkspaceCleanupResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	diags := state.Set(ctx, &workspaceCleanupResourceModel{
+		ID:          types.StringValue(tag),
+		Tag:         types.StringValue(tag),
+		Region:      types.StringNull(),
+		Environment: types.StringValue("dev"),
+		Reason:      types.StringValue("destroyed"),
+	})
+	if diags.HasError() {
+		t.Fatalf("state: %v", diags)
+	}
+
+	resp := resource.DeleteResponse{State: state}
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Delete: %v", resp.Diagnostics)
+	}
+	if len(*released) != 1 || (*released)[0].Name != "vmwus2devleaked" || (*released)[0].Reason != "destroyed" {
+		t.Fatalf("expected only the tagged claim to be released, got %#v", *released)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected the release to be reported, got %v", resp.Diagnostics)
+	}
+}
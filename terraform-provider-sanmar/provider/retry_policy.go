@@ -0,0 +1,78 @@
+package provider
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// retryBlockModel is the provider's optional retry block.
+type retryBlockModel struct {
+	Read  *retryPolicyModel `tfsdk:"read"`
+	Write *retryPolicyModel `tfsdk:"write"`
+}
+
+// retryPolicyModel overrides the provider-wide retry settings for one kind of request.
+type retryPolicyModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+}
+
+func retryPolicyBlock(description string) schema.SingleNestedBlock {
+	return schema.SingleNestedBlock{
+		Description: description,
+		Attributes: map[string]schema.Attribute{
+			"max_attempts": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of attempts; 1 disables retries.",
+			},
+			"min_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: "Minimum backoff duration between retries.",
+			},
+			"max_backoff": schema.StringAttribute{
+				Optional:    true,
+				Description: "Maximum backoff duration between retries.",
+			},
+		},
+	}
+}
+
+// apply returns base with the settings of m applied. A nil policy keeps base.
+func (m *retryPolicyModel) apply(base RetryConfig, block path.Path) (RetryConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if m == nil {
+		return base, diags
+	}
+
+	if !m.MaxAttempts.IsNull() && !m.MaxAttempts.IsUnknown() {
+		base.MaxAttempts = int(m.MaxAttempts.ValueInt64())
+	}
+
+	durations := []struct {
+		name  string
+		value types.String
+		dest  *time.Duration
+	}{
+		{"min_backoff", m.MinBackoff, &base.MinBackoff},
+		{"max_backoff", m.MaxBackoff, &base.MaxBackoff},
+	}
+	for _, d := range durations {
+		if d.value.IsNull() || d.value.IsUnknown() {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(block.AtName(d.name), "Invalid retry duration", fmt.Sprintf("failed to parse duration: %v", err))
+			continue
+		}
+		*d.dest = duration
+	}
+
+	return base, diags
+}
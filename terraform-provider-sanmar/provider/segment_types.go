@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = segmentType{}
+	_ basetypes.StringValuableWithSemanticEquals = segmentValue{}
+)
+
+// segmentKind selects how a segment value is normalised for comparison.
+type segmentKind int
+
+const (
+	segmentRegion segmentKind = iota
+	segmentEnvironment
+)
+
+// Custom types for the claim segments the service treats loosely: region
+// and environment codes are case-insensitive, so `WUS2` and `wus2` name the
+// same claim.
+var (
+	regionType      = segmentType{kind: segmentRegion}
+	environmentType = segmentType{kind: segmentEnvironment}
+)
+
+// segmentType is a string type whose values compare by their normalised form.
+type segmentType struct {
+	basetypes.StringType
+	kind segmentKind
+}
+
+func (t segmentType) Equal(o attr.Type) bool {
+	other, ok := o.(segmentType)
+	return ok && other.kind == t.kind
+}
+
+func (t segmentType) String() string {
+	switch t.kind {
+	case segmentRegion:
+		return "regionType"
+	default:
+		return "environmentType"
+	}
+}
+
+func (t segmentType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return t.from(in), nil
+}
+
+func (t segmentType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type %T", v)
+	}
+	return t.from(s), nil
+}
+
+func (t segmentType) ValueType(context.Context) attr.Value {
+	return segmentValue{kind: t.kind}
+}
+
+// from wraps a plain string value in the segment type.
+func (t segmentType) from(v basetypes.StringValue) segmentValue {
+	return segmentValue{StringValue: v, kind: t.kind}
+}
+
+// value returns a known segment value.
+func (t segmentType) value(s string) segmentValue {
+	return t.from(types.StringValue(s))
+}
+
+// segmentValue is a value of segmentType.
+type segmentValue struct {
+	basetypes.StringValue
+	kind segmentKind
+}
+
+func (v segmentValue) Equal(o attr.Value) bool {
+	other, ok := o.(segmentValue)
+	return ok && other.kind == v.kind && v.StringValue.Equal(other.StringValue)
+}
+
+func (v segmentValue) Type(context.Context) attr.Type {
+	return segmentType{kind: v.kind}
+}
+
+// StringSemanticEquals lets the framework keep the configured spelling when
+// the service returns the same segment in another form.
+func (v segmentValue) StringSemanticEquals(_ context.Context, o basetypes.StringValuable) (bool, diag.Diagnostics) {
+	other, ok := o.(segmentValue)
+	if !ok {
+		var diags diag.Diagnostics
+		diags.AddError("Semantic equality check error", fmt.Sprintf("expected %T, got %T", v, o))
+		return false, diags
+	}
+	return v.sameSegment(other), nil
+}
+
+// sameSegment reports whether v and other name the same segment. Null and
+// unknown values only match themselves.
+func (v segmentValue) sameSegment(other segmentValue) bool {
+	if v.IsNull() || v.IsUnknown() || other.IsNull() || other.IsUnknown() {
+		return v.Equal(other)
+	}
+	return normalizeSegment(v.kind, v.ValueString()) == normalizeSegment(other.kind, other.ValueString())
+}
+
+// normalizeSegment returns the canonical form of a segment value, which is
+// lower case for both kinds of code.
+func normalizeSegment(_ segmentKind, s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
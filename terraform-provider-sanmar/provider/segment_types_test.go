@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSegmentSemanticEquality(t *testing.T) {
+	cases := []struct {
+		typ  segmentType
+		a, b string
+		want bool
+	}{
+		{regionType, "WUS2", "wus2", true},
+		{environmentType, "Prd", "prd", true},
+		{regionType, "wus2", "eus2", false},
+	}
+	for _, tc := range cases {
+		equal, diags := tc.typ.value(tc.a).StringSemanticEquals(context.Background(), tc.typ.value(tc.b))
+		if diags.HasError() {
+			t.Fatalf("StringSemanticEquals(%q, %q): %v", tc.a, tc.b, diags)
+		}
+		if equal != tc.want {
+			t.Errorf("%s: %q == %q is %v, want %v", tc.typ, tc.a, tc.b, equal, tc.want)
+		}
+	}
+
+	null := regionType.from(types.StringNull())
+	if null.sameSegment(regionType.value("")) || !null.sameSegment(regionType.from(types.StringNull())) {
+		t.Fatal("expected null region to only match null")
+	}
+	if regionType.value("wus2").Equal(environmentType.value("wus2")) {
+		t.Fatal("values of different segment types must not be equal")
+	}
+}
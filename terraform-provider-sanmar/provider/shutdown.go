@@ -0,0 +1,36 @@
+package provider
+
+import "sync"
+
+// configuredClients holds every client a provider instance configured, so
+// their open files are closed when the plugin stops.
+var configuredClients struct {
+	mu      sync.Mutex
+	clients []*APIClient
+}
+
+func trackClient(c *APIClient) {
+	configuredClients.mu.Lock()
+	defer configuredClients.mu.Unlock()
+	configuredClients.clients = append(configuredClients.clients, c)
+}
+
+// Shutdown closes the files held by every configured client. Call it once
+// the provider server has stopped.
+func Shutdown() {
+	configuredClients.mu.Lock()
+	clients := configuredClients.clients
+	configuredClients.clients = nil
+	configuredClients.mu.Unlock()
+
+	for _, c := range clients {
+		c.Close()
+	}
+}
+
+// Close releases the files the client holds open. Later claims and releases
+// are no longer logged or journaled.
+func (c *APIClient) Close() {
+	c.auditLog.close()
+	c.journal.close()
+}
@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// slugDefaultsCache holds the segment defaults declared in the slug table by
+// resource type, so a plan looks each type up once rather than once per claim.
+type slugDefaultsCache struct {
+	mu sync.Mutex
+	// byType is nil for types without a slug or without defaults.
+	byType map[string]map[string]string
+}
+
+// segmentDefaults returns the segment values the service applies to
+// resourceType claims that leave them unset. Failed lookups return nil and are
+// not cached.
+func (c *APIClient) segmentDefaults(ctx context.Context, resourceType string) map[string]string {
+	resourceType = strings.ToLower(resourceType)
+	c.slugDefaults.mu.Lock()
+	defer c.slugDefaults.mu.Unlock()
+
+	if defaults, ok := c.slugDefaults.byType[resourceType]; ok {
+		return defaults
+	}
+
+	slug, err := c.LookupSlug(ctx, resourceType)
+	if err != nil {
+		tflog.Warn(ctx, "failed to look up slug defaults; effective segments will be known after apply", map[string]any{
+			"resource_type": resourceType,
+			"error":         err.Error(),
+		})
+		return nil
+	}
+
+	var defaults map[string]string
+	if slug != nil {
+		defaults = slug.Defaults
+	}
+	if c.slugDefaults.byType == nil {
+		c.slugDefaults.byType = map[string]map[string]string{}
+	}
+	c.slugDefaults.byType[resourceType] = defaults
+	return defaults
+}
+
+// planSegmentDefaults sets the effective segments of a new claim that leaves
+// segments with a default in the slug table unset, so the plan shows the
+// values the service will use instead of leaving them unknown until apply.
+func (r *ClaimResource) planSegmentDefaults(ctx context.Context, plan claimResourceModel, resp *resource.ModifyPlanResponse) {
+	if plan.ResourceType.IsUnknown() {
+		return
+	}
+
+	var defaults map[string]string
+	resolved := plan.resolveSegments()
+	for name, value := range resolved.segmentValues() {
+		if !value.IsNull() {
+			continue
+		}
+		// Only look the type up once a segment could use a default.
+		if defaults == nil {
+			if defaults = r.client.segmentDefaults(ctx, plan.ResourceType.ValueString()); defaults == nil {
+				return
+			}
+		}
+		def, ok := defaults[name]
+		if !ok || def == "" {
+			continue
+		}
+		if name == "index" {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_index"), indexNumber(def))...)
+			continue
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("effective_"+name), types.StringValue(def))...)
+	}
+}
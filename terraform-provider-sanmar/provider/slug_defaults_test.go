@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSegmentDefaultsAreCached(t *testing.T) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/slug", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Query().Get("resource_type") {
+		case "key_vault":
+			w.Write([]byte(`{"resourceType":"key_vault","slug":"kv","defaults":{"purpose":"sec"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if defaults := client.segmentDefaults(ctx, "Key_Vault"); defaults["purpose"] != "sec" {
+			t.Fatalf("unexpected defaults: %v", defaults)
+		}
+		if defaults := client.segmentDefaults(ctx, "vm"); defaults != nil {
+			t.Fatalf("expected no defaults for an unknown type, got %v", defaults)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("expected one lookup per resource type, got %d", requests.Load())
+	}
+}
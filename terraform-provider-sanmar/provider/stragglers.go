@@ -0,0 +1,84 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StragglerWalk selects claims to release from the claim listing.
+type StragglerWalk struct {
+	// Filter narrows the claim listing.
+	Filter ClaimFilter
+	// Candidate reports whether a listed claim is worth reading. Nil
+	// accepts every claim.
+	Candidate func(ClaimSummary) bool
+	// Match reports whether an active claim's record makes it a straggler.
+	Match func(*AuditRecord) bool
+	// Reason is recorded in the audit history of each release.
+	Reason string
+	// DryRun reports the stragglers without releasing them.
+	DryRun bool
+}
+
+// StragglerOutcome is what a walk did with one straggler.
+type StragglerOutcome struct {
+	Claim ClaimSummary
+	// Err is set when the release failed.
+	Err error
+}
+
+// ReleaseStragglers lists the claims matching walk.Filter, reads the record
+// of each candidate and releases the active claims walk.Match accepts,
+// passing each to report. Listing or reading failures stop the walk; failed
+// releases are reported and counted in the returned error.
+func (c *APIClient) ReleaseStragglers(ctx context.Context, walk StragglerWalk, report func(StragglerOutcome)) error {
+	claims, err := c.ListClaims(ctx, walk.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to list claims: %w", err)
+	}
+
+	var released, failed int
+	for _, claim := range claims {
+		if walk.Candidate != nil && !walk.Candidate(claim) {
+			continue
+		}
+
+		// The audit history omits metadata, so read each candidate's record.
+		record, err := c.GetAudit(ctx, claim.Region, claim.Environment, claim.Name)
+		if err != nil {
+			return fmt.Errorf("failed to read claim %s: %w", claim.Identity(), err)
+		}
+		if record == nil || !record.InUse || !walk.Match(record) {
+			continue
+		}
+
+		outcome := StragglerOutcome{Claim: claim}
+		if !walk.DryRun {
+			outcome.Err = c.ReleaseName(ctx, ReleaseRequest{
+				Name:        claim.Name,
+				Region:      claim.Region,
+				Environment: claim.Environment,
+				Reason:      walk.Reason,
+			})
+		}
+		if outcome.Err != nil {
+			failed++
+		} else {
+			released++
+		}
+		report(outcome)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d claims could not be released", failed, released+failed)
+	}
+	return nil
+}
+
+// TaggedWith reports whether a claim's record carries the cleanup workspace
+// tag. The service may change the case of stored metadata, so the tag is
+// compared without regard to case.
+func TaggedWith(record *AuditRecord, workspace string) bool {
+	return workspace != "" && strings.EqualFold(record.Metadata[CleanupMetadataKey], workspace)
+}
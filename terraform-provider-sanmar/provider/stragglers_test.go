@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// auditResponse returns an audit lookup body in the shape the service
+// returns it: custom claim metadata is a JSON string under "metadata".
+func auditResponse(name string, inUse bool, metadata map[string]string) map[string]any {
+	nested, _ := json.Marshal(metadata)
+	return map[string]any{
+		"name": name, "resource_type": "virtual_machine", "in_use": inUse,
+		"claimed_by": "ci@sanmar.com", "claimed_at": "2025-01-01T00:00:00+00:00",
+		"released_by": nil, "released_at": nil, "release_reason": nil,
+		"region": "wus2", "environment": "dev", "slug": "vm",
+		"project": nil, "purpose": nil, "subsystem": nil, "system": "erp", "index": "01",
+		"metadata": string(nested),
+	}
+}
+
+// stragglerServer serves a claim listing of names, the audit records in
+// records, and records released names.
+func stragglerServer(t *testing.T, names []string, records map[string]map[string]any) (*APIClient, *[]ReleaseRequest) {
+	t.Helper()
+	var released []ReleaseRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/audit_bulk", func(w http.ResponseWriter, r *http.Request) {
+		var events []AuditEvent
+		for _, name := range names {
+			events = append(events, AuditEvent{Name: name, Action: "claimed", Region: "wus2", Environment: "dev", Timestamp: "2025-01-01T00:00:00"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": events})
+	})
+	mux.HandleFunc("/api/audit", func(w http.ResponseWriter, r *http.Request) {
+		record, ok := records[r.URL.Query().Get("name")]
+		if !ok {
+			http.Error(w, "Audit entry not found.", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(record)
+	})
+	mux.HandleFunc("/api/release", func(w http.ResponseWriter, r *http.Request) {
+		var payload ReleaseRequest
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		released = append(released, payload)
+		if payload.Name == "vmwus2devstuck" {
+			http.Error(w, `{"message":"forbidden"}`, http.StatusForbidden)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"message": "Name released successfully."})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client, err := NewAPIClient(context.Background(), srv.URL, "", RetryConfig{MaxAttempts: 1})
+	if err != nil {
+		t.Fatalf("NewAPIClient: %v", err)
+	}
+	return client, &released
+}
+
+func TestReleaseStragglers(t *testing.T) {
+	names := []string{"vmwus2devkept", "vmwus2devleaked", "vmwus2devother", "vmwus2devgone", "vmwus2devstuck"}
+	records := map[string]map[string]any{
+		"vmwus2devkept":   auditResponse("vmwus2devkept", true, map[string]string{CleanupMetadataKey: "payments-dev"}),
+		"vmwus2devleaked": auditResponse("vmwus2devleaked", true, map[string]string{CleanupMetadataKey: "payments-dev", "owner": "team"}),
+		"vmwus2devother":  auditResponse("vmwus2devother", true, map[string]string{CleanupMetadataKey: "another-workspace"}),
+		"vmwus2devgone":   auditResponse("vmwus2devgone", false, map[string]string{CleanupMetadataKey: "payments-dev"}),
+		"vmwus2devstuck":  auditResponse("vmwus2devstuck", true, map[string]string{CleanupMetadataKey: "Payments-Dev"}),
+	}
+	client, released := stragglerServer(t, names, records)
+
+	walk := StragglerWalk{
+		Filter:    ClaimFilter{Environment: "dev"},
+		Candidate: func(claim ClaimSummary) bool { return claim.Name != "vmwus2devkept" },
+		// The tag is compared without regard to case.
+		Match:  func(record *AuditRecord) bool { return TaggedWith(record, "PAYMENTS-DEV") },
+		Reason: "cleanup",
+		DryRun: true,
+	}
+	var reported []string
+	report := func(outcome StragglerOutcome) {
+		entry := outcome.Claim.Identity()
+		if outcome.Err != nil {
+			entry += " failed"
+		}
+		reported = append(reported, entry)
+	}
+
+	if err := client.ReleaseStragglers(context.Background(), walk, report); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	want := []string{"wus2/dev/vmwus2devleaked", "wus2/dev/vmwus2devstuck"}
+	if !slices.Equal(reported, want) || len(*released) != 0 {
+		t.Fatalf("unexpected dry run: reported %v, released %v", reported, *released)
+	}
+
+	reported = nil
+	walk.DryRun = false
+	err := client.ReleaseStragglers(context.Background(), walk, report)
+	if err == nil || err.Error() != "1 of 2 claims could not be released" {
+		t.Fatalf("expected the failed release to be counted, got %v", err)
+	}
+	want = []string{"wus2/dev/vmwus2devleaked", "wus2/dev/vmwus2devstuck failed"}
+	if !slices.Equal(reported, want) {
+		t.Fatalf("unexpected outcomes: %v", reported)
+	}
+	if (*released)[0].Name != "vmwus2devleaked" || (*released)[0].Reason != "cleanup" {
+		t.Fatalf("unexpected release: %#v", (*released)[0])
+	}
+}
+
+func TestTaggedWithNestedMetadata(t *testing.T) {
+	content, _ := json.Marshal(auditResponse("vmwus2devleaked", true, map[string]string{CleanupMetadataKey: "payments-dev"}))
+	var record AuditRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !TaggedWith(&record, "Payments-Dev") {
+		t.Fatalf("expected the nested tag to match, got metadata %v", record.Metadata)
+	}
+	if TaggedWith(&record, "") || TaggedWith(&record, "payments") {
+		t.Fatal("expected only the exact workspace to match")
+	}
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Keys of the tag contract: the tags that tie an Azure resource to the claim
+// for its name, so deployed resources can be reconciled with the service.
+const (
+	tagNamingID  = "naming-id"
+	tagClaimedBy = "claimed-by"
+	tagClaimDate = "claim-date"
+)
+
+// claimDateLayout formats the claim-date tag.
+const claimDateLayout = "2006-01-02"
+
+// setTagContract sets the tag contract from the claim's identity. claimDate
+// is empty when it is not known, such as for state moved from the SDKv2
+// provider, and the tag is then left out until a refresh reads the date from
+// the audit record. Previewed names are never claimed, so have no tags.
+func (m *claimResourceModel) setTagContract(claimDate string) {
+	tags := map[string]attr.Value{}
+	if !m.DryRun.ValueBool() {
+		identity := claimIdentity{Region: m.Region.ValueString(), Environment: m.Environment.ValueString(), Name: m.ID.ValueString()}
+		tags[tagNamingID] = types.StringValue(identity.String())
+		if claimedBy := m.ClaimedBy.ValueString(); claimedBy != "" {
+			tags[tagClaimedBy] = types.StringValue(claimedBy)
+		}
+		if claimDate != "" {
+			tags[tagClaimDate] = types.StringValue(claimDate)
+		}
+	}
+	m.TagContract = types.MapValueMust(types.StringType, tags)
+}
+
+// claimDate returns the claim-date tag of the current tag contract, or "".
+func (m claimResourceModel) claimDate() string {
+	if date, ok := m.TagContract.Elements()[tagClaimDate].(types.String); ok {
+		return date.ValueString()
+	}
+	return ""
+}
+
+// auditClaimDate returns the UTC date of an RFC 3339 audit timestamp, or ""
+// if it cannot be parsed.
+func auditClaimDate(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(claimDateLayout)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSetTagContract(t *testing.T) {
+	m := claimResourceModel{
+		ID:          types.StringValue("kvsanmaratlas01"),
+		Region:      regionType.value("wus2"),
+		Environment: environmentType.value("prd"),
+		ClaimedBy:   types.StringValue("pipeline@sanmar.com"),
+		DryRun:      types.BoolValue(false),
+	}
+	m.setTagContract(auditClaimDate("2024-05-01T23:30:00-04:00"))
+
+	tags := m.TagContract.Elements()
+	if tags[tagNamingID].(types.String).ValueString() != "wus2/prd/kvsanmaratlas01" || tags[tagClaimedBy].(types.String).ValueString() != "pipeline@sanmar.com" {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+	if m.claimDate() != "2024-05-02" {
+		t.Fatalf("expected the UTC claim date, got %q", m.claimDate())
+	}
+
+	m.setTagContract(auditClaimDate("not a time"))
+	if _, ok := m.TagContract.Elements()[tagClaimDate]; ok {
+		t.Fatalf("expected no claim-date for an unparsable timestamp, got %v", m.TagContract)
+	}
+
+	m.DryRun = types.BoolValue(true)
+	m.setTagContract("2024-05-02")
+	if m.TagContract.IsNull() || len(m.TagContract.Elements()) != 0 {
+		t.Fatalf("expected no tags for a dry run, got %v", m.TagContract)
+	}
+}
@@ -0,0 +1,400 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	segmentPattern        = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	catalogCodePattern    = regexp.MustCompile(`^[a-z0-9-]+$`)
+	compactSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+	indexPattern          = regexp.MustCompile(`^[0-9]+$`)
+	placeholderPattern    = regexp.MustCompile(`\{([^{}]*)\}`)
+)
+
+// templatePlaceholders are the values the service can substitute into a
+// name template. Optional segments also have a "<segment>_segment" form.
+var templatePlaceholders = map[string]bool{
+	"slug":          true,
+	"region":        true,
+	"environment":   true,
+	"env":           true,
+	"sanmar_prefix": true,
+	"project":       true,
+	"purpose":       true,
+	"system":        true,
+	"subsystem":     true,
+	"index":         true,
+}
+
+// defaultMaxNameLength mirrors the service's base rule set.
+const defaultMaxNameLength = 80
+
+// resourceTypeRule captures the subset of service naming rules that can be
+// checked without a network call.
+type resourceTypeRule struct {
+	MaxLength int
+	Required  []string
+	// Lowercase is set for resource types whose names must be lowercase.
+	Lowercase bool
+	// DNSLabel is set for resource types whose name becomes a DNS label.
+	DNSLabel bool
+	// Compact is set for resource types whose name template joins segments
+	// without separators.
+	Compact bool
+	// SanmarPrefix is set for resource types whose names must include "sanmar".
+	SanmarPrefix bool
+	// Slug is the type's slug, counted towards the name length before the
+	// service has resolved it.
+	Slug string
+}
+
+// resourceTypeRules mirrors the overlays shipped in rules/*.json.
+var resourceTypeRules = map[string]resourceTypeRule{
+	"storage_account": {MaxLength: 24, Required: []string{"system"}, Lowercase: true, DNSLabel: true, Compact: true, SanmarPrefix: true, Slug: "st"},
+	"key_vault":       {MaxLength: 24, Required: []string{"system"}, Compact: true, SanmarPrefix: true, Slug: "kv"},
+}
+
+// claimSegment pairs a segment attribute with its value for validation.
+type claimSegment struct {
+	Name  string
+	Value types.String
+	Path  path.Path
+}
+
+func claimSegments(m claimResourceModel) []claimSegment {
+	resolved := m.resolveSegments()
+	return []claimSegment{
+		{"region", m.Region.StringValue, path.Root("region")},
+		{"environment", m.Environment.StringValue, path.Root("environment")},
+		{"project", resolved.Project, m.segmentPath("project")},
+		{"purpose", resolved.Purpose, m.segmentPath("purpose")},
+		{"system", resolved.System, m.segmentPath("system")},
+		{"subsystem", resolved.Subsystem, m.segmentPath("subsystem")},
+		{"index", resolved.Index.segment(), m.segmentPath("index")},
+	}
+}
+
+// validateClaimModel checks segment character sets, per-type required
+// segments, and the length of the name the type's template renders. Unknown
+// values are skipped so the same checks can run at plan time and again once
+// every value is known.
+func validateClaimModel(m claimResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	rule, ruleKnown := resourceTypeRule{}, false
+	if !m.ResourceType.IsNull() && !m.ResourceType.IsUnknown() {
+		rule, ruleKnown = resourceTypeRules[m.ResourceType.ValueString()]
+	}
+
+	segments := claimSegments(m)
+	for _, seg := range segments {
+		if seg.Value.IsNull() || seg.Value.IsUnknown() {
+			continue
+		}
+		v := seg.Value.ValueString()
+
+		pattern := segmentPattern
+		expected := "letters, digits, and hyphens"
+		switch {
+		case seg.Name == "index":
+			pattern = indexPattern
+			expected = "digits"
+		case rule.Compact:
+			// Compact names join segments directly, and their types do not
+			// allow hyphens.
+			pattern = compactSegmentPattern
+			expected = "letters and digits"
+		}
+		if !pattern.MatchString(v) {
+			diags.AddAttributeError(seg.Path, "Invalid segment value",
+				fmt.Sprintf("%s %q may only contain %s.", seg.Name, v, expected))
+		}
+	}
+
+	diags.Append(validateTemplate(m.Template)...)
+	diags.Append(validateFallback(m)...)
+	_, expiryDiags := parseExpiresAt(m.ExpiresAt)
+	diags.Append(expiryDiags...)
+	if !m.ReleaseAfter.IsNull() && !m.ReleaseAfter.IsUnknown() {
+		if d, err := time.ParseDuration(m.ReleaseAfter.ValueString()); err != nil || d <= 0 {
+			diags.AddAttributeError(path.Root("release_after"), "Invalid release_after",
+				fmt.Sprintf("release_after must be a positive duration such as 720h, got %q.", m.ReleaseAfter.ValueString()))
+		}
+	}
+
+	if m.ResourceType.IsNull() || m.ResourceType.IsUnknown() {
+		return diags
+	}
+
+	if !ruleKnown {
+		rule = resourceTypeRule{MaxLength: defaultMaxNameLength}
+	}
+
+	// Session defaults may supply missing segments server-side.
+	if m.SessionID.IsNull() {
+		for _, required := range rule.Required {
+			for _, seg := range segments {
+				if seg.Name == required && seg.Value.IsNull() {
+					diags.AddAttributeError(seg.Path, "Missing required segment",
+						fmt.Sprintf("resource type %s requires %s to be set.", m.ResourceType.ValueString(), required))
+				}
+			}
+		}
+	}
+
+	if rule.Lowercase && m.Case.ValueString() == "upper" {
+		diags.AddAttributeError(path.Root("case"), "Unsupported name case",
+			fmt.Sprintf("%s names must be lowercase; use case = \"lower\" or \"preserve\".", m.ResourceType.ValueString()))
+	}
+
+	diags.Append(validatePlannedName(m, rule, segments)...)
+	return diags
+}
+
+// validatePlannedName renders the type's name template from the configured
+// segments, its slug, and the sanmar prefix, and rejects names longer than
+// the type allows or, for DNS-facing types, names that are not DNS labels.
+// It is skipped while a templated value is unknown.
+func validatePlannedName(m claimResourceModel, rule resourceTypeRule, segments []claimSegment) diag.Diagnostics {
+	var diags diag.Diagnostics
+	template := m.nameTemplate()
+	if m.Template.IsUnknown() || m.UniqueSuffix.IsUnknown() || m.UniqueLength.IsUnknown() {
+		return diags
+	}
+
+	slug := rule.Slug
+	if !m.Slug.IsNull() && !m.Slug.IsUnknown() && m.Slug.ValueString() != "" {
+		slug = m.Slug.ValueString()
+	}
+	values := map[string]string{"slug": slug}
+	longest, longestLen := "", 0
+	var longestPath path.Path
+	for _, seg := range segments {
+		if !templateUses(template, seg.Name) {
+			continue
+		}
+		if seg.Value.IsUnknown() {
+			return diags
+		}
+		if seg.Value.IsNull() {
+			continue
+		}
+		v := seg.Value.ValueString()
+		if seg.Name == "index" && len(v) < defaultIndexWidth {
+			v = strings.Repeat("0", defaultIndexWidth-len(v)) + v
+		}
+		values[seg.Name] = v
+		if seg.Name != "region" && seg.Name != "environment" && seg.Name != "index" && len(v) > longestLen {
+			longest, longestLen, longestPath = seg.Name, len(v), seg.Path
+		}
+	}
+
+	name := renderNameTemplate(template, values, rule.SanmarPrefix)
+	if label := dnsLabel(name); rule.DNSLabel && len(name) <= dnsLabelMaxLength && label != name {
+		diags.AddAttributeError(path.Root("name"), "Name is not DNS safe",
+			fmt.Sprintf("%s name %q must be usable as a DNS label (lowercase letters, digits, and hyphens, starting with a letter, at most %d characters), but it would become %q.",
+				m.ResourceType.ValueString(), name, dnsLabelMaxLength, label))
+	}
+
+	length := len(name)
+	if m.UniqueSuffix.ValueBool() {
+		// The service appends the suffix, after a hyphen unless the
+		// template joins segments directly.
+		length += int(m.UniqueLength.ValueInt64())
+		if strings.Contains(template, "-") {
+			length++
+		}
+	}
+
+	maxLength := rule.MaxLength
+	if rule.DNSLabel && dnsLabelMaxLength < maxLength {
+		maxLength = dnsLabelMaxLength
+	}
+	if length > maxLength && longest != "" {
+		diags.AddAttributeError(longestPath, "Name too long",
+			fmt.Sprintf("the name would be %d characters, but %s names are limited to %d; shorten %s or another segment.",
+				length, m.ResourceType.ValueString(), maxLength, longest))
+	}
+	return diags
+}
+
+// templateUses reports whether template places segment, in either form.
+func templateUses(template, segment string) bool {
+	return strings.Contains(template, "{"+segment+"}") || strings.Contains(template, "{"+segment+"_segment}")
+}
+
+// nameTemplate returns the template the service renders m's name with: the
+// configured template, or the one its resource type's rules use.
+func (m claimResourceModel) nameTemplate() string {
+	if !m.Template.IsNull() && !m.Template.IsUnknown() && m.Template.ValueString() != "" {
+		return normalizeTemplate(m.Template.ValueString())
+	}
+	if resourceTypeRules[m.ResourceType.ValueString()].Compact {
+		return compactNameTemplate
+	}
+	return baseNameTemplate
+}
+
+// applyCase converts a name returned by the service to the configured case,
+// lowercasing it regardless for resource types that require that.
+func (m claimResourceModel) applyCase(name string) string {
+	if resourceTypeRules[m.ResourceType.ValueString()].Lowercase {
+		return strings.ToLower(name)
+	}
+	switch m.Case.ValueString() {
+	case "lower":
+		return strings.ToLower(name)
+	case "upper":
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// validateDNSName rejects names of DNS-facing resource types that are not
+// already valid DNS labels apart from case.
+func validateDNSName(m claimResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !resourceTypeRules[m.ResourceType.ValueString()].DNSLabel || m.Name.IsNull() || m.Name.IsUnknown() {
+		return diags
+	}
+
+	name := m.Name.ValueString()
+	if label := dnsLabel(name); label != strings.ToLower(name) {
+		diags.AddAttributeError(path.Root("name"), "Name is not DNS safe",
+			fmt.Sprintf("%s name %q must be usable as a DNS label (lowercase letters, digits, and hyphens, starting with a letter, at most %d characters), but it would become %q.",
+				m.ResourceType.ValueString(), name, dnsLabelMaxLength, label))
+	}
+	return diags
+}
+
+// validateTemplate checks that a name template only uses placeholders the
+// service can fill.
+func validateTemplate(template types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if template.IsNull() || template.IsUnknown() {
+		return diags
+	}
+
+	matches := placeholderPattern.FindAllStringSubmatch(template.ValueString(), -1)
+	if len(matches) == 0 {
+		diags.AddAttributeError(path.Root("template"), "Invalid name template",
+			"template must contain at least one placeholder such as {slug}.")
+	}
+	for _, match := range matches {
+		name := strings.TrimSuffix(match[1], "_segment")
+		if !templatePlaceholders[name] || (name != match[1] && !optionalSegment(name)) {
+			diags.AddAttributeError(path.Root("template"), "Invalid name template",
+				fmt.Sprintf("unknown placeholder {%s}.", match[1]))
+		}
+	}
+	return diags
+}
+
+// validateFallback refuses offline fallback for claims with a custom
+// template. The service ignores template, so a name rendered from it offline
+// could never be registered.
+func validateFallback(m claimResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if m.Fallback.ValueString() != fallbackOffline || m.Template.IsNull() || m.Template.IsUnknown() || m.Template.ValueString() == "" {
+		return diags
+	}
+	diags.AddAttributeError(path.Root("fallback"), "Invalid fallback",
+		"fallback = \"offline\" cannot be combined with a custom template: the service names claims from its own templates, so the offline name could never be registered. Remove template or fallback.")
+	return diags
+}
+
+func optionalSegment(name string) bool {
+	switch name {
+	case "project", "purpose", "system", "subsystem", "index":
+		return true
+	}
+	return false
+}
+
+// normalizeTemplate expands the {env} shorthand to the {environment}
+// placeholder the service understands.
+func normalizeTemplate(template string) string {
+	return strings.ReplaceAll(template, "{env}", "{environment}")
+}
+
+// validateEnvironment rejects environments outside the provider's
+// allowed_environments list. An empty list allows every environment.
+func validateEnvironment(env types.String, allowed []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(allowed) == 0 || env.IsNull() || env.IsUnknown() {
+		return diags
+	}
+
+	for _, candidate := range allowed {
+		if strings.EqualFold(candidate, env.ValueString()) {
+			return diags
+		}
+	}
+
+	diags.AddAttributeError(path.Root("environment"), "Environment not allowed",
+		fmt.Sprintf("environment %q is not one of the provider's allowed_environments: %s.", env.ValueString(), strings.Join(allowed, ", ")))
+	return diags
+}
+
+// Values of the provider's validation_mode setting.
+const (
+	validationModeError = "error"
+	validationModeWarn  = "warn"
+)
+
+var validationModes = []string{validationModeError, validationModeWarn}
+
+// conventionSummaries are the summaries of diagnostics that report a name
+// breaking the naming convention, as opposed to configuration the provider
+// cannot use at all, such as an unparsable expires_at.
+var conventionSummaries = map[string]bool{
+	"Invalid segment value":    true,
+	"Missing required segment": true,
+	"Unsupported name case":    true,
+	"Name too long":            true,
+	"Name is not DNS safe":     true,
+	"Environment not allowed":  true,
+}
+
+// splitConventions separates convention violations from other diagnostics.
+func splitConventions(diags diag.Diagnostics) (conventions, other diag.Diagnostics) {
+	for _, d := range diags {
+		if conventionSummaries[d.Summary()] {
+			conventions = append(conventions, d)
+		} else {
+			other = append(other, d)
+		}
+	}
+	return conventions, other
+}
+
+// conventionDiagnostics returns diags with convention violations downgraded
+// to warnings when the provider's validation_mode is warn. A nil client, as
+// before the provider is configured, keeps them as errors.
+func (c *APIClient) conventionDiagnostics(diags diag.Diagnostics) diag.Diagnostics {
+	if c == nil || c.validationMode != validationModeWarn {
+		return diags
+	}
+
+	result := make(diag.Diagnostics, 0, len(diags))
+	for _, d := range diags {
+		if d.Severity() != diag.SeverityError || !conventionSummaries[d.Summary()] {
+			result = append(result, d)
+			continue
+		}
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			result = append(result, diag.NewAttributeWarningDiagnostic(withPath.Path(), d.Summary(), d.Detail()))
+		} else {
+			result = append(result, diag.NewWarningDiagnostic(d.Summary(), d.Detail()))
+		}
+	}
+	return result
+}
@@ -0,0 +1,348 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateClaimModel(t *testing.T) {
+	base := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		Project:      types.StringNull(),
+		Purpose:      types.StringNull(),
+		System:       types.StringValue("atlas"),
+		Subsystem:    types.StringNull(),
+		Index:        indexNumber("01"),
+		SessionID:    types.StringNull(),
+	}
+
+	if diags := validateClaimModel(base); diags.HasError() {
+		t.Fatalf("expected valid model, got %v", diags)
+	}
+
+	missing := base
+	missing.System = types.StringNull()
+	diags := validateClaimModel(missing)
+	if !diags.HasError() || !diagsHavePath(diags.Errors(), path.Root("system")) {
+		t.Fatalf("expected missing system error, got %v", diags)
+	}
+
+	unknown := missing
+	unknown.System = types.StringUnknown()
+	if diags := validateClaimModel(unknown); diags.HasError() {
+		t.Fatalf("expected unknown values to be skipped, got %v", diags)
+	}
+
+	badIndex := base
+	badIndex.Index = indexNumberValue{Int64Value: types.Int64Value(-1)}
+	if diags := validateClaimModel(badIndex); !diagsHavePath(diags.Errors(), path.Root("index")) {
+		t.Fatalf("expected index error, got %v", diags)
+	}
+
+	// wus2prdstsanmar + system + 01 fits 24 characters up to a 7 character system.
+	fits := base
+	fits.System = types.StringValue("atlasxy")
+	if diags := validateClaimModel(fits); diags.HasError() {
+		t.Fatalf("expected a 24 character name to fit, got %v", diags)
+	}
+	long := base
+	long.System = types.StringValue("atlasxyz")
+	if diags := validateClaimModel(long); !diagsHavePath(diags.Errors(), path.Root("system")) {
+		t.Fatalf("expected length error on system, got %v", diags)
+	}
+
+	// Project and purpose are not part of the name.
+	longPurpose := base
+	longPurpose.Purpose = types.StringValue("averyverylongpurpose")
+	if diags := validateClaimModel(longPurpose); diags.HasError() {
+		t.Fatalf("expected purpose not to count towards the length, got %v", diags)
+	}
+
+	// The unique suffix does.
+	suffixed := fits
+	suffixed.UniqueSuffix = types.BoolValue(true)
+	suffixed.UniqueLength = types.Int64Value(4)
+	if diags := validateClaimModel(suffixed); !diagsHavePath(diags.Errors(), path.Root("system")) {
+		t.Fatalf("expected the suffix to count towards the length, got %v", diags)
+	}
+
+	hyphenated := base
+	hyphenated.System = types.StringValue("at-las")
+	if diags := validateClaimModel(hyphenated); !diagsHavePath(diags.Errors(), path.Root("system")) {
+		t.Fatalf("expected hyphens to be rejected for compact types, got %v", diags)
+	}
+	hyphenated.ResourceType = types.StringValue("virtual_network")
+	if diags := validateClaimModel(hyphenated); diags.HasError() {
+		t.Fatalf("expected hyphens to be allowed for other types, got %v", diags)
+	}
+}
+
+func TestValidateClaimModelSegments(t *testing.T) {
+	segments := func(system string, index int64) types.Object {
+		return types.ObjectValueMust(claimSegmentAttrTypes, map[string]attr.Value{
+			"project":   types.StringNull(),
+			"purpose":   types.StringNull(),
+			"system":    types.StringValue(system),
+			"subsystem": types.StringNull(),
+			"index":     indexNumberValue{Int64Value: types.Int64Value(index)},
+		})
+	}
+	base := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		SessionID:    types.StringNull(),
+		Segments:     segments("atlas", 1),
+	}
+
+	if diags := validateClaimModel(base); diags.HasError() {
+		t.Fatalf("expected system from segments to satisfy the rule, got %v", diags)
+	}
+
+	badIndex := base
+	badIndex.Segments = segments("atlas", -1)
+	if diags := validateClaimModel(badIndex); !diagsHavePath(diags.Errors(), path.Root("segments").AtName("index")) {
+		t.Fatalf("expected index error inside segments, got %v", diags)
+	}
+
+	conflict := base
+	conflict.System = types.StringValue("atlas")
+	if diags := validateSegmentConflicts(conflict); !diagsHavePath(diags.Errors(), path.Root("segments").AtName("system")) {
+		t.Fatalf("expected conflict on system, got %v", diags)
+	}
+	if diags := validateSegmentConflicts(base); diags.HasError() {
+		t.Fatalf("unexpected conflict: %v", diags)
+	}
+
+	if got := base.segmentsFromFlat(); !got.Attributes()["system"].IsNull() {
+		t.Fatalf("expected segmentsFromFlat to read top-level attributes, got %v", got)
+	}
+}
+
+func TestEmptySegmentsAreUnset(t *testing.T) {
+	nested := types.ObjectValueMust(claimSegmentAttrTypes, map[string]attr.Value{
+		"project":   types.StringValue(""),
+		"purpose":   types.StringNull(),
+		"system":    types.StringValue("atlas"),
+		"subsystem": types.StringNull(),
+		"index":     indexNumber(""),
+	})
+	m := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		SessionID:    types.StringNull(),
+		Project:      types.StringNull(),
+		Purpose:      types.StringValue(""),
+		System:       types.StringValue(""),
+		Segments:     nested,
+	}
+
+	resolved := m.resolveSegments()
+	if !resolved.Project.IsNull() || !resolved.Purpose.IsNull() {
+		t.Fatalf("expected empty segments to resolve to null, got %v and %v", resolved.Project, resolved.Purpose)
+	}
+	if resolved.System.ValueString() != "atlas" {
+		t.Fatalf("expected empty top-level system to defer to segments, got %v", resolved.System)
+	}
+	if diags := validateSegmentConflicts(m); diags.HasError() {
+		t.Fatalf("empty values should not conflict: %v", diags)
+	}
+	if diags := validateClaimModel(m); diags.HasError() {
+		t.Fatalf("empty segments should pass validation: %v", diags)
+	}
+
+	payload, diags := buildClaimPayload(context.Background(), m)
+	if diags.HasError() {
+		t.Fatalf("buildClaimPayload: %v", diags)
+	}
+	if payload.Project != nil || payload.Purpose != nil {
+		t.Fatalf("expected empty segments to be left out of the claim, got %+v", payload)
+	}
+
+	prior := m
+	prior.Purpose = types.StringNull()
+	prior.Segments = types.ObjectNull(claimSegmentAttrTypes)
+	prior.System = types.StringValue("atlas")
+	if !m.resolveSegments().Purpose.Equal(prior.resolveSegments().Purpose) {
+		t.Fatal("expected empty and null purpose to compare equal")
+	}
+}
+
+func diagsHavePath(diags []diag.Diagnostic, p path.Path) bool {
+	for _, d := range diags {
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok && withPath.Path().Equal(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	allowed := []string{"dev", "stg", "prd"}
+	if diags := validateEnvironment(types.StringValue("PRD"), allowed); diags.HasError() {
+		t.Fatalf("expected case-insensitive match, got %v", diags)
+	}
+	if diags := validateEnvironment(types.StringValue("porduction"), allowed); !diagsHavePath(diags.Errors(), path.Root("environment")) {
+		t.Fatalf("expected environment error, got %v", diags)
+	}
+	if diags := validateEnvironment(types.StringValue("anything"), nil); diags.HasError() {
+		t.Fatalf("expected empty list to allow all, got %v", diags)
+	}
+}
+
+func TestConventionDiagnostics(t *testing.T) {
+	m := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		Project:      types.StringNull(),
+		Purpose:      types.StringNull(),
+		System:       types.StringNull(),
+		Subsystem:    types.StringNull(),
+		Index:        indexNumber("01"),
+		SessionID:    types.StringNull(),
+		ReleaseAfter: types.StringValue("soon"),
+	}
+	diags := validateClaimModel(m)
+
+	if got := (*APIClient)(nil).conventionDiagnostics(diags); got.ErrorsCount() != 2 {
+		t.Fatalf("expected errors without a client, got %v", got)
+	}
+
+	warn := &APIClient{validationMode: validationModeWarn}
+	got := warn.conventionDiagnostics(diags)
+	if !diagsHavePath(got.Warnings(), path.Root("system")) {
+		t.Fatalf("expected missing system to be a warning, got %v", got)
+	}
+	if !diagsHavePath(got.Errors(), path.Root("release_after")) || got.ErrorsCount() != 1 {
+		t.Fatalf("expected release_after to stay an error, got %v", got)
+	}
+
+	conventions, other := splitConventions(diags)
+	if len(conventions) != 1 || len(other) != 1 {
+		t.Fatalf("expected one convention and one other diagnostic, got %v and %v", conventions, other)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	valid := []string{
+		"{slug}-{project}-{env}-{region}-{index}",
+		"{region}{environment}{slug}{sanmar_prefix}{system}{subsystem_segment}",
+	}
+	for _, template := range valid {
+		if diags := validateTemplate(types.StringValue(template)); diags.HasError() {
+			t.Fatalf("expected %q to be valid, got %v", template, diags)
+		}
+	}
+
+	invalid := []string{"static-name", "{slug}-{owner}", "{slug}-{region_segment}"}
+	for _, template := range invalid {
+		if diags := validateTemplate(types.StringValue(template)); !diagsHavePath(diags.Errors(), path.Root("template")) {
+			t.Fatalf("expected %q to be rejected, got %v", template, diags)
+		}
+	}
+
+	if got := normalizeTemplate("{slug}-{env}-{region}"); got != "{slug}-{environment}-{region}" {
+		t.Fatalf("unexpected normalized template %q", got)
+	}
+}
+
+func TestValidateFallback(t *testing.T) {
+	m := claimResourceModel{
+		Template: types.StringValue("{slug}-{region}-{index}"),
+		Fallback: types.StringValue(fallbackOffline),
+	}
+	if diags := validateFallback(m); !diagsHavePath(diags.Errors(), path.Root("fallback")) {
+		t.Fatalf("expected offline fallback with a template to be rejected, got %v", diags)
+	}
+	m.Template = types.StringNull()
+	if diags := validateFallback(m); diags.HasError() {
+		t.Fatalf("expected offline fallback without a template to pass, got %v", diags)
+	}
+	m.Template, m.Fallback = types.StringValue("{slug}-{region}-{index}"), types.StringNull()
+	if diags := validateFallback(m); diags.HasError() {
+		t.Fatalf("expected a template without fallback to pass, got %v", diags)
+	}
+}
+
+func TestApplyCase(t *testing.T) {
+	cases := []struct {
+		resourceType string
+		nameCase     types.String
+		want         string
+	}{
+		{"function_app", types.StringNull(), "Func-WUS2-prd"},
+		{"function_app", types.StringValue("preserve"), "Func-WUS2-prd"},
+		{"function_app", types.StringValue("lower"), "func-wus2-prd"},
+		{"function_app", types.StringValue("upper"), "FUNC-WUS2-PRD"},
+		{"storage_account", types.StringNull(), "func-wus2-prd"},
+	}
+	for _, c := range cases {
+		m := claimResourceModel{ResourceType: types.StringValue(c.resourceType), Case: c.nameCase}
+		if got := m.applyCase("Func-WUS2-prd"); got != c.want {
+			t.Fatalf("applyCase(%s, %s) = %q, want %q", c.resourceType, c.nameCase, got, c.want)
+		}
+	}
+
+	upper := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		System:       types.StringValue("atlas"),
+		SessionID:    types.StringNull(),
+		Case:         types.StringValue("upper"),
+	}
+	if diags := validateClaimModel(upper); !diagsHavePath(diags.Errors(), path.Root("case")) {
+		t.Fatalf("expected upper case to be rejected for storage accounts, got %v", diags)
+	}
+}
+
+func TestValidateDNSName(t *testing.T) {
+	m := claimResourceModel{ResourceType: types.StringValue("storage_account"), Name: types.StringValue("WUS2prdstsanmaratlas01")}
+	if diags := validateDNSName(m); diags.HasError() {
+		t.Fatalf("expected a name that only needs lowercasing to pass, got %v", diags)
+	}
+	m.setNameVariants()
+	if m.DNSPrefix.ValueString() != "wus2prdstsanmaratlas01" {
+		t.Fatalf("unexpected dns_prefix %s", m.DNSPrefix)
+	}
+
+	for _, name := range []string{"01wus2prdst", "wus2_prd_st", strings.Repeat("a", 64)} {
+		m.Name = types.StringValue(name)
+		if diags := validateDNSName(m); !diagsHavePath(diags.Errors(), path.Root("name")) {
+			t.Fatalf("expected %q to be rejected, got %v", name, diags)
+		}
+	}
+
+	other := claimResourceModel{ResourceType: types.StringValue("function_app"), Name: types.StringValue("func_wus2")}
+	other.setNameVariants()
+	if diags := validateDNSName(other); diags.HasError() || !other.DNSPrefix.IsNull() {
+		t.Fatalf("expected other resource types to be skipped, got %v and %s", diags, other.DNSPrefix)
+	}
+
+	// New claims have no name yet, so the name the template renders is
+	// checked at plan time.
+	planned := claimResourceModel{
+		ResourceType: types.StringValue("storage_account"),
+		Region:       regionType.value("wus2"),
+		Environment:  environmentType.value("prd"),
+		System:       types.StringValue("atlas"),
+		Template:     types.StringValue("{slug}_{system}"),
+	}
+	if diags := validateClaimModel(planned); !diagsHavePath(diags.Errors(), path.Root("name")) {
+		t.Fatalf("expected the planned name to be rejected, got %v", diags)
+	}
+	planned.Template = types.StringNull()
+	if diags := validateClaimModel(planned); diags.HasError() {
+		t.Fatalf("expected the rule's template to render a DNS label, got %v", diags)
+	}
+}
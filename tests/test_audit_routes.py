@@ -27,6 +27,7 @@ from app.routes.audit import (
 # FunctionBuilder objects.  Extract the raw user functions for unit tests.
 _audit_name_fn = audit_routes.audit_name._function.get_user_function()
 _audit_bulk_fn = audit_routes.audit_bulk._function.get_user_function()
+_link_fn = audit_routes.link_azure_resource._function.get_user_function()
 
 
 # ---------------------------------------------------------------------------
@@ -39,6 +40,7 @@ class FakeAuditTable:
         self.list_called = False
         self._entities = entities or {}
         self._raise_on_get = raise_on_get
+        self.updated = None
 
     def get_entity(self, partition_key, row_key):
         if self._raise_on_get:
@@ -57,6 +59,9 @@ class FakeAuditTable:
         self.list_called = True
         yield from self._entities.values()
 
+    def update_entity(self, entity, mode=None, match_condition=None):
+        self.updated = entity
+
 
 def _make_auth_error(msg="Auth failed", status=401):
     from app.dependencies import AuthError
@@ -161,8 +166,31 @@ class TestBuildFilter:
 # ---------------------------------------------------------------------------
 
 class TestAuditName:
-    def _make_request(self, params=None, headers=None):
-        return SimpleNamespace(params=params or {}, headers=headers or {})
+    def _make_request(self, params=None, headers=None, method="GET"):
+        return SimpleNamespace(params=params or {}, headers=headers or {}, method=method)
+
+    def test_head_reports_in_use_without_ownership_check(self, monkeypatch):
+        entity = {
+            "PartitionKey": "wus2-dev", "RowKey": "res",
+            "ClaimedBy": "other", "ReleasedBy": "",
+            "ResourceType": "vm", "InUse": True,
+        }
+        table = FakeAuditTable({("wus2-dev", "res"): entity})
+        monkeypatch.setattr(audit_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        monkeypatch.setattr(audit_routes, "get_table_client", lambda name: table)
+        req = self._make_request(params={"region": "wus2", "environment": "dev", "name": "res"}, method="HEAD")
+        resp = _audit_name_fn(req)
+        assert resp.status_code == 200
+        assert resp.headers.get(audit_routes.IN_USE_HEADER) == "true"
+        assert resp.get_body() == b""
+
+    def test_head_not_found_carries_header(self, monkeypatch):
+        monkeypatch.setattr(audit_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        monkeypatch.setattr(audit_routes, "get_table_client", lambda name: FakeAuditTable())
+        req = self._make_request(params={"region": "wus2", "environment": "dev", "name": "free"}, method="HEAD")
+        resp = _audit_name_fn(req)
+        assert resp.status_code == 404
+        assert resp.headers.get(audit_routes.IN_USE_HEADER) == "false"
 
     def test_auth_error(self, monkeypatch):
         monkeypatch.setattr(audit_routes, "require_role", mock.Mock(side_effect=_make_auth_error()))
@@ -281,6 +309,53 @@ class TestAuditBulk:
         assert len(body["results"]) == 1
         assert body["results"][0]["user"] == "alice"
 
+    def test_claim_segments(self, monkeypatch):
+        entities = {
+            ("stwus2prdsanmaratlas01", "row1"): {
+                "PartitionKey": "stwus2prdsanmaratlas01", "RowKey": "row1",
+                "User": "alice", "Action": "claimed", "Note": "",
+                "EventTime": datetime(2025, 1, 15, 10, 0, 0),
+                "Region": "wus2", "Environment": "prd", "ResourceType": "storage_account",
+                "Slug": "st", "System": "atlas", "Index": "01",
+            },
+        }
+        monkeypatch.setattr(audit_routes, "require_role", lambda h, min_role: ("alice", ["reader"]))
+        monkeypatch.setattr(audit_routes, "get_table_client", lambda name: FakeAuditTable(entities))
+        resp = _audit_bulk_fn(self._make_request(params={"user": "alice"}))
+        (record,) = json.loads(resp.get_body())["results"]
+        assert (record["slug"], record["system"], record["subsystem"], record["index"]) == ("st", "atlas", None, "01")
+
+    def test_transfer_reports_new_owner(self, monkeypatch):
+        entities = {
+            ("vmwus2dev01", "row1"): {
+                "PartitionKey": "vmwus2dev01", "RowKey": "row1",
+                "User": "alice", "Action": "transferred", "Note": "Transferred from alice to bob",
+                "EventTime": datetime(2025, 1, 15, 10, 0, 0), "NewOwner": "bob",
+            },
+        }
+        monkeypatch.setattr(audit_routes, "require_role", lambda h, min_role: ("alice", ["reader"]))
+        monkeypatch.setattr(audit_routes, "get_table_client", lambda name: FakeAuditTable(entities))
+        resp = _audit_bulk_fn(self._make_request(params={"user": "alice"}))
+        (record,) = json.loads(resp.get_body())["results"]
+        assert record["new_owner"] == "bob"
+
+    def test_reports_run_headers(self, monkeypatch):
+        entities = {
+            ("vmwus2dev01", "row1"): {
+                "PartitionKey": "vmwus2dev01", "RowKey": "row1",
+                "User": "alice", "Action": "claimed", "Note": "",
+                "EventTime": datetime(2025, 1, 15, 10, 0, 0),
+                "TerraformVersion": "1.9.5", "TerraformWorkspace": "payments-dev", "TerraformExecution": "tfc",
+            },
+        }
+        monkeypatch.setattr(audit_routes, "require_role", lambda h, min_role: ("alice", ["reader"]))
+        monkeypatch.setattr(audit_routes, "get_table_client", lambda name: FakeAuditTable(entities))
+        resp = _audit_bulk_fn(self._make_request(params={"user": "alice"}))
+        (record,) = json.loads(resp.get_body())["results"]
+        assert (record["terraform_version"], record["terraform_workspace"], record["terraform_execution"]) == (
+            "1.9.5", "payments-dev", "tfc",
+        )
+
     def test_event_time_string(self, monkeypatch):
         entities = {
             ("n", "r"): {
@@ -296,3 +371,80 @@ class TestAuditBulk:
         assert resp.status_code == 200
         body = json.loads(resp.get_body())
         assert body["results"][0]["timestamp"] == "2025-01-01T00:00:00"
+
+
+# ---------------------------------------------------------------------------
+# link_azure_resource
+# ---------------------------------------------------------------------------
+
+LINK_PARAMS = {"region": "wus2", "environment": "dev", "name": "res"}
+RESOURCE_ID = "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Web/sites/res"
+
+
+class TestLinkAzureResource:
+    def _make_request(self, body, params=None):
+        def get_json():
+            if body is None:
+                raise ValueError("No body")
+            return body
+
+        return SimpleNamespace(params=LINK_PARAMS if params is None else params, headers={}, method="PATCH", get_json=get_json)
+
+    def _setup(self, monkeypatch, entity=None, authorized=True):
+        entities = {}
+        if entity is not None:
+            entities[("wus2-dev", "res")] = entity
+        table = FakeAuditTable(entities)
+        monkeypatch.setattr(audit_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+        monkeypatch.setattr(audit_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(audit_routes, "is_authorized", lambda roles, uid, cb, rb: authorized)
+        return table
+
+    def test_links_resource(self, monkeypatch):
+        table = self._setup(monkeypatch, {"PartitionKey": "wus2-dev", "RowKey": "res", "ClaimedBy": "u1", "InUse": True})
+        resp = _link_fn(self._make_request({"azure_resource_id": RESOURCE_ID}))
+        assert resp.status_code == 200
+        assert table.updated["AzureResourceId"] == RESOURCE_ID
+
+    def test_linked_resource_is_reported_by_audit(self, monkeypatch):
+        entity = {"PartitionKey": "wus2-dev", "RowKey": "res", "ClaimedBy": "u1", "InUse": True, "ResourceType": "vm"}
+        table = self._setup(monkeypatch, entity)
+        _link_fn(self._make_request({"azure_resource_id": RESOURCE_ID}))
+        table._entities[("wus2-dev", "res")] = table.updated
+        resp = _audit_name_fn(SimpleNamespace(params=LINK_PARAMS, headers={}, method="GET"))
+        assert json.loads(resp.get_body())["azure_resource_id"] == RESOURCE_ID
+
+    def test_empty_id_removes_link(self, monkeypatch):
+        entity = {"PartitionKey": "wus2-dev", "RowKey": "res", "ClaimedBy": "u1", "InUse": True, "AzureResourceId": RESOURCE_ID}
+        table = self._setup(monkeypatch, entity)
+        resp = _link_fn(self._make_request({"azure_resource_id": ""}))
+        assert resp.status_code == 200
+        assert "AzureResourceId" not in table.updated
+
+    def test_rejects_non_arm_id(self, monkeypatch):
+        table = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True})
+        resp = _link_fn(self._make_request({"azure_resource_id": "res"}))
+        assert resp.status_code == 400
+        assert table.updated is None
+
+    def test_missing_params(self, monkeypatch):
+        self._setup(monkeypatch)
+        resp = _link_fn(self._make_request({"azure_resource_id": RESOURCE_ID}, params={}))
+        assert resp.status_code == 400
+
+    def test_not_found(self, monkeypatch):
+        self._setup(monkeypatch)
+        resp = _link_fn(self._make_request({"azure_resource_id": RESOURCE_ID}))
+        assert resp.status_code == 404
+
+    def test_released_name(self, monkeypatch):
+        table = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": False})
+        resp = _link_fn(self._make_request({"azure_resource_id": RESOURCE_ID}))
+        assert resp.status_code == 409
+        assert table.updated is None
+
+    def test_forbidden(self, monkeypatch):
+        table = self._setup(monkeypatch, {"ClaimedBy": "other", "InUse": True}, authorized=False)
+        resp = _link_fn(self._make_request({"azure_resource_id": RESOURCE_ID}))
+        assert resp.status_code == 403
+        assert table.updated is None
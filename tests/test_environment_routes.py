@@ -0,0 +1,157 @@
+"""Tests for app.routes.environments module."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+from unittest import mock
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from app.routes import environments as environment_routes
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _auth_error(msg="Auth failed", status=401):
+    from app.dependencies import AuthError
+    return AuthError(msg, status=status)
+
+
+def _make_request(body=None, route_params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params={}, headers={}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeEnvironmentTable:
+    def __init__(self, entities=None):
+        self._entities = {entity["RowKey"]: entity for entity in entities or []}
+
+    def get_entity(self, partition_key, row_key):
+        if row_key not in self._entities:
+            raise environment_routes.ResourceNotFoundError("not found")
+        return dict(self._entities[row_key])
+
+    def create_entity(self, entity):
+        if entity["RowKey"] in self._entities:
+            raise environment_routes.ResourceExistsError("exists")
+        self._entities[entity["RowKey"]] = entity
+
+    def update_entity(self, entity, mode=None):
+        self._entities[entity["RowKey"]] = entity
+
+    def delete_entity(self, partition_key, row_key):
+        del self._entities[row_key]
+
+
+PRD = {
+    "PartitionKey": "environment",
+    "RowKey": "prd",
+    "DisplayName": "Production",
+    "AllowedRegions": "eus2,wus2",
+    "ReleasedRetention": "720h",
+}
+
+
+def _setup(monkeypatch, *entities):
+    table = FakeEnvironmentTable(list(entities))
+    monkeypatch.setattr(environment_routes, "require_role", lambda h, min_role: ("u1", ["admin"]))
+    monkeypatch.setattr(environment_routes, "get_table_client", lambda name: table)
+    return table
+
+
+# ---------------------------------------------------------------------------
+# create_environment
+# ---------------------------------------------------------------------------
+
+class TestCreateEnvironment:
+    def test_auth_error(self, monkeypatch):
+        monkeypatch.setattr(environment_routes, "require_role", mock.Mock(side_effect=_auth_error(status=403)))
+        resp = _fn(environment_routes.create_environment)(_make_request({"code": "prd"}))
+        assert resp.status_code == 403
+
+    def test_creates(self, monkeypatch):
+        table = _setup(monkeypatch)
+        body = {"code": "PRD", "display_name": "Production", "allowed_regions": ["wus2", "eus2"], "released_retention": "720h"}
+        resp = _fn(environment_routes.create_environment)(_make_request(body))
+        assert resp.status_code == 201
+        assert json.loads(resp.get_body()) == {
+            "code": "prd",
+            "display_name": "Production",
+            "allowed_regions": ["eus2", "wus2"],
+            "released_retention": "720h",
+        }
+        assert table._entities["prd"]["AllowedRegions"] == "eus2,wus2"
+
+    def test_conflict(self, monkeypatch):
+        _setup(monkeypatch, PRD)
+        resp = _fn(environment_routes.create_environment)(_make_request({"code": "prd", "display_name": "Production"}))
+        assert resp.status_code == 409
+
+    def test_invalid_payloads(self, monkeypatch):
+        _setup(monkeypatch)
+        create = _fn(environment_routes.create_environment)
+        assert create(_make_request(None)).status_code == 400
+        assert create(_make_request({"code": "p r d", "display_name": "x"})).status_code == 400
+        assert create(_make_request({"code": "prd"})).status_code == 400
+        assert create(_make_request({"code": "prd", "display_name": "x", "allowed_regions": ["w us2"]})).status_code == 400
+        assert create(_make_request({"code": "prd", "display_name": "x", "released_retention": "30 days"})).status_code == 400
+
+
+# ---------------------------------------------------------------------------
+# get/update/delete_environment
+# ---------------------------------------------------------------------------
+
+class TestEnvironmentByCode:
+    def test_get(self, monkeypatch):
+        _setup(monkeypatch, PRD)
+        resp = _fn(environment_routes.get_environment)(_make_request(route_params={"code": "prd"}))
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body())["allowed_regions"] == ["eus2", "wus2"]
+
+    def test_get_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(environment_routes.get_environment)(_make_request(route_params={"code": "prd"}))
+        assert resp.status_code == 404
+
+    def test_update_replaces_settings(self, monkeypatch):
+        table = _setup(monkeypatch, PRD)
+        resp = _fn(environment_routes.update_environment)(
+            _make_request({"code": "prd", "display_name": "Prod"}, route_params={"code": "prd"})
+        )
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body()) == {"code": "prd", "display_name": "Prod"}
+        assert "AllowedRegions" not in table._entities["prd"]
+
+    def test_update_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(environment_routes.update_environment)(
+            _make_request({"display_name": "Prod"}, route_params={"code": "prd"})
+        )
+        assert resp.status_code == 404
+
+    def test_delete(self, monkeypatch):
+        table = _setup(monkeypatch, PRD)
+        resp = _fn(environment_routes.delete_environment)(_make_request(route_params={"code": "prd"}))
+        assert resp.status_code == 204
+        assert "prd" not in table._entities
+
+    def test_delete_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(environment_routes.delete_environment)(_make_request(route_params={"code": "prd"}))
+        assert resp.status_code == 404
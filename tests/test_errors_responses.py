@@ -29,6 +29,15 @@ class TestHandleNameGenerationError:
         assert resp.status_code == 400
         assert b"missing field" in resp.get_body()
 
+    def test_invalid_request_error_names_its_fields(self):
+        exc = InvalidRequestError("Field 'suffix' must be 1-16 letters or digits.", fields=["suffix"])
+        resp = handle_name_generation_error(exc, log_prefix="test")
+        assert resp.status_code == 400
+        assert json.loads(resp.get_body()) == {
+            "message": "Field 'suffix' must be 1-16 letters or digits.",
+            "errors": [{"field": "suffix", "message": "Field 'suffix' must be 1-16 letters or digits."}],
+        }
+
     def test_name_conflict_error(self):
         exc = NameConflictError("name taken")
         resp = handle_name_generation_error(exc, log_prefix="test")
@@ -0,0 +1,175 @@
+"""Tests for app.routes.index_reservations and core.index_reservations."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from app.routes import index_reservations as reservation_routes
+from core import index_reservations
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _make_request(body=None, route_params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params={}, headers={}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeReservationTable:
+    def __init__(self, entities=None):
+        self._entities = {entity["RowKey"]: entity for entity in entities or []}
+
+    def get_entity(self, partition_key, row_key):
+        if row_key not in self._entities:
+            raise reservation_routes.ResourceNotFoundError("not found")
+        return dict(self._entities[row_key])
+
+    def create_entity(self, entity):
+        self._entities[entity["RowKey"]] = entity
+
+    def delete_entity(self, partition_key, row_key):
+        del self._entities[row_key]
+
+    def query_entities(self, query):
+        return [dict(entity) for entity in self._entities.values()]
+
+
+ORION = {
+    "PartitionKey": "reservation",
+    "RowKey": "res-1",
+    "Region": "wus2",
+    "Environment": "prd",
+    "ResourceType": "storage_account",
+    "Project": "",
+    "Team": "orion",
+    "Start": 10,
+    "End": 19,
+}
+
+BODY = {"region": "WUS2", "environment": "prd", "team": "Atlas", "start": 20, "end": 29}
+
+
+def _setup(monkeypatch, *entities):
+    table = FakeReservationTable(list(entities))
+    monkeypatch.setattr(reservation_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+    monkeypatch.setattr(reservation_routes, "get_table_client", lambda name: table)
+    monkeypatch.setattr(index_reservations, "get_table_client", lambda name: table)
+    return table
+
+
+# ---------------------------------------------------------------------------
+# Routes
+# ---------------------------------------------------------------------------
+
+def test_create_index_reservation(monkeypatch):
+    table = _setup(monkeypatch, ORION)
+    resp = _fn(reservation_routes.create_index_reservation)(_make_request(body=BODY))
+    assert resp.status_code == 201
+    payload = json.loads(resp.get_body())
+    assert payload["id"]
+    assert payload["region"] == "wus2"
+    assert payload["team"] == "atlas"
+    assert payload["resource_type"] is None
+    assert (payload["start"], payload["end"]) == (20, 29)
+    assert table._entities[payload["id"]]["Team"] == "atlas"
+
+
+def test_create_index_reservation_rejects_overlap(monkeypatch):
+    _setup(monkeypatch, ORION)
+    create = _fn(reservation_routes.create_index_reservation)
+    # No resource type covers every type, so it overlaps orion's storage block.
+    assert create(_make_request(body={**BODY, "start": 15})).status_code == 409
+    # A different type or environment does not.
+    assert create(_make_request(body={**BODY, "start": 15, "end": 15, "resource_type": "key_vault"})).status_code == 201
+    assert create(_make_request(body={**BODY, "start": 10, "end": 19, "environment": "dev"})).status_code == 201
+
+
+def test_create_index_reservation_rejects_invalid_fields(monkeypatch):
+    _setup(monkeypatch)
+    create = _fn(reservation_routes.create_index_reservation)
+    assert create(_make_request(body={**BODY, "team": ""})).status_code == 400
+    assert create(_make_request(body={**BODY, "start": 30})).status_code == 400
+    assert create(_make_request(body={**BODY, "end": 100})).status_code == 400
+    assert create(_make_request(body={**BODY, "start": "20"})).status_code == 400
+    assert create(_make_request(body=None)).status_code == 400
+    assert create(_make_request(body={**BODY, "expires_at": "2025-01-31"})).status_code == 400
+
+
+def test_create_index_reservation_with_expiry(monkeypatch):
+    table = _setup(monkeypatch, {**ORION, "ExpiresAt": "2000-01-01T00:00:00Z"})
+    create = _fn(reservation_routes.create_index_reservation)
+    # orion's block has expired, so it no longer overlaps.
+    resp = create(_make_request(body={**BODY, "start": 15, "expires_at": "2999-01-01T00:00:00Z"}))
+    assert resp.status_code == 201
+    payload = json.loads(resp.get_body())
+    assert payload["expires_at"] == "2999-01-01T00:00:00Z"
+    assert table._entities[payload["id"]]["ExpiresAt"] == "2999-01-01T00:00:00Z"
+
+
+def test_get_index_reservation(monkeypatch):
+    _setup(monkeypatch, ORION)
+    resp = _fn(reservation_routes.get_index_reservation)(_make_request(route_params={"id": "res-1"}))
+    assert resp.status_code == 200
+    assert json.loads(resp.get_body()) == {
+        "id": "res-1",
+        "region": "wus2",
+        "environment": "prd",
+        "resource_type": "storage_account",
+        "project": None,
+        "team": "orion",
+        "start": 10,
+        "end": 19,
+        "expires_at": None,
+    }
+    missing = _fn(reservation_routes.get_index_reservation)(_make_request(route_params={"id": "nope"}))
+    assert missing.status_code == 404
+
+
+def test_delete_index_reservation(monkeypatch):
+    table = _setup(monkeypatch, ORION)
+    delete = _fn(reservation_routes.delete_index_reservation)
+    assert delete(_make_request(route_params={"id": "res-1"})).status_code == 204
+    assert "res-1" not in table._entities
+    assert delete(_make_request(route_params={"id": "res-1"})).status_code == 404
+
+
+# ---------------------------------------------------------------------------
+# Lookup
+# ---------------------------------------------------------------------------
+
+def test_reserving_team(monkeypatch):
+    _setup(monkeypatch, ORION, {**ORION, "RowKey": "res-2", "ResourceType": "", "Project": "atlas", "Team": "atlas", "Start": 30, "End": 39})
+    assert index_reservations.reserving_team("WUS2", "prd", "storage_account", None, "12") == "orion"
+    assert index_reservations.reserving_team("wus2", "prd", "key_vault", None, "12") is None
+    assert index_reservations.reserving_team("wus2", "dev", "storage_account", None, "12") is None
+    assert index_reservations.reserving_team("wus2", "prd", "key_vault", "atlas", "31") == "atlas"
+    assert index_reservations.reserving_team("wus2", "prd", "key_vault", "orion", "31") is None
+    assert index_reservations.reserving_team("wus2", "prd", "storage_account", None, None) is None
+
+
+def test_reserving_team_ignores_expired_reservations(monkeypatch):
+    _setup(
+        monkeypatch,
+        {**ORION, "ExpiresAt": "2000-01-01T00:00:00Z"},
+        {**ORION, "RowKey": "res-2", "Team": "atlas", "Start": 20, "End": 29, "ExpiresAt": "2999-01-01T00:00:00Z"},
+    )
+    assert index_reservations.reserving_team("wus2", "prd", "storage_account", None, "12") is None
+    assert index_reservations.reserving_team("wus2", "prd", "storage_account", None, "21") == "atlas"
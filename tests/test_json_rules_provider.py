@@ -161,3 +161,35 @@ def test_provider_rejects_invalid_resource_config(tmp_path, invalid):
 
     with pytest.raises(ValueError):
         JsonRuleProvider(rules_path=rules_file)
+
+
+def test_provider_reads_name_constraints(tmp_path):
+    _write_rules(tmp_path, "base.json", _base_rule_payload())
+    overlay = {
+        "metadata": {"name": "overlay", "priority": 10},
+        "resources": {
+            "storage_account": {"valid_characters": "a-z0-9", "uniqueness_scope": "global"},
+            "key_vault": {"uniqueness_scope": "global"},
+        },
+    }
+    _write_rules(tmp_path, "overlay.json", overlay)
+
+    provider = JsonRuleProvider(rules_path=tmp_path)
+    storage_rule = provider.get_rule("storage_account")
+    assert storage_rule.valid_characters == "a-z0-9"
+    assert storage_rule.uniqueness_scope == "global"
+    assert storage_rule.max_length == 24
+    assert provider.get_rule("key_vault").valid_characters is None
+    assert provider.get_rule("default").uniqueness_scope is None
+
+
+@pytest.mark.parametrize("constraint", [{"valid_characters": "a-z]["}, {"uniqueness_scope": "tenant"}])
+def test_provider_rejects_invalid_name_constraints(tmp_path, constraint):
+    payload = {
+        "default": {"segments": ["slug"], "max_length": 10},
+        "resources": {"alpha": constraint},
+    }
+    rules_file = _write_rules(tmp_path, "rules.json", payload)
+
+    with pytest.raises(ValueError):
+        JsonRuleProvider(rules_path=rules_file)
@@ -200,3 +200,30 @@ class TestBuildNameTemplate:
         assert name.startswith("sanmar")
         # Should NOT double-prefix
         assert not name.startswith("sanmar-sanmar")
+
+
+# ---------------------------------------------------------------------------
+# unique suffix
+# ---------------------------------------------------------------------------
+
+class TestUniqueSuffix:
+    def test_appended_without_separator_for_compact_templates(self):
+        rule = SimpleNamespace(
+            segments=[], require_sanmar_prefix=True,
+            name_template="{region}{environment}{slug}{sanmar_prefix}{system}{subsystem}{index}",
+        )
+        name = build_name("wus2", "prd", "st", rule, {"system": "erp", "suffix": "a1b2"})
+        assert name == "wus2prdstsanmarerpa1b2"
+
+    def test_appended_with_hyphen_for_hyphenated_templates(self):
+        rule = SimpleNamespace(segments=[], name_template="{region}-{environment}-{slug}-{system}{index_segment}")
+        name = build_name("wus2", "prd", "app", rule, {"system": "erp", "index": "01", "suffix": "a1b2"})
+        assert name == "wus2-prd-app-erp-01-a1b2"
+
+    def test_placed_by_templates_that_use_it(self):
+        rule = SimpleNamespace(segments=[], name_template="{slug}{suffix}{region}")
+        assert build_name("wus2", "prd", "st", rule, {"suffix": "a1b2"}) == "sta1b2wus2"
+
+    def test_appended_to_segment_names(self):
+        rule = SimpleNamespace(segments=["slug", "region"], name_template=None)
+        assert build_name("wus2", "prd", "vm", rule, {"suffix": "a1b2"}) == "vm-wus2-a1b2"
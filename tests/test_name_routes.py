@@ -57,6 +57,7 @@ class FakeTable:
         self._raise_on_get = raise_on_get
         self._raise_on_update = raise_on_update
         self.updated = None
+        self.deleted = []
 
     def get_entity(self, partition_key, row_key):
         if self._raise_on_get:
@@ -71,6 +72,16 @@ class FakeTable:
             raise self._raise_on_update
         self.updated = entity
 
+    def delete_entity(self, partition_key, row_key):
+        self.deleted.append((partition_key, row_key))
+
+    def query_entities(self, query_filter):
+        return [
+            dict(entity, PartitionKey=pk, RowKey=rk)
+            for (pk, rk), entity in self._entities.items()
+            if entity.get("InUse")
+        ]
+
 
 # ---------------------------------------------------------------------------
 # _handle_claim_request
@@ -89,11 +100,100 @@ class TestHandleClaimRequest:
 
     def test_success(self, monkeypatch):
         monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
-        monkeypatch.setattr(names_routes, "generate_and_claim_name", lambda p, requested_by: FakeResult())
+        monkeypatch.setattr(names_routes, "generate_and_claim_name", lambda p, requested_by, run_metadata: FakeResult())
         monkeypatch.setattr(names_routes, "build_claim_response", lambda result, uid: SimpleNamespace(status_code=201))
         resp = names_routes._handle_claim_request(_make_request(body={"resource_type": "vm"}), log_prefix="test")
         assert resp.status_code == 201
 
+    def test_forwards_run_headers(self, monkeypatch):
+        runs = []
+
+        def claim(payload, requested_by, run_metadata):
+            runs.append(run_metadata)
+            return FakeResult()
+
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+        monkeypatch.setattr(names_routes, "generate_and_claim_name", claim)
+        monkeypatch.setattr(names_routes, "build_claim_response", lambda result, uid: SimpleNamespace(status_code=201))
+        headers = {"X-Sanmar-Workspace": "payments-dev", "X-Sanmar-Execution": " ", "Authorization": "Bearer x"}
+        names_routes._handle_claim_request(_make_request(body={"resource_type": "vm"}, headers=headers), log_prefix="test")
+        assert runs == [{"TerraformWorkspace": "payments-dev"}]
+
+
+# ---------------------------------------------------------------------------
+# preview
+# ---------------------------------------------------------------------------
+
+class TestPreview:
+    def test_returns_name_and_availability(self, monkeypatch):
+        previews = []
+
+        def fake_preview(payload, requested_by):
+            previews.append(payload)
+            return SimpleNamespace(
+                name="wus2devstvm01",
+                region="wus2",
+                environment="dev",
+                to_dict=lambda: {"name": "wus2devstvm01", "region": "wus2", "environment": "dev"},
+            )
+
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        monkeypatch.setattr(names_routes, "preview_name", fake_preview)
+        monkeypatch.setattr(names_routes, "check_name_exists", lambda region, environment, name: True)
+
+        body = {"resource_type": "vm", "idempotency_key": "key-00001"}
+        resp = _fn(names_routes.preview)(_make_request(body=body))
+
+        assert resp.status_code == 200
+        payload = json.loads(resp.get_body())
+        assert payload["name"] == "wus2devstvm01"
+        assert payload["available"] is False
+        assert previews == [{"resource_type": "vm"}]
+
+    def test_invalid_request(self, monkeypatch):
+        from app.dependencies import InvalidRequestError
+
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        monkeypatch.setattr(names_routes, "preview_name", mock.Mock(side_effect=InvalidRequestError("Missing required field(s): region")))
+        resp = _fn(names_routes.preview)(_make_request(body={"resource_type": "vm"}))
+        assert resp.status_code == 400
+
+    def test_invalid_json(self, monkeypatch):
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        assert _fn(names_routes.preview)(_make_request(body=None)).status_code == 400
+        assert _fn(names_routes.preview)(_make_request(body=["vm"])).status_code == 400
+
+
+# ---------------------------------------------------------------------------
+# suggestions
+# ---------------------------------------------------------------------------
+
+class TestSuggestions:
+    def test_returns_candidates(self, monkeypatch):
+        calls = []
+
+        def fake_suggest(payload, user_id, purposes, count):
+            calls.append((payload, purposes, count))
+            return [{"name": "st01", "purpose": "api", "index": "01", "available": True}]
+
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        monkeypatch.setattr(names_routes, "suggest_names", fake_suggest)
+
+        body = {"resource_type": "storage_account", "purposes": ["api"], "count": 3, "index": "07"}
+        resp = _fn(names_routes.suggestions)(_make_request(body=body))
+
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body())["suggestions"][0]["name"] == "st01"
+        assert calls == [({"resource_type": "storage_account"}, ["api"], 3)]
+
+    def test_rejects_invalid_options(self, monkeypatch):
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["reader"]))
+        suggest = _fn(names_routes.suggestions)
+        assert suggest(_make_request(body={"count": 0})).status_code == 400
+        assert suggest(_make_request(body={"count": 51})).status_code == 400
+        assert suggest(_make_request(body={"purposes": "api"})).status_code == 400
+        assert suggest(_make_request(body=None)).status_code == 400
+
 
 # ---------------------------------------------------------------------------
 # release_name
@@ -216,3 +316,501 @@ class TestReleaseName:
         resp = _fn(names_routes.release_name)(_make_request(body={"name": "myname", "region": "wus2", "environment": "dev"}))
         assert resp.status_code == 200
         assert "CustomField" in captured["metadata"]
+
+    def test_run_headers_in_audit(self, monkeypatch):
+        entity = {"PartitionKey": "wus2-dev", "RowKey": "myname", "ClaimedBy": "u1", "InUse": True}
+        table = FakeTable({("wus2-dev", "myname"): entity})
+        captured = {}
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(names_routes, "is_authorized", lambda roles, uid, cb, rb: True)
+        monkeypatch.setattr(names_routes, "write_audit_log", lambda *a, **kw: captured.update(kw["metadata"]))
+        headers = {"X-Sanmar-Terraform-Version": "1.9.5", "x-sanmar-workspace": "payments-dev", "X-Sanmar-Execution": "tfc"}
+        resp = _fn(names_routes.release_name)(
+            _make_request(body={"name": "myname", "region": "wus2", "environment": "dev"}, headers=headers)
+        )
+        assert resp.status_code == 200
+        assert captured["TerraformVersion"] == "1.9.5"
+        assert captured["TerraformWorkspace"] == "payments-dev"
+        assert captured["TerraformExecution"] == "tfc"
+
+
+# ---------------------------------------------------------------------------
+# idempotent claims
+# ---------------------------------------------------------------------------
+
+class FakeIdempotencyTable:
+    def __init__(self, entities=None):
+        self.entities = dict(entities or {})
+        self.deleted = []
+
+    def get_entity(self, partition_key, row_key):
+        from azure.core.exceptions import ResourceNotFoundError
+        key = (partition_key, row_key)
+        if key not in self.entities:
+            raise ResourceNotFoundError("not found")
+        return dict(self.entities[key])
+
+    def create_entity(self, entity):
+        from azure.core.exceptions import ResourceExistsError
+        key = (entity["PartitionKey"], entity["RowKey"])
+        if key in self.entities:
+            raise ResourceExistsError("exists")
+        self.entities[key] = dict(entity)
+
+    def update_entity(self, entity, mode=None):
+        key = (entity["PartitionKey"], entity["RowKey"])
+        self.entities.setdefault(key, {}).update(entity)
+
+    def delete_entity(self, partition_key, row_key):
+        self.deleted.append(row_key)
+        self.entities.pop((partition_key, row_key), None)
+
+    def query_entities(self, query):
+        cutoff = query.split("'")[1]
+        return [dict(e, PartitionKey=k[0], RowKey=k[1]) for k, e in list(self.entities.items()) if e["StartedAt"] < cutoff]
+
+
+class ClaimedResult:
+    name = "wus2devstvm01"
+    region = "wus2"
+    environment = "dev"
+
+
+class TestIdempotentClaim:
+    KEY = "0b6f1c1e-7d2a-4c55-9f53-2f7a1d9c0e11"
+
+    def _setup(self, monkeypatch, table, claim):
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("U1", ["contributor"]))
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(names_routes, "generate_and_claim_name", claim)
+        monkeypatch.setattr(names_routes, "preview_name", lambda payload, requested_by: ClaimedResult())
+        monkeypatch.setattr(
+            names_routes,
+            "build_claim_response",
+            lambda result, uid: names_routes.func.HttpResponse(
+                json.dumps({"name": result.name}), mimetype="application/json", status_code=201
+            ),
+        )
+
+    def test_repeated_key_replays_first_claim(self, monkeypatch):
+        table = FakeIdempotencyTable()
+        calls = []
+
+        def claim(payload, requested_by, run_metadata):
+            calls.append(payload)
+            return ClaimedResult()
+
+        self._setup(monkeypatch, table, claim)
+        body = {"resource_type": "vm", "idempotency_key": self.KEY}
+        first = names_routes._handle_claim_request(_make_request(body=dict(body)), log_prefix="test")
+        second = names_routes._handle_claim_request(_make_request(body=dict(body)), log_prefix="test")
+
+        assert first.status_code == 201 and second.status_code == 201
+        assert json.loads(second.get_body()) == {"name": "wus2devstvm01"}
+        assert second.headers.get("Idempotent-Replayed") == "true"
+        assert len(calls) == 1
+        assert "idempotency_key" not in calls[0]
+        assert ("u1", self.KEY) in table.entities
+
+    def test_key_reused_with_another_request(self, monkeypatch):
+        table = FakeIdempotencyTable()
+        claim = mock.Mock(return_value=ClaimedResult())
+        self._setup(monkeypatch, table, claim)
+        first = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        second = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "kv", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        assert first.status_code == 201
+        assert second.status_code == 422
+        assert claim.call_count == 1
+
+    def test_name_stored_before_claiming(self, monkeypatch):
+        table = FakeIdempotencyTable()
+
+        def claim(payload, requested_by, run_metadata):
+            stored = table.entities[("u1", self.KEY)]
+            assert (stored["Name"], stored["Region"], stored["Environment"]) == ("wus2devstvm01", "wus2", "dev")
+            return ClaimedResult()
+
+        self._setup(monkeypatch, table, claim)
+        resp = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        assert resp.status_code == 201
+
+    def _stale_record(self, body=None):
+        from datetime import datetime, timedelta, timezone
+
+        started = datetime.now(tz=timezone.utc) - timedelta(minutes=30)
+        record = {
+            "StartedAt": started.isoformat(),
+            "PayloadHash": names_routes._payload_hash(body or {"resource_type": "vm"}),
+            "Name": "wus2devstvm01",
+            "Region": "wus2",
+            "Environment": "dev",
+        }
+        return started, FakeIdempotencyTable({("u1", self.KEY): record})
+
+    def test_stale_takeover_adopts_interrupted_claim(self, monkeypatch):
+        from datetime import timedelta
+
+        started, table = self._stale_record()
+        self._setup(monkeypatch, table, mock.Mock(side_effect=AssertionError("must not claim a second name")))
+        monkeypatch.setattr(
+            names_routes,
+            "get_name_record",
+            lambda region, env, name: {
+                "InUse": True,
+                "ClaimedBy": "u1",
+                "ClaimedAt": (started + timedelta(seconds=1)).isoformat(),
+            },
+        )
+        resp = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        assert resp.status_code == 201
+        assert resp.headers.get("Idempotent-Replayed") == "true"
+        assert json.loads(resp.get_body()) == {"name": "wus2devstvm01"}
+        assert table.entities[("u1", self.KEY)]["Response"]
+
+    def test_stale_takeover_under_index_reuse_replays_claimed_name(self, monkeypatch):
+        from datetime import timedelta
+
+        body = {"resource_type": "vm", "index_reuse": "never"}
+        started, table = self._stale_record(body)
+        record = table.entities[("u1", self.KEY)]
+        self._setup(monkeypatch, table, mock.Mock(side_effect=AssertionError("must not claim a second name")))
+        # Rendering the request again allocates the next free index, since the
+        # interrupted claim now holds the first.
+        monkeypatch.setattr(
+            names_routes,
+            "preview_name",
+            lambda payload, requested_by: SimpleNamespace(name="wus2devstvm02", region="wus2", environment="dev"),
+        )
+        monkeypatch.setattr(
+            names_routes,
+            "get_name_record",
+            lambda region, env, name: {
+                "PartitionKey": "wus2-dev",
+                "RowKey": name,
+                "InUse": True,
+                "ResourceType": "vm",
+                "ClaimedBy": "u1",
+                "ClaimedAt": (started + timedelta(seconds=1)).isoformat(),
+                "Slug": "vm",
+                "Index": "01",
+                "RequestedBy": "u1",
+            },
+        )
+
+        resp = names_routes._handle_claim_request(
+            _make_request(body={**body, "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        replay = names_routes._handle_claim_request(
+            _make_request(body={**body, "idempotency_key": self.KEY}), log_prefix="test"
+        )
+
+        assert resp.status_code == 201
+        assert json.loads(resp.get_body())["name"] == record["Name"]
+        assert json.loads(replay.get_body())["name"] == record["Name"]
+
+    def test_stale_takeover_claims_when_name_was_not_claimed(self, monkeypatch):
+        _started, table = self._stale_record()
+        claim = mock.Mock(return_value=ClaimedResult())
+        self._setup(monkeypatch, table, claim)
+        monkeypatch.setattr(names_routes, "get_name_record", lambda region, env, name: None)
+        resp = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        assert resp.status_code == 201
+        assert claim.call_count == 1
+        assert table.deleted == [self.KEY]
+
+    def test_expired_keys_are_purged(self, monkeypatch):
+        from datetime import datetime, timedelta, timezone
+
+        now = datetime.now(tz=timezone.utc)
+        table = FakeIdempotencyTable(
+            {
+                ("u1", "old-key-0001"): {"StartedAt": (now - timedelta(days=2)).isoformat()},
+                ("u1", "new-key-0001"): {"StartedAt": (now - timedelta(hours=1)).isoformat()},
+            }
+        )
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        assert names_routes._purge_expired_idempotency_keys(now) == 1
+        assert list(table.entities) == [("u1", "new-key-0001")]
+
+    def test_refused_claim_forgets_key(self, monkeypatch):
+        from app.dependencies import NameConflictError
+
+        table = FakeIdempotencyTable()
+
+        def claim(payload, requested_by, run_metadata):
+            raise NameConflictError("taken")
+
+        self._setup(monkeypatch, table, claim)
+        resp = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        assert resp.status_code == 409
+        assert table.deleted == [self.KEY]
+        assert not table.entities
+
+    def test_claim_in_progress_is_retryable(self, monkeypatch):
+        from datetime import datetime, timezone
+
+        started = datetime.now(tz=timezone.utc).isoformat()
+        table = FakeIdempotencyTable({("u1", self.KEY): {"StartedAt": started}})
+        self._setup(monkeypatch, table, mock.Mock(side_effect=AssertionError("must not claim")))
+        resp = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": self.KEY}), log_prefix="test"
+        )
+        assert resp.status_code == 503
+
+    def test_invalid_key(self, monkeypatch):
+        self._setup(monkeypatch, FakeIdempotencyTable(), mock.Mock())
+        resp = names_routes._handle_claim_request(
+            _make_request(body={"resource_type": "vm", "idempotency_key": "a/b"}), log_prefix="test"
+        )
+        assert resp.status_code == 400
+
+
+class TestClaimBatch:
+    KEY = TestIdempotentClaim.KEY
+
+    def _setup(self, monkeypatch, claim):
+        table = FakeIdempotencyTable()
+        TestIdempotentClaim()._setup(monkeypatch, table, claim)
+        return table
+
+    def test_claims_each_entry_in_order(self, monkeypatch):
+        names = iter(["wus2devstvm01", "wus2devstvm02"])
+
+        def claim(payload, requested_by, run_metadata):
+            return SimpleNamespace(name=next(names), region="wus2", environment="dev")
+
+        self._setup(monkeypatch, claim)
+        body = {"claims": [{"resource_type": "vm"}, {"resource_type": "vm"}]}
+        resp = _fn(names_routes.claim_names)(_make_request(body=body))
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body()) == {
+            "results": [{"claim": {"name": "wus2devstvm01"}}, {"claim": {"name": "wus2devstvm02"}}]
+        }
+
+    def test_applies_each_idempotency_key(self, monkeypatch):
+        claim = mock.Mock(return_value=ClaimedResult())
+        table = self._setup(monkeypatch, claim)
+        body = {"claims": [{"resource_type": "vm", "idempotency_key": self.KEY}]}
+        first = _fn(names_routes.claim_names)(_make_request(body=json.loads(json.dumps(body))))
+        second = _fn(names_routes.claim_names)(_make_request(body=json.loads(json.dumps(body))))
+        assert json.loads(first.get_body()) == json.loads(second.get_body())
+        assert claim.call_count == 1
+        assert ("u1", self.KEY) in table.entities
+
+    def test_refused_claims_do_not_fail_the_batch(self, monkeypatch):
+        from app.dependencies import InvalidRequestError
+
+        def claim(payload, requested_by, run_metadata):
+            if payload.get("unique_suffix"):
+                raise InvalidRequestError("Suffix is too long.", fields=["unique_suffix"])
+            return ClaimedResult()
+
+        self._setup(monkeypatch, claim)
+        body = {"claims": [{"resource_type": "vm", "unique_suffix": "x" * 40}, "vm", {"resource_type": "vm"}]}
+        resp = _fn(names_routes.claim_names)(_make_request(body=body))
+        assert resp.status_code == 200
+        refused, invalid, claimed = json.loads(resp.get_body())["results"]
+        assert refused == {
+            "error": {
+                "status": 400,
+                "message": "Suffix is too long.",
+                "errors": [{"field": "unique_suffix", "message": "Suffix is too long."}],
+            }
+        }
+        assert invalid == {"error": {"status": 400, "message": "Invalid JSON payload."}}
+        assert claimed == {"claim": {"name": "wus2devstvm01"}}
+
+    @pytest.mark.parametrize("claims", [None, [], "vm", [{"resource_type": "vm"}] * 26])
+    def test_rejects_invalid_batches(self, monkeypatch, claims):
+        self._setup(monkeypatch, mock.Mock(side_effect=AssertionError("must not claim")))
+        resp = _fn(names_routes.claim_names)(_make_request(body={"claims": claims}))
+        assert resp.status_code == 400
+
+    def test_auth_error(self, monkeypatch):
+        monkeypatch.setattr(names_routes, "require_role", mock.Mock(side_effect=_auth_error()))
+        resp = _fn(names_routes.claim_names)(_make_request(body={"claims": [{"resource_type": "vm"}]}))
+        assert resp.status_code == 401
+
+
+# ---------------------------------------------------------------------------
+# transfer_claim / purge_claim
+# ---------------------------------------------------------------------------
+
+OPERATION_BODY = {"name": "myname", "region": "wus2", "environment": "dev"}
+
+
+class TestTransferClaim:
+    def _setup(self, monkeypatch, entity, authorized=True):
+        table = FakeTable({("wus2-dev", "myname"): entity})
+        audit = []
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(names_routes, "is_authorized", lambda roles, uid, cb, rb: authorized)
+        monkeypatch.setattr(names_routes, "write_audit_log", lambda *a, **kw: audit.append((a, kw)))
+        return table, audit
+
+    def test_success(self, monkeypatch):
+        table, audit = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True, "ResourceType": "vm"})
+        body = dict(OPERATION_BODY, new_owner="Platform-Team", reason="reorg")
+        resp = _fn(names_routes.transfer_claim)(_make_request(body=body))
+        assert resp.status_code == 200
+        assert table.updated["ClaimedBy"] == "platform-team"
+        assert table.updated["TransferredBy"] == "u1"
+        (args, kwargs), = audit
+        assert args[2] == "transferred"
+        assert "reorg" in args[3]
+        assert kwargs["metadata"]["NewOwner"] == "platform-team"
+
+    def test_missing_owner(self, monkeypatch):
+        self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True})
+        resp = _fn(names_routes.transfer_claim)(_make_request(body=OPERATION_BODY))
+        assert resp.status_code == 400
+
+    def test_missing_scope(self, monkeypatch):
+        self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True})
+        resp = _fn(names_routes.transfer_claim)(_make_request(body={"name": "myname", "new_owner": "u2"}))
+        assert resp.status_code == 400
+
+    def test_released_name(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": False})
+        resp = _fn(names_routes.transfer_claim)(_make_request(body=dict(OPERATION_BODY, new_owner="u2")))
+        assert resp.status_code == 409
+        assert table.updated is None
+
+    def test_forbidden(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"ClaimedBy": "other", "InUse": True}, authorized=False)
+        resp = _fn(names_routes.transfer_claim)(_make_request(body=dict(OPERATION_BODY, new_owner="u2")))
+        assert resp.status_code == 403
+        assert table.updated is None
+
+
+class TestRenewClaim:
+    def _setup(self, monkeypatch, entity, authorized=True):
+        table = FakeTable({("wus2-dev", "myname"): entity})
+        audit = []
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(names_routes, "is_authorized", lambda roles, uid, cb, rb: authorized)
+        monkeypatch.setattr(names_routes, "write_audit_log", lambda *a, **kw: audit.append(a))
+        return table, audit
+
+    def test_stores_expiry_as_sent(self, monkeypatch):
+        table, audit = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True, "ExpiresAt": "2030-01-01T00:00:00Z"})
+        body = dict(OPERATION_BODY, expires_at="2031-06-30T12:00:00+02:00")
+        resp = _fn(names_routes.renew_claim)(_make_request(body=body))
+        assert resp.status_code == 200
+        assert table.updated["ExpiresAt"] == "2031-06-30T12:00:00+02:00"
+        assert audit[0][2] == "renewed"
+
+    def test_empty_expiry_removes_it(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True, "ExpiresAt": "2030-01-01T00:00:00Z"})
+        resp = _fn(names_routes.renew_claim)(_make_request(body=dict(OPERATION_BODY, expires_at="")))
+        assert resp.status_code == 200
+        assert "ExpiresAt" not in table.updated
+
+    @pytest.mark.parametrize("expires_at", ["soon", "2031-06-30T12:00:00", "2001-01-01T00:00:00Z"])
+    def test_invalid_expiry(self, monkeypatch, expires_at):
+        table, _ = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": True})
+        resp = _fn(names_routes.renew_claim)(_make_request(body=dict(OPERATION_BODY, expires_at=expires_at)))
+        assert resp.status_code == 400
+        assert table.updated is None
+
+    def test_released_name(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"ClaimedBy": "u1", "InUse": False})
+        resp = _fn(names_routes.renew_claim)(_make_request(body=dict(OPERATION_BODY, expires_at="2031-01-01T00:00:00Z")))
+        assert resp.status_code == 409
+        assert table.updated is None
+
+    def test_forbidden(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"ClaimedBy": "other", "InUse": True}, authorized=False)
+        resp = _fn(names_routes.renew_claim)(_make_request(body=dict(OPERATION_BODY, expires_at="2031-01-01T00:00:00Z")))
+        assert resp.status_code == 403
+        assert table.updated is None
+
+
+class TestReleaseLapsedClaims:
+    def test_releases_expired_claims_only(self, monkeypatch):
+        from datetime import datetime, timezone
+
+        table = FakeTable(
+            {
+                ("wus2-dev", "expired"): {"InUse": True, "ExpiresAt": "2030-01-01T00:00:00Z"},
+                ("wus2-dev", "current"): {"InUse": True, "ExpiresAt": "2030-01-03T00:00:00Z"},
+                ("wus2-dev", "forever"): {"InUse": True},
+                ("wus2-dev", "scheduled"): {"InUse": True, "ReleaseAt": "2030-01-01T12:00:00Z"},
+                ("wus2-dev", "later"): {"InUse": True, "ReleaseAt": "2030-01-02T12:00:00Z"},
+            }
+        )
+        audit = []
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(names_routes, "write_audit_log", lambda *a, **kw: audit.append(a))
+        monkeypatch.setattr(names_routes, "notify", lambda *a, **kw: None)
+
+        released = names_routes._release_lapsed_claims(datetime(2030, 1, 2, tzinfo=timezone.utc))
+
+        assert released == 2
+        assert [entry[:3] for entry in audit] == [
+            ("expired", "system", "released"),
+            ("scheduled", "system", "released"),
+        ]
+        assert table.updated["InUse"] is False
+        assert table.updated["ReleasedBy"] == "system"
+
+
+class TestPurgeClaim:
+    def _setup(self, monkeypatch, entity):
+        table = FakeTable({("wus2-dev", "myname"): entity})
+        audit = []
+        monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("admin1", ["admin"]))
+        monkeypatch.setattr(names_routes, "get_table_client", lambda name: table)
+        monkeypatch.setattr(names_routes, "write_audit_log", lambda *a, **kw: audit.append(a))
+        return table, audit
+
+    def test_requires_admin(self, monkeypatch):
+        roles = []
+
+        def require_role(headers, min_role):
+            roles.append(min_role)
+            raise _auth_error("Forbidden", status=403)
+
+        monkeypatch.setattr(names_routes, "require_role", require_role)
+        resp = _fn(names_routes.purge_claim)(_make_request(body=dict(OPERATION_BODY, reason="typo")))
+        assert resp.status_code == 403
+        assert roles == ["admin"]
+
+    def test_success(self, monkeypatch):
+        table, audit = self._setup(monkeypatch, {"InUse": False, "ResourceType": "vm"})
+        resp = _fn(names_routes.purge_claim)(_make_request(body=dict(OPERATION_BODY, reason="typo")))
+        assert resp.status_code == 200
+        assert table.deleted == [("wus2-dev", "myname")]
+        assert audit[0][2] == "purged"
+
+    def test_missing_reason(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"InUse": False})
+        resp = _fn(names_routes.purge_claim)(_make_request(body=OPERATION_BODY))
+        assert resp.status_code == 400
+        assert not table.deleted
+
+    def test_claimed_name(self, monkeypatch):
+        table, _ = self._setup(monkeypatch, {"InUse": True})
+        resp = _fn(names_routes.purge_claim)(_make_request(body=dict(OPERATION_BODY, reason="typo")))
+        assert resp.status_code == 409
+        assert not table.deleted
+
+    def test_not_found(self, monkeypatch):
+        self._setup(monkeypatch, {"InUse": False})
+        resp = _fn(names_routes.purge_claim)(_make_request(body=dict(OPERATION_BODY, name="other", reason="typo")))
+        assert resp.status_code == 404
@@ -4,6 +4,7 @@ import string
 import sys
 
 import json
+from datetime import datetime, timedelta, timezone
 
 import pytest
 
@@ -41,6 +42,8 @@ def test_generate_and_claim_name_success(monkeypatch):
     monkeypatch.setattr(name_service, "build_name", fake_build_name)
     monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
 
     def fake_claim_name(*args, **kwargs):
         captured["claim_args"] = kwargs
@@ -57,8 +60,13 @@ def test_generate_and_claim_name_success(monkeypatch):
         }
 
     monkeypatch.setattr(name_service, "write_audit_log", fake_write_audit_log)
+    monkeypatch.setattr(
+        name_service, "notify", lambda event, name, details: captured.setdefault("notify", (event, name, details))
+    )
 
-    result = name_service.generate_and_claim_name(payload, requested_by="user@example.com")
+    result = name_service.generate_and_claim_name(
+        payload, requested_by="user@example.com", run_metadata={"TerraformWorkspace": "payments-dev"}
+    )
 
     assert result.name == "sanmar-st-dev-wus2-erp-01"
     assert result.metadata["System"] == "erp"
@@ -67,6 +75,13 @@ def test_generate_and_claim_name_success(monkeypatch):
 
     assert captured["claim_args"]["metadata"]["System"] == "erp"
     assert captured["audit"]["metadata"]["Region"] == "wus2"
+    assert captured["audit"]["metadata"]["TerraformWorkspace"] == "payments-dev"
+    assert "TerraformWorkspace" not in captured["claim_args"]["metadata"]
+    assert captured["notify"] == (
+        "claim",
+        "sanmar-st-dev-wus2-erp-01",
+        {"user": "user@example.com", "resourceType": "storage_account", "region": "wus2", "environment": "dev"},
+    )
 
 
 def test_generate_and_claim_name_conflict(monkeypatch):
@@ -115,6 +130,8 @@ def test_generate_and_claim_name_uses_user_defaults(monkeypatch):
     monkeypatch.setattr(name_service, "build_name", lambda **kwargs: "sanmar")
     monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
     monkeypatch.setattr(name_service, "claim_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
 
@@ -171,6 +188,8 @@ def test_generate_and_claim_name_for_sample_combinations(monkeypatch):
         lambda resource_type: slug_lookup[resource_type.lower()],
     )
     monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
 
     def fake_claim_name(*, region, environment, name, resource_type, claimed_by, metadata):
         claimed_records.append(
@@ -294,6 +313,8 @@ def test_to_dict_includes_display(monkeypatch):
     monkeypatch.setattr(name_service, "build_name", lambda **kwargs: "storage-st-dev-wus2")
     monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
     monkeypatch.setattr(name_service, "claim_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
 
@@ -360,6 +381,8 @@ def test_us_provider_enforces_region(monkeypatch, tmp_path):
     monkeypatch.setattr(name_service, "build_name", lambda **kwargs: "sanmar")
     monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
     monkeypatch.setattr(name_service, "claim_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
 
@@ -391,6 +414,8 @@ def test_us_provider_accepts_valid_payload(monkeypatch, tmp_path):
     monkeypatch.setattr(name_service, "build_name", lambda **kwargs: "sanmar-st-erp-billing-prd-wus-01")
     monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
     monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
     monkeypatch.setattr(name_service, "claim_name", lambda **kwargs: captured.update(kwargs))
     monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
 
@@ -405,3 +430,330 @@ def test_us_provider_accepts_valid_payload(monkeypatch, tmp_path):
         )
     finally:
         naming_rules.set_rule_provider(original_provider)
+
+
+def test_preview_name_matches_claim_without_claiming(monkeypatch):
+    payload = {"resource_type": "storage_account", "region": "wus2", "environment": "dev", "system": "ERP", "index": "01"}
+
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "st")
+    monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
+
+    def fail_claim(*args, **kwargs):
+        raise AssertionError("preview must not claim")
+
+    monkeypatch.setattr(name_service, "claim_name", fail_claim)
+    monkeypatch.setattr(name_service, "write_audit_log", fail_claim)
+    monkeypatch.setattr(name_service, "notify", fail_claim)
+
+    preview = name_service.preview_name(dict(payload), requested_by="user@example.com")
+
+    claimed = []
+    monkeypatch.setattr(name_service, "claim_name", lambda **kwargs: claimed.append(kwargs["name"]))
+    monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "notify", lambda *args, **kwargs: None)
+    result = name_service.generate_and_claim_name(dict(payload), requested_by="user@example.com")
+
+    assert preview.name == result.name == claimed[0]
+    assert preview.to_dict() == result.to_dict()
+
+
+def test_suggest_names_walks_indices_and_purposes(monkeypatch):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "st")
+    monkeypatch.setattr(
+        name_service,
+        "build_name",
+        lambda region, environment, slug, rule, optional_inputs: f"{slug}{optional_inputs.get('index', '')}",
+    )
+    monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "check_name_exists", lambda region, environment, name: name == "st01")
+
+    payload = {"resource_type": "storage_account", "region": "wus2", "environment": "dev", "system": "erp"}
+    candidates = name_service.suggest_names(payload, "user@example.com", purposes=["api", "web"], count=3)
+
+    # The purpose is not part of this name, so the duplicate at each index is skipped.
+    assert candidates == [
+        {"name": "st01", "purpose": "api", "index": "01", "available": False},
+        {"name": "st02", "purpose": "api", "index": "02", "available": True},
+        {"name": "st03", "purpose": "api", "index": "03", "available": True},
+    ]
+
+
+def test_claim_template_overrides_rule(monkeypatch):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
+    monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "notify", lambda *args, **kwargs: None)
+    claims = []
+    monkeypatch.setattr(name_service, "claim_name", lambda **kwargs: claims.append(kwargs))
+
+    payload = {
+        "resource_type": "app_service",
+        "region": "wus2",
+        "environment": "prd",
+        "project": "Orion",
+        "index": "01",
+        "template": "{slug}-{project}-{env}-{region}{purpose_segment}-{index}",
+    }
+    result = name_service.generate_and_claim_name(payload, "user@example.com")
+
+    assert result.name == "app-orion-prd-wus2-01"
+    # The exception to the convention is recorded with the claim.
+    assert claims[0]["metadata"]["Template"] == payload["template"]
+    assert name_service.preview_name(payload, "user@example.com").name == result.name
+
+
+@pytest.mark.parametrize("template", ["no placeholders", "{slug}-{owner}", "{region_segment}-{slug}", 7])
+def test_invalid_template_is_rejected(monkeypatch, template):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    payload = {"resource_type": "app_service", "region": "wus2", "environment": "prd", "template": template}
+    with pytest.raises(name_service.InvalidRequestError):
+        name_service.preview_name(payload, "user@example.com")
+
+
+def test_claim_stores_expiry_as_sent(monkeypatch):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
+    monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "notify", lambda *args, **kwargs: None)
+    claims = []
+    monkeypatch.setattr(name_service, "claim_name", lambda **kwargs: claims.append(kwargs))
+
+    payload = {"resource_type": "app_service", "region": "wus2", "environment": "prd", "expires_at": "2031-01-31T00:00:00Z"}
+    name_service.generate_and_claim_name(payload, "user@example.com")
+
+    assert claims[0]["metadata"]["ExpiresAt"] == "2031-01-31T00:00:00Z"
+    assert "Expires_at" not in claims[0]["metadata"]
+
+
+def test_claim_schedules_release(monkeypatch):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "reserving_team", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
+    monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "notify", lambda *args, **kwargs: None)
+    claims = []
+    monkeypatch.setattr(name_service, "claim_name", lambda **kwargs: claims.append(kwargs))
+
+    payload = {"resource_type": "app_service", "region": "wus2", "environment": "prd", "release_after": "720h"}
+    result = name_service.generate_and_claim_name(payload, "user@example.com")
+
+    release_at = claims[0]["metadata"]["ReleaseAt"]
+    assert result.to_dict()["releaseAt"] == release_at
+    scheduled = datetime.fromisoformat(release_at.replace("Z", "+00:00"))
+    assert abs(scheduled - datetime.now(tz=timezone.utc) - timedelta(hours=720)) < timedelta(minutes=1)
+    assert "Release_after" not in claims[0]["metadata"]
+
+
+@pytest.mark.parametrize("release_after", ["30d", "0h", "-1h", "h", 7])
+def test_invalid_release_after_is_rejected(monkeypatch, release_after):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    payload = {"resource_type": "app_service", "region": "wus2", "environment": "prd", "release_after": release_after}
+    with pytest.raises(name_service.InvalidRequestError):
+        name_service.preview_name(payload, "user@example.com")
+
+
+def test_release_after_and_expiry_cannot_be_combined(monkeypatch):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    payload = {
+        "resource_type": "app_service",
+        "region": "wus2",
+        "environment": "prd",
+        "expires_at": "2031-01-31T00:00:00Z",
+        "release_after": "720h",
+    }
+    with pytest.raises(name_service.InvalidRequestError) as excinfo:
+        name_service.preview_name(payload, "user@example.com")
+    assert excinfo.value.fields == ("expires_at", "release_after")
+
+
+@pytest.mark.parametrize("expires_at", ["tomorrow", "2031-01-31T00:00:00", 7])
+def test_invalid_expiry_is_rejected(monkeypatch, expires_at):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "app")
+    payload = {"resource_type": "app_service", "region": "wus2", "environment": "prd", "expires_at": expires_at}
+    with pytest.raises(name_service.InvalidRequestError):
+        name_service.preview_name(payload, "user@example.com")
+
+
+def test_invalid_suffix_is_rejected(monkeypatch):
+    payload = {"resource_type": "storage_account", "region": "wus2", "environment": "dev", "suffix": "a-b"}
+    with pytest.raises(name_service.InvalidRequestError) as excinfo:
+        name_service._normalise_payload(payload)
+    assert excinfo.value.fields == ("suffix",)
+
+
+def _claim_with_reservation(monkeypatch, team, payload_extra):
+    monkeypatch.setattr(name_service, "get_slug", lambda _: "st")
+    monkeypatch.setattr(name_service, "validate_name", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "check_name_exists", lambda *args, **kwargs: False)
+    monkeypatch.setattr(name_service, "write_audit_log", lambda *args, **kwargs: None)
+    monkeypatch.setattr(name_service, "notify", lambda *args, **kwargs: None)
+    lookups = []
+
+    def fake_reserving_team(region, environment, resource_type, project, index):
+        lookups.append((region, environment, resource_type, project, index))
+        return team
+
+    claims = []
+    monkeypatch.setattr(name_service, "reserving_team", fake_reserving_team)
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: None)
+    monkeypatch.setattr(name_service, "claim_name", lambda **kwargs: claims.append(kwargs["name"]))
+    payload = {"resource_type": "storage_account", "region": "WUS2", "environment": "prd", "system": "erp", "index": "12"}
+    payload.update(payload_extra)
+    return payload, lookups, claims
+
+
+def test_claim_rejects_index_reserved_for_another_team(monkeypatch):
+    payload, lookups, claims = _claim_with_reservation(monkeypatch, "orion", {"team": "atlas"})
+    with pytest.raises(name_service.NameConflictError, match="reserved for team 'orion'"):
+        name_service.generate_and_claim_name(payload, "user")
+    assert lookups == [("wus2", "prd", "storage_account", None, "12")]
+    assert claims == []
+
+
+def test_claim_accepts_index_reserved_for_its_team(monkeypatch):
+    payload, _, claims = _claim_with_reservation(monkeypatch, "orion", {"metadata": {"team": "Orion"}})
+    name_service.generate_and_claim_name(payload, "user")
+    assert len(claims) == 1
+
+
+def _claim_in_environment(monkeypatch, entry, record=None):
+    payload, _, claims = _claim_with_reservation(monkeypatch, None, {})
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: entry)
+    monkeypatch.setattr(name_service, "get_name_record", lambda region, environment, name: record)
+    return payload, claims
+
+
+def test_claim_rejects_region_outside_environment(monkeypatch):
+    payload, claims = _claim_in_environment(monkeypatch, {"AllowedRegions": "eus2,scus"})
+    with pytest.raises(name_service.InvalidRequestError, match="Region 'wus2' is not allowed in environment 'prd'"):
+        name_service.generate_and_claim_name(payload, "user")
+    assert claims == []
+
+
+def test_claim_rejects_name_held_after_release(monkeypatch):
+    released_at = (datetime.now(tz=timezone.utc) - timedelta(hours=1)).isoformat()
+    entry = {"AllowedRegions": "wus2", "ReleasedRetention": "720h"}
+    payload, claims = _claim_in_environment(monkeypatch, entry, {"InUse": False, "ReleasedAt": released_at})
+    with pytest.raises(name_service.NameConflictError, match="held until"):
+        name_service.generate_and_claim_name(payload, "user")
+    assert claims == []
+
+
+def test_claim_accepts_name_after_retention(monkeypatch):
+    released_at = (datetime.now(tz=timezone.utc) - timedelta(hours=2)).isoformat()
+    entry = {"ReleasedRetention": "1h"}
+    payload, claims = _claim_in_environment(monkeypatch, entry, {"InUse": False, "ReleasedAt": released_at})
+    name_service.generate_and_claim_name(payload, "user")
+    assert len(claims) == 1
+
+
+def _claim_with_released_indices(monkeypatch, policy):
+    now = datetime.now(tz=timezone.utc)
+    records = {
+        "st01": {"InUse": True},
+        "st02": {"InUse": False, "ReleasedAt": (now - timedelta(hours=1)).isoformat()},
+        "st03": {"InUse": False, "ReleasedAt": (now - timedelta(hours=2000)).isoformat()},
+    }
+    payload, _, claims = _claim_with_reservation(monkeypatch, None, {"index_reuse": policy})
+    del payload["index"]
+    monkeypatch.setattr(
+        name_service,
+        "build_name",
+        lambda region, environment, slug, rule, optional_inputs: f"{slug}{optional_inputs.get('index', '')}",
+    )
+    monkeypatch.setattr(name_service, "get_environment", lambda environment: {"ReleasedRetention": "720h"})
+    monkeypatch.setattr(name_service, "get_name_record", lambda region, environment, name: records.get(name))
+    monkeypatch.setattr(
+        name_service, "check_name_exists", lambda region, environment, name: records.get(name, {}).get("InUse", False)
+    )
+    return payload, claims
+
+
+@pytest.mark.parametrize(
+    "policy, expected",
+    [("never", "st04"), ("after_hold", "st03"), ("always", "st02"), ("ALWAYS", "st02")],
+)
+def test_claim_allocates_index_by_reuse_policy(monkeypatch, policy, expected):
+    payload, claims = _claim_with_released_indices(monkeypatch, policy)
+
+    preview = name_service.preview_name(dict(payload), "user")
+    result = name_service.generate_and_claim_name(payload, "user")
+
+    assert preview.name == result.name == expected
+    assert claims == [expected]
+    assert result.metadata["Index"] == expected[-2:]
+    assert "Index_reuse" not in result.metadata
+
+
+def test_claim_without_index_reuse_is_not_allocated(monkeypatch):
+    payload, claims = _claim_with_released_indices(monkeypatch, None)
+    del payload["index_reuse"]
+
+    result = name_service.generate_and_claim_name(payload, "user")
+
+    assert result.name == "st"
+    assert claims == ["st"]
+
+
+def test_claim_keeps_explicit_index_with_index_reuse(monkeypatch):
+    payload, claims = _claim_with_released_indices(monkeypatch, "always")
+    payload["index"] = "02"
+
+    with pytest.raises(name_service.NameConflictError, match="held until"):
+        name_service.generate_and_claim_name(payload, "user")
+    assert claims == []
+
+
+def test_claim_allocation_skips_indices_reserved_for_another_team(monkeypatch):
+    payload, claims = _claim_with_released_indices(monkeypatch, "never")
+    monkeypatch.setattr(
+        name_service,
+        "reserving_team",
+        lambda region, environment, resource_type, project, index: "orion" if index == "04" else None,
+    )
+
+    result = name_service.generate_and_claim_name(payload, "user")
+
+    assert result.name == "st05"
+
+
+def test_invalid_index_reuse_is_rejected(monkeypatch):
+    payload, claims = _claim_with_released_indices(monkeypatch, "lowest")
+    with pytest.raises(name_service.InvalidRequestError, match="index_reuse") as excinfo:
+        name_service.generate_and_claim_name(payload, "user")
+    assert excinfo.value.fields == ("index_reuse",)
+    assert claims == []
+
+
+def test_interrupted_claim_under_index_reuse_is_answered_from_its_record(monkeypatch):
+    payload, claims = _claim_with_released_indices(monkeypatch, "never")
+    records = {name: name_service.get_name_record("wus2", "prd", name) for name in ("st01", "st02", "st03")}
+
+    def fake_claim_name(region, environment, name, resource_type, claimed_by, metadata):
+        records[name] = {"InUse": True, "ResourceType": resource_type, "ClaimedBy": claimed_by, **metadata}
+        claims.append(name)
+
+    monkeypatch.setattr(name_service, "claim_name", fake_claim_name)
+    monkeypatch.setattr(name_service, "get_name_record", lambda region, environment, name: records.get(name))
+    monkeypatch.setattr(
+        name_service, "check_name_exists", lambda region, environment, name: records.get(name, {}).get("InUse", False)
+    )
+
+    # The idempotent claim route stores the previewed name before claiming,
+    # and the claim allocates the same index.
+    pending = name_service.preview_name(dict(payload), "user")
+    result = name_service.generate_and_claim_name(dict(payload), "user")
+    assert pending.name == result.name == "st04"
+
+    # Once the claim holds its index, rendering the request again allocates
+    # the next one, so an adopted claim is answered from its record.
+    assert name_service.preview_name(dict(payload), "user").name == "st05"
+    adopted = name_service.claimed_name_result("st04", "WUS2", "prd", records["st04"])
+    assert adopted.to_dict() == result.to_dict()
@@ -0,0 +1,244 @@
+"""Tests for app.routes.notifications and adapters.notifications."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from adapters import notifications as notifications_adapter
+from app.routes import names as names_routes
+from app.routes import notifications as notification_routes
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _make_request(body=None, route_params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params={}, headers={}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeNotificationTable:
+    def __init__(self, entities=None):
+        self._entities = {entity["RowKey"]: entity for entity in entities or []}
+
+    def get_entity(self, partition_key, row_key):
+        if row_key not in self._entities:
+            raise notification_routes.ResourceNotFoundError("not found")
+        return dict(self._entities[row_key])
+
+    def create_entity(self, entity):
+        self._entities[entity["RowKey"]] = entity
+
+    def update_entity(self, entity, mode=None):
+        self._entities[entity["RowKey"]] = entity
+
+    def delete_entity(self, partition_key, row_key):
+        del self._entities[row_key]
+
+    def query_entities(self, query):
+        return [dict(entity) for entity in self._entities.values()]
+
+
+OPS = {
+    "PartitionKey": "notification",
+    "RowKey": "sub-1",
+    "Name": "platform-ops",
+    "Kind": "teams",
+    "Url": "https://example.webhook.office.com/hook",
+    "Events": "claim,release",
+    "Enabled": True,
+}
+
+BODY = {
+    "name": "platform-ops",
+    "kind": "slack",
+    "url": "https://hooks.slack.com/services/T/B/X",
+    "events": ["release"],
+    "enabled": True,
+}
+
+
+def _setup(monkeypatch, *entities):
+    table = FakeNotificationTable(list(entities))
+    monkeypatch.setattr(notification_routes, "require_role", lambda h, min_role: ("u1", ["admin"]))
+    monkeypatch.setattr(notification_routes, "get_table_client", lambda name: table)
+    return table
+
+
+# ---------------------------------------------------------------------------
+# Routes
+# ---------------------------------------------------------------------------
+
+def test_create_notification_assigns_id(monkeypatch):
+    table = _setup(monkeypatch)
+    resp = _fn(notification_routes.create_notification)(_make_request(body=BODY))
+    assert resp.status_code == 201
+    payload = json.loads(resp.get_body())
+    assert payload["id"]
+    assert payload["events"] == ["release"]
+    assert table._entities[payload["id"]]["Kind"] == "slack"
+
+
+def test_create_notification_defaults_events(monkeypatch):
+    _setup(monkeypatch)
+    body = {key: value for key, value in BODY.items() if key != "events"}
+    resp = _fn(notification_routes.create_notification)(_make_request(body=body))
+    assert json.loads(resp.get_body())["events"] == ["claim", "release"]
+
+
+def test_create_notification_rejects_invalid_fields(monkeypatch):
+    _setup(monkeypatch)
+    create = _fn(notification_routes.create_notification)
+    assert create(_make_request(body={**BODY, "kind": "email"})).status_code == 400
+    assert create(_make_request(body={**BODY, "url": "http://insecure"})).status_code == 400
+    assert create(_make_request(body={**BODY, "events": ["purge"]})).status_code == 400
+    assert create(_make_request(body=None)).status_code == 400
+
+
+def test_get_notification(monkeypatch):
+    _setup(monkeypatch, OPS)
+    resp = _fn(notification_routes.get_notification)(_make_request(route_params={"id": "sub-1"}))
+    assert resp.status_code == 200
+    payload = json.loads(resp.get_body())
+    assert payload == {
+        "id": "sub-1",
+        "name": "platform-ops",
+        "kind": "teams",
+        "url": "https://example.webhook.office.com/hook",
+        "events": ["claim", "release"],
+        "enabled": True,
+    }
+
+
+def test_get_missing_notification(monkeypatch):
+    _setup(monkeypatch)
+    resp = _fn(notification_routes.get_notification)(_make_request(route_params={"id": "nope"}))
+    assert resp.status_code == 404
+
+
+def test_update_notification(monkeypatch):
+    table = _setup(monkeypatch, OPS)
+    req = _make_request(body={**BODY, "enabled": False}, route_params={"id": "sub-1"})
+    resp = _fn(notification_routes.update_notification)(req)
+    assert resp.status_code == 200
+    assert table._entities["sub-1"]["Enabled"] is False
+    assert table._entities["sub-1"]["Kind"] == "slack"
+
+
+def test_update_missing_notification(monkeypatch):
+    _setup(monkeypatch)
+    req = _make_request(body=BODY, route_params={"id": "nope"})
+    assert _fn(notification_routes.update_notification)(req).status_code == 404
+
+
+def test_delete_notification(monkeypatch):
+    table = _setup(monkeypatch, OPS)
+    resp = _fn(notification_routes.delete_notification)(_make_request(route_params={"id": "sub-1"}))
+    assert resp.status_code == 204
+    assert "sub-1" not in table._entities
+    resp = _fn(notification_routes.delete_notification)(_make_request(route_params={"id": "sub-1"}))
+    assert resp.status_code == 404
+
+
+# ---------------------------------------------------------------------------
+# Delivery
+# ---------------------------------------------------------------------------
+
+def _capture_posts(monkeypatch, *entities, fail=False):
+    table = FakeNotificationTable(list(entities))
+    monkeypatch.setattr(notifications_adapter, "get_table_client", lambda name: table)
+    posts = []
+
+    def post(url, json=None, timeout=None):
+        posts.append((url, json))
+        if fail:
+            raise RuntimeError("webhook down")
+        return SimpleNamespace(raise_for_status=lambda: None)
+
+    monkeypatch.setattr(notifications_adapter.requests, "post", post)
+    return posts
+
+
+def test_notify_posts_to_matching_subscriptions(monkeypatch):
+    disabled = {**OPS, "RowKey": "sub-2", "Enabled": False}
+    release_only = {**OPS, "RowKey": "sub-3", "Kind": "slack", "Url": "https://hooks.slack.com/x", "Events": "release"}
+    posts = _capture_posts(monkeypatch, OPS, disabled, release_only)
+
+    notifications_adapter.notify("claim", "stwus2prdatlas01", {"user": "u1", "region": "wus2", "environment": "prd"})
+
+    assert posts == [
+        ("https://example.webhook.office.com/hook", {"text": "stwus2prdatlas01 was claimed by u1 (wus2-prd)"}),
+    ]
+
+
+def test_notify_event_grid_schema(monkeypatch):
+    grid = {**OPS, "Kind": "event_grid", "Url": "https://topic.westus2-1.eventgrid.azure.net/api/events"}
+    posts = _capture_posts(monkeypatch, grid)
+
+    notifications_adapter.notify("release", "stwus2prdatlas01", {"user": "u1"})
+
+    (event,) = posts[0][1]
+    assert event["eventType"] == "SanMar.Naming.NameRelease"
+    assert event["subject"] == "names/stwus2prdatlas01"
+    assert event["data"] == {"name": "stwus2prdatlas01", "user": "u1"}
+
+
+def test_notify_swallows_delivery_failures(monkeypatch):
+    posts = _capture_posts(monkeypatch, OPS, fail=True)
+    notifications_adapter.notify("claim", "stwus2prdatlas01", {"user": "u1"})
+    assert len(posts) == 1
+
+
+def test_release_notifies_subscribers(monkeypatch):
+    entity = {
+        "PartitionKey": "wus2-prd", "RowKey": "stwus2prdatlas01",
+        "ClaimedBy": "u1", "InUse": True, "ResourceType": "storage_account",
+    }
+
+    class Table:
+        def get_entity(self, partition_key, row_key):
+            return dict(entity)
+
+        def update_entity(self, entity, mode=None, match_condition=None):
+            pass
+
+    events = []
+    monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("u1", ["contributor"]))
+    monkeypatch.setattr(names_routes, "get_table_client", lambda name: Table())
+    monkeypatch.setattr(names_routes, "write_audit_log", lambda *a, **k: None)
+    monkeypatch.setattr(names_routes, "notify", lambda event, name, details: events.append((event, name, details)))
+
+    body = {"name": "stwus2prdatlas01", "region": "wus2", "environment": "prd", "reason": "decommissioned"}
+    resp = _fn(names_routes.release_name)(_make_request(body=body))
+
+    assert resp.status_code == 200
+    assert events == [
+        (
+            "release",
+            "stwus2prdatlas01",
+            {
+                "user": "u1",
+                "resourceType": "storage_account",
+                "region": "wus2",
+                "environment": "prd",
+                "reason": "decommissioned",
+            },
+        )
+    ]
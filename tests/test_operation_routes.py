@@ -0,0 +1,192 @@
+"""Tests for app.routes.operations module."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+import azure.functions as func
+
+from app.dependencies import InvalidRequestError, NameConflictError
+from app.routes import names as names_routes
+from app.routes import operations as operation_routes
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+def _make_request(body=None, headers=None, route_params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params={}, headers=headers or {}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeOperationTable:
+    def __init__(self):
+        self.entities = {}
+
+    def delete_entity(self, partition_key, row_key):
+        self.entities.pop((partition_key, row_key), None)
+
+    def get_entity(self, partition_key, row_key):
+        if (partition_key, row_key) not in self.entities:
+            raise operation_routes.ResourceNotFoundError("not found")
+        return dict(self.entities[(partition_key, row_key)])
+
+    def create_entity(self, entity):
+        self.entities[(entity["PartitionKey"], entity["RowKey"])] = dict(entity)
+
+    def update_entity(self, entity, mode=None):
+        self.entities[(entity["PartitionKey"], entity["RowKey"])].update(entity)
+
+
+class ClaimedResult:
+    name = "wus2devstvm01"
+
+    def to_dict(self):
+        return {"name": self.name, "resourceType": "virtual_machine"}
+
+
+def _setup(monkeypatch, claim):
+    table = FakeOperationTable()
+    monkeypatch.setattr(names_routes, "require_role", lambda h, min_role: ("U1", ["contributor"]))
+    monkeypatch.setattr(operation_routes, "require_role", lambda h, min_role: ("U1", ["contributor"]))
+    monkeypatch.setattr(operation_routes, "get_table_client", lambda name: table)
+    monkeypatch.setattr(operation_routes, "generate_and_claim_name", claim)
+    return table
+
+
+def _setup_idempotent(monkeypatch, claim):
+    tables = {}
+
+    def get_table(name):
+        return tables.setdefault(name, FakeOperationTable())
+
+    _setup(monkeypatch, claim)
+    monkeypatch.setattr(operation_routes, "get_table_client", get_table)
+    monkeypatch.setattr(names_routes, "get_table_client", get_table)
+    return tables
+
+
+def _start(queue, headers=None):
+    if headers is None:
+        headers = {"Prefer": "respond-async"}
+    request = _make_request(body={"resource_type": "vm"}, headers=headers)
+    return _fn(names_routes.claim_name)(request, queue)
+
+
+def _poll(operation_id):
+    return _fn(operation_routes.get_operation)(_make_request(route_params={"operation_id": operation_id}))
+
+
+def test_async_claim_is_polled_until_it_succeeds(monkeypatch):
+    claims = []
+
+    def claim(payload, requested_by, run_metadata):
+        claims.append((payload, requested_by, run_metadata))
+        return ClaimedResult()
+
+    _setup(monkeypatch, claim)
+    queue = func.Out()
+    resp = _start(queue, {"Prefer": "respond-async", "X-Sanmar-Workspace": "payments-dev"})
+    assert resp.status_code == 202
+    operation_id = json.loads(resp.get_body())["id"]
+    assert resp.headers["Operation-Location"] == f"/api/operations/{operation_id}"
+    assert claims == []
+
+    running = _poll(operation_id)
+    assert json.loads(running.get_body())["status"] == "running"
+    assert running.headers["Retry-After"] == "2"
+
+    message = json.loads(queue.get())
+    operation_routes._run_claim_operation(message["user"], message["id"])
+    assert claims == [({"resource_type": "vm"}, "U1", {"TerraformWorkspace": "payments-dev"})]
+
+    body = json.loads(_poll(operation_id).get_body())
+    assert body["status"] == "succeeded"
+    assert body["result"]["name"] == "wus2devstvm01"
+    assert body["result"]["claimedBy"] == "U1"
+
+    # A redelivered message does not claim a second name.
+    operation_routes._run_claim_operation(message["user"], message["id"])
+    assert len(claims) == 1
+
+
+def test_failed_claim_reports_its_status(monkeypatch):
+    def claim(payload, requested_by, run_metadata):
+        raise NameConflictError("Name 'wus2devstvm01' is already in use.")
+
+    _setup(monkeypatch, claim)
+    queue = func.Out()
+    operation_id = json.loads(_start(queue).get_body())["id"]
+    message = json.loads(queue.get())
+    operation_routes._run_claim_operation(message["user"], message["id"])
+
+    body = json.loads(_poll(operation_id).get_body())
+    assert body["status"] == "failed"
+    assert body["error"] == {"status": 409, "message": "Name 'wus2devstvm01' is already in use."}
+
+
+def test_failed_claim_reports_its_field_errors(monkeypatch):
+    def claim(payload, requested_by, run_metadata):
+        raise InvalidRequestError("Region 'wus2' is not allowed in environment 'prd'.", fields=["region"])
+
+    _setup(monkeypatch, claim)
+    queue = func.Out()
+    operation_id = json.loads(_start(queue).get_body())["id"]
+    message = json.loads(queue.get())
+    operation_routes._run_claim_operation(message["user"], message["id"])
+
+    error = json.loads(_poll(operation_id).get_body())["error"]
+    assert error["status"] == 400
+    assert error["errors"] == [{"field": "region", "message": "Region 'wus2' is not allowed in environment 'prd'."}]
+
+
+def test_claim_without_prefer_stays_synchronous(monkeypatch):
+    table = _setup(monkeypatch, None)
+    monkeypatch.setattr(names_routes, "generate_and_claim_name", lambda p, requested_by, run_metadata: ClaimedResult())
+    queue = func.Out()
+    resp = _start(queue, {})
+    assert resp.status_code == 201
+    assert queue.get() is None
+    assert table.entities == {}
+
+
+def test_unknown_operation_is_not_found(monkeypatch):
+    _setup(monkeypatch, None)
+    assert _poll("missing").status_code == 404
+
+
+def test_idempotent_async_claim_points_repeats_at_the_operation(monkeypatch):
+    claims = []
+
+    def claim(payload, requested_by, run_metadata):
+        claims.append(payload)
+        return ClaimedResult()
+
+    tables = _setup_idempotent(monkeypatch, claim)
+    body = {"resource_type": "vm", "idempotency_key": "0b6f1c1e-7d2a-4c55-9f53-2f7a1d9c0e11"}
+    queue = func.Out()
+    first = _fn(names_routes.claim_name)(_make_request(body=dict(body), headers={"Prefer": "respond-async"}), queue)
+    repeat = _fn(names_routes.claim_name)(_make_request(body=dict(body), headers={"Prefer": "respond-async"}), func.Out())
+    assert first.status_code == repeat.status_code == 202
+    assert first.headers["Operation-Location"] == repeat.headers["Operation-Location"]
+
+    message = json.loads(queue.get())
+    operation_routes._run_claim_operation(message["user"], message["id"])
+    replay = _fn(names_routes.claim_name)(_make_request(body=dict(body)), func.Out())
+    assert replay.status_code == 201
+    assert json.loads(replay.get_body())["name"] == "wus2devstvm01"
+    assert len(claims) == 1
+    assert tables[names_routes.IDEMPOTENCY_TABLE_NAME].entities[("u1", body["idempotency_key"])]["Name"] == "wus2devstvm01"
@@ -0,0 +1,175 @@
+"""Tests for app.routes.projects module."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+from unittest import mock
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from app.routes import projects as project_routes
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _auth_error(msg="Auth failed", status=401):
+    from app.dependencies import AuthError
+    return AuthError(msg, status=status)
+
+
+def _make_request(body=None, route_params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params={}, headers={}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeProjectTable:
+    def __init__(self, entities=None):
+        self._entities = {entity["RowKey"]: entity for entity in entities or []}
+
+    def get_entity(self, partition_key, row_key):
+        if row_key not in self._entities:
+            raise project_routes.ResourceNotFoundError("not found")
+        return dict(self._entities[row_key])
+
+    def query_entities(self, query_filter, parameters=None):
+        return [dict(entity) for entity in self._entities.values() if entity["PartitionKey"] == parameters["pk"]]
+
+    def create_entity(self, entity):
+        if entity["RowKey"] in self._entities:
+            raise project_routes.ResourceExistsError("exists")
+        self._entities[entity["RowKey"]] = entity
+
+    def update_entity(self, entity, mode=None):
+        self._entities[entity["RowKey"]] = entity
+
+    def delete_entity(self, partition_key, row_key):
+        del self._entities[row_key]
+
+
+ATLAS = {
+    "PartitionKey": "project",
+    "RowKey": "atlas",
+    "Owner": "platform-team@sanmar.com",
+    "IndexPolicy": "manual",
+    "CostCenter": "CC-1042",
+}
+
+
+def _setup(monkeypatch, *entities):
+    table = FakeProjectTable(list(entities))
+    monkeypatch.setattr(project_routes, "require_role", lambda h, min_role: ("u1", ["admin"]))
+    monkeypatch.setattr(project_routes, "get_table_client", lambda name: table)
+    return table
+
+
+# ---------------------------------------------------------------------------
+# create_project / list_projects
+# ---------------------------------------------------------------------------
+
+class TestCreateProject:
+    def test_auth_error(self, monkeypatch):
+        monkeypatch.setattr(project_routes, "require_role", mock.Mock(side_effect=_auth_error(status=403)))
+        resp = _fn(project_routes.create_project)(_make_request({"code": "atlas"}))
+        assert resp.status_code == 403
+
+    def test_creates(self, monkeypatch):
+        table = _setup(monkeypatch)
+        body = {"code": "Atlas", "owner": "platform-team@sanmar.com", "description": "Customer data platform"}
+        resp = _fn(project_routes.create_project)(_make_request(body))
+        assert resp.status_code == 201
+        assert json.loads(resp.get_body()) == {
+            "code": "atlas",
+            "owner": "platform-team@sanmar.com",
+            "index_policy": "auto",
+            "description": "Customer data platform",
+        }
+        assert table._entities["atlas"]["IndexPolicy"] == "auto"
+
+    def test_conflict(self, monkeypatch):
+        _setup(monkeypatch, ATLAS)
+        resp = _fn(project_routes.create_project)(_make_request({"code": "atlas", "owner": "someone"}))
+        assert resp.status_code == 409
+
+    def test_invalid_payloads(self, monkeypatch):
+        _setup(monkeypatch)
+        create = _fn(project_routes.create_project)
+        assert create(_make_request(None)).status_code == 400
+        assert create(_make_request({"code": "at las", "owner": "x"})).status_code == 400
+        assert create(_make_request({"code": "atlas"})).status_code == 400
+        assert create(_make_request({"code": "atlas", "owner": "x", "index_policy": "next"})).status_code == 400
+
+
+class TestListProjects:
+    def test_lists_in_code_order(self, monkeypatch):
+        _setup(monkeypatch, ATLAS, {"PartitionKey": "project", "RowKey": "apollo", "Owner": "ops"})
+        resp = _fn(project_routes.list_projects)(_make_request())
+        assert resp.status_code == 200
+        projects = json.loads(resp.get_body())["projects"]
+        assert [project["code"] for project in projects] == ["apollo", "atlas"]
+        assert projects[0]["index_policy"] == "auto"
+        assert projects[1]["cost_center"] == "CC-1042"
+
+    def test_empty_registry(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(project_routes.list_projects)(_make_request())
+        assert json.loads(resp.get_body()) == {"projects": []}
+
+
+# ---------------------------------------------------------------------------
+# get/update/delete_project
+# ---------------------------------------------------------------------------
+
+class TestProjectByCode:
+    def test_get(self, monkeypatch):
+        _setup(monkeypatch, ATLAS)
+        resp = _fn(project_routes.get_project)(_make_request(route_params={"code": "ATLAS"}))
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body())["index_policy"] == "manual"
+
+    def test_get_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(project_routes.get_project)(_make_request(route_params={"code": "atlas"}))
+        assert resp.status_code == 404
+
+    def test_update_replaces_settings(self, monkeypatch):
+        table = _setup(monkeypatch, ATLAS)
+        resp = _fn(project_routes.update_project)(
+            _make_request({"code": "atlas", "owner": "data-team"}, route_params={"code": "atlas"})
+        )
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body()) == {"code": "atlas", "owner": "data-team", "index_policy": "auto"}
+        assert "CostCenter" not in table._entities["atlas"]
+
+    def test_update_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(project_routes.update_project)(
+            _make_request({"owner": "data-team"}, route_params={"code": "atlas"})
+        )
+        assert resp.status_code == 404
+
+    def test_delete(self, monkeypatch):
+        table = _setup(monkeypatch, ATLAS)
+        resp = _fn(project_routes.delete_project)(_make_request(route_params={"code": "atlas"}))
+        assert resp.status_code == 204
+        assert "atlas" not in table._entities
+
+    def test_delete_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(project_routes.delete_project)(_make_request(route_params={"code": "atlas"}))
+        assert resp.status_code == 404
@@ -0,0 +1,145 @@
+"""Tests for app.routes.regions module."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+from unittest import mock
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from app.routes import regions as region_routes
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _auth_error(msg="Auth failed", status=401):
+    from app.dependencies import AuthError
+    return AuthError(msg, status=status)
+
+
+def _make_request(body=None, route_params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params={}, headers={}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeRegionTable:
+    def __init__(self, entities=None):
+        self._entities = {entity["RowKey"]: entity for entity in entities or []}
+
+    def get_entity(self, partition_key, row_key):
+        if row_key not in self._entities:
+            raise region_routes.ResourceNotFoundError("not found")
+        return dict(self._entities[row_key])
+
+    def create_entity(self, entity):
+        if entity["RowKey"] in self._entities:
+            raise region_routes.ResourceExistsError("exists")
+        self._entities[entity["RowKey"]] = entity
+
+    def update_entity(self, entity, mode=None):
+        self._entities[entity["RowKey"]] = entity
+
+    def delete_entity(self, partition_key, row_key):
+        del self._entities[row_key]
+
+
+MXC = {"PartitionKey": "region", "RowKey": "mxc", "Location": "mexicocentral", "DisplayName": "Mexico Central"}
+
+
+def _setup(monkeypatch, *entities):
+    table = FakeRegionTable(list(entities))
+    monkeypatch.setattr(region_routes, "require_role", lambda h, min_role: ("u1", ["admin"]))
+    monkeypatch.setattr(region_routes, "get_table_client", lambda name: table)
+    return table
+
+
+# ---------------------------------------------------------------------------
+# create_region
+# ---------------------------------------------------------------------------
+
+class TestCreateRegion:
+    def test_auth_error(self, monkeypatch):
+        monkeypatch.setattr(region_routes, "require_role", mock.Mock(side_effect=_auth_error(status=403)))
+        resp = _fn(region_routes.create_region)(_make_request({"code": "mxc"}))
+        assert resp.status_code == 403
+
+    def test_creates(self, monkeypatch):
+        table = _setup(monkeypatch)
+        body = {"code": "MXC", "location": "mexicocentral", "display_name": "Mexico Central"}
+        resp = _fn(region_routes.create_region)(_make_request(body))
+        assert resp.status_code == 201
+        assert json.loads(resp.get_body()) == {"code": "mxc", "location": "mexicocentral", "display_name": "Mexico Central"}
+        assert table._entities["mxc"]["Location"] == "mexicocentral"
+
+    def test_conflict(self, monkeypatch):
+        _setup(monkeypatch, MXC)
+        resp = _fn(region_routes.create_region)(_make_request({"code": "mxc", "location": "mexicocentral"}))
+        assert resp.status_code == 409
+
+    def test_invalid_payloads(self, monkeypatch):
+        _setup(monkeypatch)
+        create = _fn(region_routes.create_region)
+        assert create(_make_request(None)).status_code == 400
+        assert create(_make_request({"code": "m x c", "location": "mexicocentral"})).status_code == 400
+        assert create(_make_request({"code": "mxc"})).status_code == 400
+        assert create(_make_request({"code": "mxc", "location": "Mexico Central"})).status_code == 400
+
+
+# ---------------------------------------------------------------------------
+# get/update/delete_region
+# ---------------------------------------------------------------------------
+
+class TestRegionByCode:
+    def test_get(self, monkeypatch):
+        _setup(monkeypatch, MXC)
+        resp = _fn(region_routes.get_region)(_make_request(route_params={"code": "mxc"}))
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body())["location"] == "mexicocentral"
+
+    def test_get_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(region_routes.get_region)(_make_request(route_params={"code": "mxc"}))
+        assert resp.status_code == 404
+
+    def test_update_remaps_location(self, monkeypatch):
+        table = _setup(monkeypatch, MXC)
+        resp = _fn(region_routes.update_region)(
+            _make_request({"code": "mxc", "location": "mexiconorth"}, route_params={"code": "mxc"})
+        )
+        assert resp.status_code == 200
+        assert json.loads(resp.get_body()) == {"code": "mxc", "location": "mexiconorth"}
+        assert "DisplayName" not in table._entities["mxc"]
+
+    def test_update_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(region_routes.update_region)(
+            _make_request({"location": "mexicocentral"}, route_params={"code": "mxc"})
+        )
+        assert resp.status_code == 404
+
+    def test_delete(self, monkeypatch):
+        table = _setup(monkeypatch, MXC)
+        resp = _fn(region_routes.delete_region)(_make_request(route_params={"code": "mxc"}))
+        assert resp.status_code == 204
+        assert "mxc" not in table._entities
+
+    def test_delete_missing(self, monkeypatch):
+        _setup(monkeypatch)
+        resp = _fn(region_routes.delete_region)(_make_request(route_params={"code": "mxc"}))
+        assert resp.status_code == 404
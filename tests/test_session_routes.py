@@ -0,0 +1,91 @@
+"""Tests for app.routes.sessions module."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from datetime import datetime, timedelta, timezone
+from types import SimpleNamespace
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from app.routes import sessions as session_routes
+from core.user_settings import InMemorySettingsRepository, UserSettingsService
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+def _make_request(body=None, params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params=params or {}, headers={}, route_params={}, get_json=get_json)
+
+
+def _setup(monkeypatch):
+    service = UserSettingsService(repository=InMemorySettingsRepository())
+    monkeypatch.setattr(session_routes, "settings_service", service)
+    monkeypatch.setattr(session_routes, "require_role", lambda h, min_role: ("u1", [min_role]))
+    return service
+
+
+def test_get_session_overlays_permanent_defaults(monkeypatch):
+    service = _setup(monkeypatch)
+    service.set_permanent_defaults("u1", {"region": "wus2", "project": "atlas"})
+    service.set_session_defaults("u1", "s-1", {"project": "orion", "system": "erp"})
+
+    resp = _fn(session_routes.get_session)(_make_request(params={"session_id": "s-1"}))
+
+    assert resp.status_code == 200
+    payload = json.loads(resp.get_body())
+    assert payload["sessionId"] == "s-1"
+    assert payload["defaults"] == {"region": "wus2", "project": "orion", "system": "erp"}
+    assert payload["lastSeen"]
+
+
+def test_get_session_does_not_extend_session(monkeypatch):
+    service = _setup(monkeypatch)
+    last_seen = datetime.now(timezone.utc) - timedelta(minutes=30)
+    service.set_session_defaults("u1", "s-1", {"system": "erp"}, now=last_seen)
+
+    _fn(session_routes.get_session)(_make_request(params={"session_id": "s-1"}))
+
+    assert service.repository.get_session("u1", "s-1")[1] == last_seen
+
+
+def test_get_unknown_or_expired_session(monkeypatch):
+    service = _setup(monkeypatch)
+    service.set_session_defaults("u1", "old", {"system": "erp"}, now=datetime.now(timezone.utc) - timedelta(hours=2))
+
+    get = _fn(session_routes.get_session)
+    assert get(_make_request(params={"session_id": "missing"})).status_code == 404
+    assert get(_make_request(params={"session_id": "old"})).status_code == 404
+    assert get(_make_request(params={})).status_code == 400
+
+
+def test_set_session_stores_defaults(monkeypatch):
+    service = _setup(monkeypatch)
+    body = {"session_id": "s-1", "defaults": {"project": "Atlas", "index": 2, "system": ""}}
+
+    resp = _fn(session_routes.set_session)(_make_request(body=body))
+
+    assert resp.status_code == 200
+    assert json.loads(resp.get_body())["defaults"] == {"project": "atlas", "index": "2"}
+    assert service.get_defaults("u1", session_id="s-1") == {"project": "atlas", "index": "2"}
+
+
+def test_set_session_rejects_invalid_payloads(monkeypatch):
+    _setup(monkeypatch)
+    put = _fn(session_routes.set_session)
+    assert put(_make_request(body=None)).status_code == 400
+    assert put(_make_request(body={"session_id": "bad id", "defaults": {}})).status_code == 400
+    assert put(_make_request(body={"session_id": "s-1", "defaults": []})).status_code == 400
+    assert put(_make_request(body={"session_id": "s-1", "defaults": {"owner": "me"}})).status_code == 400
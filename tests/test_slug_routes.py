@@ -81,3 +81,27 @@ def test_slug_lookup_returns_404_when_missing(monkeypatch):
     assert response.status_code == 404
     body = json.loads(response.get_body())
     assert body["message"].startswith("Slug not found")
+
+
+def test_slug_lookup_maps_slug_to_resource_type(monkeypatch):
+    class FakeTable:
+        def get_entity(self, partition_key, row_key):
+            assert partition_key == slug_routes.SLUG_PARTITION_KEY
+            if row_key != "st":
+                raise slug_routes.ResourceNotFoundError("missing")
+            return {"ResourceType": "storage_account", "FullName": "Storage Account", "ClaimedBy": "someone"}
+
+    monkeypatch.setattr(slug_routes, "require_role", lambda headers, min_role: ("user", ["reader"]))
+    monkeypatch.setattr(slug_routes, "get_table_client", lambda table_name: FakeTable())
+
+    response = slug_routes._handle_slug_lookup(SimpleNamespace(params={"slug": " ST "}, headers={}))
+    assert response.status_code == 200
+    assert json.loads(response.get_body()) == {
+        "resourceType": "storage_account",
+        "slug": "st",
+        "fullName": "Storage Account",
+    }
+
+    response = slug_routes._handle_slug_lookup(SimpleNamespace(params={"slug": "zz"}, headers={}))
+    assert response.status_code == 404
+    assert json.loads(response.get_body())["message"] == "No resource type uses slug 'zz'."
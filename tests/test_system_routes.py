@@ -0,0 +1,177 @@
+"""Tests for app.routes.systems module."""
+
+from __future__ import annotations
+
+import json
+import pathlib
+import sys
+from types import SimpleNamespace
+from unittest import mock
+
+ROOT = pathlib.Path(__file__).resolve().parents[1]
+if str(ROOT) not in sys.path:
+    sys.path.insert(0, str(ROOT))
+
+from app.constants import SUBSYSTEMS_TABLE_NAME, SYSTEMS_TABLE_NAME
+from app.routes import systems as system_routes
+
+
+def _fn(builder):
+    """Extract the user function from an Azure Functions FunctionBuilder."""
+    return builder._function.get_user_function()
+
+
+# ---------------------------------------------------------------------------
+# Helpers
+# ---------------------------------------------------------------------------
+
+def _auth_error(msg="Auth failed", status=401):
+    from app.dependencies import AuthError
+    return AuthError(msg, status=status)
+
+
+def _make_request(body=None, route_params=None, params=None):
+    def get_json():
+        if body is None:
+            raise ValueError("No body")
+        return body
+
+    return SimpleNamespace(params=params or {}, headers={}, route_params=route_params or {}, get_json=get_json)
+
+
+class FakeCatalogTable:
+    def __init__(self, entities=None):
+        self._entities = {(entity["PartitionKey"], entity["RowKey"]): entity for entity in entities or []}
+
+    def get_entity(self, partition_key, row_key):
+        if (partition_key, row_key) not in self._entities:
+            raise system_routes.ResourceNotFoundError("not found")
+        return dict(self._entities[(partition_key, row_key)])
+
+    def query_entities(self, query_filter, parameters=None):
+        return [dict(entity) for key, entity in self._entities.items() if key[0] == parameters["pk"]]
+
+    def list_entities(self):
+        return [dict(entity) for entity in self._entities.values()]
+
+    def create_entity(self, entity):
+        key = (entity["PartitionKey"], entity["RowKey"])
+        if key in self._entities:
+            raise system_routes.ResourceExistsError("exists")
+        self._entities[key] = entity
+
+    def update_entity(self, entity, mode=None):
+        self._entities[(entity["PartitionKey"], entity["RowKey"])] = entity
+
+    def delete_entity(self, partition_key, row_key):
+        del self._entities[(partition_key, row_key)]
+
+
+ERP = {"PartitionKey": "system", "RowKey": "erp", "Description": "Enterprise resource planning", "Owner": "finance"}
+CRM = {"PartitionKey": "system", "RowKey": "crm"}
+BILLING = {"PartitionKey": "erp", "RowKey": "billing", "Description": "Invoicing", "Owner": "finance"}
+LEDGER = {"PartitionKey": "erp", "RowKey": "ledger"}
+LEADS = {"PartitionKey": "crm", "RowKey": "leads"}
+
+
+def _setup(monkeypatch, systems=(), subsystems=()):
+    tables = {
+        SYSTEMS_TABLE_NAME: FakeCatalogTable(list(systems)),
+        SUBSYSTEMS_TABLE_NAME: FakeCatalogTable(list(subsystems)),
+    }
+    monkeypatch.setattr(system_routes, "require_role", lambda h, min_role: ("u1", ["admin"]))
+    monkeypatch.setattr(system_routes, "get_table_client", lambda name: tables[name])
+    return tables[SYSTEMS_TABLE_NAME], tables[SUBSYSTEMS_TABLE_NAME]
+
+
+# ---------------------------------------------------------------------------
+# systems
+# ---------------------------------------------------------------------------
+
+class TestSystems:
+    def test_auth_error(self, monkeypatch):
+        monkeypatch.setattr(system_routes, "require_role", mock.Mock(side_effect=_auth_error(status=403)))
+        resp = _fn(system_routes.create_system)(_make_request({"code": "erp"}))
+        assert resp.status_code == 403
+
+    def test_create_and_list(self, monkeypatch):
+        systems, _ = _setup(monkeypatch, systems=[ERP])
+        resp = _fn(system_routes.create_system)(_make_request({"code": "CRM", "owner": "sales"}))
+        assert resp.status_code == 201
+        assert json.loads(resp.get_body()) == {"code": "crm", "owner": "sales"}
+        assert ("system", "crm") in systems._entities
+
+        resp = _fn(system_routes.list_systems)(_make_request())
+        assert json.loads(resp.get_body()) == {
+            "systems": [
+                {"code": "crm", "owner": "sales"},
+                {"code": "erp", "description": "Enterprise resource planning", "owner": "finance"},
+            ]
+        }
+
+    def test_create_rejects_invalid_and_duplicate_codes(self, monkeypatch):
+        _setup(monkeypatch, systems=[ERP])
+        create = _fn(system_routes.create_system)
+        assert create(_make_request(None)).status_code == 400
+        assert create(_make_request({"code": "e rp"})).status_code == 400
+        assert create(_make_request({"code": "erp"})).status_code == 409
+
+    def test_get_update(self, monkeypatch):
+        systems, _ = _setup(monkeypatch, systems=[ERP])
+        resp = _fn(system_routes.get_system)(_make_request(route_params={"code": "ERP"}))
+        assert json.loads(resp.get_body())["owner"] == "finance"
+
+        resp = _fn(system_routes.update_system)(_make_request({"owner": "platform"}, route_params={"code": "erp"}))
+        assert resp.status_code == 200
+        assert systems._entities[("system", "erp")] == {"PartitionKey": "system", "RowKey": "erp", "Owner": "platform"}
+
+        resp = _fn(system_routes.update_system)(_make_request({"owner": "x"}, route_params={"code": "hr"}))
+        assert resp.status_code == 404
+
+    def test_delete_refuses_systems_with_subsystems(self, monkeypatch):
+        systems, _ = _setup(monkeypatch, systems=[ERP, CRM], subsystems=[BILLING])
+        delete = _fn(system_routes.delete_system)
+        assert delete(_make_request(route_params={"code": "erp"})).status_code == 409
+        assert delete(_make_request(route_params={"code": "crm"})).status_code == 204
+        assert delete(_make_request(route_params={"code": "crm"})).status_code == 404
+        assert list(systems._entities) == [("system", "erp")]
+
+
+# ---------------------------------------------------------------------------
+# subsystems
+# ---------------------------------------------------------------------------
+
+class TestSubsystems:
+    def test_create_requires_registered_system(self, monkeypatch):
+        _, subsystems = _setup(monkeypatch, systems=[ERP], subsystems=[BILLING])
+        create = _fn(system_routes.create_subsystem)
+        resp = create(_make_request({"code": "ledger", "system": "ERP", "description": "General ledger"}))
+        assert resp.status_code == 201
+        assert json.loads(resp.get_body()) == {"code": "ledger", "description": "General ledger", "system": "erp"}
+        assert ("erp", "ledger") in subsystems._entities
+
+        assert create(_make_request({"code": "leads", "system": "crm"})).status_code == 400
+        assert create(_make_request({"code": "billing", "system": "erp"})).status_code == 409
+        assert create(_make_request({"code": "billing"})).status_code == 400
+
+    def test_list_filters_by_system(self, monkeypatch):
+        _setup(monkeypatch, systems=[ERP, CRM], subsystems=[LEDGER, LEADS, BILLING])
+        resp = _fn(system_routes.list_subsystems)(_make_request())
+        codes = [(s["system"], s["code"]) for s in json.loads(resp.get_body())["subsystems"]]
+        assert codes == [("crm", "leads"), ("erp", "billing"), ("erp", "ledger")]
+
+        resp = _fn(system_routes.list_subsystems)(_make_request(params={"system": "ERP"}))
+        assert [s["code"] for s in json.loads(resp.get_body())["subsystems"]] == ["billing", "ledger"]
+
+    def test_get_update_delete(self, monkeypatch):
+        _, subsystems = _setup(monkeypatch, systems=[ERP], subsystems=[BILLING])
+        route = {"system": "erp", "code": "billing"}
+        resp = _fn(system_routes.get_subsystem)(_make_request(route_params=route))
+        assert json.loads(resp.get_body()) == {"code": "billing", "description": "Invoicing", "owner": "finance", "system": "erp"}
+
+        resp = _fn(system_routes.update_subsystem)(_make_request({"owner": "billing-team"}, route_params=route))
+        assert json.loads(resp.get_body()) == {"code": "billing", "owner": "billing-team", "system": "erp"}
+
+        assert _fn(system_routes.delete_subsystem)(_make_request(route_params=route)).status_code == 204
+        assert _fn(system_routes.get_subsystem)(_make_request(route_params=route)).status_code == 404
+        assert subsystems._entities == {}
@@ -220,6 +220,13 @@ def test_validate_name_characters():
         validation.validate_name("no_good$", rule)
 
 
+def test_validate_name_rule_characters():
+    rule = {"max_length": 30, "valid_characters": "a-z0-9"}
+    validation.validate_name("wus2prdstsanmarerp01", rule)
+    with pytest.raises(ValueError, match="Allowed characters: a-z0-9"):
+        validation.validate_name("wus2prdstsanmar-erp01", rule)
+
+
 def test_render_display_skips_optional_missing_values():
     rule = naming_rules.DEFAULT_RULE
     payload = {
@@ -300,6 +307,11 @@ def test_describe_rule_exposes_template_details():
         assert spec["nameTemplate"] == custom_rule.name_template
         assert any(field["name"] == "index_segment" for field in spec["templateFields"])
         assert any(mapping["segment"] == "system_short" for mapping in spec["segmentMappings"])
+        assert spec["canonicalization"]["project"] == ["trim", "lower"]
+        assert spec["maxLength"] == 30
+        assert spec["validCharacters"] == "a-z0-9-"
+        assert spec["caseRule"] == "lower"
+        assert spec["uniquenessScope"] is None
     finally:
         naming_rules.set_rule_provider(original_provider)
 